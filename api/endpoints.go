@@ -15,6 +15,10 @@ func put(i *jsonAPIHandler, path string, w http.ResponseWriter, r *http.Request)
 		i.PUTModerator(w, r)
 	case strings.HasPrefix(path, "/ob/listing"):
 		i.PUTListing(w, r)
+	case strings.HasPrefix(path, "/ob/groupchat"):
+		i.PUTGroupChat(w, r)
+	case strings.HasPrefix(path, "/ob/cart"):
+		i.PUTCart(w, r)
 	default:
 		ErrorResponse(w, http.StatusNotFound, "Not Found")
 	}
@@ -26,6 +30,22 @@ func post(i *jsonAPIHandler, path string, w http.ResponseWriter, r *http.Request
 		i.POSTListing(w, r)
 	case strings.HasPrefix(path, "/ob/purchase"):
 		i.POSTPurchase(w, r)
+	case strings.HasPrefix(path, "/ob/cart/checkout"):
+		i.POSTCheckout(w, r)
+	case strings.HasPrefix(path, "/ob/cart"):
+		i.POSTCart(w, r)
+	case strings.HasPrefix(path, "/ob/fundcheckout"):
+		i.POSTFundCheckout(w, r)
+	case strings.HasPrefix(path, "/ob/digitalasset"):
+		i.POSTDigitalAsset(w, r)
+	case strings.HasPrefix(path, "/ob/licensekeys"):
+		i.POSTLicenseKeys(w, r)
+	case strings.HasPrefix(path, "/ob/bid"):
+		i.POSTBid(w, r)
+	case strings.HasPrefix(path, "/ob/estimatetotal"):
+		i.POSTEstimateTotal(w, r)
+	case strings.HasPrefix(path, "/ob/quote"):
+		i.POSTQuote(w, r)
 	case strings.HasPrefix(path, "/ob/follow"):
 		i.POSTFollow(w, r)
 	case strings.HasPrefix(path, "/ob/unfollow"):
@@ -68,12 +88,26 @@ func post(i *jsonAPIHandler, path string, w http.ResponseWriter, r *http.Request
 		i.POSTChat(w, r)
 	case strings.HasPrefix(path, "/ob/markchatasread"):
 		i.POSTMarkChatAsRead(w, r)
+	case strings.HasPrefix(path, "/ob/groupchatmessage"):
+		i.POSTGroupChatMessage(w, r)
+	case strings.HasPrefix(path, "/ob/groupchat"):
+		i.POSTGroupChat(w, r)
+	case strings.HasPrefix(path, "/ob/markgroupchatasread"):
+		i.POSTMarkGroupChatAsRead(w, r)
 	case strings.HasPrefix(path, "/ob/marknotificationasread"):
 		i.POSTMarkNotificationAsRead(w, r)
 	case strings.HasPrefix(path, "/ob/fetchprofiles"):
 		i.POSTFetchProfiles(w, r)
+	case strings.HasPrefix(path, "/ob/fetchmessages"):
+		i.POSTFetchMessages(w, r)
 	case strings.HasPrefix(path, "/ob/blocknode"):
 		i.POSTBlockNode(w, r)
+	case strings.HasPrefix(path, "/ob/apitokens"):
+		i.POSTAPIToken(w, r)
+	case strings.HasPrefix(path, "/ob/webhooks"):
+		i.POSTWebhook(w, r)
+	case strings.HasPrefix(path, "/ob/channel"):
+		i.POSTChannel(w, r)
 	case strings.HasPrefix(path, "/ob/shutdown"):
 		i.POSTShutdown(w, r)
 	default:
@@ -95,12 +129,18 @@ func get(i *jsonAPIHandler, path string, w http.ResponseWriter, r *http.Request)
 		i.GETMnemonic(w, r)
 	case strings.HasPrefix(path, "/wallet/balance"):
 		i.GETBalance(w, r)
+	case strings.HasPrefix(path, "/wallet/currencies"):
+		i.GETCurrencies(w, r)
 	case strings.HasPrefix(path, "/wallet/transactions"):
 		i.GETTransactions(w, r)
+	case strings.HasPrefix(path, "/wallet/export"):
+		i.GETExportWallet(w, r)
 	case strings.HasPrefix(path, "/ob/settings"):
 		i.GETSettings(w, r)
 	case strings.HasPrefix(path, "/ob/closestpeers"):
 		i.GETClosestPeers(w, r)
+	case strings.HasPrefix(path, "/ob/exchangerate/history"):
+		i.GETExchangeRateHistory(w, r)
 	case strings.HasPrefix(path, "/ob/exchangerate"):
 		i.GETExchangeRate(w, r)
 	case strings.HasPrefix(path, "/ob/followers"):
@@ -119,26 +159,70 @@ func get(i *jsonAPIHandler, path string, w http.ResponseWriter, r *http.Request)
 		i.GETFollowsMe(w, r)
 	case strings.HasPrefix(path, "/ob/isfollowing"):
 		i.GETIsFollowing(w, r)
+	case strings.HasPrefix(path, "/ob/orderhistory"):
+		i.GETOrderHistory(w, r)
 	case strings.HasPrefix(path, "/ob/order"):
 		i.GETOrder(w, r)
+	case strings.HasPrefix(path, "/ob/tags"):
+		i.GETTag(w, r)
+	case strings.HasPrefix(path, "/ob/channel"):
+		i.GETChannel(w, r)
+	case strings.HasPrefix(path, "/ob/outbox"):
+		i.GETOutbox(w, r)
+	case strings.HasPrefix(path, "/ob/outgoingmessages"):
+		i.GETOutgoingMessages(w, r)
 	case strings.HasPrefix(path, "/ob/moderators"):
 		i.GETModerators(w, r)
 	case strings.HasPrefix(path, "/ob/case"):
 		i.GETCase(w, r)
+	case strings.HasPrefix(path, "/ob/chatattachment"):
+		i.GETChatAttachment(w, r)
 	case strings.HasPrefix(path, "/ob/chatmessages"):
 		i.GETChatMessages(w, r)
 	case strings.HasPrefix(path, "/ob/chatconversations"):
 		i.GETChatConversations(w, r)
+	case strings.HasPrefix(path, "/ob/groupchats"):
+		i.GETGroupChats(w, r)
+	case strings.HasPrefix(path, "/ob/groupchat"):
+		i.GETGroupChatMessages(w, r)
 	case strings.HasPrefix(path, "/ob/notifications"):
 		i.GETNotifications(w, r)
 	case strings.HasPrefix(path, "/ob/images"):
 		i.GETImage(w, r)
 	case strings.HasPrefix(path, "/ob/purchases"):
 		i.GETPurchases(w, r)
+	case strings.HasPrefix(path, "/ob/cart"):
+		i.GETCart(w, r)
+	case strings.HasPrefix(path, "/ob/checkoutgroup"):
+		i.GETCheckoutGroup(w, r)
+	case strings.HasPrefix(path, "/ob/digitalassets"):
+		i.GETDigitalAssets(w, r)
+	case strings.HasPrefix(path, "/ob/licensekeys"):
+		i.GETLicenseKeys(w, r)
+	case strings.HasPrefix(path, "/ob/digitaldeliveryfile"):
+		i.GETDigitalDeliveryFile(w, r)
+	case strings.HasPrefix(path, "/ob/digitaldelivery"):
+		i.GETDigitalDelivery(w, r)
 	case strings.HasPrefix(path, "/ob/sales"):
 		i.GETSales(w, r)
+	case strings.HasPrefix(path, "/ob/bids"):
+		i.GETBids(w, r)
+	case strings.HasPrefix(path, "/ob/campaign"):
+		i.GETCampaign(w, r)
 	case strings.HasPrefix(path, "/ob/cases"):
 		i.GETCases(w, r)
+	case strings.HasPrefix(path, "/ob/apitokens"):
+		i.GETAPITokens(w, r)
+	case strings.HasPrefix(path, "/ob/webhooks/deliveries"):
+		i.GETWebhookDeliveries(w, r)
+	case strings.HasPrefix(path, "/ob/webhooks"):
+		i.GETWebhooks(w, r)
+	case strings.HasPrefix(path, "/ob/search"):
+		i.GETSearch(w, r)
+	case strings.HasPrefix(path, "/ob/export/sales"):
+		i.GETExportSales(w, r)
+	case strings.HasPrefix(path, "/ob/export/purchases"):
+		i.GETExportPurchases(w, r)
 	default:
 		ErrorResponse(w, http.StatusNotFound, "Not Found")
 	}
@@ -165,10 +249,22 @@ func deleter(i *jsonAPIHandler, path string, w http.ResponseWriter, r *http.Requ
 		i.DELETEChatMessage(w, r)
 	case strings.HasPrefix(path, "/ob/chatconversation"):
 		i.DELETEChatConversation(w, r)
+	case strings.HasPrefix(path, "/ob/groupchat"):
+		i.DELETEGroupChat(w, r)
+	case strings.HasPrefix(path, "/ob/cart"):
+		i.DELETECart(w, r)
+	case strings.HasPrefix(path, "/ob/digitalasset"):
+		i.DELETEDigitalAsset(w, r)
+	case strings.HasPrefix(path, "/ob/licensekeys"):
+		i.DELETELicenseKeys(w, r)
 	case strings.HasPrefix(path, "/ob/notifications"):
 		i.DELETENotification(w, r)
 	case strings.HasPrefix(path, "/ob/blocknode"):
 		i.DELETEBlockNode(w, r)
+	case strings.HasPrefix(path, "/ob/apitokens"):
+		i.DELETEAPIToken(w, r)
+	case strings.HasPrefix(path, "/ob/webhooks"):
+		i.DELETEWebhook(w, r)
 	default:
 		ErrorResponse(w, http.StatusNotFound, "Not Found")
 	}
@@ -2,9 +2,13 @@ package api
 
 import (
 	"crypto/rand"
+	"encoding/base64"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	mh "gx/ipfs/QmbZ6Cee2uHjG7hf19qLHppgKDRtaG4CVtMzdmK9VCVqLu/go-multihash"
+	"mime"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
@@ -12,6 +16,7 @@ import (
 	"path"
 	"path/filepath"
 	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -20,6 +25,7 @@ import (
 
 	"crypto/sha256"
 	"github.com/OpenBazaar/jsonpb"
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
 	"github.com/OpenBazaar/openbazaar-go/core"
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
 	"github.com/OpenBazaar/openbazaar-go/pb"
@@ -97,13 +103,21 @@ func (i *jsonAPIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", *i.config.Cors)
 		w.Header().Set("Access-Control-Allow-Methods", "PUT,POST,DELETE")
 		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Total-Count")
 	}
 
 	for k, v := range i.config.Headers {
 		w.Header()[k] = v
 	}
 
-	if i.config.Authenticated {
+	// A bearer token is checked against its scopes whether or not other authentication is enabled
+	if token, ok := bearerToken(r); ok {
+		if !authorizeToken(i.node.Datastore.APITokens(), token, r.Method, r.URL.Path) {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, "403 - Forbidden")
+			return
+		}
+	} else if i.config.Authenticated {
 		if i.config.Username == "" || i.config.Password == "" {
 			cookie, err := r.Cookie("OpenBazaar_Auth_Cookie")
 			if err != nil {
@@ -695,8 +709,139 @@ func (i *jsonAPIHandler) POSTPurchase(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
+func (i *jsonAPIHandler) POSTEstimateTotal(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var data core.PurchaseData
+	err := decoder.Decode(&data)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(data.Items) == 0 {
+		ErrorResponse(w, http.StatusBadRequest, "Order has no items")
+		return
+	}
+	breakdown, err := i.node.EstimateOrderTotal(&data)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	b, err := json.MarshalIndent(breakdown, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(b))
+}
+
+func (i *jsonAPIHandler) POSTQuote(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var data core.PurchaseData
+	err := decoder.Decode(&data)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(data.Items) == 0 {
+		ErrorResponse(w, http.StatusBadRequest, "Order has no items")
+		return
+	}
+	quote, err := i.node.QuoteOrder(&data)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	b, err := json.MarshalIndent(quote, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(b))
+}
+
+func (i *jsonAPIHandler) POSTBid(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var data core.BidData
+	err := decoder.Decode(&data)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	orderId, err := i.node.Bid(&data)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, fmt.Sprintf(`{"orderId": "%s"}`, orderId))
+}
+
+func (i *jsonAPIHandler) GETBids(w http.ResponseWriter, r *http.Request) {
+	_, slug := path.Split(r.URL.Path)
+	var bids []repo.Bid
+	var err error
+	if slug == "" || strings.ToLower(slug) == "bids" {
+		limit := r.URL.Query().Get("limit")
+		if limit == "" {
+			limit = "-1"
+		}
+		var l int
+		l, err = strconv.Atoi(limit)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		bids, err = i.node.Datastore.Bids().GetOutgoing(r.URL.Query().Get("offsetId"), l)
+	} else {
+		bids, err = i.node.Datastore.Bids().GetBySlug(slug)
+	}
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(bids, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if string(ret) == "null" {
+		ret = []byte("[]")
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) GETCampaign(w http.ResponseWriter, r *http.Request) {
+	_, slug := path.Split(r.URL.Path)
+	campaign, err := i.node.CampaignProgress(slug)
+	if err != nil {
+		ErrorResponse(w, http.StatusNotFound, "Campaign not found.")
+		return
+	}
+	ret, err := json.MarshalIndent(campaign, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
 func (i *jsonAPIHandler) GETStatus(w http.ResponseWriter, r *http.Request) {
 	_, peerId := path.Split(r.URL.Path)
+	// Without a peer ID return the status of our own node
+	if peerId == "" || peerId == "status" {
+		var nodeStatus struct {
+			Retriever interface{} `json:"retriever"`
+		}
+		if i.node.MessageRetriever != nil {
+			nodeStatus.Retriever = i.node.MessageRetriever.Status()
+		}
+		ret, err := json.MarshalIndent(nodeStatus, "", "    ")
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		SanitizedResponse(w, string(ret))
+		return
+	}
 	status, err := i.node.GetPeerStatus(peerId)
 	if err != nil {
 		ErrorResponse(w, http.StatusBadRequest, err.Error())
@@ -759,8 +904,35 @@ func (i *jsonAPIHandler) POSTUnfollow(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
+// Return the wallet for the optional coin query parameter, writing an error response if we don't have one
+func (i *jsonAPIHandler) requestWallet(w http.ResponseWriter, r *http.Request) (bitcoin.BitcoinWallet, bool) {
+	wal, err := i.node.WalletForCurrency(r.URL.Query().Get("coin"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	return wal, true
+}
+
+func (i *jsonAPIHandler) GETCurrencies(w http.ResponseWriter, r *http.Request) {
+	codes := []string{i.node.Wallet.CurrencyCode()}
+	if i.node.Wallets != nil {
+		codes = i.node.Wallets.CurrencyCodes()
+	}
+	ret, err := json.MarshalIndent(codes, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
 func (i *jsonAPIHandler) GETAddress(w http.ResponseWriter, r *http.Request) {
-	addr := i.node.Wallet.CurrentAddress(spvwallet.EXTERNAL)
+	wal, ok := i.requestWallet(w, r)
+	if !ok {
+		return
+	}
+	addr := wal.CurrentAddress(spvwallet.EXTERNAL)
 	SanitizedResponse(w, fmt.Sprintf(`{"address": "%s"}`, addr.EncodeAddress()))
 }
 
@@ -774,7 +946,11 @@ func (i *jsonAPIHandler) GETMnemonic(w http.ResponseWriter, r *http.Request) {
 }
 
 func (i *jsonAPIHandler) GETBalance(w http.ResponseWriter, r *http.Request) {
-	confirmed, unconfirmed := i.node.Wallet.Balance()
+	wal, ok := i.requestWallet(w, r)
+	if !ok {
+		return
+	}
+	confirmed, unconfirmed := wal.Balance()
 	SanitizedResponse(w, fmt.Sprintf(`{"confirmed": "%d", "unconfirmed": "%d"}`, int(confirmed), int(unconfirmed)))
 }
 
@@ -785,6 +961,10 @@ func (i *jsonAPIHandler) POSTSpendCoins(w http.ResponseWriter, r *http.Request)
 		FeeLevel string `json:"feeLevel"`
 		Memo     string `json:"memo"`
 	}
+	wal, ok := i.requestWallet(w, r)
+	if !ok {
+		return
+	}
 	decoder := json.NewDecoder(r.Body)
 	var snd Send
 	err := decoder.Decode(&snd)
@@ -801,12 +981,12 @@ func (i *jsonAPIHandler) POSTSpendCoins(w http.ResponseWriter, r *http.Request)
 	case "ECONOMIC":
 		feeLevel = spvwallet.ECONOMIC
 	}
-	addr, err := btc.DecodeAddress(snd.Address, i.node.Wallet.Params())
+	addr, err := btc.DecodeAddress(snd.Address, wal.Params())
 	if err != nil {
 		ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	txid, err := i.node.Wallet.Spend(snd.Amount, addr, feeLevel)
+	txid, err := wal.Spend(snd.Amount, addr, feeLevel)
 	if err != nil {
 		ErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -865,6 +1045,10 @@ func (i *jsonAPIHandler) POSTSettings(w http.ResponseWriter, r *http.Request) {
 		ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if err = validateOrderRules(settings); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	_, err = i.node.Datastore.Settings().Get()
 	if err == nil {
 		ErrorResponse(w, http.StatusConflict, "Settings is already set. Use PUT.")
@@ -908,6 +1092,10 @@ func (i *jsonAPIHandler) PUTSettings(w http.ResponseWriter, r *http.Request) {
 		ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if err = validateOrderRules(settings); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	_, err = i.node.Datastore.Settings().Get()
 	if err != nil {
 		ErrorResponse(w, http.StatusNotFound, "Settings is not yet set. Use POST.")
@@ -966,6 +1154,10 @@ func (i *jsonAPIHandler) PATCHSettings(w http.ResponseWriter, r *http.Request) {
 		ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if err = validateOrderRules(settings); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if settings.StoreModerators != nil {
 		go i.node.NotifyModerators(*settings.StoreModerators)
 		if err := i.node.SetModeratorsOnListings(*settings.StoreModerators); err != nil {
@@ -994,6 +1186,24 @@ func (i *jsonAPIHandler) PATCHSettings(w http.ResponseWriter, r *http.Request) {
 	SanitizedResponse(w, `{}`)
 }
 
+func validateOrderRules(s repo.SettingsData) error {
+	if s.OrderRules == nil {
+		return nil
+	}
+	for slug, rule := range *s.OrderRules {
+		if slug == "" {
+			return errors.New("Order rules must be keyed by a listing slug or " + repo.DefaultOrderRule)
+		}
+		if rule.DeliveryUrl != "" {
+			u, err := url.Parse(rule.DeliveryUrl)
+			if err != nil || u.Scheme == "" || u.Host == "" {
+				return fmt.Errorf("Invalid delivery url for %s", slug)
+			}
+		}
+	}
+	return nil
+}
+
 func (i *jsonAPIHandler) GETClosestPeers(w http.ResponseWriter, r *http.Request) {
 	_, peerId := path.Split(r.URL.Path)
 	var peerIds []string
@@ -1035,128 +1245,334 @@ func (i *jsonAPIHandler) GETExchangeRate(w http.ResponseWriter, r *http.Request)
 	}
 }
 
-func (i *jsonAPIHandler) GETFollowers(w http.ResponseWriter, r *http.Request) {
-	_, peerId := path.Split(r.URL.Path)
-	var err error
-	if peerId == "" || strings.ToLower(peerId) == "followers" || peerId == i.node.IpfsNode.Identity.Pretty() {
-		offset := r.URL.Query().Get("offsetId")
-		limit := r.URL.Query().Get("limit")
-		if limit == "" {
-			limit = "-1"
-		}
-		l, err := strconv.ParseInt(limit, 10, 32)
-		if err != nil {
-			ErrorResponse(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-		followers, err := i.node.Datastore.Followers().Get(offset, int(l))
-		if err != nil {
-			ErrorResponse(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-		ret, _ := json.MarshalIndent(followers, "", "    ")
-		if string(ret) == "null" {
-			ret = []byte("[]")
-		}
-		SanitizedResponse(w, string(ret))
-	} else {
-		if strings.HasPrefix(peerId, "@") {
-			peerId, err = i.node.Resolver.Resolve(peerId)
-			if err != nil {
-				ErrorResponse(w, http.StatusNotFound, err.Error())
-				return
-			}
-		}
-		followBytes, err := ipfs.ResolveThenCat(i.node.Context, ipnspath.FromString(path.Join(peerId, "followers")))
-		if err != nil {
-			ErrorResponse(w, http.StatusNotFound, err.Error())
-			return
-		}
-		w.Header().Set("Cache-Control", "public, max-age=600, immutable")
-		SanitizedResponse(w, string(followBytes))
-	}
-}
-
-func (i *jsonAPIHandler) GETFollowing(w http.ResponseWriter, r *http.Request) {
-	_, peerId := path.Split(r.URL.Path)
-	var err error
-	if peerId == "" || strings.ToLower(peerId) == "following" || peerId == i.node.IpfsNode.Identity.Pretty() {
-		offset := r.URL.Query().Get("offsetId")
-		limit := r.URL.Query().Get("limit")
-		if limit == "" {
-			limit = "-1"
-		}
-		l, err := strconv.ParseInt(limit, 10, 32)
-		if err != nil {
-			ErrorResponse(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-		followers, err := i.node.Datastore.Following().Get(offset, int(l))
-		if err != nil {
-			ErrorResponse(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-		ret, _ := json.MarshalIndent(followers, "", "    ")
-		if string(ret) == "null" {
-			ret = []byte("[]")
-		}
-		SanitizedResponse(w, string(ret))
-	} else {
-		if strings.HasPrefix(peerId, "@") {
-			peerId, err = i.node.Resolver.Resolve(peerId)
-			if err != nil {
-				ErrorResponse(w, http.StatusNotFound, err.Error())
-				return
-			}
-		}
-		followBytes, err := ipfs.ResolveThenCat(i.node.Context, ipnspath.FromString(path.Join(peerId, "following")))
-		if err != nil {
-			ErrorResponse(w, http.StatusNotFound, err.Error())
-			return
-		}
-		w.Header().Set("Cache-Control", "public, max-age=600, immutable")
-		SanitizedResponse(w, string(followBytes))
+// Return the rates recorded for a currency, oldest first, optionally between from and to
+func (i *jsonAPIHandler) GETExchangeRateHistory(w http.ResponseWriter, r *http.Request) {
+	currencyCode := r.URL.Query().Get("currency")
+	if currencyCode == "" {
+		ErrorResponse(w, http.StatusBadRequest, "A currency must be specified")
+		return
 	}
-}
-
-func (i *jsonAPIHandler) GETInventory(w http.ResponseWriter, r *http.Request) {
-	type inv struct {
-		Slug     string `json:"slug"`
-		Variant  int    `json:"variant"`
-		Quantity int    `json:"quantity"`
+	from, err := parseTimeParam(r.URL.Query().Get("from"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
 	}
-	var invList []inv
-	inventory, err := i.node.Datastore.Inventory().GetAll()
+	to, err := parseTimeParam(r.URL.Query().Get("to"))
 	if err != nil {
-		fmt.Fprint(w, `[]`)
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	for slug, m := range inventory {
-		for variant, count := range m {
-			i := inv{slug, variant, count}
-			invList = append(invList, i)
-		}
+	samples, err := i.node.Datastore.ExchangeRateHistory().Get(currencyCode, from, to)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
 	}
-	ret, _ := json.MarshalIndent(invList, "", "    ")
-	if string(ret) == "null" {
-		fmt.Fprint(w, `[]`)
+	ret, err := json.MarshalIndent(samples, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 	SanitizedResponse(w, string(ret))
-	return
 }
 
-func (i *jsonAPIHandler) POSTInventory(w http.ResponseWriter, r *http.Request) {
-	type inv struct {
-		Slug     string `json:"slug"`
-		Variant  int    `json:"variant"`
-		Quantity int    `json:"quantity"`
-	}
-	decoder := json.NewDecoder(r.Body)
-	var invList []inv
-	err := decoder.Decode(&invList)
+func (i *jsonAPIHandler) GETAPITokens(w http.ResponseWriter, r *http.Request) {
+	tokens, err := i.node.Datastore.APITokens().GetAll()
 	if err != nil {
-		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(tokens, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+/* Create a token with the given name and scopes. The token itself is only returned
+   here as just its hash is saved. */
+func (i *jsonAPIHandler) POSTAPIToken(w http.ResponseWriter, r *http.Request) {
+	type tokenRequest struct {
+		Name   string   `json:"name"`
+		Scopes []string `json:"scopes"`
+	}
+	var req tokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	tokens, err := i.node.Datastore.APITokens().GetAll()
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for _, t := range tokens {
+		if t.Name == req.Name {
+			ErrorResponse(w, http.StatusConflict, "A token named "+req.Name+" already exists")
+			return
+		}
+	}
+	token, apiToken, err := repo.NewAPIToken(i.node.Datastore.APITokens(), req.Name, req.Scopes)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	type tokenResponse struct {
+		repo.APIToken
+		Token string `json:"token"`
+	}
+	ret, err := json.MarshalIndent(tokenResponse{apiToken, token}, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) DELETEAPIToken(w http.ResponseWriter, r *http.Request) {
+	_, name := path.Split(r.URL.Path)
+	if name == "" || name == "apitokens" {
+		ErrorResponse(w, http.StatusBadRequest, "A token name must be given")
+		return
+	}
+	err := i.node.Datastore.APITokens().Delete(name)
+	if err == repo.ErrAPITokenNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, `{}`)
+}
+
+func (i *jsonAPIHandler) GETWebhooks(w http.ResponseWriter, r *http.Request) {
+	hooks, err := i.node.Datastore.Webhooks().GetAll()
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(hooks, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+/* Add a webhook. Notifications are signed with its secret which is only returned
+   here, so one is generated and returned if none is given. */
+func (i *jsonAPIHandler) POSTWebhook(w http.ResponseWriter, r *http.Request) {
+	type webhookRequest struct {
+		URL    string   `json:"url"`
+		Secret string   `json:"secret"`
+		Events []string `json:"events"`
+	}
+	var req webhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	hook, err := newWebhook(req.URL, req.Secret, req.Events)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := i.node.Datastore.Webhooks().Put(hook); err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	type webhookResponse struct {
+		repo.Webhook
+		Secret string `json:"secret"`
+	}
+	ret, err := json.MarshalIndent(webhookResponse{hook, hook.Secret}, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) DELETEWebhook(w http.ResponseWriter, r *http.Request) {
+	_, id := path.Split(r.URL.Path)
+	if id == "" || id == "webhooks" {
+		ErrorResponse(w, http.StatusBadRequest, "A webhook id must be given")
+		return
+	}
+	err := i.node.Datastore.Webhooks().Delete(id)
+	if err == repo.ErrWebhookNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, `{}`)
+}
+
+/* Return the webhook delivery log, newest first. It can be filtered by status and
+   paged with offset and limit. The total is returned in the X-Total-Count header. */
+func (i *jsonAPIHandler) GETWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", repo.WebhookDeliveryPending, repo.WebhookDeliveryDelivered, repo.WebhookDeliveryFailed:
+	default:
+		ErrorResponse(w, http.StatusBadRequest, "Unknown status "+status)
+		return
+	}
+	offset, limit := 0, -1
+	var err error
+	if o := r.URL.Query().Get("offset"); o != "" {
+		offset, err = strconv.Atoi(o)
+		if err != nil || offset < 0 {
+			ErrorResponse(w, http.StatusBadRequest, "Invalid offset")
+			return
+		}
+	}
+	if l := r.URL.Query().Get("limit"); l != "" {
+		limit, err = strconv.Atoi(l)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+	}
+	deliveries, total, err := i.node.Datastore.WebhookDeliveries().GetAll(status, offset, limit)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(deliveries, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) GETFollowers(w http.ResponseWriter, r *http.Request) {
+	_, peerId := path.Split(r.URL.Path)
+	var err error
+	if peerId == "" || strings.ToLower(peerId) == "followers" || peerId == i.node.IpfsNode.Identity.Pretty() {
+		offset := r.URL.Query().Get("offsetId")
+		limit := r.URL.Query().Get("limit")
+		if limit == "" {
+			limit = "-1"
+		}
+		l, err := strconv.ParseInt(limit, 10, 32)
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		followers, err := i.node.Datastore.Followers().Get(offset, int(l))
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		ret, _ := json.MarshalIndent(followers, "", "    ")
+		if string(ret) == "null" {
+			ret = []byte("[]")
+		}
+		SanitizedResponse(w, string(ret))
+	} else {
+		if strings.HasPrefix(peerId, "@") {
+			peerId, err = i.node.Resolver.Resolve(peerId)
+			if err != nil {
+				ErrorResponse(w, http.StatusNotFound, err.Error())
+				return
+			}
+		}
+		followBytes, err := ipfs.ResolveThenCat(i.node.Context, ipnspath.FromString(path.Join(peerId, "followers")))
+		if err != nil {
+			ErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+		w.Header().Set("Cache-Control", "public, max-age=600, immutable")
+		SanitizedResponse(w, string(followBytes))
+	}
+}
+
+func (i *jsonAPIHandler) GETFollowing(w http.ResponseWriter, r *http.Request) {
+	_, peerId := path.Split(r.URL.Path)
+	var err error
+	if peerId == "" || strings.ToLower(peerId) == "following" || peerId == i.node.IpfsNode.Identity.Pretty() {
+		offset := r.URL.Query().Get("offsetId")
+		limit := r.URL.Query().Get("limit")
+		if limit == "" {
+			limit = "-1"
+		}
+		l, err := strconv.ParseInt(limit, 10, 32)
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		followers, err := i.node.Datastore.Following().Get(offset, int(l))
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		ret, _ := json.MarshalIndent(followers, "", "    ")
+		if string(ret) == "null" {
+			ret = []byte("[]")
+		}
+		SanitizedResponse(w, string(ret))
+	} else {
+		if strings.HasPrefix(peerId, "@") {
+			peerId, err = i.node.Resolver.Resolve(peerId)
+			if err != nil {
+				ErrorResponse(w, http.StatusNotFound, err.Error())
+				return
+			}
+		}
+		followBytes, err := ipfs.ResolveThenCat(i.node.Context, ipnspath.FromString(path.Join(peerId, "following")))
+		if err != nil {
+			ErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+		w.Header().Set("Cache-Control", "public, max-age=600, immutable")
+		SanitizedResponse(w, string(followBytes))
+	}
+}
+
+func (i *jsonAPIHandler) GETInventory(w http.ResponseWriter, r *http.Request) {
+	type inv struct {
+		Slug     string `json:"slug"`
+		Variant  int    `json:"variant"`
+		Quantity int    `json:"quantity"`
+	}
+	var invList []inv
+	inventory, err := i.node.Datastore.Inventory().GetAll()
+	if err != nil {
+		fmt.Fprint(w, `[]`)
+		return
+	}
+	for slug, m := range inventory {
+		for variant, count := range m {
+			i := inv{slug, variant, count}
+			invList = append(invList, i)
+		}
+	}
+	ret, _ := json.MarshalIndent(invList, "", "    ")
+	if string(ret) == "null" {
+		fmt.Fprint(w, `[]`)
+		return
+	}
+	SanitizedResponse(w, string(ret))
+	return
+}
+
+func (i *jsonAPIHandler) POSTInventory(w http.ResponseWriter, r *http.Request) {
+	type inv struct {
+		Slug     string `json:"slug"`
+		Variant  int    `json:"variant"`
+		Quantity int    `json:"quantity"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	var invList []inv
+	err := decoder.Decode(&invList)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	for _, in := range invList {
@@ -1466,7 +1882,11 @@ func (i *jsonAPIHandler) POSTOrderCancel(w http.ResponseWriter, r *http.Request)
 }
 
 func (i *jsonAPIHandler) POSTResyncBlockchain(w http.ResponseWriter, r *http.Request) {
-	i.node.Wallet.ReSyncBlockchain(0)
+	wal, ok := i.requestWallet(w, r)
+	if !ok {
+		return
+	}
+	wal.ReSyncBlockchain(0)
 	SanitizedResponse(w, `{}`)
 	return
 }
@@ -1489,6 +1909,11 @@ func (i *jsonAPIHandler) GETOrder(w http.ResponseWriter, r *http.Request) {
 		}
 		isSale = true
 	}
+	wal, err := i.node.WalletForContract(contract)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
 	resp := new(pb.OrderRespApi)
 	resp.Contract = contract
 	resp.Funded = funded
@@ -1504,7 +1929,7 @@ func (i *jsonAPIHandler) GETOrder(w http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			continue
 		}
-		confirmations, err := i.node.Wallet.GetConfirmations(*ch)
+		confirmations, err := wal.GetConfirmations(*ch)
 		if err != nil {
 			continue
 		}
@@ -1541,7 +1966,7 @@ func (i *jsonAPIHandler) POSTShutdown(w http.ResponseWriter, r *http.Request) {
 			core.Node.Datastore.Close()
 			repoLockFile := filepath.Join(core.Node.RepoPath, lockfile.LockFile)
 			os.Remove(repoLockFile)
-			core.Node.Wallet.Close()
+			core.Node.CloseWallets()
 			core.Node.IpfsNode.Close()
 		}
 		os.Exit(1)
@@ -1727,37 +2152,252 @@ func (i *jsonAPIHandler) GETModerators(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (i *jsonAPIHandler) POSTOrderFulfill(w http.ResponseWriter, r *http.Request) {
-	decoder := json.NewDecoder(r.Body)
-	var fulfill pb.OrderFulfillment
-	err := decoder.Decode(&fulfill)
-	if err != nil {
-		ErrorResponse(w, http.StatusBadRequest, err.Error())
-		return
-	}
-	contract, state, _, records, _, err := i.node.Datastore.Sales().GetByOrderId(fulfill.OrderId)
-	if err != nil {
-		ErrorResponse(w, http.StatusNotFound, "order not found")
+/* Find vendors who have published a pointer for the tag and return the entries in
+   their listing index which carry it. Peers whose index can't be fetched are skipped. */
+func (i *jsonAPIHandler) GETTag(w http.ResponseWriter, r *http.Request) {
+	_, tag := path.Split(r.URL.Path)
+	tag = core.NormalizeTag(tag)
+	if tag == "" || tag == "tags" {
+		ErrorResponse(w, http.StatusBadRequest, "A tag must be given")
 		return
 	}
-	if state != pb.OrderState_FUNDED {
-		ErrorResponse(w, http.StatusBadRequest, "order must be funded before fulfilling")
+	dht, ok := i.node.IpfsNode.Routing.(*routing.IpfsDHT)
+	if !ok {
+		ErrorResponse(w, http.StatusInternalServerError, "Tag lookups need the DHT")
 		return
 	}
-	err = i.node.FulfillOrder(&fulfill, contract, records)
+	pointerID, err := core.TagPointerID(tag)
 	if err != nil {
 		ErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	SanitizedResponse(w, `{}`)
-	return
-}
-
-func (i *jsonAPIHandler) POSTOrderComplete(w http.ResponseWriter, r *http.Request) {
-	checkRatingValue := func(val int) {
-		if val < core.RatingMin || val > core.RatingMax {
-			ErrorResponse(w, http.StatusBadRequest, "rating values must be between 1 and 5")
-			return
+	type peerListings struct {
+		PeerId   string            `json:"peerId"`
+		Listings []json.RawMessage `json:"listings"`
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	var (
+		ret   = []peerListings{}
+		lock  sync.Mutex
+		wg    sync.WaitGroup
+		found = make(map[string]bool)
+	)
+	for p := range ipfs.FindPointersAsync(dht, ctx, pointerID, core.TagPointerPrefixLength) {
+		pid, err := core.ExtractIDFromPointer(p)
+		if err != nil || found[pid] {
+			continue
+		}
+		found[pid] = true
+		wg.Add(1)
+		go func(pid string) {
+			defer wg.Done()
+			indexBytes, err := ipfs.ResolveThenCat(i.node.Context, ipnspath.FromString(path.Join(pid, "listings", "index.json")))
+			if err != nil {
+				return
+			}
+			var index []json.RawMessage
+			if err := json.Unmarshal(indexBytes, &index); err != nil {
+				return
+			}
+			matches := peerListings{PeerId: pid}
+			for _, entry := range index {
+				var ld struct {
+					Tags []string `json:"tags"`
+				}
+				if err := json.Unmarshal(entry, &ld); err != nil {
+					continue
+				}
+				for _, t := range ld.Tags {
+					if core.NormalizeTag(t) == tag {
+						matches.Listings = append(matches.Listings, entry)
+						break
+					}
+				}
+			}
+			if len(matches.Listings) == 0 {
+				return
+			}
+			lock.Lock()
+			ret = append(ret, matches)
+			lock.Unlock()
+		}(pid)
+	}
+	wg.Wait()
+	out, err := json.MarshalIndent(ret, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(out))
+}
+
+func (i *jsonAPIHandler) GETChannel(w http.ResponseWriter, r *http.Request) {
+	_, channel := path.Split(r.URL.Path)
+	channel = core.NormalizeChannel(channel)
+	if channel == "" || channel == "channel" {
+		ErrorResponse(w, http.StatusBadRequest, "A channel name must be given")
+		return
+	}
+	limit := -1
+	if l := r.URL.Query().Get("limit"); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+	}
+	posts, err := i.node.GetChannelPosts(channel, limit)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(posts, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) POSTChannel(w http.ResponseWriter, r *http.Request) {
+	type channelPost struct {
+		Channel     string `json:"channel"`
+		ListingHash string `json:"listingHash"`
+		Text        string `json:"text"`
+	}
+	var req channelPost
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	post, err := i.node.PostToChannel(req.Channel, req.ListingHash, req.Text)
+	if err == core.ErrChannelUnavailable {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(post, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) GETOutbox(w http.ResponseWriter, r *http.Request) {
+	outbox, err := i.node.GetOutbox()
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(outbox, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) GETOutgoingMessages(w http.ResponseWriter, r *http.Request) {
+	_, id := path.Split(r.URL.Path)
+	if id != "" && id != "outgoingmessages" {
+		out, err := i.node.Datastore.OutgoingMessages().Get(id)
+		if err == repo.ErrOutgoingMessageNotFound {
+			ErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		} else if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		ret, err := json.MarshalIndent(out, "", "    ")
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		SanitizedResponse(w, string(ret))
+		return
+	}
+	var (
+		messages []repo.OutgoingMessage
+		total    int
+		err      error
+	)
+	if reference := r.URL.Query().Get("reference"); reference != "" {
+		messages, err = i.node.Datastore.OutgoingMessages().GetByReference(reference)
+		total = len(messages)
+	} else {
+		state := r.URL.Query().Get("state")
+		switch state {
+		case "", repo.OutgoingMessageSentDirect, repo.OutgoingMessageStoredOffline, repo.OutgoingMessageAcked, repo.OutgoingMessageFailed:
+		default:
+			ErrorResponse(w, http.StatusBadRequest, "Unknown state "+state)
+			return
+		}
+		offset, limit := 0, -1
+		if o := r.URL.Query().Get("offset"); o != "" {
+			offset, err = strconv.Atoi(o)
+			if err != nil || offset < 0 {
+				ErrorResponse(w, http.StatusBadRequest, "Invalid offset")
+				return
+			}
+		}
+		if l := r.URL.Query().Get("limit"); l != "" {
+			limit, err = strconv.Atoi(l)
+			if err != nil {
+				ErrorResponse(w, http.StatusBadRequest, "Invalid limit")
+				return
+			}
+		}
+		messages, total, err = i.node.Datastore.OutgoingMessages().GetAll(r.URL.Query().Get("peerId"), state, offset, limit)
+	}
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(messages, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) POSTOrderFulfill(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	var fulfill pb.OrderFulfillment
+	err := decoder.Decode(&fulfill)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	contract, state, _, records, _, err := i.node.Datastore.Sales().GetByOrderId(fulfill.OrderId)
+	if err != nil {
+		ErrorResponse(w, http.StatusNotFound, "order not found")
+		return
+	}
+	if state != pb.OrderState_FUNDED {
+		ErrorResponse(w, http.StatusBadRequest, "order must be funded before fulfilling")
+		return
+	}
+	err = i.node.FulfillOrder(&fulfill, contract, records)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, `{}`)
+	return
+}
+
+func (i *jsonAPIHandler) POSTOrderComplete(w http.ResponseWriter, r *http.Request) {
+	checkRatingValue := func(val int) {
+		if val < core.RatingMin || val > core.RatingMax {
+			ErrorResponse(w, http.StatusBadRequest, "rating values must be between 1 and 5")
+			return
 		}
 	}
 	decoder := json.NewDecoder(r.Body)
@@ -1954,7 +2594,15 @@ func (i *jsonAPIHandler) POSTReleaseFunds(w http.ResponseWriter, r *http.Request
 
 func (i *jsonAPIHandler) POSTChat(w http.ResponseWriter, r *http.Request) {
 	decoder := json.NewDecoder(r.Body)
-	var chat repo.ChatMessage
+	var chat struct {
+		PeerId      string `json:"peerId"`
+		Subject     string `json:"subject"`
+		Message     string `json:"message"`
+		Attachments []struct {
+			Filename string `json:"filename"`
+			Data     string `json:"data"`
+		} `json:"attachments"`
+	}
 	err := decoder.Decode(&chat)
 	if err != nil {
 		ErrorResponse(w, http.StatusBadRequest, err.Error())
@@ -1968,12 +2616,28 @@ func (i *jsonAPIHandler) POSTChat(w http.ResponseWriter, r *http.Request) {
 		ErrorResponse(w, http.StatusBadRequest, "Message is too long")
 		return
 	}
+	if len(chat.Attachments) > core.ChatMaxAttachments {
+		ErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("A message can have at most %d attachments", core.ChatMaxAttachments))
+		return
+	}
+	files := make([][]byte, len(chat.Attachments))
+	for n, a := range chat.Attachments {
+		files[n], err = base64.StdEncoding.DecodeString(a.Data)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, "Attachment data must be base64 encoded")
+			return
+		}
+		if len(files[n]) == 0 || len(files[n]) > core.ChatAttachmentMaxBytes {
+			ErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Attachments must be between 1 and %d bytes", core.ChatAttachmentMaxBytes))
+			return
+		}
+	}
 
 	t := time.Now()
 	ts := new(timestamp.Timestamp)
 	ts.Seconds = t.Unix()
 	var flag pb.Chat_Flag
-	if chat.Message == "" {
+	if chat.Message == "" && len(chat.Attachments) == 0 {
 		flag = pb.Chat_TYPING
 	} else {
 		flag = pb.Chat_MESSAGE
@@ -1998,6 +2662,14 @@ func (i *jsonAPIHandler) POSTChat(w http.ResponseWriter, r *http.Request) {
 		Timestamp: ts,
 		Flag:      flag,
 	}
+	for n, a := range chat.Attachments {
+		attachment, err := i.node.AddChatAttachment(a.Filename, files[n])
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		chatPb.Attachments = append(chatPb.Attachments, attachment)
+	}
 	err = i.node.SendChat(chat.PeerId, chatPb)
 	if err != nil {
 		ErrorResponse(w, http.StatusInternalServerError, err.Error())
@@ -2010,11 +2682,63 @@ func (i *jsonAPIHandler) POSTChat(w http.ResponseWriter, r *http.Request) {
 			ErrorResponse(w, http.StatusInternalServerError, err.Error())
 			return
 		}
+		err = i.node.SaveChatAttachments(msgId.B58String(), chatPb.Attachments)
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if len(chatPb.Attachments) == 0 {
+		SanitizedResponse(w, fmt.Sprintf(`{"messageId": "%s"}`, msgId.B58String()))
+		return
+	}
+	ids := make([]string, len(chatPb.Attachments))
+	for n, a := range chatPb.Attachments {
+		ids[n] = a.Hash
+	}
+	ret, err := json.MarshalIndent(struct {
+		MessageId   string   `json:"messageId"`
+		Attachments []string `json:"attachments"`
+	}{msgId.B58String(), ids}, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
 	}
-	SanitizedResponse(w, fmt.Sprintf(`{"messageId": "%s"}`, msgId.B58String()))
+	SanitizedResponse(w, string(ret))
 	return
 }
 
+/* Fetch an attachment sent or received in a chat and return it decrypted. Images
+   have a JPEG thumbnail which is returned instead with ?thumbnail=true. */
+func (i *jsonAPIHandler) GETChatAttachment(w http.ResponseWriter, r *http.Request) {
+	_, id := path.Split(r.URL.Path)
+	attachment, err := i.node.Datastore.Chat().GetAttachment(id)
+	if err == repo.ErrChatAttachmentNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	thumbnail := r.URL.Query().Get("thumbnail") == "true"
+	data, err := i.node.GetChatAttachment(attachment, thumbnail)
+	if err == core.ErrNoThumbnail {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	mediaType := attachment.MediaType
+	if thumbnail {
+		mediaType = "image/jpeg"
+	}
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write(data)
+}
+
 func (i *jsonAPIHandler) GETChatMessages(w http.ResponseWriter, r *http.Request) {
 	_, peerId := path.Split(r.URL.Path)
 	limit := r.URL.Query().Get("limit")
@@ -2074,27 +2798,552 @@ func (i *jsonAPIHandler) POSTMarkChatAsRead(w http.ResponseWriter, r *http.Reque
 			return
 		}
 	}
-	SanitizedResponse(w, `{}`)
+	SanitizedResponse(w, `{}`)
+}
+
+func (i *jsonAPIHandler) DELETEChatMessage(w http.ResponseWriter, r *http.Request) {
+	_, messageId := path.Split(r.URL.Path)
+	err := i.node.Datastore.Chat().DeleteMessage(messageId)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, `{}`)
+}
+
+func (i *jsonAPIHandler) DELETEChatConversation(w http.ResponseWriter, r *http.Request) {
+	_, peerId := path.Split(r.URL.Path)
+	err := i.node.Datastore.Chat().DeleteConversation(peerId)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, `{}`)
+}
+
+func (i *jsonAPIHandler) GETGroupChats(w http.ResponseWriter, r *http.Request) {
+	groups, err := i.node.Datastore.GroupChats().GetAll()
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(groups, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) GETGroupChatMessages(w http.ResponseWriter, r *http.Request) {
+	_, groupId := path.Split(r.URL.Path)
+	if _, err := i.node.Datastore.GroupChats().Get(groupId); err == repo.ErrGroupChatNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	limit := r.URL.Query().Get("limit")
+	if limit == "" {
+		limit = "-1"
+	}
+	l, err := strconv.Atoi(limit)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	messages, err := i.node.Datastore.GroupChats().GetMessages(groupId, r.URL.Query().Get("offsetId"), l)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(messages, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) POSTGroupChat(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Subject string   `json:"subject"`
+		Members []string `json:"members"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := validateGroupChat(req.Subject, req.Members); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	group, err := i.node.CreateGroupChat(req.Subject, req.Members)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(group, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) PUTGroupChat(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		GroupId string   `json:"groupId"`
+		Subject string   `json:"subject"`
+		Members []string `json:"members"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := validateGroupChat(req.Subject, req.Members); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	group, err := i.node.UpdateGroupChat(req.GroupId, req.Subject, req.Members)
+	if err == repo.ErrGroupChatNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err == core.ErrNotGroupCreator {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(group, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+// Check the parts of a group the caller controls so mistakes are reported as bad requests
+func validateGroupChat(subject string, members []string) error {
+	if len(subject) > core.CHAT_SUBJECT_MAX_CHARACTERS {
+		return errors.New("Subject line is too long")
+	}
+	if len(members) == 0 {
+		return errors.New("A group needs at least one other member")
+	}
+	if len(members) >= core.GroupChatMaxMembers {
+		return fmt.Errorf("A group can have at most %d members", core.GroupChatMaxMembers)
+	}
+	for _, m := range members {
+		if _, err := peer.IDB58Decode(m); err != nil {
+			return fmt.Errorf("Invalid member %s", m)
+		}
+	}
+	return nil
+}
+
+func (i *jsonAPIHandler) POSTGroupChatMessage(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		GroupId string `json:"groupId"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Message == "" {
+		ErrorResponse(w, http.StatusBadRequest, "Message is empty")
+		return
+	}
+	if len(req.Message) > core.CHAT_MESSAGE_MAX_CHARACTERS {
+		ErrorResponse(w, http.StatusBadRequest, "Message is too long")
+		return
+	}
+	msg, err := i.node.SendGroupChat(req.GroupId, req.Message)
+	if err == repo.ErrGroupChatNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err == core.ErrNotGroupMember {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, fmt.Sprintf(`{"messageId": "%s"}`, msg.MessageId))
+}
+
+func (i *jsonAPIHandler) POSTMarkGroupChatAsRead(w http.ResponseWriter, r *http.Request) {
+	_, groupId := path.Split(r.URL.Path)
+	if err := i.node.Datastore.GroupChats().MarkAsRead(groupId); err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, `{}`)
+}
+
+// Deleting a group only removes our copy, the other members can still write to it
+func (i *jsonAPIHandler) DELETEGroupChat(w http.ResponseWriter, r *http.Request) {
+	_, groupId := path.Split(r.URL.Path)
+	err := i.node.Datastore.GroupChats().Delete(groupId)
+	if err == repo.ErrGroupChatNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, `{}`)
+}
+
+func (i *jsonAPIHandler) GETCart(w http.ResponseWriter, r *http.Request) {
+	items, err := i.node.Datastore.Cart().GetAll()
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(items, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) POSTCart(w http.ResponseWriter, r *http.Request) {
+	var item repo.CartItem
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if item.ListingHash == "" {
+		ErrorResponse(w, http.StatusBadRequest, "Listing hash must be set")
+		return
+	}
+	if item.Quantity <= 0 {
+		ErrorResponse(w, http.StatusBadRequest, "Quantity must be at least one")
+		return
+	}
+	item, err := i.node.AddToCart(item)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(item, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) PUTCart(w http.ResponseWriter, r *http.Request) {
+	var item repo.CartItem
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if item.Quantity <= 0 {
+		ErrorResponse(w, http.StatusBadRequest, "Quantity must be at least one")
+		return
+	}
+	err := i.node.UpdateCartItem(item)
+	if err == repo.ErrCartItemNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, `{}`)
+}
+
+func (i *jsonAPIHandler) DELETECart(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/ob/cart"), "/")
+	var err error
+	if id == "" {
+		err = i.node.Datastore.Cart().DeleteAll()
+	} else {
+		err = i.node.Datastore.Cart().Delete(id)
+	}
+	if err == repo.ErrCartItemNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, `{}`)
+}
+
+func (i *jsonAPIHandler) POSTCheckout(w http.ResponseWriter, r *http.Request) {
+	var data core.CheckoutData
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	group, failed, err := i.node.Checkout(&data)
+	if err == core.ErrCartEmpty {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	} else if err != nil && len(group.Orders) == 0 {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if failed == nil {
+		failed = []core.CheckoutFailure{}
+	}
+	ret, err := json.MarshalIndent(struct {
+		CheckoutGroup repo.CheckoutGroup     `json:"checkoutGroup"`
+		Failed        []core.CheckoutFailure `json:"failed"`
+	}{group, failed}, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) GETCheckoutGroup(w http.ResponseWriter, r *http.Request) {
+	_, groupId := path.Split(r.URL.Path)
+	group, err := i.node.Datastore.CheckoutGroups().Get(groupId)
+	if err == repo.ErrCheckoutGroupNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(group, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) POSTFundCheckout(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		CheckoutGroup string `json:"checkoutGroup"`
+		FeeLevel      string `json:"feeLevel"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var feeLevel spvwallet.FeeLevel
+	switch strings.ToUpper(req.FeeLevel) {
+	case "PRIORITY":
+		feeLevel = spvwallet.PRIOIRTY
+	case "NORMAL":
+		feeLevel = spvwallet.NORMAL
+	case "ECONOMIC":
+		feeLevel = spvwallet.ECONOMIC
+	}
+	txids, err := i.node.FundCheckoutGroup(req.CheckoutGroup, feeLevel)
+	switch err {
+	case nil:
+	case repo.ErrCheckoutGroupNotFound:
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	case core.ErrCheckoutGroupFunded:
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	case core.ErrCheckoutGroupFunding:
+		ErrorResponse(w, http.StatusConflict, err.Error())
+		return
+	default:
+		// Some orders may have been paid before the error so the txids of those are returned with it
+		if len(txids) > 0 {
+			ErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("%s. Paid in %s", err.Error(), strings.Join(txids, ", ")))
+			return
+		}
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(struct {
+		Txids []string `json:"txids"`
+	}{txids}, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) GETDigitalAssets(w http.ResponseWriter, r *http.Request) {
+	assets, err := i.node.Datastore.DigitalAssets().GetAll()
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(assets, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) POSTDigitalAsset(w http.ResponseWriter, r *http.Request) {
+	var file struct {
+		Slug     string `json:"slug"`
+		Filename string `json:"filename"`
+		Data     string `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&file); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	data, err := base64.StdEncoding.DecodeString(file.Data)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "File data must be base64 encoded")
+		return
+	}
+	asset, err := i.node.SetDigitalAsset(file.Slug, file.Filename, data)
+	if err == core.ErrListingNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(asset, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) DELETEDigitalAsset(w http.ResponseWriter, r *http.Request) {
+	_, slug := path.Split(r.URL.Path)
+	err := i.node.Datastore.DigitalAssets().Delete(slug)
+	if err == repo.ErrDigitalAssetNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, `{}`)
+}
+
+func (i *jsonAPIHandler) GETLicenseKeys(w http.ResponseWriter, r *http.Request) {
+	var ret []byte
+	slug := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/ob/licensekeys"), "/")
+	if slug == "" {
+		counts, err := i.node.Datastore.LicenseKeys().GetAll()
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		ret, err = json.MarshalIndent(counts, "", "    ")
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	} else {
+		keys, err := i.node.Datastore.LicenseKeys().Get(slug)
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		ret, err = json.MarshalIndent(keys, "", "    ")
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) POSTLicenseKeys(w http.ResponseWriter, r *http.Request) {
+	var pool struct {
+		Slug string   `json:"slug"`
+		Keys []string `json:"keys"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&pool); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	unused, err := i.node.AddLicenseKeys(pool.Slug, pool.Keys)
+	if err == core.ErrListingNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	SanitizedResponse(w, fmt.Sprintf(`{"unused": %d}`, unused))
+}
+
+func (i *jsonAPIHandler) DELETELicenseKeys(w http.ResponseWriter, r *http.Request) {
+	_, slug := path.Split(r.URL.Path)
+	if err := i.node.Datastore.LicenseKeys().DeleteUnused(slug); err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, `{}`)
+}
+
+func (i *jsonAPIHandler) GETDigitalDelivery(w http.ResponseWriter, r *http.Request) {
+	_, orderId := path.Split(r.URL.Path)
+	contract, _, _, _, _, err := i.node.Datastore.Purchases().GetByOrderId(orderId)
+	if err != nil {
+		ErrorResponse(w, http.StatusNotFound, "Order not found")
+		return
+	}
+	deliveries, err := i.node.GetDigitalDeliveries(contract)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(deliveries, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
 }
 
-func (i *jsonAPIHandler) DELETEChatMessage(w http.ResponseWriter, r *http.Request) {
-	_, messageId := path.Split(r.URL.Path)
-	err := i.node.Datastore.Chat().DeleteMessage(messageId)
+func (i *jsonAPIHandler) GETDigitalDeliveryFile(w http.ResponseWriter, r *http.Request) {
+	urlPath, slug := path.Split(r.URL.Path)
+	_, orderId := path.Split(strings.TrimSuffix(urlPath, "/"))
+	contract, _, _, _, _, err := i.node.Datastore.Purchases().GetByOrderId(orderId)
 	if err != nil {
+		ErrorResponse(w, http.StatusNotFound, "Order not found")
+		return
+	}
+	delivery, data, err := i.node.GetDigitalDeliveryFile(contract, slug)
+	if err == core.ErrDeliveryMissing {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
 		ErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	SanitizedResponse(w, `{}`)
+	w.Header().Set("Content-Type", delivery.MediaType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": delivery.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write(data)
 }
 
-func (i *jsonAPIHandler) DELETEChatConversation(w http.ResponseWriter, r *http.Request) {
-	_, peerId := path.Split(r.URL.Path)
-	err := i.node.Datastore.Chat().DeleteConversation(peerId)
+func (i *jsonAPIHandler) GETOrderHistory(w http.ResponseWriter, r *http.Request) {
+	_, orderId := path.Split(r.URL.Path)
+	history, err := i.node.Datastore.OrderHistory().Get(orderId)
 	if err != nil {
 		ErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	SanitizedResponse(w, `{}`)
+	ret, err := json.MarshalIndent(history, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
 }
 
 func (i *jsonAPIHandler) GETNotifications(w http.ResponseWriter, r *http.Request) {
@@ -2175,6 +3424,20 @@ func (i *jsonAPIHandler) GETImage(w http.ResponseWriter, r *http.Request) {
 	http.ServeContent(w, r, imageHash, time.Now(), dr)
 }
 
+// Check for offline messages now rather than waiting for the retriever's next run
+func (i *jsonAPIHandler) POSTFetchMessages(w http.ResponseWriter, r *http.Request) {
+	if i.node.MessageRetriever == nil {
+		ErrorResponse(w, http.StatusInternalServerError, "Message retriever is not running")
+		return
+	}
+	ret, err := json.MarshalIndent(i.node.MessageRetriever.FetchNow(), "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
 func (i *jsonAPIHandler) POSTFetchProfiles(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("async")
 	async, _ := strconv.ParseBool(query)
@@ -2297,7 +3560,11 @@ func (i *jsonAPIHandler) GETTransactions(w http.ResponseWriter, r *http.Request)
 		Thumbnail     string    `json:"thumbnail"`
 		CanBumpFee    bool      `json:"canBumpFee"`
 	}
-	transactions, err := i.node.Wallet.Transactions()
+	wal, ok := i.requestWallet(w, r)
+	if !ok {
+		return
+	}
+	transactions, err := wal.Transactions()
 	if err != nil {
 		ErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -2307,7 +3574,7 @@ func (i *jsonAPIHandler) GETTransactions(w http.ResponseWriter, r *http.Request)
 		ErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	height := i.node.Wallet.ChainTip()
+	height := wal.ChainTip()
 	var txs []Tx
 	for _, t := range transactions {
 		var confirmations int32
@@ -2360,27 +3627,52 @@ func (i *jsonAPIHandler) GETTransactions(w http.ResponseWriter, r *http.Request)
 }
 
 func (i *jsonAPIHandler) GETPurchases(w http.ResponseWriter, r *http.Request) {
-	limit := r.URL.Query().Get("limit")
-	if limit == "" {
-		limit = "-1"
-	}
-	l, err := strconv.Atoi(limit)
-	if err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
-	}
+	var purchases []repo.Purchase
 	offsetId := r.URL.Query().Get("offsetId")
-	purchases, err := i.node.Datastore.Purchases().GetAll(offsetId, l)
-	if err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
-	}
-	for _, p := range purchases {
+	if offsetId != "" {
+		// Paging by order ID is kept for older clients and ignores the filters
+		limit := r.URL.Query().Get("limit")
+		if limit == "" {
+			limit = "-1"
+		}
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		purchases, err = i.node.Datastore.Purchases().GetAll(offsetId, l)
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	} else {
+		query, err := parseOrderQuery(r)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		var total int
+		purchases, total, err = i.node.Datastore.Purchases().Query(query)
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	}
+	for n, p := range purchases {
+		if group, err := i.node.Datastore.CheckoutGroups().GetByOrderId(p.OrderId); err == nil {
+			purchases[n].CheckoutGroup = group.Id
+			for _, o := range group.Orders {
+				if o.OrderId == p.OrderId {
+					purchases[n].FundingTxid = o.FundingTxid
+				}
+			}
+		}
 		unread, err := i.node.Datastore.Chat().GetUnreadCount(p.OrderId)
 		if err != nil {
 			continue
 		}
-		p.UnreadChatMessages = unread
+		purchases[n].UnreadChatMessages = unread
 	}
 	ret, err := json.MarshalIndent(purchases, "", "    ")
 	if err != nil {
@@ -2395,20 +3687,37 @@ func (i *jsonAPIHandler) GETPurchases(w http.ResponseWriter, r *http.Request) {
 }
 
 func (i *jsonAPIHandler) GETSales(w http.ResponseWriter, r *http.Request) {
-	limit := r.URL.Query().Get("limit")
-	if limit == "" {
-		limit = "-1"
-	}
-	l, err := strconv.Atoi(limit)
-	if err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
-	}
+	var sales []repo.Sale
 	offsetId := r.URL.Query().Get("offsetId")
-	sales, err := i.node.Datastore.Sales().GetAll(offsetId, l)
-	if err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
+	if offsetId != "" {
+		// Paging by order ID is kept for older clients and ignores the filters
+		limit := r.URL.Query().Get("limit")
+		if limit == "" {
+			limit = "-1"
+		}
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		sales, err = i.node.Datastore.Sales().GetAll(offsetId, l)
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	} else {
+		query, err := parseOrderQuery(r)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		var total int
+		sales, total, err = i.node.Datastore.Sales().Query(query)
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("X-Total-Count", strconv.Itoa(total))
 	}
 	for _, s := range sales {
 		unread, err := i.node.Datastore.Chat().GetUnreadCount(s.OrderId)
@@ -2526,13 +3835,17 @@ func (i *jsonAPIHandler) DELETEBlockNode(w http.ResponseWriter, r *http.Request)
 }
 
 func (i *jsonAPIHandler) POSTBumpFee(w http.ResponseWriter, r *http.Request) {
+	wal, ok := i.requestWallet(w, r)
+	if !ok {
+		return
+	}
 	_, txid := path.Split(r.URL.Path)
 	txHash, err := chainhash.NewHashFromStr(txid)
 	if err != nil {
 		ErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	newTxid, err := i.node.Wallet.BumpFee(*txHash)
+	newTxid, err := wal.BumpFee(*txHash)
 	if err != nil {
 		ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
@@ -2560,3 +3873,359 @@ func (i *jsonAPIHandler) POSTBumpFee(w http.ResponseWriter, r *http.Request) {
 	}
 	SanitizedResponse(w, fmt.Sprintf(`{"txid": "%s"}`, newTxid.String()))
 }
+
+func (i *jsonAPIHandler) GETSearch(w http.ResponseWriter, r *http.Request) {
+	terms := repo.SearchTerms(r.URL.Query().Get("q"))
+	if len(terms) == 0 {
+		ErrorResponse(w, http.StatusBadRequest, "A search query must be specified with q")
+		return
+	}
+	query := repo.SearchQuery{Terms: terms}
+	if types := r.URL.Query().Get("type"); types != "" {
+		for _, t := range strings.Split(types, ",") {
+			switch t {
+			case repo.SearchTypeListing, repo.SearchTypePurchase, repo.SearchTypeSale, repo.SearchTypeChat:
+				query.Types = append(query.Types, t)
+			default:
+				ErrorResponse(w, http.StatusBadRequest, "Unknown search type "+t)
+				return
+			}
+		}
+	}
+	var err error
+	query.States, err = parseOrderStates(r.URL.Query().Get("state"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	query.From, err = parseTimeParam(r.URL.Query().Get("from"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	query.To, err = parseTimeParam(r.URL.Query().Get("to"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	offset := r.URL.Query().Get("offset")
+	if offset == "" {
+		offset = "0"
+	}
+	query.Offset, err = strconv.Atoi(offset)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit := r.URL.Query().Get("limit")
+	if limit == "" {
+		limit = "-1"
+	}
+	query.Limit, err = strconv.Atoi(limit)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, total, err := i.node.Datastore.Search().Query(query)
+	if err == repo.ErrSearchUnavailable {
+		ErrorResponse(w, http.StatusNotImplemented, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if results == nil {
+		results = []repo.SearchResult{}
+	}
+	type searchResponse struct {
+		Total   int                 `json:"total"`
+		Results []repo.SearchResult `json:"results"`
+	}
+	ret, err := json.MarshalIndent(searchResponse{total, results}, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+// Times in query parameters are given either in RFC 3339 format or as a unix timestamp
+func parseTimeParam(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if seconds, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid time %s, use RFC 3339 or a unix timestamp", s)
+	}
+	return t, nil
+}
+
+// Parse a comma separated list of order state names
+func parseOrderStates(s string) ([]pb.OrderState, error) {
+	var states []pb.OrderState
+	if s == "" {
+		return states, nil
+	}
+	for _, name := range strings.Split(s, ",") {
+		state, ok := pb.OrderState_value[strings.ToUpper(name)]
+		if !ok {
+			return nil, fmt.Errorf("Unknown order state %s", name)
+		}
+		states = append(states, pb.OrderState(state))
+	}
+	return states, nil
+}
+
+/* Parse the filters and sorting for /ob/purchases and /ob/sales. The funded and read
+   filters take true or false. Results are sorted by timestamp or total with sortBy,
+   in descending order unless order=asc is given. */
+func parseOrderQuery(r *http.Request) (repo.OrderQuery, error) {
+	var query repo.OrderQuery
+	var err error
+	params := r.URL.Query()
+	query.States, err = parseOrderStates(params.Get("state"))
+	if err != nil {
+		return query, err
+	}
+	for _, param := range []struct {
+		name  string
+		value **bool
+	}{{"funded", &query.Funded}, {"read", &query.Read}} {
+		if params.Get(param.name) == "" {
+			continue
+		}
+		b, err := strconv.ParseBool(params.Get(param.name))
+		if err != nil {
+			return query, fmt.Errorf("%s must be true or false", param.name)
+		}
+		*param.value = &b
+	}
+	query.From, err = parseTimeParam(params.Get("from"))
+	if err != nil {
+		return query, err
+	}
+	query.To, err = parseTimeParam(params.Get("to"))
+	if err != nil {
+		return query, err
+	}
+	switch params.Get("sortBy") {
+	case "":
+	case repo.OrderSortTimestamp, repo.OrderSortTotal:
+		query.SortBy = params.Get("sortBy")
+	default:
+		return query, fmt.Errorf("Orders can only be sorted by %s or %s", repo.OrderSortTimestamp, repo.OrderSortTotal)
+	}
+	switch params.Get("order") {
+	case "", "desc":
+	case "asc":
+		query.Ascending = true
+	default:
+		return query, fmt.Errorf("order must be asc or desc")
+	}
+	offset := params.Get("offset")
+	if offset == "" {
+		offset = "0"
+	}
+	query.Offset, err = strconv.Atoi(offset)
+	if err != nil {
+		return query, err
+	}
+	limit := params.Get("limit")
+	if limit == "" {
+		limit = "-1"
+	}
+	query.Limit, err = strconv.Atoi(limit)
+	if err != nil {
+		return query, err
+	}
+	return query, nil
+}
+
+func (i *jsonAPIHandler) GETExportSales(w http.ResponseWriter, r *http.Request) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+	rows, err := i.node.ExportSales()
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeOrderExport(w, format, "sales", rows)
+}
+
+func (i *jsonAPIHandler) GETExportPurchases(w http.ResponseWriter, r *http.Request) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+	rows, err := i.node.ExportPurchases()
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeOrderExport(w, format, "purchases", rows)
+}
+
+func (i *jsonAPIHandler) GETExportWallet(w http.ResponseWriter, r *http.Request) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+	wal, ok := i.requestWallet(w, r)
+	if !ok {
+		return
+	}
+	rows, err := i.node.ExportTransactions(wal)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	header := []string{"txid", "timestamp", "value", "fee", "height", "address", "memo", "orderId", "currency", "exchangeRate", "fiatValue"}
+	writeExport(w, format, "transactions", header, len(rows), func(n int) (interface{}, []string) {
+		row := rows[n]
+		return row, []string{
+			row.Txid,
+			row.Timestamp.Format(time.RFC3339),
+			strconv.FormatInt(row.Value, 10),
+			formatExportFee(row.Fee),
+			strconv.Itoa(int(row.Height)),
+			row.Address,
+			row.Memo,
+			row.OrderId,
+			row.Currency,
+			formatExportRate(row.Currency, row.ExchangeRate),
+			formatExportFiat(row.Currency, row.FiatValue),
+		}
+	})
+}
+
+// Exports are written as CSV unless format=json is given
+func exportFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	switch format {
+	case "":
+		return "csv", true
+	case "csv", "json":
+		return format, true
+	}
+	ErrorResponse(w, http.StatusBadRequest, "Export format must be csv or json")
+	return "", false
+}
+
+func writeOrderExport(w http.ResponseWriter, format, name string, rows []core.OrderExportRow) {
+	header := []string{"orderId", "timestamp", "title", "peerId", "handle", "state", "total", "funded", "txids", "memo", "fee", "currency", "exchangeRate", "fiatValue", "orderExchangeRates"}
+	writeExport(w, format, name, header, len(rows), func(n int) (interface{}, []string) {
+		row := rows[n]
+		return row, []string{
+			row.OrderId,
+			row.Timestamp.Format(time.RFC3339),
+			row.Title,
+			row.PeerId,
+			row.Handle,
+			row.State,
+			strconv.FormatUint(row.Total, 10),
+			strconv.FormatBool(row.Funded),
+			strings.Join(row.Txids, " "),
+			row.Memo,
+			formatExportFee(row.Fee),
+			row.Currency,
+			formatExportRate(row.Currency, row.ExchangeRate),
+			formatExportFiat(row.Currency, row.FiatValue),
+			formatExportRates(row.OrderExchangeRates),
+		}
+	})
+}
+
+/* Stream the rows of an export as they are formatted rather than building the whole
+   response in memory. row returns the value to encode as JSON and the CSV record for
+   the nth row. */
+func writeExport(w http.ResponseWriter, format, name string, header []string, count int, row func(n int) (interface{}, []string)) {
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", "attachment; filename="+name+".json")
+		fmt.Fprint(w, "[")
+		for n := 0; n < count; n++ {
+			value, _ := row(n)
+			out, err := json.Marshal(value)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			out, err = SanitizeJSON(out)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			if n > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprint(w, "\n    ", string(out))
+		}
+		fmt.Fprint(w, "\n]")
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", "attachment; filename="+name+".csv")
+	writer := csv.NewWriter(w)
+	writer.Write(header)
+	for n := 0; n < count; n++ {
+		_, record := row(n)
+		for i, cell := range record {
+			record[i] = escapeCSVCell(cell)
+		}
+		writer.Write(record)
+	}
+	writer.Flush()
+}
+
+/* Spreadsheets evaluate cells starting with =, +, - or @ as formulas so a memo or title
+   could run one when the export is opened. Those cells are prefixed with a quote unless
+   they are just a negative number. */
+func escapeCSVCell(cell string) string {
+	if cell == "" || !strings.ContainsAny(cell[:1], "=+-@") {
+		return cell
+	}
+	if _, err := strconv.ParseFloat(cell, 64); err == nil && cell[0] == '-' {
+		return cell
+	}
+	return "'" + cell
+}
+
+// An unknown fee is left blank rather than reported as zero
+func formatExportFee(fee *int64) string {
+	if fee == nil {
+		return ""
+	}
+	return strconv.FormatInt(*fee, 10)
+}
+
+// Transactions seen before rates were recorded have no currency and their value is left blank
+func formatExportRate(currency string, rate float64) string {
+	if currency == "" {
+		return ""
+	}
+	return strconv.FormatFloat(rate, 'f', -1, 64)
+}
+
+// Format the rates an order was priced at as USD=2500.5 EUR=2100 sorted by currency
+func formatExportRates(rates map[string]float64) string {
+	var formatted []string
+	for currency, rate := range rates {
+		formatted = append(formatted, currency+"="+strconv.FormatFloat(rate, 'f', -1, 64))
+	}
+	sort.Strings(formatted)
+	return strings.Join(formatted, " ")
+}
+
+func formatExportFiat(currency string, value float64) string {
+	if currency == "" {
+		return ""
+	}
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
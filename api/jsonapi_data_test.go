@@ -57,6 +57,20 @@ const settingsJSON = `{
         "password": "letmein",
         "senderEmail": "notifications@urbanart.com",
         "recipientEmail": "Dave@gmail.com"
+    },
+    "orderRules": {
+        "*": {
+            "autoConfirmMax": 100000,
+            "autoFulfill": false,
+            "rejectOutsideShippingRegions": true
+        },
+        "ebook": {
+            "autoConfirmMax": 0,
+            "autoFulfill": true,
+            "deliveryUrl": "https://urbanart.com/ebook",
+            "deliveryPassword": "letmein",
+            "rejectOutsideShippingRegions": false
+        }
     }
 }`
 
@@ -91,6 +105,20 @@ const settingsUpdateJSON = `{
         "password": "letmein",
         "senderEmail": "notifications@urbanart.com",
         "recipientEmail": "Dave@gmail.com"
+    },
+    "orderRules": {
+        "*": {
+            "autoConfirmMax": 100000,
+            "autoFulfill": false,
+            "rejectOutsideShippingRegions": true
+        },
+        "ebook": {
+            "autoConfirmMax": 0,
+            "autoFulfill": true,
+            "deliveryUrl": "https://urbanart.com/ebook",
+            "deliveryPassword": "letmein",
+            "rejectOutsideShippingRegions": false
+        }
     }
 }`
 
@@ -135,6 +163,20 @@ const settingsPatchedJSON = `{
         "password": "letmein",
         "senderEmail": "notifications@urbanart.com",
         "recipientEmail": "Dave@gmail.com"
+    },
+    "orderRules": {
+        "*": {
+            "autoConfirmMax": 100000,
+            "autoFulfill": false,
+            "rejectOutsideShippingRegions": true
+        },
+        "ebook": {
+            "autoConfirmMax": 0,
+            "autoFulfill": true,
+            "deliveryUrl": "https://urbanart.com/ebook",
+            "deliveryPassword": "letmein",
+            "rejectOutsideShippingRegions": false
+        }
     }
 }`
 
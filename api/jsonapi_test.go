@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"net/http"
 	"os"
 	"testing"
@@ -178,9 +179,249 @@ func TestListings(t *testing.T) {
 	})
 }
 
+func TestBids(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"GET", "/ob/bids", "", 200, "[]"},
+		{"GET", "/ob/bids/test-auction", "", 200, "[]"},
+	})
+}
+
+func TestOrderQueries(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"GET", "/ob/purchases?state=PENDING,FULFILLED&funded=true&sortBy=total&order=asc", "", 200, "[]"},
+		{"GET", "/ob/sales?read=false&from=2017-01-01T00:00:00Z&to=1500000000&offset=10&limit=5", "", 200, "[]"},
+		{"GET", "/ob/sales?state=UNKNOWN", "", 400, anyResponseJSON},
+		{"GET", "/ob/purchases?sortBy=title", "", 400, anyResponseJSON},
+		{"GET", "/ob/purchases?funded=maybe", "", 400, anyResponseJSON},
+	})
+}
+
+func TestExports(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"GET", "/ob/export/sales?format=json", "", 200, "[]"},
+		{"GET", "/ob/export/purchases?format=json", "", 200, "[]"},
+		{"GET", "/ob/export/purchases?format=xml", "", 400, anyResponseJSON},
+		{"GET", "/wallet/export?format=json", "", 200, anyResponseJSON},
+	})
+}
+
+func TestEscapeCSVCell(t *testing.T) {
+	cells := map[string]string{
+		"":                  "",
+		"Book":              "Book",
+		"-1500":             "-1500",
+		"=HYPERLINK(\"x\")": "'=HYPERLINK(\"x\")",
+		"+1+1":              "'+1+1",
+		"-1+1":              "'-1+1",
+		"@SUM(A1)":          "'@SUM(A1)",
+	}
+	for cell, expected := range cells {
+		if escaped := escapeCSVCell(cell); escaped != expected {
+			t.Errorf("Escaped %q to %q, expected %q", cell, escaped, expected)
+		}
+	}
+}
+
+func TestExchangeRateHistory(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"GET", "/ob/exchangerate/history", "", 400, anyResponseJSON},
+		{"GET", "/ob/exchangerate/history?currency=USD&from=yesterday", "", 400, anyResponseJSON},
+		{"GET", "/ob/exchangerate/history?currency=USD&from=0", "", 200, "[]"},
+	})
+}
+
+func TestAPITokens(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"GET", "/ob/apitokens", "", 200, "[]"},
+		{"POST", "/ob/apitokens", `{"name": "tool", "scopes": ["everything"]}`, 400, anyResponseJSON},
+		{"POST", "/ob/apitokens", `{"name": "tool", "scopes": ["orders"]}`, 200, anyResponseJSON},
+		{"POST", "/ob/apitokens", `{"name": "tool", "scopes": ["orders"]}`, 409, anyResponseJSON},
+		{"DELETE", "/ob/apitokens/tool", "", 200, "{}"},
+		{"DELETE", "/ob/apitokens/tool", "", 404, anyResponseJSON},
+		{"POST", "/ob/apitokens", `{"name": "fulfillment", "scopes": ["orders"]}`, 200, anyResponseJSON},
+	})
+
+	// Create a token and check what it can reach
+	req, err := buildRequest("POST", "/ob/apitokens", `{"name": "scoped", "scopes": ["orders"]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := testHTTPClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var created struct {
+		Token string `json:"token"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&created)
+	resp.Body.Close()
+	if err != nil || created.Token == "" {
+		t.Fatal("No token returned", err)
+	}
+	for _, c := range []struct {
+		method, path, token string
+		allowed             bool
+	}{
+		{"GET", "/ob/sales", created.Token, true},
+		{"GET", "/wallet/mnemonic", created.Token, false},
+		{"POST", "/wallet/spend", created.Token, false},
+		{"GET", "/ob/apitokens", created.Token, false},
+		{"GET", "/ob/sales", "wrong", false},
+	} {
+		req, err := http.NewRequest(c.method, testURIRoot+c.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		resp, err := testHTTPClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if (resp.StatusCode != http.StatusForbidden) != c.allowed {
+			t.Errorf("%s %s returned %d", c.method, c.path, resp.StatusCode)
+		}
+	}
+}
+
+func TestWebhooks(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"GET", "/ob/webhooks", "", 200, "[]"},
+		{"POST", "/ob/webhooks", `{"url": "ftp://example.com"}`, 400, anyResponseJSON},
+		{"POST", "/ob/webhooks", `{"url": "https://example.com/hook", "events": ["everything"]}`, 400, anyResponseJSON},
+		{"POST", "/ob/webhooks", `{"url": "https://example.com/hook", "events": ["order"]}`, 200, anyResponseJSON},
+		{"GET", "/ob/webhooks/deliveries?status=unknown", "", 400, anyResponseJSON},
+		{"GET", "/ob/webhooks/deliveries?status=failed", "", 200, "[]"},
+		{"DELETE", "/ob/webhooks/unknown", "", 404, anyResponseJSON},
+	})
+}
+
+func TestTags(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"GET", "/ob/tags/", "", 400, anyResponseJSON},
+		{"GET", "/ob/tags/%20", "", 400, anyResponseJSON},
+	})
+}
+
+func TestChannels(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"GET", "/ob/channel/", "", 400, anyResponseJSON},
+		{"GET", "/ob/channel/shirts?limit=few", "", 400, anyResponseJSON},
+		{"POST", "/ob/channel", `{"channel": "", "text": "New stock"}`, 400, anyResponseJSON},
+		{"POST", "/ob/channel", `{"channel": "shirts"}`, 400, anyResponseJSON},
+		{"POST", "/ob/channel", `{"channel": "shirts", "listingHash": "nothash"}`, 400, anyResponseJSON},
+	})
+}
+
+func TestOutbox(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"GET", "/ob/outbox", "", 200, "[]"},
+	})
+}
+
+func TestOutgoingMessages(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"GET", "/ob/outgoingmessages", "", 200, "[]"},
+		{"GET", "/ob/outgoingmessages?reference=QmNoSuchMessage", "", 200, "[]"},
+		{"GET", "/ob/outgoingmessages?state=lost", "", 400, anyResponseJSON},
+		{"GET", "/ob/outgoingmessages/0123456789abcdef", "", 404, anyResponseJSON},
+	})
+}
+
+func TestFetchMessages(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"POST", "/ob/fetchmessages", "", 500, anyResponseJSON},
+	})
+}
+
+func TestChatAttachments(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"GET", "/ob/chatattachment/QmNoSuchFile", "", 404, anyResponseJSON},
+		{"POST", "/ob/chat", `{"peerId": "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG", "attachments": [{"filename": "a.txt", "data": "not base64!"}]}`, 400, anyResponseJSON},
+		{"POST", "/ob/chat", `{"peerId": "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG", "attachments": [{"filename": "a.txt", "data": ""}]}`, 400, anyResponseJSON},
+		{"POST", "/ob/chat", `{"peerId": "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG", "attachments": [{}, {}, {}, {}, {}, {}]}`, 400, anyResponseJSON},
+	})
+}
+
+func TestEstimateTotal(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"POST", "/ob/estimatetotal", `{"items": []}`, 400, anyResponseJSON},
+		{"POST", "/ob/estimatetotal", `{"items": [`, 400, anyResponseJSON},
+	})
+}
+
+func TestQuote(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"POST", "/ob/quote", `{"items": []}`, 400, anyResponseJSON},
+		{"POST", "/ob/quote", `{"items": [`, 400, anyResponseJSON},
+	})
+}
+
+func TestCart(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"GET", "/ob/cart", "", 200, "[]"},
+		{"POST", "/ob/cart", `{"quantity": 1}`, 400, anyResponseJSON},
+		{"POST", "/ob/cart/checkout", `{}`, 400, anyResponseJSON},
+		{"PUT", "/ob/cart", `{"id": "nosuch", "quantity": 1}`, 404, anyResponseJSON},
+		{"DELETE", "/ob/cart/nosuch", "", 404, anyResponseJSON},
+		{"GET", "/ob/checkoutgroup/nosuch", "", 404, anyResponseJSON},
+		{"POST", "/ob/fundcheckout", `{"checkoutGroup": "nosuch"}`, 404, anyResponseJSON},
+	})
+}
+
+func TestDigitalGoods(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"GET", "/ob/digitalassets", "", 200, "[]"},
+		{"POST", "/ob/digitalasset", `{"slug": "no-such-listing", "filename": "book.pdf", "data": "aGVsbG8="}`, 404, anyResponseJSON},
+		{"POST", "/ob/digitalasset", `{"slug": "no-such-listing", "filename": "book.pdf", "data": "not base64"}`, 400, anyResponseJSON},
+		{"DELETE", "/ob/digitalasset/no-such-listing", "", 404, anyResponseJSON},
+		{"GET", "/ob/licensekeys", "", 200, "{}"},
+		{"GET", "/ob/licensekeys/no-such-listing", "", 200, "[]"},
+		{"POST", "/ob/licensekeys", `{"slug": "no-such-listing", "keys": ["AAAA-BBBB"]}`, 404, anyResponseJSON},
+		{"GET", "/ob/digitaldelivery/QmNoSuchOrder", "", 404, anyResponseJSON},
+		{"GET", "/ob/digitaldeliveryfile/QmNoSuchOrder/ebook", "", 404, anyResponseJSON},
+	})
+}
+
+func TestOrderRules(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"GET", "/ob/orderhistory/QmNoSuchOrder", "", 200, "[]"},
+		{"POST", "/ob/settings", `{"orderRules": {"ebook": {"autoFulfill": true, "deliveryUrl": "not a url"}}}`, 400, anyResponseJSON},
+		{"POST", "/ob/settings", `{"storeModerators": [], "orderRules": {"*": {"autoConfirmMax": 100000, "rejectOutsideShippingRegions": true}}}`, 200, "{}"},
+		{"PATCH", "/ob/settings", `{"orderRules": {"ebook": {"autoFulfill": true, "deliveryUrl": "https://example.com/ebook"}}}`, 200, "{}"},
+	})
+}
+
+func TestGroupChat(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"GET", "/ob/groupchats", "", 200, "[]"},
+		{"GET", "/ob/groupchat/QmNoSuchGroup", "", 404, anyResponseJSON},
+		{"POST", "/ob/groupchat", `{"subject": "dispute", "members": []}`, 400, anyResponseJSON},
+		{"POST", "/ob/groupchat", `{"subject": "dispute", "members": ["not a peer"]}`, 400, anyResponseJSON},
+		{"PUT", "/ob/groupchat", `{"groupId": "QmNoSuchGroup", "members": ["QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG"]}`, 404, anyResponseJSON},
+		{"POST", "/ob/groupchatmessage", `{"groupId": "QmNoSuchGroup", "message": ""}`, 400, anyResponseJSON},
+		{"POST", "/ob/groupchatmessage", `{"groupId": "QmNoSuchGroup", "message": "hello"}`, 404, anyResponseJSON},
+		{"DELETE", "/ob/groupchat/QmNoSuchGroup", "", 404, anyResponseJSON},
+	})
+}
+
+func TestSearch(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"GET", "/ob/search", "", 400, anyResponseJSON},
+		{"GET", "/ob/search?q=shirt&state=NOT_A_STATE", "", 400, anyResponseJSON},
+		{"GET", "/ob/search?q=shirt&type=listing,chat", "", 200, `{"total": 0, "results": []}`},
+	})
+}
+
+func TestCampaign(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"GET", "/ob/campaign/test-campaign", "", 404, NotFoundJSON("Campaign")},
+	})
+}
+
 func TestStatus(t *testing.T) {
 	runAPITests(t, apiTests{
-		{"GET", "/ob/status", "", 400, anyResponseJSON},
+		{"GET", "/ob/status", "", 200, `{"retriever": null}`},
 		{"GET", "/ob/status/QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG", "", 200, anyResponseJSON},
 	})
 }
@@ -190,6 +431,8 @@ func TestWallet(t *testing.T) {
 		{"GET", "/wallet/address", "", 200, walletAddressJSONResponse},
 		{"GET", "/wallet/balance", "", 200, walletBalanceJSONResponse},
 		{"GET", "/wallet/mnemonic", "", 200, walletMneumonicJSONResponse},
+		{"GET", "/wallet/currencies", "", 200, `["tbtc"]`},
+		{"GET", "/wallet/balance?coin=ltc", "", 400, anyResponseJSON},
 		{"POST", "/wallet/spend", spendJSON, 500, insuffientFundsJSON},
 		// TODO: Test successful spend on regnet with coins
 	})
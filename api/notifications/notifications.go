@@ -38,6 +38,18 @@ type messageTypingWrapper struct {
 	MessageRead interface{} `json:"messageTyping"`
 }
 
+type messageDeliveryWrapper struct {
+	MessageDelivery interface{} `json:"messageDelivery"`
+}
+
+type groupMessageWrapper struct {
+	GroupMessage interface{} `json:"groupMessage"`
+}
+
+type groupUpdateWrapper struct {
+	GroupUpdate interface{} `json:"groupUpdate"`
+}
+
 type orderWrapper struct {
 	OrderNotification `json:"order"`
 }
@@ -78,6 +90,14 @@ type disputeCloseWrapper struct {
 	DisputeCloseNotification `json:"disputeClose"`
 }
 
+type bidWrapper struct {
+	BidNotification `json:"bid"`
+}
+
+type auctionWonWrapper struct {
+	AuctionWonNotification `json:"auctionWon"`
+}
+
 type OrderNotification struct {
 	Title             string `json:"title"`
 	BuyerGuid         string `json:"buyerGuid"`
@@ -124,6 +144,22 @@ type DisputeCloseNotification struct {
 	OrderId string `json:"orderId"`
 }
 
+type BidNotification struct {
+	Title             string `json:"title"`
+	Slug              string `json:"slug"`
+	BuyerGuid         string `json:"buyerGuid"`
+	BuyerBlockchainId string `json:"buyerBlockchainId"`
+	Amount            uint64 `json:"amount"`
+	OrderId           string `json:"orderId"`
+}
+
+type AuctionWonNotification struct {
+	Title          string `json:"title"`
+	OrderId        string `json:"orderId"`
+	PaymentAddress string `json:"paymentAddress"`
+	PaymentAmount  uint64 `json:"paymentAmount"`
+}
+
 type FollowNotification struct {
 	Follow string `json:"follow"`
 }
@@ -145,11 +181,21 @@ type StatusNotification struct {
 }
 
 type ChatMessage struct {
-	MessageId string    `json:"messageId"`
-	PeerId    string    `json:"peerId"`
-	Subject   string    `json:"subject"`
-	Message   string    `json:"message"`
-	Timestamp time.Time `json:"timestamp"`
+	MessageId   string           `json:"messageId"`
+	PeerId      string           `json:"peerId"`
+	Subject     string           `json:"subject"`
+	Message     string           `json:"message"`
+	Timestamp   time.Time        `json:"timestamp"`
+	Attachments []ChatAttachment `json:"attachments,omitempty"`
+}
+
+// An attachment can be fetched from /ob/chatattachment by its id
+type ChatAttachment struct {
+	Id        string `json:"id"`
+	Filename  string `json:"filename"`
+	MediaType string `json:"mediaType"`
+	Size      int64  `json:"size"`
+	Thumbnail string `json:"thumbnail,omitempty"`
 }
 
 type ChatRead struct {
@@ -163,6 +209,31 @@ type ChatTyping struct {
 	Subject string `json:"subject"`
 }
 
+// Sent when a message we sent is delivered directly on a retry or its offline copy is acked
+type MessageDelivery struct {
+	Id        string `json:"id"`
+	PeerId    string `json:"peerId"`
+	Reference string `json:"reference"`
+	State     string `json:"state"`
+}
+
+type GroupChatMessage struct {
+	MessageId string    `json:"messageId"`
+	GroupId   string    `json:"groupId"`
+	PeerId    string    `json:"peerId"`
+	Subject   string    `json:"subject"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Sent when we're added to a group or its creator changes its subject or members
+type GroupChatUpdate struct {
+	GroupId string   `json:"groupId"`
+	Creator string   `json:"creator"`
+	Subject string   `json:"subject"`
+	Members []string `json:"members"`
+}
+
 func Serialize(i interface{}) []byte {
 	var n notificationWrapper
 	switch i.(type) {
@@ -226,6 +297,18 @@ func Serialize(i interface{}) []byte {
 				DisputeCloseNotification: i.(DisputeCloseNotification),
 			},
 		}
+	case BidNotification:
+		n = notificationWrapper{
+			bidWrapper{
+				BidNotification: i.(BidNotification),
+			},
+		}
+	case AuctionWonNotification:
+		n = notificationWrapper{
+			auctionWonWrapper{
+				AuctionWonNotification: i.(AuctionWonNotification),
+			},
+		}
 	case FollowNotification:
 		n = notificationWrapper{
 			i.(FollowNotification),
@@ -264,6 +347,24 @@ func Serialize(i interface{}) []byte {
 		}
 		b, _ := json.MarshalIndent(m, "", "    ")
 		return b
+	case MessageDelivery:
+		m := messageDeliveryWrapper{
+			i.(MessageDelivery),
+		}
+		b, _ := json.MarshalIndent(m, "", "    ")
+		return b
+	case GroupChatMessage:
+		m := groupMessageWrapper{
+			i.(GroupChatMessage),
+		}
+		b, _ := json.MarshalIndent(m, "", "    ")
+		return b
+	case GroupChatUpdate:
+		m := groupUpdateWrapper{
+			i.(GroupChatUpdate),
+		}
+		b, _ := json.MarshalIndent(m, "", "    ")
+		return b
 	case []byte:
 		return i.([]byte)
 	}
@@ -350,6 +451,81 @@ func Describe(i interface{}) (string, string) {
 		n := i.(DisputeCloseNotification)
 		form := "Dispute around order \"%s\" was closed."
 		body = fmt.Sprintf(form, n.OrderId)
+
+	case BidNotification:
+		head = "Bid received"
+
+		n := i.(BidNotification)
+		var buyer string
+		if n.BuyerBlockchainId != "" {
+			buyer = n.BuyerBlockchainId
+		} else {
+			buyer = n.BuyerGuid
+		}
+		form := "You received a bid of %d on \"%s\".\n\nBid ID: %s\nBuyer: %s"
+		body = fmt.Sprintf(form, n.Amount, n.Title, n.OrderId, buyer)
+
+	case AuctionWonNotification:
+		head = "Auction won"
+
+		n := i.(AuctionWonNotification)
+		form := "You won the auction for \"%s\". Please fund order \"%s\" by sending %d to %s."
+		body = fmt.Sprintf(form, n.Title, n.OrderId, n.PaymentAmount, n.PaymentAddress)
 	}
 	return head, body
 }
+
+// The events which can be sent to webhooks
+var Events = []string{
+	"order", "payment", "orderConfirmation", "orderCancel", "refund", "orderFulfillment",
+	"orderCompletion", "disputeOpen", "disputeUpdate", "disputeClose", "bid", "auctionWon",
+	"follow", "unfollow", "moderatorAdd", "moderatorRemove", "chat",
+	"groupChat", "groupUpdate",
+}
+
+/* Return the name of the event a notification is sent to webhooks as. Status updates,
+   read and delivery receipts and typing indicators only matter to a connected client
+   so they have none. */
+func Event(i interface{}) string {
+	switch i.(type) {
+	case OrderNotification:
+		return "order"
+	case PaymentNotification:
+		return "payment"
+	case OrderConfirmationNotification:
+		return "orderConfirmation"
+	case OrderCancelNotification:
+		return "orderCancel"
+	case RefundNotification:
+		return "refund"
+	case FulfillmentNotification:
+		return "orderFulfillment"
+	case CompletionNotification:
+		return "orderCompletion"
+	case DisputeOpenNotification:
+		return "disputeOpen"
+	case DisputeUpdateNotification:
+		return "disputeUpdate"
+	case DisputeCloseNotification:
+		return "disputeClose"
+	case BidNotification:
+		return "bid"
+	case AuctionWonNotification:
+		return "auctionWon"
+	case FollowNotification:
+		return "follow"
+	case UnfollowNotification:
+		return "unfollow"
+	case ModeratorAddNotification:
+		return "moderatorAdd"
+	case ModeratorRemoveNotification:
+		return "moderatorRemove"
+	case ChatMessage:
+		return "chat"
+	case GroupChatMessage:
+		return "groupChat"
+	case GroupChatUpdate:
+		return "groupUpdate"
+	}
+	return ""
+}
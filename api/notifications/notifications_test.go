@@ -10,3 +10,23 @@ func TestSerialization(t *testing.T) {
 		t.Error("Incorrect serialization")
 	}
 }
+
+func TestEvent(t *testing.T) {
+	if Event(PaymentNotification{OrderId: "abc"}) != "payment" {
+		t.Error("Incorrect event for a payment")
+	}
+	if Event(ChatMessage{Message: "hi"}) != "chat" {
+		t.Error("Incorrect event for a chat message")
+	}
+	if Event(GroupChatMessage{Message: "hi"}) != "groupChat" {
+		t.Error("Incorrect event for a group chat message")
+	}
+	if Event(StatusNotification{"publishing"}) != "" || Event(ChatTyping{}) != "" {
+		t.Error("Client only notifications should have no event")
+	}
+	for _, e := range Events {
+		if e == "" {
+			t.Error("Empty event name")
+		}
+	}
+}
@@ -16,11 +16,16 @@ import (
 // which is listened by websocket API, while adding specific handling for
 // each received object.
 type notificationManager struct {
-	node *core.OpenBazaarNode
+	node     *core.OpenBazaarNode
+	webhooks *webhookDeliverer
 }
 
 func manageNotifications(node *core.OpenBazaarNode, out chan []byte) chan interface{} {
-	manager := &notificationManager{node: node}
+	manager := &notificationManager{
+		node:     node,
+		webhooks: newWebhookDeliverer(node.Datastore.Webhooks(), node.Datastore.WebhookDeliveries(), node.TorDialer),
+	}
+	go manager.webhooks.run()
 	nodeBroadcast := make(chan interface{})
 	go func() {
 		for {
@@ -53,18 +58,26 @@ func (m *notificationManager) sendNotification(n interface{}) {
 	}
 }
 
-// Create list of notifiers based on settings data (currently only SMTP is available)
-// TODO: should be extended to include new notifiers in the list
+// Create list of notifiers based on the configured webhooks and settings data
 func (m *notificationManager) getNotifiers() []notifier {
-	settings, err := m.node.Datastore.Settings().Get()
 	notifiers := make([]notifier, 0)
+
+	// Webhook notifier
+	hooks, err := m.node.Datastore.Webhooks().GetAll()
+	if err != nil {
+		log.Error(err)
+	} else if len(hooks) > 0 {
+		notifiers = append(notifiers, &webhookNotifier{hooks: hooks, deliverer: m.webhooks})
+	}
+
+	settings, err := m.node.Datastore.Settings().Get()
 	if err != nil {
 		return notifiers
 	}
 
 	// SMTP notifier
 	conf := settings.SMTPSettings
-	if conf.Notifications {
+	if conf != nil && conf.Notifications {
 		notifiers = append(notifiers, &smtpNotifier{settings: conf})
 	}
 	return notifiers
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+// Paths which only an admin token may use whatever the method
+var adminPaths = []string{
+	"/wallet/mnemonic",
+	"/ob/apitokens",
+	"/ob/webhooks",
+	"/ob/settings",
+	"/ob/digitalasset",
+	"/ob/licensekeys",
+	"/ob/shutdown",
+}
+
+// Paths which change the state of an order
+var orderPaths = []string{
+	"/ob/purchase",
+	"/ob/estimatetotal",
+	"/ob/quote",
+	"/ob/cart",
+	"/ob/bid",
+	"/ob/orderconfirmation",
+	"/ob/ordercancel",
+	"/ob/orderfulfillment",
+	"/ob/ordercompletion",
+	"/ob/refund",
+	"/ob/opendispute",
+	"/ob/closedispute",
+	"/ob/releasefunds",
+}
+
+// Return the scope an API token needs to make a request
+func requiredScope(method, path string) string {
+	for _, p := range adminPaths {
+		if strings.HasPrefix(path, p) {
+			return repo.APIScopeAdmin
+		}
+	}
+	if method == "GET" || method == "OPTIONS" {
+		return repo.APIScopeReadOnly
+	}
+	if strings.HasPrefix(path, "/wallet/") {
+		return repo.APIScopeWallet
+	}
+	for _, p := range orderPaths {
+		if strings.HasPrefix(path, p) {
+			return repo.APIScopeOrders
+		}
+	}
+	return repo.APIScopeAdmin
+}
+
+// Admin grants every scope and every scope grants read-only
+func scopesAllow(scopes []string, required string) bool {
+	for _, scope := range scopes {
+		if scope == repo.APIScopeAdmin || scope == required || required == repo.APIScopeReadOnly {
+			return true
+		}
+	}
+	return false
+}
+
+// Return the token from an Authorization: Bearer header
+func bearerToken(r *http.Request) (string, bool) {
+	auth := r.Header.Get("Authorization")
+	if len(auth) < 7 || !strings.EqualFold(auth[:7], "Bearer ") {
+		return "", false
+	}
+	return strings.TrimSpace(auth[7:]), true
+}
+
+// Returns whether the token exists and has the scope needed for the request
+func authorizeToken(tokens repo.APITokens, token, method, path string) bool {
+	apiToken, err := tokens.GetByHash(repo.HashAPIToken(token))
+	if err != nil {
+		return false
+	}
+	return scopesAllow(apiToken.Scopes, requiredScope(method, path))
+}
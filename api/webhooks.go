@@ -0,0 +1,220 @@
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/api/notifications"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"golang.org/x/net/proxy"
+)
+
+const (
+	// A delivery is marked failed after this many attempts
+	webhookMaxAttempts = 8
+
+	// The delay before the first retry. It doubles after each failed attempt.
+	webhookRetryBackoff = 30 * time.Second
+
+	// How often pending deliveries are checked for retries
+	webhookRetryInterval = time.Minute
+)
+
+/* The webhookDeliverer posts deliveries in the background. They're saved before the
+   first attempt so those still pending when the node stops are retried after it
+   restarts. */
+type webhookDeliverer struct {
+	hooks      repo.Webhooks
+	deliveries repo.WebhookDeliveries
+	client     *http.Client
+	wake       chan struct{}
+}
+
+func newWebhookDeliverer(hooks repo.Webhooks, deliveries repo.WebhookDeliveries, dialer proxy.Dialer) *webhookDeliverer {
+	dial := net.Dial
+	if dialer != nil {
+		dial = dialer.Dial
+	}
+	return &webhookDeliverer{
+		hooks:      hooks,
+		deliveries: deliveries,
+		client:     &http.Client{Transport: &http.Transport{Dial: dial}, Timeout: 30 * time.Second},
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+func (d *webhookDeliverer) run() {
+	d.deliverDue()
+	ticker := time.NewTicker(webhookRetryInterval)
+	for {
+		select {
+		case <-ticker.C:
+		case <-d.wake:
+		}
+		d.deliverDue()
+	}
+}
+
+// Ask the deliverer to send any new deliveries now rather than at the next retry interval
+func (d *webhookDeliverer) notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (d *webhookDeliverer) deliverDue() {
+	due, err := d.deliveries.GetDue(time.Now())
+	if err != nil {
+		log.Error("Failed to load webhook deliveries:", err)
+		return
+	}
+	for _, delivery := range due {
+		delivery = d.attempt(delivery)
+		if err := d.deliveries.Update(delivery); err != nil {
+			log.Error("Failed to save webhook delivery:", err)
+		}
+	}
+}
+
+// Post a delivery and return it with the outcome recorded
+func (d *webhookDeliverer) attempt(delivery repo.WebhookDelivery) repo.WebhookDelivery {
+	delivery.Attempts++
+	delivery.ResponseCode = 0
+	delivery.Error = ""
+	hook, err := d.hooks.Get(delivery.WebhookId)
+	if err != nil {
+		delivery.Status = repo.WebhookDeliveryFailed
+		delivery.Error = "The webhook was removed"
+		return delivery
+	}
+	payload := []byte(delivery.Payload)
+	req, err := http.NewRequest("POST", delivery.URL, bytes.NewReader(payload))
+	if err == nil {
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-OpenBazaar-Event", delivery.Event)
+		req.Header.Set("X-OpenBazaar-Delivery", strconv.FormatInt(delivery.Id, 10))
+		req.Header.Set("X-OpenBazaar-Signature", "sha256="+signWebhookPayload(hook.Secret, payload))
+		var resp *http.Response
+		resp, err = d.client.Do(req)
+		if err == nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+			delivery.ResponseCode = resp.StatusCode
+			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+				delivery.Status = repo.WebhookDeliveryDelivered
+				return delivery
+			}
+			err = fmt.Errorf("Webhook responded with %s", resp.Status)
+		}
+	}
+	delivery.Error = err.Error()
+	if delivery.Attempts >= webhookMaxAttempts {
+		delivery.Status = repo.WebhookDeliveryFailed
+	} else {
+		delivery.NextAttempt = time.Now().Add(webhookBackoff(delivery.Attempts))
+	}
+	return delivery
+}
+
+// The delay before retrying a delivery which has failed the given number of times
+func webhookBackoff(attempts int) time.Duration {
+	return webhookRetryBackoff << uint(attempts-1)
+}
+
+// Return the hex encoded HMAC-SHA256 of the payload so receivers can check it came from us
+func signWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+/* Check the URL and events of a new webhook and give it an id. A secret is generated
+   if one isn't given. */
+func newWebhook(rawURL, secret string, events []string) (repo.Webhook, error) {
+	hook := repo.Webhook{URL: rawURL, Secret: secret, Events: []string{}, Created: time.Now()}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return hook, errors.New("A webhook needs an http or https URL")
+	}
+	for _, event := range events {
+		known := false
+		for _, e := range notifications.Events {
+			if e == event {
+				known = true
+			}
+		}
+		if !known {
+			return hook, fmt.Errorf("Unknown event %s, use one of %s", event, strings.Join(notifications.Events, ", "))
+		}
+		hook.Events = append(hook.Events, event)
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return hook, err
+	}
+	hook.Id = hex.EncodeToString(id)
+	if hook.Secret == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return hook, err
+		}
+		hook.Secret = hex.EncodeToString(b)
+	}
+	return hook, nil
+}
+
+type webhookNotifier struct {
+	hooks     []repo.Webhook
+	deliverer *webhookDeliverer
+}
+
+func (notifier *webhookNotifier) notify(n interface{}) error {
+	event := notifications.Event(n)
+	if event == "" {
+		return nil
+	}
+	type webhookPayload struct {
+		Event     string          `json:"event"`
+		Timestamp time.Time       `json:"timestamp"`
+		Data      json.RawMessage `json:"data"`
+	}
+	now := time.Now()
+	payload, err := json.Marshal(webhookPayload{event, now, notifications.Serialize(n)})
+	if err != nil {
+		return err
+	}
+	for _, hook := range notifier.hooks {
+		if !hook.Wants(event) {
+			continue
+		}
+		_, err := notifier.deliverer.deliveries.Put(repo.WebhookDelivery{
+			WebhookId:   hook.Id,
+			URL:         hook.URL,
+			Event:       event,
+			Payload:     string(payload),
+			Status:      repo.WebhookDeliveryPending,
+			NextAttempt: now,
+			Timestamp:   now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	notifier.deliverer.notify()
+	return nil
+}
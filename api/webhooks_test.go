@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/api/notifications"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/OpenBazaar/openbazaar-go/test"
+)
+
+func TestWebhookDelivery(t *testing.T) {
+	repository, err := test.NewRepository()
+	if err != nil {
+		t.Fatal(err)
+	}
+	repository.MustReset()
+	defer repository.MustReset()
+
+	received := make(chan *http.Request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Header.Get("X-OpenBazaar-Signature") != "sha256="+signWebhookPayload("secret", body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var payload struct {
+			Event string          `json:"event"`
+			Data  json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil || payload.Event != "order" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- r
+	}))
+	defer server.Close()
+
+	hooks := repository.DB.Webhooks()
+	deliveries := repository.DB.WebhookDeliveries()
+	orders, err := newWebhook(server.URL, "secret", []string{"order"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chat, err := newWebhook(server.URL, "secret", []string{"chat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, hook := range []repo.Webhook{orders, chat} {
+		if err := hooks.Put(hook); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deliverer := newWebhookDeliverer(hooks, deliveries, nil)
+	notifier := &webhookNotifier{[]repo.Webhook{orders, chat}, deliverer}
+	if err := notifier.notify(notifications.OrderNotification{OrderId: "abc"}); err != nil {
+		t.Fatal(err)
+	}
+	deliverer.deliverDue()
+
+	select {
+	case r := <-received:
+		if r.Header.Get("X-OpenBazaar-Event") != "order" {
+			t.Error("Wrong event header", r.Header.Get("X-OpenBazaar-Event"))
+		}
+	default:
+		t.Fatal("Webhook was not delivered")
+	}
+	// The deliveries table isn't cleared between runs so only those to this run's webhooks are checked
+	delivered, _, err := deliveries.GetAll(repo.WebhookDeliveryDelivered, 0, -1)
+	delivered = deliveriesTo(delivered, orders.Id, chat.Id)
+	if err != nil || len(delivered) != 1 || delivered[0].WebhookId != orders.Id || delivered[0].ResponseCode != 200 {
+		t.Error("Delivery was not recorded", err)
+	}
+	pending, _, err := deliveries.GetAll(repo.WebhookDeliveryPending, 0, -1)
+	if err != nil || len(deliveriesTo(pending, orders.Id, chat.Id)) != 0 {
+		t.Error("Delivered to a webhook not subscribed to the event", err)
+	}
+}
+
+func deliveriesTo(deliveries []repo.WebhookDelivery, hookIds ...string) []repo.WebhookDelivery {
+	var ret []repo.WebhookDelivery
+	for _, d := range deliveries {
+		for _, id := range hookIds {
+			if d.WebhookId == id {
+				ret = append(ret, d)
+			}
+		}
+	}
+	return ret
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	if webhookBackoff(1) != webhookRetryBackoff || webhookBackoff(3) != 4*webhookRetryBackoff {
+		t.Error("Wrong retry backoff")
+	}
+}
@@ -65,6 +65,7 @@ type wsHandler struct {
 	cookie        http.Cookie
 	username      string
 	password      string
+	tokens        repo.APITokens
 }
 
 func newWSAPIHandler(node *core.OpenBazaarNode, ctx commands.Context, authCookie http.Cookie, config repo.APIConfig) (*wsHandler, error) {
@@ -84,16 +85,12 @@ func newWSAPIHandler(node *core.OpenBazaarNode, ctx commands.Context, authCookie
 		cookie:        authCookie,
 		username:      config.Username,
 		password:      config.Password,
+		tokens:        node.Datastore.APITokens(),
 	}
 	return &handler, nil
 }
 
 func (wsh wsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	ws, err := upgrader.Upgrade(w, r, nil)
-	if err != nil {
-		log.Error("Error upgrading to websockets:", err)
-		return
-	}
 	if !wsh.enabled {
 		w.WriteHeader(http.StatusForbidden)
 		fmt.Fprint(w, "403 - Forbidden")
@@ -107,7 +104,18 @@ func (wsh wsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
-	if wsh.authenticated {
+	// Browsers can't set headers on a websocket so the token may also be given as a query parameter
+	token, ok := bearerToken(r)
+	if !ok && r.URL.Query().Get("token") != "" {
+		token, ok = r.URL.Query().Get("token"), true
+	}
+	if ok {
+		if !authorizeToken(wsh.tokens, token, "GET", r.URL.Path) {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, "403 - Forbidden")
+			return
+		}
+	} else if wsh.authenticated {
 		if wsh.username == "" || wsh.password == "" {
 			cookie, err := r.Cookie("OpenBazaar_Auth_Cookie")
 			if err != nil {
@@ -131,6 +139,12 @@ func (wsh wsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
+	// Only upgrade once the request is allowed so the 403 responses above reach the client
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Error("Error upgrading to websockets:", err)
+		return
+	}
 	c := &connection{send: make(chan []byte, 256), ws: ws, h: wsh.h}
 	c.h.register <- c
 	defer func() { c.h.unregister <- c }()
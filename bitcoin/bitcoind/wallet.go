@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
 	"github.com/OpenBazaar/spvwallet"
 	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -239,6 +240,26 @@ func (w *BitcoindWallet) Spend(amount int64, addr btc.Address, feeLevel spvwalle
 	return w.rpcClient.SendFrom(Account, addr, amt)
 }
 
+// Pay several addresses in one transaction
+func (w *BitcoindWallet) SpendMulti(outputs []bitcoin.SpendOutput, feeLevel spvwallet.FeeLevel) (*chainhash.Hash, error) {
+	// Addresses are interfaces so outputs to the same address are combined by their encoding
+	addrs := make(map[string]btc.Address)
+	totals := make(map[string]int64)
+	for _, o := range outputs {
+		addrs[o.Address.EncodeAddress()] = o.Address
+		totals[o.Address.EncodeAddress()] += o.Amount
+	}
+	amounts := make(map[btc.Address]btc.Amount)
+	for encoded, total := range totals {
+		amt, err := btc.NewAmount(float64(total) / 100000000)
+		if err != nil {
+			return nil, err
+		}
+		amounts[addrs[encoded]] = amt
+	}
+	return w.rpcClient.SendMany(Account, amounts)
+}
+
 func (w *BitcoindWallet) BumpFee(txid chainhash.Hash) (*chainhash.Hash, error) {
 	includeWatchOnly := false
 	tx, err := w.rpcClient.GetTransaction(&txid, &includeWatchOnly)
@@ -0,0 +1,213 @@
+package electrum
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang.org/x/net/proxy"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ProtocolVersion = "1.2"
+	RequestTimeout  = time.Second * 30
+)
+
+var ErrClosed = errors.New("Connection to Electrum server closed")
+
+/* Client speaks the Electrum JSON-RPC protocol to a single server. Requests and
+   responses are newline delimited JSON objects. Responses are matched to their
+   request by id and anything without an id is a subscription notification which
+   is passed to the notify callback. */
+type Client struct {
+	conn    net.Conn
+	nextId  uint64
+	pending map[uint64]chan *response
+	notify  func(method string, params json.RawMessage)
+	done    chan struct{}
+	lock    sync.Mutex
+	wlock   sync.Mutex
+}
+
+type request struct {
+	Id     uint64        `json:"id"`
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+}
+
+type response struct {
+	Id     *uint64         `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  json.RawMessage `json:"error"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("Electrum server error %d: %s", e.Code, e.Message)
+}
+
+// Servers are given as host:port with an optional tcp:// or ssl:// scheme. TLS is the default.
+func ParseServer(server string) (address string, useTLS bool) {
+	switch {
+	case strings.HasPrefix(server, "tcp://"):
+		return strings.TrimPrefix(server, "tcp://"), false
+	case strings.HasPrefix(server, "ssl://"):
+		return strings.TrimPrefix(server, "ssl://"), true
+	case strings.HasPrefix(server, "tls://"):
+		return strings.TrimPrefix(server, "tls://"), true
+	}
+	return server, true
+}
+
+// Connect to an Electrum server, optionally through a proxy such as Tor
+func Dial(address string, useTLS bool, dialer proxy.Dialer, notify func(method string, params json.RawMessage)) (*Client, error) {
+	if dialer == nil {
+		dialer = &net.Dialer{Timeout: RequestTimeout}
+	}
+	conn, err := dialer.Dial("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	if useTLS {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: host})
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+	return NewClient(conn, notify), nil
+}
+
+// Wrap an established connection. The client owns the connection from here on.
+func NewClient(conn net.Conn, notify func(method string, params json.RawMessage)) *Client {
+	c := &Client{
+		conn:    conn,
+		pending: make(map[uint64]chan *response),
+		notify:  notify,
+		done:    make(chan struct{}),
+	}
+	go c.readLoop()
+	return c
+}
+
+// Call a method on the server and unmarshal the result into result, which may be nil
+func (c *Client) Call(method string, params []interface{}, result interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	c.lock.Lock()
+	select {
+	case <-c.done:
+		c.lock.Unlock()
+		return ErrClosed
+	default:
+	}
+	id := c.nextId
+	c.nextId++
+	ch := make(chan *response, 1)
+	c.pending[id] = ch
+	c.lock.Unlock()
+
+	defer func() {
+		c.lock.Lock()
+		delete(c.pending, id)
+		c.lock.Unlock()
+	}()
+
+	b, err := json.Marshal(request{Id: id, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	c.wlock.Lock()
+	c.conn.SetWriteDeadline(time.Now().Add(RequestTimeout))
+	_, err = c.conn.Write(append(b, '\n'))
+	c.wlock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	select {
+	case resp := <-ch:
+		if len(resp.Error) > 0 && string(resp.Error) != "null" {
+			return parseError(resp.Error)
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(resp.Result, result)
+	case <-c.done:
+		return ErrClosed
+	case <-time.After(RequestTimeout):
+		return fmt.Errorf("Electrum request %s timed out", method)
+	}
+}
+
+// Done is closed when the connection to the server is lost
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) readLoop() {
+	defer close(c.done)
+	reader := bufio.NewReader(c.conn)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			log.Debugf("Electrum connection closed: %s", err.Error())
+			c.conn.Close()
+			return
+		}
+		resp := new(response)
+		if err := json.Unmarshal(line, resp); err != nil {
+			log.Warningf("Received malformed message from Electrum server: %s", err.Error())
+			continue
+		}
+		if resp.Id == nil {
+			// Handle notifications off the read loop so the handler can make calls of its own
+			if resp.Method != "" && c.notify != nil {
+				go c.notify(resp.Method, resp.Params)
+			}
+			continue
+		}
+		c.lock.Lock()
+		ch, ok := c.pending[*resp.Id]
+		c.lock.Unlock()
+		if ok {
+			ch <- resp
+		}
+	}
+}
+
+// Older servers return the error as a plain string rather than an object
+func parseError(raw json.RawMessage) error {
+	rpcErr := new(RPCError)
+	if err := json.Unmarshal(raw, rpcErr); err == nil && rpcErr.Message != "" {
+		return rpcErr
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return errors.New(s)
+	}
+	return errors.New(string(raw))
+}
@@ -0,0 +1,132 @@
+package electrum
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	btc "github.com/btcsuite/btcutil"
+)
+
+// Set in the hash type of Bitcoin Cash signatures
+const sigHashForkID txscript.SigHashType = 0x40
+
+/* Sign the input spending subScript with the hash type the wallet's network expects.
+   Bitcoin Cash signs the BIP 143 digest, which includes the amount being spent. */
+func (w *ElectrumWallet) rawTxInSignature(tx *wire.MsgTx, idx int, subScript []byte, amount int64, key *btcec.PrivateKey) ([]byte, error) {
+	if !bitcoin.UsesForkID(w.params) {
+		return txscript.RawTxInSignature(tx, idx, subScript, txscript.SigHashAll, key)
+	}
+	hashType := txscript.SigHashAll | sigHashForkID
+	sig, err := key.Sign(calcBip143SigHash(tx, idx, subScript, amount, hashType))
+	if err != nil {
+		return nil, err
+	}
+	return append(sig.Serialize(), byte(hashType)), nil
+}
+
+/* Sign each input with the key for the script it spends. Pay to script hash inputs
+   are signed as a multisig needing only our signature using redeemScript. The
+   scripts and amounts of the outputs being spent are looked up by outpoint. */
+func (w *ElectrumWallet) signInputs(tx *wire.MsgTx, prevScripts map[wire.OutPoint][]byte, amounts map[wire.OutPoint]int64, getKey txscript.KeyClosure, redeemScript []byte) error {
+	for i, txIn := range tx.TxIn {
+		prevOutScript := prevScripts[txIn.PreviousOutPoint]
+		if !bitcoin.UsesForkID(w.params) {
+			getScript := txscript.ScriptClosure(func(addr btc.Address) ([]byte, error) {
+				return redeemScript, nil
+			})
+			script, err := txscript.SignTxOutput(w.params, tx, i, prevOutScript, txscript.SigHashAll, getKey, getScript, txIn.SignatureScript)
+			if err != nil {
+				return errors.New("Failed to sign transaction")
+			}
+			txIn.SignatureScript = script
+			continue
+		}
+		class, addrs, _, err := txscript.ExtractPkScriptAddrs(prevOutScript, w.params)
+		if err != nil || len(addrs) != 1 {
+			return errors.New("Failed to sign transaction")
+		}
+		builder := txscript.NewScriptBuilder()
+		switch class {
+		case txscript.PubKeyHashTy:
+			key, compressed, err := getKey.GetKey(addrs[0])
+			if err != nil {
+				return errors.New("Failed to sign transaction")
+			}
+			sig, err := w.rawTxInSignature(tx, i, prevOutScript, amounts[txIn.PreviousOutPoint], key)
+			if err != nil {
+				return err
+			}
+			pubkey := key.PubKey().SerializeUncompressed()
+			if compressed {
+				pubkey = key.PubKey().SerializeCompressed()
+			}
+			builder.AddData(sig).AddData(pubkey)
+		case txscript.ScriptHashTy:
+			key, err := w.multisigKey(redeemScript, getKey)
+			if err != nil {
+				return err
+			}
+			sig, err := w.rawTxInSignature(tx, i, redeemScript, amounts[txIn.PreviousOutPoint], key)
+			if err != nil {
+				return err
+			}
+			builder.AddOp(txscript.OP_0).AddData(sig).AddData(redeemScript)
+		default:
+			return errors.New("Failed to sign transaction")
+		}
+		script, err := builder.Script()
+		if err != nil {
+			return err
+		}
+		txIn.SignatureScript = script
+	}
+	return nil
+}
+
+// Return our key in a multisig redeem script which only needs one signature
+func (w *ElectrumWallet) multisigKey(redeemScript []byte, getKey txscript.KeyClosure) (*btcec.PrivateKey, error) {
+	class, addrs, required, err := txscript.ExtractPkScriptAddrs(redeemScript, w.params)
+	if err != nil || class != txscript.MultiSigTy || required != 1 {
+		return nil, errors.New("Only redeem scripts for a 1 of n multisig can be signed")
+	}
+	for _, addr := range addrs {
+		if key, _, err := getKey.GetKey(addr); err == nil {
+			return key, nil
+		}
+	}
+	return nil, errors.New("Failed to sign transaction")
+}
+
+// Return the BIP 143 signature hash of the input spending subScript. Only SigHashAll is supported.
+func calcBip143SigHash(tx *wire.MsgTx, idx int, subScript []byte, amount int64, hashType txscript.SigHashType) []byte {
+	var prevouts, sequences, outputs bytes.Buffer
+	for _, in := range tx.TxIn {
+		prevouts.Write(in.PreviousOutPoint.Hash[:])
+		binary.Write(&prevouts, binary.LittleEndian, in.PreviousOutPoint.Index)
+		binary.Write(&sequences, binary.LittleEndian, in.Sequence)
+	}
+	for _, out := range tx.TxOut {
+		binary.Write(&outputs, binary.LittleEndian, out.Value)
+		wire.WriteVarBytes(&outputs, 0, out.PkScript)
+	}
+
+	var preimage bytes.Buffer
+	binary.Write(&preimage, binary.LittleEndian, tx.Version)
+	preimage.Write(chainhash.DoubleHashB(prevouts.Bytes()))
+	preimage.Write(chainhash.DoubleHashB(sequences.Bytes()))
+	in := tx.TxIn[idx]
+	preimage.Write(in.PreviousOutPoint.Hash[:])
+	binary.Write(&preimage, binary.LittleEndian, in.PreviousOutPoint.Index)
+	wire.WriteVarBytes(&preimage, 0, subScript)
+	binary.Write(&preimage, binary.LittleEndian, amount)
+	binary.Write(&preimage, binary.LittleEndian, in.Sequence)
+	preimage.Write(chainhash.DoubleHashB(outputs.Bytes()))
+	binary.Write(&preimage, binary.LittleEndian, tx.LockTime)
+	binary.Write(&preimage, binary.LittleEndian, uint32(hashType))
+	return chainhash.DoubleHashB(preimage.Bytes())
+}
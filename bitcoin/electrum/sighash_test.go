@@ -0,0 +1,76 @@
+package electrum
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/OpenBazaar/spvwallet"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	btc "github.com/btcsuite/btcutil"
+)
+
+// The native P2WPKH example from BIP 143
+func TestCalcBip143SigHash(t *testing.T) {
+	raw, _ := hex.DecodeString("0100000002fff7f7881a8099afa6940d42d1e7f6362bec38171ea3edf433541db4e4ad969f0000000000eeffffffef51e1b804cc89d182d279655c3aa89e815b1b309fe287d9b2b55d57b90ec68a0100000000ffffffff02202cb206000000001976a9148280b37df378db99f66f85c95a783a76ac7a6d5988ac9093510d000000001976a9143bde42dbee7e4dbe6a21b2d50ce2f0167faa815988ac11000000")
+	tx := wire.NewMsgTx(wire.TxVersion)
+	if err := tx.Deserialize(bytes.NewReader(raw)); err != nil {
+		t.Fatal(err)
+	}
+	scriptCode, _ := hex.DecodeString("76a9141d0f172a0ecb48aee1be1f2687d2963ae33f71a188ac")
+	hash := calcBip143SigHash(tx, 1, scriptCode, 600000000, txscript.SigHashAll)
+	if hex.EncodeToString(hash) != "c37af31116d1b27caf68aae9e3ac82f1477929014d5b917657d0eb49478cb670" {
+		t.Error("Returned incorrect signature hash")
+	}
+}
+
+func TestElectrumWallet_SpendForkID(t *testing.T) {
+	server := newFakeServer(t)
+	defer server.Close()
+	w, cleanup := newTestWalletWithParams(t, server.Addr(), &bitcoin.BitcoinCashTestNetParams)
+	defer cleanup()
+	if w.CurrencyCode() != "tbch" {
+		t.Error("Returned incorrect currency code")
+	}
+
+	script, _ := txscript.PayToAddrScript(w.CurrentAddress(spvwallet.EXTERNAL))
+	funding := paymentTx(script, 1000000)
+	server.addTx(funding, 90)
+	if err := w.connect(); err != nil {
+		t.Fatal(err)
+	}
+	to, err := btc.NewAddressPubKeyHash(bytes.Repeat([]byte{0x01}, 20), &bitcoin.BitcoinCashTestNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Spend(400000, to, spvwallet.NORMAL); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.broadcast) != 1 {
+		t.Fatal("Transaction was not broadcast")
+	}
+	tx := server.broadcast[0]
+	pushes, err := txscript.PushedData(tx.TxIn[0].SignatureScript)
+	if err != nil || len(pushes) != 2 {
+		t.Fatal("Input does not have a pay to pubkey hash signature script")
+	}
+	sigBytes := pushes[0]
+	if txscript.SigHashType(sigBytes[len(sigBytes)-1]) != txscript.SigHashAll|sigHashForkID {
+		t.Error("Signature does not have the fork ID hash type")
+	}
+	sig, err := btcec.ParseDERSignature(sigBytes[:len(sigBytes)-1], btcec.S256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubkey, err := btcec.ParsePubKey(pushes[1], btcec.S256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := calcBip143SigHash(tx, 0, script, 1000000, txscript.SigHashAll|sigHashForkID)
+	if !sig.Verify(hash, pubkey) {
+		t.Error("Signature does not commit to the amount spent")
+	}
+}
@@ -0,0 +1,407 @@
+package electrum
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/OpenBazaar/spvwallet"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	btc "github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/coinset"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/btcsuite/btcutil/txsort"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+)
+
+// Send the transaction to the server and add it to our own store at height zero
+func (w *ElectrumWallet) Broadcast(tx *wire.MsgTx) error {
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		return err
+	}
+	var txid string
+	if err := w.call("blockchain.transaction.broadcast", []interface{}{hex.EncodeToString(buf.Bytes())}, &txid); err != nil {
+		return err
+	}
+	log.Debugf("Broadcast tx %s to Electrum server", txid)
+	_, err := w.ingest(tx, 0)
+	return err
+}
+
+func (w *ElectrumWallet) gatherCoins() map[coinset.Coin]*hd.ExtendedKey {
+	height := w.ChainTip()
+	utxos, _ := w.txstore.Utxos().GetAll()
+	m := make(map[coinset.Coin]*hd.ExtendedKey)
+	for _, u := range utxos {
+		if u.Freeze {
+			continue
+		}
+		var confirmations int32
+		if u.AtHeight > 0 {
+			confirmations = int32(height) - u.AtHeight
+		}
+		c := spvwallet.NewCoin(u.Op.Hash.CloneBytes(), u.Op.Index, btc.Amount(u.Value), int64(confirmations), u.ScriptPubkey)
+		key, err := w.txstore.GetKeyForScript(u.ScriptPubkey)
+		if err != nil {
+			continue
+		}
+		m[c] = key
+	}
+	return m
+}
+
+func (w *ElectrumWallet) Spend(amount int64, addr btc.Address, feeLevel spvwallet.FeeLevel) (*chainhash.Hash, error) {
+	return w.SpendMulti([]bitcoin.SpendOutput{{Address: addr, Amount: amount}}, feeLevel)
+}
+
+// Pay several addresses in one transaction
+func (w *ElectrumWallet) SpendMulti(outputs []bitcoin.SpendOutput, feeLevel spvwallet.FeeLevel) (*chainhash.Hash, error) {
+	tx, err := w.buildTx(outputs, feeLevel)
+	if err != nil {
+		return nil, err
+	}
+	err = w.Broadcast(tx)
+	if err != nil {
+		return nil, err
+	}
+	ch := tx.TxHash()
+	return &ch, nil
+}
+
+// Only CPFP for now
+func (w *ElectrumWallet) BumpFee(txid chainhash.Hash) (*chainhash.Hash, error) {
+	_, txn, err := w.txstore.Txns().Get(txid)
+	if err != nil {
+		return nil, err
+	}
+	if txn.Height > 0 {
+		return nil, errors.New("Transaction is confirmed, cannot bump fee")
+	}
+	utxos, err := w.txstore.Utxos().GetAll()
+	if err != nil {
+		return nil, errors.New("No unspent transactions")
+	}
+	for _, u := range utxos {
+		if u.Op.Hash.IsEqual(&txid) {
+			key, err := w.txstore.GetKeyForScript(u.ScriptPubkey)
+			if err != nil {
+				return nil, err
+			}
+			return w.SweepAddress([]spvwallet.Utxo{u}, nil, key, nil, spvwallet.FEE_BUMP)
+		}
+	}
+	return nil, errors.New("Transaction either doesn't exist or has already been spent")
+}
+
+/* Electrum reports fee estimates in BTC per kilobyte. If the server has no estimate
+   for the target we fall back to the configured defaults. */
+func (w *ElectrumWallet) GetFeePerByte(feeLevel spvwallet.FeeLevel) uint64 {
+	var blocks int
+	var defaultFee uint64
+	switch feeLevel {
+	case spvwallet.PRIOIRTY:
+		blocks, defaultFee = 1, w.priorityFee
+	case spvwallet.NORMAL:
+		blocks, defaultFee = 3, w.normalFee
+	case spvwallet.ECONOMIC:
+		blocks, defaultFee = 6, w.economicFee
+	case spvwallet.FEE_BUMP:
+		return w.GetFeePerByte(spvwallet.PRIOIRTY) * 2
+	default:
+		return w.normalFee
+	}
+	var btcPerKb float64
+	if err := w.call("blockchain.estimatefee", []interface{}{blocks}, &btcPerKb); err != nil || btcPerKb <= 0 {
+		return defaultFee
+	}
+	fee := uint64(btcPerKb * btc.SatoshiPerBitcoin / 1000)
+	if fee > w.maxFee {
+		return w.maxFee
+	}
+	if fee == 0 {
+		return defaultFee
+	}
+	return fee
+}
+
+func (w *ElectrumWallet) EstimateFee(ins []spvwallet.TransactionInput, outs []spvwallet.TransactionOutput, feePerByte uint64) uint64 {
+	tx := new(wire.MsgTx)
+	for _, out := range outs {
+		output := wire.NewTxOut(out.Value, out.ScriptPubKey)
+		tx.TxOut = append(tx.TxOut, output)
+	}
+	estimatedSize := spvwallet.EstimateSerializeSize(len(ins), tx.TxOut, false)
+	fee := estimatedSize * int(feePerByte)
+	return uint64(fee)
+}
+
+// Build the unsigned spend of a multisig, subtracting the fee evenly from each output
+func buildMultisigTx(ins []spvwallet.TransactionInput, outs []spvwallet.TransactionOutput, feePerByte uint64) (*wire.MsgTx, error) {
+	tx := new(wire.MsgTx)
+	for _, in := range ins {
+		ch, err := chainhash.NewHashFromStr(hex.EncodeToString(in.OutpointHash))
+		if err != nil {
+			return nil, err
+		}
+		outpoint := wire.NewOutPoint(ch, in.OutpointIndex)
+		input := wire.NewTxIn(outpoint, []byte{})
+		tx.TxIn = append(tx.TxIn, input)
+	}
+	for _, out := range outs {
+		output := wire.NewTxOut(out.Value, out.ScriptPubKey)
+		tx.TxOut = append(tx.TxOut, output)
+	}
+
+	// Subtract fee
+	estimatedSize := spvwallet.EstimateSerializeSize(len(ins), tx.TxOut, false)
+	fee := estimatedSize * int(feePerByte)
+	feePerOutput := fee / len(tx.TxOut)
+	for _, output := range tx.TxOut {
+		output.Value -= int64(feePerOutput)
+	}
+
+	// BIP 69 sorting
+	txsort.InPlaceSort(tx)
+	return tx, nil
+}
+
+func (w *ElectrumWallet) CreateMultisigSignature(ins []spvwallet.TransactionInput, outs []spvwallet.TransactionOutput, key *hd.ExtendedKey, redeemScript []byte, feePerByte uint64) ([]spvwallet.Signature, error) {
+	var sigs []spvwallet.Signature
+	tx, err := buildMultisigTx(ins, outs, feePerByte)
+	if err != nil {
+		return sigs, err
+	}
+	signingKey, err := key.ECPrivKey()
+	if err != nil {
+		return sigs, err
+	}
+	// The inputs were sorted so look up each amount by outpoint
+	amounts := make(map[wire.OutPoint]int64)
+	for _, in := range ins {
+		ch, err := chainhash.NewHash(in.OutpointHash)
+		if err != nil {
+			return sigs, err
+		}
+		amounts[*wire.NewOutPoint(ch, in.OutpointIndex)] = in.Value
+	}
+	for i, txIn := range tx.TxIn {
+		sig, err := w.rawTxInSignature(tx, i, redeemScript, amounts[txIn.PreviousOutPoint], signingKey)
+		if err != nil {
+			continue
+		}
+		bs := spvwallet.Signature{InputIndex: uint32(i), Signature: sig}
+		sigs = append(sigs, bs)
+	}
+	return sigs, nil
+}
+
+func (w *ElectrumWallet) Multisign(ins []spvwallet.TransactionInput, outs []spvwallet.TransactionOutput, sigs1 []spvwallet.Signature, sigs2 []spvwallet.Signature, redeemScript []byte, feePerByte uint64) error {
+	tx, err := buildMultisigTx(ins, outs, feePerByte)
+	if err != nil {
+		return err
+	}
+	for i, input := range tx.TxIn {
+		var sig1 []byte
+		var sig2 []byte
+		for _, sig := range sigs1 {
+			if int(sig.InputIndex) == i {
+				sig1 = sig.Signature
+			}
+		}
+		for _, sig := range sigs2 {
+			if int(sig.InputIndex) == i {
+				sig2 = sig.Signature
+			}
+		}
+		builder := txscript.NewScriptBuilder()
+		builder.AddOp(txscript.OP_0)
+		builder.AddData(sig1)
+		builder.AddData(sig2)
+		builder.AddData(redeemScript)
+		scriptSig, err := builder.Script()
+		if err != nil {
+			return err
+		}
+		input.SignatureScript = scriptSig
+	}
+	return w.Broadcast(tx)
+}
+
+func (w *ElectrumWallet) SweepAddress(utxos []spvwallet.Utxo, address *btc.Address, key *hd.ExtendedKey, redeemScript *[]byte, feeLevel spvwallet.FeeLevel) (*chainhash.Hash, error) {
+	var internalAddr btc.Address
+	if address != nil {
+		internalAddr = *address
+	} else {
+		internalAddr = w.CurrentAddress(spvwallet.INTERNAL)
+	}
+	script, err := txscript.PayToAddrScript(internalAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	var val int64
+	var inputs []*wire.TxIn
+	additionalPrevScripts := make(map[wire.OutPoint][]byte)
+	amounts := make(map[wire.OutPoint]int64)
+	for _, u := range utxos {
+		val += u.Value
+		in := wire.NewTxIn(&u.Op, []byte{})
+		inputs = append(inputs, in)
+		additionalPrevScripts[u.Op] = u.ScriptPubkey
+		amounts[u.Op] = u.Value
+	}
+	out := wire.NewTxOut(val, script)
+
+	estimatedSize := spvwallet.EstimateSerializeSize(len(utxos), []*wire.TxOut{out}, false)
+
+	// Calculate the fee
+	feePerByte := int(w.GetFeePerByte(feeLevel))
+	fee := estimatedSize * feePerByte
+
+	outVal := val - int64(fee)
+	if outVal < 0 {
+		outVal = 0
+	}
+	out.Value = outVal
+
+	tx := &wire.MsgTx{
+		Version:  wire.TxVersion,
+		TxIn:     inputs,
+		TxOut:    []*wire.TxOut{out},
+		LockTime: 0,
+	}
+
+	// BIP 69 sorting
+	txsort.InPlaceSort(tx)
+
+	// Sign tx
+	privKey, err := key.ECPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	pk := privKey.PubKey().SerializeCompressed()
+	addressPub, err := btc.NewAddressPubKey(pk, w.params)
+	if err != nil {
+		return nil, err
+	}
+
+	getKey := txscript.KeyClosure(func(addr btc.Address) (*btcec.PrivateKey, bool, error) {
+		if addressPub.EncodeAddress() == addr.EncodeAddress() {
+			wif, err := btc.NewWIF(privKey, w.params, true)
+			if err != nil {
+				return nil, false, err
+			}
+			return wif.PrivKey, wif.CompressPubKey, nil
+		}
+		return nil, false, errors.New("Not found")
+	})
+	redeem := []byte{}
+	if redeemScript != nil {
+		redeem = *redeemScript
+	}
+	if err := w.signInputs(tx, additionalPrevScripts, amounts, getKey, redeem); err != nil {
+		return nil, err
+	}
+
+	err = w.Broadcast(tx)
+	if err != nil {
+		return nil, err
+	}
+	txid := tx.TxHash()
+	return &txid, nil
+}
+
+func (w *ElectrumWallet) buildTx(outputs []bitcoin.SpendOutput, feeLevel spvwallet.FeeLevel) (*wire.MsgTx, error) {
+	if len(outputs) == 0 {
+		return nil, errors.New("Transaction has no outputs")
+	}
+	var outs []*wire.TxOut
+	for _, o := range outputs {
+		script, err := txscript.PayToAddrScript(o.Address)
+		if err != nil {
+			return nil, err
+		}
+		// Check for dust
+		if txrules.IsDustAmount(btc.Amount(o.Amount), len(script), txrules.DefaultRelayFeePerKb) {
+			return nil, errors.New("Amount is below dust threshold")
+		}
+		outs = append(outs, wire.NewTxOut(o.Amount, script))
+	}
+
+	var additionalPrevScripts map[wire.OutPoint][]byte
+	var additionalKeysByAddress map[string]*btc.WIF
+	var amounts map[wire.OutPoint]int64
+
+	// Create input source
+	coinMap := w.gatherCoins()
+	coins := make([]coinset.Coin, 0, len(coinMap))
+	for k := range coinMap {
+		coins = append(coins, k)
+	}
+	inputSource := func(target btc.Amount) (total btc.Amount, inputs []*wire.TxIn, scripts [][]byte, err error) {
+		coinSelector := coinset.MaxValueAgeCoinSelector{MaxInputs: 10000, MinChangeAmount: btc.Amount(10000)}
+		coins, err := coinSelector.CoinSelect(target, coins)
+		if err != nil {
+			return total, inputs, scripts, errors.New("insuffient funds")
+		}
+		additionalPrevScripts = make(map[wire.OutPoint][]byte)
+		additionalKeysByAddress = make(map[string]*btc.WIF)
+		amounts = make(map[wire.OutPoint]int64)
+		for _, c := range coins.Coins() {
+			total += c.Value()
+			outpoint := wire.NewOutPoint(c.Hash(), c.Index())
+			in := wire.NewTxIn(outpoint, []byte{})
+			in.Sequence = 0 // Opt-in RBF so we can bump fees
+			inputs = append(inputs, in)
+			additionalPrevScripts[*outpoint] = c.PkScript()
+			amounts[*outpoint] = int64(c.Value())
+			key := coinMap[c]
+			addr, err := key.Address(w.params)
+			if err != nil {
+				continue
+			}
+			privKey, err := key.ECPrivKey()
+			if err != nil {
+				continue
+			}
+			wif, _ := btc.NewWIF(privKey, w.params, true)
+			additionalKeysByAddress[addr.EncodeAddress()] = wif
+		}
+		return total, inputs, scripts, nil
+	}
+
+	// Get the fee per kilobyte
+	feePerKB := int64(w.GetFeePerByte(feeLevel)) * 1000
+
+	// Create change source
+	changeSource := func() ([]byte, error) {
+		addr := w.CurrentAddress(spvwallet.INTERNAL)
+		return txscript.PayToAddrScript(addr)
+	}
+
+	authoredTx, err := txauthor.NewUnsignedTransaction(outs, btc.Amount(feePerKB), inputSource, changeSource)
+	if err != nil {
+		return nil, err
+	}
+
+	// BIP 69 sorting
+	txsort.InPlaceSort(authoredTx.Tx)
+
+	// Sign tx
+	getKey := txscript.KeyClosure(func(addr btc.Address) (*btcec.PrivateKey, bool, error) {
+		wif, ok := additionalKeysByAddress[addr.EncodeAddress()]
+		if !ok {
+			return nil, false, errors.New("Not found")
+		}
+		return wif.PrivKey, wif.CompressPubKey, nil
+	})
+	if err := w.signInputs(authoredTx.Tx, additionalPrevScripts, amounts, getKey, []byte{}); err != nil {
+		return nil, err
+	}
+	return authoredTx.Tx, nil
+}
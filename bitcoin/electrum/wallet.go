@@ -0,0 +1,613 @@
+package electrum
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/OpenBazaar/spvwallet"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	btc "github.com/btcsuite/btcutil"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/op/go-logging"
+	b39 "github.com/tyler-smith/go-bip39"
+	"golang.org/x/net/proxy"
+	"sync"
+	"time"
+)
+
+var log = logging.MustGetLogger("electrum")
+
+const ReconnectInterval = time.Second * 30
+
+/* ElectrumWallet is a light wallet which keeps its keys and transactions locally
+   and asks an Electrum server for the history of each of its scripts. Unlike the
+   SPV wallet it trusts the server to report transactions honestly, but it does not
+   need to sync headers and can run against an ElectrumX server the user controls. */
+type ElectrumWallet struct {
+	params *chaincfg.Params
+
+	masterPrivateKey *hd.ExtendedKey
+	masterPublicKey  *hd.ExtendedKey
+
+	maxFee      uint64
+	priorityFee uint64
+	normalFee   uint64
+	economicFee uint64
+
+	address string
+	useTLS  bool
+	dialer  proxy.Dialer
+
+	txstore   *spvwallet.TxStore
+	client    *Client
+	listeners []func(spvwallet.TransactionCallback)
+	chainTip  uint32
+
+	// Electrum identifies scripts by the reversed sha256 of the script
+	scripts  map[string][]byte
+	statuses map[string]string
+
+	lock     sync.RWMutex
+	syncLock sync.Mutex
+	stopChan chan struct{}
+	running  bool
+}
+
+type header struct {
+	Height uint32 `json:"height"`
+	Hex    string `json:"hex"`
+}
+
+type historyItem struct {
+	TxHash string `json:"tx_hash"`
+	Height int32  `json:"height"`
+}
+
+func NewElectrumWallet(mnemonic string, params *chaincfg.Params, server string, maxFee uint64, lowFee uint64, mediumFee uint64, highFee uint64, db spvwallet.Datastore, dialer proxy.Dialer) (*ElectrumWallet, error) {
+	if server == "" {
+		return nil, errors.New("An Electrum server address is required")
+	}
+	seed := b39.NewSeed(mnemonic, "")
+	mPrivKey, err := hd.NewMaster(seed, params)
+	if err != nil {
+		return nil, err
+	}
+	mPubKey, err := mPrivKey.Neuter()
+	if err != nil {
+		return nil, err
+	}
+	txstore, err := spvwallet.NewTxStore(params, db, mPrivKey)
+	if err != nil {
+		return nil, err
+	}
+	address, useTLS := ParseServer(server)
+	w := &ElectrumWallet{
+		params:           params,
+		masterPrivateKey: mPrivKey,
+		masterPublicKey:  mPubKey,
+		maxFee:           maxFee,
+		priorityFee:      highFee,
+		normalFee:        mediumFee,
+		economicFee:      lowFee,
+		address:          address,
+		useTLS:           useTLS,
+		dialer:           dialer,
+		txstore:          txstore,
+		scripts:          make(map[string][]byte),
+		statuses:         make(map[string]string),
+		stopChan:         make(chan struct{}),
+	}
+	return w, nil
+}
+
+func (w *ElectrumWallet) Start() {
+	w.lock.Lock()
+	w.running = true
+	w.lock.Unlock()
+	go w.maintainConnection()
+}
+
+// Stay connected to the server, reconnecting and resyncing whenever the connection drops
+func (w *ElectrumWallet) maintainConnection() {
+	for {
+		if err := w.connect(); err != nil {
+			log.Warningf("Error connecting to Electrum server %s: %s", w.address, err.Error())
+		} else {
+			log.Infof("Connected to Electrum server %s", w.address)
+			select {
+			case <-w.getClient().Done():
+				log.Warningf("Lost connection to Electrum server %s", w.address)
+			case <-w.stopChan:
+				return
+			}
+		}
+		select {
+		case <-time.After(ReconnectInterval):
+		case <-w.stopChan:
+			return
+		}
+	}
+}
+
+func (w *ElectrumWallet) connect() error {
+	client, err := Dial(w.address, w.useTLS, w.dialer, w.handleNotification)
+	if err != nil {
+		return err
+	}
+	return w.start(client)
+}
+
+// Negotiate the protocol, subscribe to headers and our scripts, then sync our history
+func (w *ElectrumWallet) start(client *Client) error {
+	if err := client.Call("server.version", []interface{}{"OpenBazaar", ProtocolVersion}, nil); err != nil {
+		client.Close()
+		return err
+	}
+	w.lock.Lock()
+	w.client = client
+	w.statuses = make(map[string]string)
+	w.lock.Unlock()
+
+	h := new(header)
+	if err := client.Call("blockchain.headers.subscribe", nil, h); err != nil {
+		client.Close()
+		return err
+	}
+	w.lock.Lock()
+	w.chainTip = h.Height
+	w.lock.Unlock()
+	if err := w.subscribeAll(); err != nil {
+		client.Close()
+		return err
+	}
+	return nil
+}
+
+func (w *ElectrumWallet) getClient() *Client {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return w.client
+}
+
+func (w *ElectrumWallet) call(method string, params []interface{}, result interface{}) error {
+	client := w.getClient()
+	if client == nil {
+		return errors.New("Not connected to an Electrum server")
+	}
+	return client.Call(method, params, result)
+}
+
+func (w *ElectrumWallet) handleNotification(method string, params json.RawMessage) {
+	switch method {
+	case "blockchain.headers.subscribe":
+		var headers []header
+		if err := json.Unmarshal(params, &headers); err != nil || len(headers) == 0 {
+			return
+		}
+		w.lock.Lock()
+		w.chainTip = headers[0].Height
+		w.lock.Unlock()
+	case "blockchain.scripthash.subscribe":
+		var p []*string
+		if err := json.Unmarshal(params, &p); err != nil || len(p) != 2 || p[0] == nil {
+			return
+		}
+		status := ""
+		if p[1] != nil {
+			status = *p[1]
+		}
+		w.lock.Lock()
+		script, ok := w.scripts[*p[0]]
+		w.statuses[*p[0]] = status
+		w.lock.Unlock()
+		if !ok {
+			return
+		}
+		if err := w.syncScript(script); err != nil {
+			log.Errorf("Error syncing script history: %s", err.Error())
+		}
+	}
+}
+
+func ScriptHash(script []byte) string {
+	h := sha256.Sum256(script)
+	for i, j := 0, len(h)-1; i < j; i, j = i+1, j-1 {
+		h[i], h[j] = h[j], h[i]
+	}
+	return hex.EncodeToString(h[:])
+}
+
+// Scripts for all of our addresses plus any watched scripts
+func (w *ElectrumWallet) allScripts() ([][]byte, error) {
+	var scripts [][]byte
+	for _, addr := range w.txstore.Adrs {
+		script, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, err
+		}
+		scripts = append(scripts, script)
+	}
+	watched, err := w.txstore.WatchedScripts().GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return append(scripts, watched...), nil
+}
+
+// Subscribe to any scripts we are not yet subscribed to and sync those which have history
+func (w *ElectrumWallet) subscribeAll() error {
+	scripts, err := w.allScripts()
+	if err != nil {
+		return err
+	}
+	for _, script := range scripts {
+		if err := w.subscribe(script); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *ElectrumWallet) subscribe(script []byte) error {
+	sh := ScriptHash(script)
+	w.lock.Lock()
+	_, ok := w.statuses[sh]
+	w.scripts[sh] = script
+	w.lock.Unlock()
+	if ok {
+		return nil
+	}
+	var status *string
+	if err := w.call("blockchain.scripthash.subscribe", []interface{}{sh}, &status); err != nil {
+		return err
+	}
+	w.lock.Lock()
+	w.statuses[sh] = ""
+	if status != nil {
+		w.statuses[sh] = *status
+	}
+	w.lock.Unlock()
+	// A null status means the script has never been used
+	if status == nil {
+		return nil
+	}
+	return w.syncScript(script)
+}
+
+// Fetch the history for a script and ingest any transactions which are new or newly confirmed
+func (w *ElectrumWallet) syncScript(script []byte) error {
+	var history []historyItem
+	if err := w.call("blockchain.scripthash.get_history", []interface{}{ScriptHash(script)}, &history); err != nil {
+		return err
+	}
+	newAddrs := false
+	for _, item := range history {
+		txid, err := chainhash.NewHashFromStr(item.TxHash)
+		if err != nil {
+			return err
+		}
+		// Unconfirmed transactions with unconfirmed parents have a height of -1
+		height := item.Height
+		if height < 0 {
+			height = 0
+		}
+		w.syncLock.Lock()
+		_, txn, err := w.txstore.Txns().Get(*txid)
+		w.syncLock.Unlock()
+		if err == nil && (txn.Height > 0 || height == 0) {
+			continue
+		}
+		tx, err := w.getTransaction(*txid)
+		if err != nil {
+			return err
+		}
+		hits, err := w.ingest(tx, height)
+		if err != nil {
+			return err
+		}
+		if hits > 0 {
+			newAddrs = true
+		}
+	}
+	// Using an address extends the lookahead window so there may be new addresses to watch
+	if newAddrs {
+		return w.subscribeAll()
+	}
+	return nil
+}
+
+func (w *ElectrumWallet) getTransaction(txid chainhash.Hash) (*wire.MsgTx, error) {
+	var txHex string
+	if err := w.call("blockchain.transaction.get", []interface{}{txid.String()}, &txHex); err != nil {
+		return nil, err
+	}
+	raw, err := hex.DecodeString(txHex)
+	if err != nil {
+		return nil, err
+	}
+	tx := wire.NewMsgTx(wire.TxVersion)
+	if err := tx.Deserialize(bytes.NewReader(raw)); err != nil {
+		return nil, err
+	}
+	if tx.TxHash() != txid {
+		return nil, errors.New("Electrum server returned the wrong transaction")
+	}
+	return tx, nil
+}
+
+/* Ingest a transaction into the tx store and fire the listeners if it's new. The store
+   only calls back on its own listeners so we build the callback ourselves, which has
+   to happen before ingesting as that removes the utxos the inputs spend. */
+func (w *ElectrumWallet) ingest(tx *wire.MsgTx, height int32) (uint32, error) {
+	hits, cb, err := w.store(tx, height)
+	if err != nil || hits == 0 {
+		return hits, err
+	}
+	w.lock.RLock()
+	listeners := w.listeners
+	w.lock.RUnlock()
+	for _, listener := range listeners {
+		listener(*cb)
+	}
+	return hits, nil
+}
+
+func (w *ElectrumWallet) store(tx *wire.MsgTx, height int32) (uint32, *spvwallet.TransactionCallback, error) {
+	w.syncLock.Lock()
+	defer w.syncLock.Unlock()
+	txid := tx.TxHash()
+	_, txn, err := w.txstore.Txns().Get(txid)
+	if err == nil {
+		if txn.Height <= 0 && height > 0 {
+			return 0, nil, w.confirm(txid, txn, height)
+		}
+		return 0, nil, nil
+	}
+	cb := spvwallet.TransactionCallback{Txid: txid.CloneBytes()}
+	for i, out := range tx.TxOut {
+		cb.Outputs = append(cb.Outputs, spvwallet.TransactionOutput{ScriptPubKey: out.PkScript, Value: out.Value, Index: uint32(i)})
+	}
+	utxos, err := w.txstore.Utxos().GetAll()
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, in := range tx.TxIn {
+		for _, u := range utxos {
+			if in.PreviousOutPoint == u.Op {
+				cb.Inputs = append(cb.Inputs, spvwallet.TransactionInput{
+					OutpointHash:       u.Op.Hash.CloneBytes(),
+					OutpointIndex:      u.Op.Index,
+					LinkedScriptPubKey: u.ScriptPubkey,
+					Value:              u.Value,
+				})
+				break
+			}
+		}
+	}
+	hits, err := w.txstore.Ingest(tx, height)
+	return hits, &cb, err
+}
+
+/* Record the height of a transaction we already have. Re-ingesting it would put back
+   any of its outputs which have since been spent so the heights are updated directly. */
+func (w *ElectrumWallet) confirm(txid chainhash.Hash, txn spvwallet.Txn, height int32) error {
+	tx, _, err := w.txstore.Txns().Get(txid)
+	if err != nil {
+		return err
+	}
+	if err := w.txstore.Txns().Put(tx, int(txn.Value), int(height), txn.Timestamp); err != nil {
+		return err
+	}
+	utxos, err := w.txstore.Utxos().GetAll()
+	if err != nil {
+		return err
+	}
+	for _, u := range utxos {
+		if u.Op.Hash.IsEqual(&txid) {
+			u.AtHeight = height
+			if err := w.txstore.Utxos().Put(u); err != nil {
+				return err
+			}
+		}
+	}
+	stxos, err := w.txstore.Stxos().GetAll()
+	if err != nil {
+		return err
+	}
+	for _, s := range stxos {
+		changed := false
+		if s.Utxo.Op.Hash.IsEqual(&txid) {
+			s.Utxo.AtHeight = height
+			changed = true
+		}
+		if s.SpendTxid.IsEqual(&txid) {
+			s.SpendHeight = height
+			changed = true
+		}
+		if changed {
+			if err := w.txstore.Stxos().Put(s); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//
+// API
+//
+//////////////
+
+func (w *ElectrumWallet) CurrencyCode() string {
+	return bitcoin.CurrencyCode(w.params)
+}
+
+func (w *ElectrumWallet) Params() *chaincfg.Params {
+	return w.params
+}
+
+func (w *ElectrumWallet) MasterPrivateKey() *hd.ExtendedKey {
+	return w.masterPrivateKey
+}
+
+func (w *ElectrumWallet) MasterPublicKey() *hd.ExtendedKey {
+	return w.masterPublicKey
+}
+
+func (w *ElectrumWallet) CurrentAddress(purpose spvwallet.KeyPurpose) btc.Address {
+	key, _ := w.txstore.GetCurrentKey(purpose)
+	addr, _ := key.Address(w.params)
+	return btc.Address(addr)
+}
+
+func (w *ElectrumWallet) NewAddress(purpose spvwallet.KeyPurpose) btc.Address {
+	key := w.txstore.GetFreshKey(purpose)
+	addr, _ := key.Address(w.params)
+	script, _ := txscript.PayToAddrScript(btc.Address(addr))
+	w.txstore.Keys().MarkKeyAsUsed(script)
+	w.txstore.PopulateAdrs()
+	if w.getClient() != nil {
+		go w.subscribeAll()
+	}
+	return btc.Address(addr)
+}
+
+func (w *ElectrumWallet) HasKey(addr btc.Address) bool {
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return false
+	}
+	_, err = w.txstore.GetKeyForScript(script)
+	if err != nil {
+		return false
+	}
+	return true
+}
+
+func (w *ElectrumWallet) Balance() (confirmed, unconfirmed int64) {
+	utxos, _ := w.txstore.Utxos().GetAll()
+	stxos, _ := w.txstore.Stxos().GetAll()
+	for _, utxo := range utxos {
+		if !utxo.Freeze {
+			if utxo.AtHeight > 0 {
+				confirmed += utxo.Value
+			} else {
+				if checkIfStxoIsConfirmed(utxo, stxos) {
+					confirmed += utxo.Value
+				} else {
+					unconfirmed += utxo.Value
+				}
+			}
+		}
+	}
+	return confirmed, unconfirmed
+}
+
+// Change from a confirmed transaction of ours counts towards the confirmed balance
+func checkIfStxoIsConfirmed(utxo spvwallet.Utxo, stxos []spvwallet.Stxo) bool {
+	for _, stxo := range stxos {
+		if stxo.SpendTxid.IsEqual(&utxo.Op.Hash) {
+			if stxo.Utxo.AtHeight > 0 {
+				return true
+			} else {
+				return checkIfStxoIsConfirmed(stxo.Utxo, stxos)
+			}
+		}
+	}
+	return false
+}
+
+func (w *ElectrumWallet) Transactions() ([]spvwallet.Txn, error) {
+	return w.txstore.Txns().GetAll()
+}
+
+func (w *ElectrumWallet) ChainTip() uint32 {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return w.chainTip
+}
+
+func (w *ElectrumWallet) GetConfirmations(txid chainhash.Hash) (uint32, error) {
+	_, txn, err := w.txstore.Txns().Get(txid)
+	if err != nil {
+		return 0, err
+	}
+	chainTip := w.ChainTip()
+	if txn.Height <= 0 || uint32(txn.Height) > chainTip {
+		return 0, nil
+	}
+	return chainTip - uint32(txn.Height) + 1, nil
+}
+
+func (w *ElectrumWallet) AddTransactionListener(callback func(spvwallet.TransactionCallback)) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.listeners = append(w.listeners, callback)
+}
+
+func (w *ElectrumWallet) AddWatchedScript(script []byte) error {
+	err := w.txstore.WatchedScripts().Put(script)
+	if err != nil {
+		return err
+	}
+	w.txstore.PopulateAdrs()
+	if w.getClient() == nil {
+		return nil
+	}
+	return w.subscribe(script)
+}
+
+func (w *ElectrumWallet) GenerateMultisigScript(keys []hd.ExtendedKey, threshold int) (addr btc.Address, redeemScript []byte, err error) {
+	var addrPubKeys []*btc.AddressPubKey
+	for _, key := range keys {
+		ecKey, err := key.ECPubKey()
+		if err != nil {
+			return nil, nil, err
+		}
+		k, err := btc.NewAddressPubKey(ecKey.SerializeCompressed(), w.params)
+		if err != nil {
+			return nil, nil, err
+		}
+		addrPubKeys = append(addrPubKeys, k)
+	}
+	redeemScript, err = txscript.MultiSigScript(addrPubKeys, threshold)
+	if err != nil {
+		return nil, nil, err
+	}
+	addr, err = btc.NewAddressScriptHash(redeemScript, w.params)
+	if err != nil {
+		return nil, nil, err
+	}
+	return addr, redeemScript, nil
+}
+
+// The server holds the full history so a resync just drops our subscriptions and fetches it again
+func (w *ElectrumWallet) ReSyncBlockchain(fromHeight int32) {
+	w.lock.Lock()
+	w.statuses = make(map[string]string)
+	w.lock.Unlock()
+	if err := w.subscribeAll(); err != nil {
+		log.Errorf("Error resyncing from Electrum server: %s", err.Error())
+	}
+}
+
+func (w *ElectrumWallet) Close() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.running {
+		log.Info("Disconnecting from Electrum server")
+		close(w.stopChan)
+		w.running = false
+	}
+	if w.client != nil {
+		w.client.Close()
+		w.client = nil
+	}
+}
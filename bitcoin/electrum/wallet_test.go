@@ -0,0 +1,504 @@
+package electrum
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/OpenBazaar/openbazaar-go/repo/db"
+	"github.com/OpenBazaar/spvwallet"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	btc "github.com/btcsuite/btcutil"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// fakeServer is an in-process Electrum server backed by maps of history and raw transactions
+type fakeServer struct {
+	listener  net.Listener
+	height    uint32
+	history   map[string][]historyItem
+	txs       map[string]string
+	broadcast []*wire.MsgTx
+	conns     []net.Conn
+	lock      sync.Mutex
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeServer{
+		listener: l,
+		height:   100,
+		history:  make(map[string][]historyItem),
+		txs:      make(map[string]string),
+	}
+	go s.serve()
+	return s
+}
+
+func (s *fakeServer) Addr() string {
+	return "tcp://" + s.listener.Addr().String()
+}
+
+func (s *fakeServer) Close() {
+	s.listener.Close()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, c := range s.conns {
+		c.Close()
+	}
+}
+
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.lock.Lock()
+		s.conns = append(s.conns, conn)
+		s.lock.Unlock()
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeServer) handle(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+		req := new(request)
+		if err := json.Unmarshal(line, req); err != nil {
+			return
+		}
+		result, rpcErr := s.respond(req)
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.Id}
+		if rpcErr != nil {
+			resp["error"] = rpcErr
+		} else {
+			resp["result"] = result
+		}
+		s.write(conn, resp)
+	}
+}
+
+func (s *fakeServer) respond(req *request) (interface{}, *RPCError) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	switch req.Method {
+	case "server.version":
+		return []string{"FakeElectrum 1.0", ProtocolVersion}, nil
+	case "blockchain.headers.subscribe":
+		return header{Height: s.height}, nil
+	case "blockchain.scripthash.subscribe":
+		return s.status(req.Params[0].(string)), nil
+	case "blockchain.scripthash.get_history":
+		h := s.history[req.Params[0].(string)]
+		if h == nil {
+			h = []historyItem{}
+		}
+		return h, nil
+	case "blockchain.transaction.get":
+		tx, ok := s.txs[req.Params[0].(string)]
+		if !ok {
+			return nil, &RPCError{Code: 2, Message: "unknown transaction"}
+		}
+		return tx, nil
+	case "blockchain.transaction.broadcast":
+		raw, _ := hex.DecodeString(req.Params[0].(string))
+		tx := wire.NewMsgTx(wire.TxVersion)
+		if err := tx.Deserialize(bytes.NewReader(raw)); err != nil {
+			return nil, &RPCError{Code: 1, Message: "invalid transaction"}
+		}
+		s.broadcast = append(s.broadcast, tx)
+		s.txs[tx.TxHash().String()] = req.Params[0].(string)
+		return tx.TxHash().String(), nil
+	case "blockchain.estimatefee":
+		return 0.0002, nil
+	}
+	return nil, &RPCError{Code: -32601, Message: "unknown method " + req.Method}
+}
+
+// The real status is a hash of the history but any value which changes with it will do
+func (s *fakeServer) status(scriptHash string) interface{} {
+	h := s.history[scriptHash]
+	if len(h) == 0 {
+		return nil
+	}
+	b, _ := json.Marshal(h)
+	return hex.EncodeToString(b)
+}
+
+func (s *fakeServer) write(conn net.Conn, msg interface{}) {
+	b, _ := json.Marshal(msg)
+	conn.Write(append(b, '\n'))
+}
+
+func (s *fakeServer) notify(method string, params ...interface{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, c := range s.conns {
+		s.write(c, map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params})
+	}
+}
+
+// Add a transaction to the history of each of its output scripts and notify subscribers
+func (s *fakeServer) addTx(tx *wire.MsgTx, height int32) {
+	var buf bytes.Buffer
+	tx.Serialize(&buf)
+	s.lock.Lock()
+	s.txs[tx.TxHash().String()] = hex.EncodeToString(buf.Bytes())
+	var changed []string
+	for _, out := range tx.TxOut {
+		sh := ScriptHash(out.PkScript)
+		found := false
+		for i, item := range s.history[sh] {
+			if item.TxHash == tx.TxHash().String() {
+				s.history[sh][i].Height = height
+				found = true
+			}
+		}
+		if !found {
+			s.history[sh] = append(s.history[sh], historyItem{TxHash: tx.TxHash().String(), Height: height})
+		}
+		changed = append(changed, sh)
+	}
+	s.lock.Unlock()
+	for _, sh := range changed {
+		s.lock.Lock()
+		status := s.status(sh)
+		s.lock.Unlock()
+		s.notify("blockchain.scripthash.subscribe", sh, status)
+	}
+}
+
+func (s *fakeServer) setHeight(height uint32) {
+	s.lock.Lock()
+	s.height = height
+	s.lock.Unlock()
+	s.notify("blockchain.headers.subscribe", header{Height: height})
+}
+
+func newTestWallet(t *testing.T, server string) (*ElectrumWallet, func()) {
+	return newTestWalletWithParams(t, server, &chaincfg.TestNet3Params)
+}
+
+func newTestWalletWithParams(t *testing.T, server string, params *chaincfg.Params) (*ElectrumWallet, func()) {
+	dir, err := ioutil.TempDir("", "electrum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(path.Join(dir, "datastore"), os.ModePerm)
+	sqliteDB, err := db.Create(dir, "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sqliteDB.Config().Init(testMnemonic, []byte("identity"), ""); err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewElectrumWallet(testMnemonic, params, server, 2000, 50, 100, 200, sqliteDB, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w, func() {
+		w.Close()
+		sqliteDB.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func txid(tx *wire.MsgTx) []byte {
+	h := tx.TxHash()
+	return h.CloneBytes()
+}
+
+func paymentTx(script []byte, value int64) *wire.MsgTx {
+	tx := wire.NewMsgTx(wire.TxVersion)
+	prev := chainhash.DoubleHashH(script)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&prev, 0), []byte{0x00}))
+	tx.AddTxOut(wire.NewTxOut(value, script))
+	return tx
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(time.Second * 5)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the wallet to sync")
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+}
+
+func TestScriptHash(t *testing.T) {
+	script, _ := hex.DecodeString("76a91462e907b15cbf27d5425399ebf6f0fb50ebb88f1888ac")
+	if ScriptHash(script) != "8b01df4e368ea28f8dc0423bcf7a4923e3a12d307c875e47a0cfbf90b5c39161" {
+		t.Error("Returned incorrect script hash")
+	}
+}
+
+func TestParseServer(t *testing.T) {
+	tests := []struct {
+		server  string
+		address string
+		useTLS  bool
+	}{
+		{"electrum.example.com:50002", "electrum.example.com:50002", true},
+		{"ssl://electrum.example.com:50002", "electrum.example.com:50002", true},
+		{"tcp://127.0.0.1:50001", "127.0.0.1:50001", false},
+	}
+	for _, test := range tests {
+		address, useTLS := ParseServer(test.server)
+		if address != test.address || useTLS != test.useTLS {
+			t.Errorf("Incorrectly parsed %s", test.server)
+		}
+	}
+}
+
+func TestClientCall(t *testing.T) {
+	server := newFakeServer(t)
+	defer server.Close()
+	address, _ := ParseServer(server.Addr())
+	client, err := Dial(address, false, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	var fee float64
+	if err := client.Call("blockchain.estimatefee", []interface{}{1}, &fee); err != nil {
+		t.Fatal(err)
+	}
+	if fee != 0.0002 {
+		t.Error("Returned incorrect result")
+	}
+	err = client.Call("blockchain.block.header", []interface{}{1}, nil)
+	if _, ok := err.(*RPCError); !ok {
+		t.Error("Server error was not returned")
+	}
+	server.Close()
+	select {
+	case <-client.Done():
+	case <-time.After(time.Second * 5):
+		t.Fatal("Client did not notice the closed connection")
+	}
+	if err := client.Call("server.version", nil, nil); err != ErrClosed {
+		t.Error("Call on a closed connection failed to return error")
+	}
+}
+
+func TestElectrumWallet_Sync(t *testing.T) {
+	server := newFakeServer(t)
+	defer server.Close()
+	w, cleanup := newTestWallet(t, server.Addr())
+	defer cleanup()
+
+	script, _ := txscript.PayToAddrScript(w.CurrentAddress(spvwallet.EXTERNAL))
+	tx := paymentTx(script, 100000)
+	server.addTx(tx, 95)
+
+	var callbacks []spvwallet.TransactionCallback
+	w.AddTransactionListener(func(cb spvwallet.TransactionCallback) {
+		callbacks = append(callbacks, cb)
+	})
+	if err := w.connect(); err != nil {
+		t.Fatal(err)
+	}
+	if w.ChainTip() != 100 {
+		t.Error("Returned incorrect chain tip")
+	}
+	confirmed, unconfirmed := w.Balance()
+	if confirmed != 100000 || unconfirmed != 0 {
+		t.Error("Returned incorrect balance")
+	}
+	txns, err := w.Transactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns) != 1 || txns[0].Txid != tx.TxHash().String() || txns[0].Height != 95 {
+		t.Error("Returned incorrect transactions")
+	}
+	confirms, err := w.GetConfirmations(tx.TxHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if confirms != 6 {
+		t.Error("Returned incorrect confirmations")
+	}
+	if len(callbacks) != 1 || !bytes.Equal(callbacks[0].Txid, txid(tx)) || callbacks[0].Outputs[0].Value != 100000 {
+		t.Error("Listener was not called with the transaction")
+	}
+	if w.CurrentAddress(spvwallet.EXTERNAL).String() == btcAddress(t, script) {
+		t.Error("Current address was not advanced after being used")
+	}
+}
+
+func TestElectrumWallet_AddWatchedScript(t *testing.T) {
+	server := newFakeServer(t)
+	defer server.Close()
+	w, cleanup := newTestWallet(t, server.Addr())
+	defer cleanup()
+	if err := w.connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	received := make(chan spvwallet.TransactionCallback, 1)
+	w.AddTransactionListener(func(cb spvwallet.TransactionCallback) {
+		received <- cb
+	})
+	k1, _ := w.MasterPublicKey().Child(0)
+	k2, _ := w.MasterPublicKey().Child(1)
+	addr, redeemScript, err := w.GenerateMultisigScript([]hd.ExtendedKey{*k1, *k2}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(redeemScript) == 0 {
+		t.Error("Returned empty redeem script")
+	}
+	script, _ := txscript.PayToAddrScript(addr)
+	if err := w.AddWatchedScript(script); err != nil {
+		t.Fatal(err)
+	}
+
+	// An unconfirmed payment to the watched script is pushed to us by the server
+	tx := paymentTx(script, 50000)
+	server.addTx(tx, 0)
+	select {
+	case cb := <-received:
+		if !bytes.Equal(cb.Txid, txid(tx)) {
+			t.Error("Listener called with incorrect transaction")
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("Listener was not called for watched script")
+	}
+	confirmed, unconfirmed := w.Balance()
+	if confirmed != 0 || unconfirmed != 0 {
+		t.Error("Watched script funds should not count towards the balance")
+	}
+	confirms, err := w.GetConfirmations(tx.TxHash())
+	if err != nil || confirms != 0 {
+		t.Error("Unconfirmed transaction returned confirmations")
+	}
+
+	// Then it confirms and another block is found
+	server.addTx(tx, 101)
+	server.setHeight(102)
+	waitFor(t, func() bool {
+		confirms, _ := w.GetConfirmations(tx.TxHash())
+		return confirms == 2
+	})
+}
+
+func TestElectrumWallet_Spend(t *testing.T) {
+	server := newFakeServer(t)
+	defer server.Close()
+	w, cleanup := newTestWallet(t, server.Addr())
+	defer cleanup()
+
+	script, _ := txscript.PayToAddrScript(w.CurrentAddress(spvwallet.EXTERNAL))
+	server.addTx(paymentTx(script, 1000000), 90)
+	if err := w.connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	if fee := w.GetFeePerByte(spvwallet.NORMAL); fee != 20 {
+		t.Errorf("Returned incorrect fee per byte %d", fee)
+	}
+	to, err := btc.NewAddressPubKeyHash(bytes.Repeat([]byte{0x01}, 20), &chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spendId, err := w.Spend(400000, to, spvwallet.NORMAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(server.broadcast) != 1 || server.broadcast[0].TxHash() != *spendId {
+		t.Fatal("Transaction was not broadcast")
+	}
+	toScript, _ := txscript.PayToAddrScript(to)
+	paid := false
+	for _, out := range server.broadcast[0].TxOut {
+		if bytes.Equal(out.PkScript, toScript) && out.Value == 400000 {
+			paid = true
+		}
+	}
+	if !paid {
+		t.Error("Broadcast transaction does not pay the recipient")
+	}
+	// The change is spendable straight away as it comes from a confirmed input
+	confirmed, unconfirmed := w.Balance()
+	if confirmed <= 590000 || confirmed >= 600000 || unconfirmed != 0 {
+		t.Error("Balance does not reflect the spend")
+	}
+	if _, _, err := w.txstore.Txns().Get(*spendId); err != nil {
+		t.Error("Spend was not recorded in the wallet")
+	}
+}
+
+func TestElectrumWallet_SpendMulti(t *testing.T) {
+	server := newFakeServer(t)
+	defer server.Close()
+	w, cleanup := newTestWallet(t, server.Addr())
+	defer cleanup()
+
+	script, _ := txscript.PayToAddrScript(w.CurrentAddress(spvwallet.EXTERNAL))
+	server.addTx(paymentTx(script, 1000000), 90)
+	if err := w.connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	var outputs []bitcoin.SpendOutput
+	for i := byte(1); i <= 3; i++ {
+		to, err := btc.NewAddressPubKeyHash(bytes.Repeat([]byte{i}, 20), &chaincfg.TestNet3Params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		outputs = append(outputs, bitcoin.SpendOutput{Address: to, Amount: int64(i) * 100000})
+	}
+	spendId, err := w.SpendMulti(outputs, spvwallet.NORMAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(server.broadcast) != 1 || server.broadcast[0].TxHash() != *spendId {
+		t.Fatal("Transaction was not broadcast")
+	}
+	for _, o := range outputs {
+		toScript, _ := txscript.PayToAddrScript(o.Address)
+		paid := false
+		for _, out := range server.broadcast[0].TxOut {
+			if bytes.Equal(out.PkScript, toScript) && out.Value == o.Amount {
+				paid = true
+			}
+		}
+		if !paid {
+			t.Errorf("Broadcast transaction does not pay %s", o.Address)
+		}
+	}
+	if _, err := w.SpendMulti(nil, spvwallet.NORMAL); err == nil {
+		t.Error("Spent a transaction without outputs")
+	}
+}
+
+func btcAddress(t *testing.T, script []byte) string {
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(script, &chaincfg.TestNet3Params)
+	if err != nil || len(addrs) != 1 {
+		t.Fatal("Could not extract address from script")
+	}
+	return addrs[0].String()
+}
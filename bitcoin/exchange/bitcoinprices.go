@@ -34,15 +34,22 @@ type BitPayDecoder struct{}
 type BlockchainInfoDecoder struct{}
 type BitcoinChartsDecoder struct{}
 
+// Saves the rates from each successful fetch so there's a history of them
+type RateRecorder interface {
+	Put(rates map[string]float64, timestamp time.Time) error
+}
+
 type BitcoinPriceFetcher struct {
 	sync.Mutex
 	cache     map[string]float64
 	providers []*ExchangeRateProvider
+	recorder  RateRecorder
 }
 
-func NewBitcoinPriceFetcher(dialer proxy.Dialer) *BitcoinPriceFetcher {
+func NewBitcoinPriceFetcher(dialer proxy.Dialer, recorder RateRecorder) *BitcoinPriceFetcher {
 	b := BitcoinPriceFetcher{
-		cache: make(map[string]float64),
+		cache:    make(map[string]float64),
+		recorder: recorder,
 	}
 	dial := net.Dial
 	if dialer != nil {
@@ -99,6 +106,7 @@ func (b *BitcoinPriceFetcher) fetchCurrentRates() error {
 		err := provider.fetch()
 		if err == nil {
 			log.Notice("Fetched current Bitcoin rates from " + provider.fetchUrl)
+			b.recordRates()
 			return nil
 		}
 	}
@@ -106,6 +114,20 @@ func (b *BitcoinPriceFetcher) fetchCurrentRates() error {
 	return errors.New("All exchange rate API queries failed")
 }
 
+// The caller must hold the lock
+func (b *BitcoinPriceFetcher) recordRates() {
+	if b.recorder == nil {
+		return
+	}
+	rates := make(map[string]float64)
+	for currency, rate := range b.cache {
+		rates[currency] = rate
+	}
+	if err := b.recorder.Put(rates, time.Now()); err != nil {
+		log.Error("Failed to record exchange rates:", err)
+	}
+}
+
 func (provider *ExchangeRateProvider) fetch() (err error) {
 	if len(provider.fetchUrl) == 0 {
 		err = errors.New("Provider has no fetchUrl")
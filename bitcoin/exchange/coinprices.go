@@ -0,0 +1,69 @@
+package exchange
+
+import (
+	"errors"
+	"strings"
+)
+
+/* CoinPriceFetcher quotes exchange rates for a coin other than bitcoin. The bitcoin
+   rate providers also list other cryptocurrencies in units of the coin per BTC, so
+   the price of the coin in any currency is the bitcoin price divided by that. */
+type CoinPriceFetcher struct {
+	code    string
+	bitcoin *BitcoinPriceFetcher
+}
+
+func NewCoinPriceFetcher(code string, bitcoin *BitcoinPriceFetcher) *CoinPriceFetcher {
+	return &CoinPriceFetcher{code: strings.ToUpper(code), bitcoin: bitcoin}
+}
+
+func (c *CoinPriceFetcher) GetExchangeRate(currencyCode string) (float64, error) {
+	all, err := c.bitcoin.GetAllRates()
+	if err != nil {
+		return 0, err
+	}
+	c.bitcoin.Lock()
+	defer c.bitcoin.Unlock()
+	return c.rate(all, currencyCode)
+}
+
+func (c *CoinPriceFetcher) GetLatestRate(currencyCode string) (float64, error) {
+	c.bitcoin.fetchCurrentRates()
+	return c.GetExchangeRate(currencyCode)
+}
+
+func (c *CoinPriceFetcher) GetAllRates() (map[string]float64, error) {
+	all, err := c.bitcoin.GetAllRates()
+	if err != nil {
+		return nil, err
+	}
+	c.bitcoin.Lock()
+	defer c.bitcoin.Unlock()
+	rates := make(map[string]float64)
+	for currency := range all {
+		if rate, err := c.rate(all, currency); err == nil {
+			rates[currency] = rate
+		}
+	}
+	return rates, nil
+}
+
+func (c *CoinPriceFetcher) UnitsPerCoin() int {
+	return SatoshiPerBTC
+}
+
+// The caller must hold the bitcoin fetcher's lock
+func (c *CoinPriceFetcher) rate(bitcoinRates map[string]float64, currencyCode string) (float64, error) {
+	coinsPerBTC, ok := bitcoinRates[c.code]
+	if !ok || coinsPerBTC <= 0 {
+		return 0, errors.New("Currency not tracked")
+	}
+	if strings.ToUpper(currencyCode) == "BTC" {
+		return 1 / coinsPerBTC, nil
+	}
+	price, ok := bitcoinRates[currencyCode]
+	if !ok {
+		return 0, errors.New("Currency not tracked")
+	}
+	return price / coinsPerBTC, nil
+}
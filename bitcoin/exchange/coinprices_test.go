@@ -0,0 +1,29 @@
+package exchange
+
+import "testing"
+
+func TestCoinPriceFetcher(t *testing.T) {
+	b := setupBitcoinPriceFetcher()
+	b.cache["USD"] = 6000
+	b.cache["LTC"] = 60
+	c := NewCoinPriceFetcher("ltc", &b)
+	r, err := c.GetExchangeRate("USD")
+	if err != nil || r != 100 {
+		t.Error("Returned incorrect exchange rate", r, err)
+	}
+	r, err = c.GetExchangeRate("BTC")
+	if err != nil || r != 1.0/60 {
+		t.Error("Returned incorrect bitcoin exchange rate", r, err)
+	}
+	if _, err := c.GetExchangeRate("EUR"); err == nil {
+		t.Error("Returned rate for a currency which isn't tracked")
+	}
+	rates, err := c.GetAllRates()
+	if err != nil || rates["USD"] != 100 || rates["LTC"] != 1 {
+		t.Error("Returned incorrect rates", rates)
+	}
+
+	if _, err := NewCoinPriceFetcher("BCH", &b).GetExchangeRate("USD"); err == nil {
+		t.Error("Returned rate for a coin the providers don't list")
+	}
+}
@@ -24,11 +24,15 @@ type TransactionListener struct {
 	db        repo.Datastore
 	broadcast chan interface{}
 	params    *chaincfg.Params
+
+	// Called in its own goroutine when a sale has been paid for. Offline orders are still pending
+	// at this point and confirmed orders have moved to funded.
+	onSaleFunded func(orderId string)
 	*sync.Mutex
 }
 
-func NewTransactionListener(db repo.Datastore, broadcast chan interface{}, params *chaincfg.Params) *TransactionListener {
-	l := &TransactionListener{db, broadcast, params, new(sync.Mutex)}
+func NewTransactionListener(db repo.Datastore, broadcast chan interface{}, params *chaincfg.Params, onSaleFunded func(orderId string)) *TransactionListener {
+	l := &TransactionListener{db, broadcast, params, onSaleFunded, new(sync.Mutex)}
 	return l
 }
 
@@ -129,15 +133,20 @@ func (l *TransactionListener) processSalePayment(txid []byte, output spvwallet.T
 	if err != nil {
 		return
 	}
+	var nowFunded bool
 	if !funded {
 		requestedAmount := int64(contract.BuyerOrder.Payment.Amount)
 		if funding >= requestedAmount {
 			log.Debugf("Recieved payment for order %s", orderId)
 			funded = true
+			nowFunded = true
 			if state == pb.OrderState_CONFIRMED {
 				l.db.Sales().Put(orderId, *contract, pb.OrderState_FUNDED, false)
 			}
 			l.adjustInventory(contract)
+			if contract.VendorListings[0].Metadata.ContractType == pb.Listing_Metadata_CROWD_FUND {
+				l.db.Pledges().UpdateFunding(orderId, true)
+			}
 
 			n := notifications.OrderNotification{
 				contract.VendorListings[0].Item.Title,
@@ -161,6 +170,9 @@ func (l *TransactionListener) processSalePayment(txid []byte, output spvwallet.T
 	}
 	records = append(records, record)
 	l.db.Sales().UpdateFunding(orderId, funded, records)
+	if nowFunded && l.onSaleFunded != nil {
+		go l.onSaleFunded(orderId)
+	}
 
 	// Save tx metadata
 	var thumbnail string
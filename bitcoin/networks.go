@@ -0,0 +1,121 @@
+package bitcoin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+)
+
+/* Network parameters for the coins other than bitcoin that a wallet can be run for.
+   Wallets for these coins trust a server for the chain so only the fields used to
+   encode addresses and keys are set. */
+var (
+	LitecoinParams = chaincfg.Params{
+		Name:             "litecoin",
+		Net:              wire.BitcoinNet(0xdbb6c0fb),
+		DefaultPort:      "9333",
+		PubKeyHashAddrID: 0x30,
+		ScriptHashAddrID: 0x32,
+		PrivateKeyID:     0xb0,
+		HDPrivateKeyID:   [4]byte{0x01, 0x9d, 0x9c, 0xfe},
+		HDPublicKeyID:    [4]byte{0x01, 0x9d, 0xa4, 0x62},
+		HDCoinType:       2,
+	}
+	LitecoinTestNetParams = chaincfg.Params{
+		Name:             "litecoin-testnet4",
+		Net:              wire.BitcoinNet(0xf1c8d2fd),
+		DefaultPort:      "19335",
+		PubKeyHashAddrID: 0x6f,
+		ScriptHashAddrID: 0x3a,
+		PrivateKeyID:     0xef,
+		HDPrivateKeyID:   [4]byte{0x04, 0x36, 0xef, 0x7d},
+		HDPublicKeyID:    [4]byte{0x04, 0x36, 0xf6, 0xe1},
+		HDCoinType:       1,
+	}
+	BitcoinCashParams = chaincfg.Params{
+		Name:             "bitcoincash",
+		Net:              wire.BitcoinNet(0xe8f3e1e3),
+		DefaultPort:      "8333",
+		PubKeyHashAddrID: 0x00,
+		ScriptHashAddrID: 0x05,
+		PrivateKeyID:     0x80,
+		HDPrivateKeyID:   [4]byte{0x04, 0x88, 0xad, 0xe4},
+		HDPublicKeyID:    [4]byte{0x04, 0x88, 0xb2, 0x1e},
+		HDCoinType:       145,
+	}
+	BitcoinCashTestNetParams = chaincfg.Params{
+		Name:             "bitcoincash-testnet",
+		Net:              wire.BitcoinNet(0xf4f3e5f4),
+		DefaultPort:      "18333",
+		PubKeyHashAddrID: 0x6f,
+		ScriptHashAddrID: 0xc4,
+		PrivateKeyID:     0xef,
+		HDPrivateKeyID:   [4]byte{0x04, 0x35, 0x83, 0x94},
+		HDPublicKeyID:    [4]byte{0x04, 0x35, 0x87, 0xcf},
+		HDCoinType:       1,
+	}
+)
+
+// Addresses can only be decoded for registered networks
+func init() {
+	for _, params := range []*chaincfg.Params{&LitecoinParams, &LitecoinTestNetParams, &BitcoinCashParams, &BitcoinCashTestNetParams} {
+		if err := chaincfg.Register(params); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Return the parameters of the network for the given coin
+func CoinParams(coin string, testnet, regtest bool) (*chaincfg.Params, error) {
+	switch strings.ToLower(coin) {
+	case "btc":
+		if regtest {
+			return &chaincfg.RegressionNetParams, nil
+		} else if testnet {
+			return &chaincfg.TestNet3Params, nil
+		}
+		return &chaincfg.MainNetParams, nil
+	case "ltc":
+		if regtest {
+			break
+		} else if testnet {
+			return &LitecoinTestNetParams, nil
+		}
+		return &LitecoinParams, nil
+	case "bch":
+		if regtest {
+			break
+		} else if testnet {
+			return &BitcoinCashTestNetParams, nil
+		}
+		return &BitcoinCashParams, nil
+	default:
+		return nil, fmt.Errorf("Unsupported coin %s", coin)
+	}
+	return nil, fmt.Errorf("There is no %s regtest network", strings.ToUpper(coin))
+}
+
+// Return the currency code of the network's coin. Codes on test networks are prefixed with a t.
+func CurrencyCode(params *chaincfg.Params) string {
+	switch params.Name {
+	case chaincfg.MainNetParams.Name:
+		return "btc"
+	case LitecoinParams.Name:
+		return "ltc"
+	case LitecoinTestNetParams.Name:
+		return "tltc"
+	case BitcoinCashParams.Name:
+		return "bch"
+	case BitcoinCashTestNetParams.Name:
+		return "tbch"
+	default:
+		return "tbtc"
+	}
+}
+
+// Bitcoin Cash signatures commit to the amount being spent and set a fork ID in the hash type
+func UsesForkID(params *chaincfg.Params) bool {
+	return params.Name == BitcoinCashParams.Name || params.Name == BitcoinCashTestNetParams.Name
+}
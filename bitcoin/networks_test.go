@@ -0,0 +1,48 @@
+package bitcoin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	btc "github.com/btcsuite/btcutil"
+)
+
+func TestCoinParams(t *testing.T) {
+	params, err := CoinParams("LTC", false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if CurrencyCode(params) != "ltc" {
+		t.Error("Returned incorrect currency code")
+	}
+	addr, err := btc.NewAddressPubKeyHash(make([]byte, 20), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(addr.EncodeAddress(), "L") {
+		t.Error("Litecoin address has the wrong prefix")
+	}
+	decoded, err := btc.DecodeAddress(addr.EncodeAddress(), params)
+	if err != nil || !decoded.IsForNet(params) {
+		t.Error("Failed to decode litecoin address")
+	}
+
+	params, err = CoinParams("bch", true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if CurrencyCode(params) != "tbch" || !UsesForkID(params) {
+		t.Error("Returned incorrect bitcoin cash testnet params")
+	}
+	params, err = CoinParams("btc", false, true)
+	if err != nil || params != &chaincfg.RegressionNetParams || UsesForkID(params) {
+		t.Error("Returned incorrect bitcoin regtest params")
+	}
+	if _, err := CoinParams("ltc", false, true); err == nil {
+		t.Error("Returned params for a litecoin regtest network")
+	}
+	if _, err := CoinParams("doge", false, false); err == nil {
+		t.Error("Returned params for an unsupported coin")
+	}
+}
@@ -0,0 +1,108 @@
+package bitcoin
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/* WalletRegistry holds one wallet per currency code so that a node can accept
+   payment in several cryptocurrencies at once. The first wallet registered is
+   the default and is used for anything not tied to a particular currency.
+   The escrow keys in our profile, listings and orders come from the default
+   wallet so every wallet must be created from the same seed and share its
+   master key. */
+type WalletRegistry struct {
+	wallets     map[string]BitcoinWallet
+	defaultCode string
+	lock        sync.RWMutex
+}
+
+func NewWalletRegistry(wallets ...BitcoinWallet) (*WalletRegistry, error) {
+	r := &WalletRegistry{wallets: make(map[string]BitcoinWallet)}
+	for _, w := range wallets {
+		if err := r.Register(w); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Add a wallet to the registry under its currency code
+func (r *WalletRegistry) Register(wallet BitcoinWallet) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	code := strings.ToLower(wallet.CurrencyCode())
+	if code == "" {
+		return errors.New("Wallet does not have a currency code")
+	}
+	if _, ok := r.wallets[code]; ok {
+		return fmt.Errorf("A wallet for %s is already registered", code)
+	}
+	if def, ok := r.wallets[r.defaultCode]; ok && !sameMasterKey(def, wallet) {
+		return fmt.Errorf("The %s wallet has a different master key to the default wallet", code)
+	}
+	r.wallets[code] = wallet
+	if r.defaultCode == "" {
+		r.defaultCode = code
+	}
+	return nil
+}
+
+func sameMasterKey(a, b BitcoinWallet) bool {
+	keyA, err := a.MasterPublicKey().ECPubKey()
+	if err != nil {
+		return false
+	}
+	keyB, err := b.MasterPublicKey().ECPubKey()
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(keyA.SerializeCompressed(), keyB.SerializeCompressed())
+}
+
+// Return the wallet for the given currency code. An empty code returns the default wallet.
+func (r *WalletRegistry) Get(currencyCode string) (BitcoinWallet, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	code := strings.ToLower(currencyCode)
+	if code == "" {
+		code = r.defaultCode
+	}
+	wallet, ok := r.wallets[code]
+	if !ok {
+		return nil, fmt.Errorf("No wallet for currency %s", currencyCode)
+	}
+	return wallet, nil
+}
+
+func (r *WalletRegistry) Default() BitcoinWallet {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.wallets[r.defaultCode]
+}
+
+// Return the registered currency codes in alphabetical order
+func (r *WalletRegistry) CurrencyCodes() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	var codes []string
+	for code := range r.wallets {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Return all registered wallets ordered by currency code
+func (r *WalletRegistry) All() []BitcoinWallet {
+	var wallets []BitcoinWallet
+	for _, code := range r.CurrencyCodes() {
+		wallet, _ := r.Get(code)
+		wallets = append(wallets, wallet)
+	}
+	return wallets
+}
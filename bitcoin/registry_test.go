@@ -0,0 +1,69 @@
+package bitcoin
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+)
+
+type mockWallet struct {
+	BitcoinWallet
+	code string
+	seed byte
+}
+
+func (m *mockWallet) CurrencyCode() string {
+	return m.code
+}
+
+func (m *mockWallet) MasterPublicKey() *hd.ExtendedKey {
+	seed := make([]byte, hd.RecommendedSeedLen)
+	seed[0] = m.seed
+	key, _ := hd.NewMaster(seed, &chaincfg.MainNetParams)
+	pub, _ := key.Neuter()
+	return pub
+}
+
+func TestWalletRegistry(t *testing.T) {
+	btc := &mockWallet{code: "btc"}
+	ltc := &mockWallet{code: "LTC"}
+	r, err := NewWalletRegistry(btc, ltc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := r.Get("ltc")
+	if err != nil || w != ltc {
+		t.Error("Returned incorrect wallet")
+	}
+	w, err = r.Get("BTC")
+	if err != nil || w != btc {
+		t.Error("Currency code lookup should be case insensitive")
+	}
+	w, err = r.Get("")
+	if err != nil || w != btc {
+		t.Error("Empty currency code should return the default wallet")
+	}
+	if r.Default() != btc {
+		t.Error("First registered wallet should be the default")
+	}
+	_, err = r.Get("bch")
+	if err == nil {
+		t.Error("Get for an unregistered currency failed to return error")
+	}
+	err = r.Register(&mockWallet{code: "btc"})
+	if err == nil {
+		t.Error("Registering a duplicate currency failed to return error")
+	}
+	err = r.Register(&mockWallet{code: "bch", seed: 1})
+	if err == nil {
+		t.Error("Registered a wallet with a different master key")
+	}
+	codes := r.CurrencyCodes()
+	if len(codes) != 2 || codes[0] != "btc" || codes[1] != "ltc" {
+		t.Error("Returned incorrect currency codes")
+	}
+	if len(r.All()) != 2 {
+		t.Error("Returned incorrect wallets")
+	}
+}
@@ -82,3 +82,15 @@ type BitcoinWallet interface {
 	// Cleanly disconnect from the wallet
 	Close()
 }
+
+// An amount to send to an address in a transaction with several outputs
+type SpendOutput struct {
+	Address btc.Address
+	Amount  int64
+}
+
+/* MultiSpender is implemented by wallets which can pay several addresses in a single
+   transaction. It's kept out of BitcoinWallet as the SPV wallet doesn't support it. */
+type MultiSpender interface {
+	SpendMulti(outputs []SpendOutput, feeLevel spvwallet.FeeLevel) (*chainhash.Hash, error)
+}
@@ -3,6 +3,7 @@
 TARGETS=${1:-windows/386,windows/amd64,darwin/amd64,linux/386,linux/amd64,linux/arm}
 
 export CGO_ENABLED=1
+export CGO_CFLAGS="$CGO_CFLAGS -DSQLITE_ENABLE_FTS5"
 docker pull karalabe/xgo-latest
 go get github.com/karalabe/xgo
 mkdir dist && cd dist/
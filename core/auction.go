@@ -0,0 +1,350 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/OpenBazaar/jsonpb"
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/OpenBazaar/spvwallet"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	ipfspath "github.com/ipfs/go-ipfs/path"
+	crypto "gx/ipfs/QmPGxZ1DP2w45WcogpW1h43BvseXbfke9N91qotpoQcUeS/go-libp2p-crypto"
+	"time"
+)
+
+var ErrNoWinningBid = errors.New("Auction listings can only be ordered with a winning bid")
+
+/* A bid is an order for a single item of an auction listing. The amount is
+   denominated in satoshis and replaces the listing price if the bid wins. */
+type BidData struct {
+	PurchaseData
+	Amount uint64 `json:"amount"`
+}
+
+/* Bid builds and signs an order for the auction item and sends it to the vendor.
+   Since the vendor will not respond until the auction ends the payment address is
+   always generated by us, either as a 1 of 2 with the vendor or a 2 of 3 with the
+   moderator. If the bid wins the vendor sends it back to us as a BID_ACCEPT. */
+func (n *OpenBazaarNode) Bid(data *BidData) (orderId string, err error) {
+	if len(data.Items) != 1 {
+		return "", errors.New("A bid must contain exactly one item")
+	}
+	data.Items[0].Quantity = 1
+	contract, err := n.createContractWithOrder(&data.PurchaseData)
+	if err != nil {
+		return "", err
+	}
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return "", err
+	}
+	listing := contract.VendorListings[0]
+	if listing.Metadata.Format != pb.Listing_Metadata_AUCTION {
+		return "", errors.New("Listing is not an auction")
+	}
+	minimum, err := n.getPriceInSatoshi(wal, listing.Metadata.PricingCurrency, listing.Item.Price)
+	if err != nil {
+		return "", err
+	}
+	if data.Amount < minimum {
+		return "", fmt.Errorf("Bid is below the starting price of %d", minimum)
+	}
+	contract.BuyerOrder.Items[0].BidAmount = data.Amount
+
+	payment, err := n.newMultisigPayment(contract, data.Moderator)
+	if err != nil {
+		return "", err
+	}
+	total, err := n.CalculateOrderTotal(contract)
+	if err != nil {
+		return "", err
+	}
+	payment.Amount = total
+	contract.BuyerOrder.Payment = payment
+	if payment.Method == pb.Order_Payment_MODERATED {
+		contract.BuyerOrder.RefundFee = wal.GetFeePerByte(spvwallet.NORMAL)
+	}
+	contract, err = n.SignOrder(contract)
+	if err != nil {
+		return "", err
+	}
+	orderId, err = n.CalcOrderId(contract.BuyerOrder)
+	if err != nil {
+		return "", err
+	}
+
+	addr, err := btcutil.DecodeAddress(payment.Address, wal.Params())
+	if err != nil {
+		return "", err
+	}
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return "", err
+	}
+	wal.AddWatchedScript(script)
+
+	err = n.Datastore.Bids().Put(orderId, *contract, repo.BidPending, true)
+	if err != nil {
+		return "", err
+	}
+	k, err := crypto.UnmarshalPublicKey(listing.VendorID.Pubkeys.Guid)
+	if err != nil {
+		return "", err
+	}
+	err = n.SendBid(listing.VendorID.Guid, &k, contract)
+	if err != nil {
+		return "", err
+	}
+	return orderId, nil
+}
+
+/* Generate a payment address using the first child key derived from the buyer's,
+   vendor's and optionally the moderator's masterPubKey and a random chaincode. */
+func (n *OpenBazaarNode) newMultisigPayment(contract *pb.RicardianContract, moderator string) (*pb.Order_Payment, error) {
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return nil, err
+	}
+	payment := new(pb.Order_Payment)
+	chaincode := make([]byte, 32)
+	_, err = rand.Read(chaincode)
+	if err != nil {
+		return nil, err
+	}
+	buyerKey, err := childPaymentKey(wal, contract.BuyerOrder.BuyerID.Pubkeys.Bitcoin, chaincode)
+	if err != nil {
+		return nil, err
+	}
+	vendorKey, err := childPaymentKey(wal, contract.VendorListings[0].VendorID.Pubkeys.Bitcoin, chaincode)
+	if err != nil {
+		return nil, err
+	}
+	keys := []hd.ExtendedKey{*buyerKey, *vendorKey}
+	threshold := 1
+	payment.Method = pb.Order_Payment_DIRECT
+	if moderator != "" {
+		ipnsPath := ipfspath.FromString(moderator + "/profile")
+		profileBytes, err := ipfs.ResolveThenCat(n.Context, ipnsPath)
+		if err != nil {
+			return nil, err
+		}
+		profile := new(pb.Profile)
+		err = jsonpb.UnmarshalString(string(profileBytes), profile)
+		if err != nil {
+			return nil, err
+		}
+		moderatorKeyBytes, err := hex.DecodeString(profile.BitcoinPubkey)
+		if err != nil {
+			return nil, err
+		}
+		moderatorKey, err := childPaymentKey(wal, moderatorKeyBytes, chaincode)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *moderatorKey)
+		threshold = 2
+		payment.Method = pb.Order_Payment_MODERATED
+		payment.Moderator = moderator
+	}
+	addr, redeemScript, err := wal.GenerateMultisigScript(keys, threshold)
+	if err != nil {
+		return nil, err
+	}
+	payment.Address = addr.EncodeAddress()
+	payment.RedeemScript = hex.EncodeToString(redeemScript)
+	payment.Chaincode = hex.EncodeToString(chaincode)
+	return payment, nil
+}
+
+// Derive the first child of a public key using the given chaincode
+func childPaymentKey(wal bitcoin.BitcoinWallet, pubkey []byte, chaincode []byte) (*hd.ExtendedKey, error) {
+	parentFP := []byte{0x00, 0x00, 0x00, 0x00}
+	hdKey := hd.NewExtendedKey(
+		wal.Params().HDPublicKeyID[:],
+		pubkey,
+		chaincode,
+		parentFP,
+		0,
+		0,
+		false)
+	return hdKey.Child(0)
+}
+
+// Validate a bid received on one of our auction listings
+func (n *OpenBazaarNode) ValidateBid(contract *pb.RicardianContract) error {
+	if err := n.validateOrder(contract); err != nil {
+		return err
+	}
+	if len(contract.BuyerOrder.Items) != 1 || contract.BuyerOrder.Items[0].Quantity != 1 {
+		return errors.New("A bid must be for a single item")
+	}
+	item := contract.BuyerOrder.Items[0]
+	listing, err := GetListingFromHash(item.ListingHash, contract)
+	if err != nil {
+		return err
+	}
+	if listing.Metadata.Format != pb.Listing_Metadata_AUCTION {
+		return errors.New("Listing is not an auction")
+	}
+	if time.Unix(listing.Metadata.Expiry.Seconds, 0).Before(time.Now()) {
+		return errors.New("Auction has ended")
+	}
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return err
+	}
+	minimum, err := n.getPriceInSatoshi(wal, listing.Metadata.PricingCurrency, listing.Item.Price)
+	if err != nil {
+		return err
+	}
+	if item.BidAmount < minimum {
+		return fmt.Errorf("Bid is below the starting price of %d", minimum)
+	}
+	bids, err := n.Datastore.Bids().GetBySlug(listing.Slug)
+	if err != nil {
+		return err
+	}
+	for _, bid := range bids {
+		if bid.State == repo.BidPending.String() {
+			if item.BidAmount <= bid.Amount {
+				return errors.New("Bid must be higher than the current highest bid")
+			}
+			break
+		}
+	}
+
+	switch contract.BuyerOrder.Payment.Method {
+	case pb.Order_Payment_DIRECT:
+		err = n.ValidateDirectPaymentAddress(contract)
+	case pb.Order_Payment_MODERATED:
+		err = n.ValidateModeratedPaymentAddress(contract)
+	default:
+		err = errors.New("Bids must include a direct or moderated payment address")
+	}
+	if err != nil {
+		return err
+	}
+	total, err := n.CalculateOrderTotal(contract)
+	if err != nil {
+		return err
+	}
+	if !n.ValidatePaymentAmount(total, contract.BuyerOrder.Payment.Amount) {
+		return errors.New("Calculated a different payment amount")
+	}
+	return nil
+}
+
+/* Return the bid a buyer won an auction with for an order they've sent us. The order
+   must be the one they bid with so its price is the bid we accepted. The vendor's copy
+   of the bid is returned and should be used in place of the order. */
+func (n *OpenBazaarNode) WinningBid(contract *pb.RicardianContract) (*pb.RicardianContract, error) {
+	orderId, err := n.CalcOrderId(contract.BuyerOrder)
+	if err != nil {
+		return nil, err
+	}
+	bid, state, outgoing, err := n.Datastore.Bids().GetByOrderId(orderId)
+	if err != nil || outgoing || state != repo.BidWon {
+		return nil, ErrNoWinningBid
+	}
+	if bid.BuyerOrder.BuyerID.Guid != contract.BuyerOrder.BuyerID.Guid ||
+		len(bid.BuyerOrder.Items) != 1 || len(contract.BuyerOrder.Items) != 1 ||
+		bid.BuyerOrder.Items[0].ListingHash != contract.BuyerOrder.Items[0].ListingHash {
+		return nil, ErrNoWinningBid
+	}
+	return bid, nil
+}
+
+func isAuction(contract *pb.RicardianContract) bool {
+	for _, listing := range contract.VendorListings {
+		if listing.Metadata != nil && listing.Metadata.Format == pb.Listing_Metadata_AUCTION {
+			return true
+		}
+	}
+	return false
+}
+
+/* CloseExpiredAuctions turns the highest bid on each expired auction into a sale
+   and notifies the winning bidder. All other open bids on the listing are marked lost. */
+func (n *OpenBazaarNode) CloseExpiredAuctions() {
+	index, err := n.getListingIndex()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, ld := range index {
+		bids, err := n.Datastore.Bids().GetBySlug(ld.Slug)
+		if err != nil {
+			log.Errorf("Error loading bids on %s: %s", ld.Slug, err.Error())
+			continue
+		}
+		var pending []repo.Bid
+		for _, bid := range bids {
+			if bid.State == repo.BidPending.String() {
+				pending = append(pending, bid)
+			}
+		}
+		if len(pending) == 0 {
+			continue
+		}
+		contract, err := n.GetListingFromSlug(ld.Slug)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		expiry := contract.VendorListings[0].Metadata.Expiry
+		if expiry == nil || time.Unix(expiry.Seconds, 0).After(time.Now()) {
+			continue
+		}
+		if err := n.closeAuction(pending); err != nil {
+			log.Errorf("Error closing auction %s: %s", ld.Slug, err.Error())
+		}
+	}
+}
+
+// The bids must be sorted highest first
+func (n *OpenBazaarNode) closeAuction(bids []repo.Bid) error {
+	winner := bids[0]
+	contract, _, _, err := n.Datastore.Bids().GetByOrderId(winner.OrderId)
+	if err != nil {
+		return err
+	}
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return err
+	}
+	addr, err := btcutil.DecodeAddress(contract.BuyerOrder.Payment.Address, wal.Params())
+	if err != nil {
+		return err
+	}
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return err
+	}
+	wal.AddWatchedScript(script)
+	err = n.Datastore.Sales().Put(winner.OrderId, *contract, pb.OrderState_PENDING, false)
+	if err != nil {
+		return err
+	}
+	err = n.Datastore.Bids().UpdateState(winner.OrderId, repo.BidWon)
+	if err != nil {
+		return err
+	}
+	for _, bid := range bids[1:] {
+		err = n.Datastore.Bids().UpdateState(bid.OrderId, repo.BidLost)
+		if err != nil {
+			return err
+		}
+	}
+	k, err := crypto.UnmarshalPublicKey(contract.BuyerOrder.BuyerID.Pubkeys.Guid)
+	if err != nil {
+		return err
+	}
+	return n.SendBidAccept(contract.BuyerOrder.BuyerID.Guid, &k, contract)
+}
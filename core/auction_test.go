@@ -0,0 +1,71 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/OpenBazaar/openbazaar-go/repo/db"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+func TestWinningBid(t *testing.T) {
+	repoPath, err := ioutil.TempDir("", "auction")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(repoPath)
+	os.MkdirAll(path.Join(repoPath, "datastore"), os.ModePerm)
+	datastore, err := db.Create(repoPath, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer datastore.Close()
+	if err := datastore.Config().Init("", []byte{}, ""); err != nil {
+		t.Fatal(err)
+	}
+	node := &OpenBazaarNode{Datastore: datastore}
+
+	bid := &pb.RicardianContract{
+		VendorListings: []*pb.Listing{{
+			Slug:     "auction",
+			VendorID: &pb.ID{Guid: "QmVendor"},
+			Metadata: &pb.Listing_Metadata{Format: pb.Listing_Metadata_AUCTION},
+			Item:     &pb.Listing_Item{Title: "Auction", Images: []*pb.Listing_Item_Image{{Tiny: "QmTiny"}}},
+		}},
+		BuyerOrder: &pb.Order{
+			BuyerID:   &pb.ID{Guid: "QmBuyer"},
+			Items:     []*pb.Order_Item{{ListingHash: "QmListing", Quantity: 1, BidAmount: 50000}},
+			Timestamp: &timestamp.Timestamp{Seconds: 1500000000},
+		},
+	}
+	orderId, err := node.CalcOrderId(bid.BuyerOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := datastore.Bids().Put(orderId, *bid, repo.BidPending, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := node.WinningBid(bid); err != ErrNoWinningBid {
+		t.Error("Accepted an order for a bid which hasn't won")
+	}
+	if err := datastore.Bids().UpdateState(orderId, repo.BidWon); err != nil {
+		t.Fatal(err)
+	}
+	won, err := node.WinningBid(bid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if won.BuyerOrder.Items[0].BidAmount != 50000 {
+		t.Error("Returned the wrong bid")
+	}
+
+	// An order with a different price isn't the bid which won
+	bid.BuyerOrder.Items[0].BidAmount = 1
+	if _, err := node.WinningBid(bid); err != ErrNoWinningBid {
+		t.Error("Accepted an order priced differently to the winning bid")
+	}
+}
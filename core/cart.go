@@ -0,0 +1,263 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/jsonpb"
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/OpenBazaar/spvwallet"
+	btc "github.com/btcsuite/btcutil"
+)
+
+var (
+	ErrCartEmpty            = errors.New("Cart is empty")
+	ErrCheckoutGroupFunded  = errors.New("Checkout group has already been funded")
+	ErrCheckoutGroupFunding = errors.New("Checkout group is already being funded")
+)
+
+// Groups being funded so a second request can't pay for them again
+var (
+	checkoutFundingLock sync.Mutex
+	checkoutFunding     = make(map[string]bool)
+)
+
+// The shipping address and payment choices used for every order placed from the cart
+type CheckoutData struct {
+	ShipTo               string  `json:"shipTo"`
+	Address              string  `json:"address"`
+	City                 string  `json:"city"`
+	State                string  `json:"state"`
+	PostalCode           string  `json:"postalCode"`
+	CountryCode          string  `json:"countryCode"`
+	AddressNotes         string  `json:"addressNotes"`
+	AlternateContactInfo string  `json:"alternateContactInfo"`
+	RefundAddress        *string `json:"refundAddress"` //optional, can be left out of json
+
+	// The moderator for each vendor's order keyed by the vendor's peer ID. Orders to other vendors are paid directly.
+	Moderators map[string]string `json:"moderators"`
+}
+
+// A vendor whose order couldn't be placed. Their items are left in the cart.
+type CheckoutFailure struct {
+	VendorId string `json:"vendorId"`
+	Error    string `json:"error"`
+}
+
+// Add an item to the cart. Its listing is fetched to find out which vendor it's from.
+func (n *OpenBazaarNode) AddToCart(item repo.CartItem) (repo.CartItem, error) {
+	if item.Quantity <= 0 {
+		return item, errors.New("Quantity must be at least one")
+	}
+	b, err := ipfs.Cat(n.Context, item.ListingHash)
+	if err != nil {
+		return item, err
+	}
+	rc := new(pb.RicardianContract)
+	if err := jsonpb.UnmarshalString(string(b), rc); err != nil {
+		return item, err
+	}
+	if err := validateVendorID(rc); err != nil {
+		return item, err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return item, err
+	}
+	item.Id = hex.EncodeToString(id)
+	item.VendorId = rc.VendorListings[0].VendorID.Guid
+	item.Added = time.Now()
+	return item, n.Datastore.Cart().Put(item)
+}
+
+// Change what's selected for an item already in the cart. Its listing can't be changed.
+func (n *OpenBazaarNode) UpdateCartItem(item repo.CartItem) error {
+	if item.Quantity <= 0 {
+		return errors.New("Quantity must be at least one")
+	}
+	existing, err := n.Datastore.Cart().Get(item.Id)
+	if err != nil {
+		return err
+	}
+	existing.Quantity = item.Quantity
+	existing.Options = item.Options
+	existing.Shipping = item.Shipping
+	existing.Memo = item.Memo
+	existing.Coupons = item.Coupons
+	return n.Datastore.Cart().Put(existing)
+}
+
+/* Place one order for each vendor with items in the cart. The orders are saved as a
+   checkout group so they can be funded together. An order which fails doesn't stop
+   the others being placed and its items are left in the cart. */
+func (n *OpenBazaarNode) Checkout(data *CheckoutData) (repo.CheckoutGroup, []CheckoutFailure, error) {
+	group := repo.CheckoutGroup{Timestamp: time.Now()}
+	items, err := n.Datastore.Cart().GetAll()
+	if err != nil {
+		return group, nil, err
+	}
+	if len(items) == 0 {
+		return group, nil, ErrCartEmpty
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return group, nil, err
+	}
+	group.Id = hex.EncodeToString(id)
+
+	var vendors []string
+	vendorItems := make(map[string][]repo.CartItem)
+	for _, item := range items {
+		if _, ok := vendorItems[item.VendorId]; !ok {
+			vendors = append(vendors, item.VendorId)
+		}
+		vendorItems[item.VendorId] = append(vendorItems[item.VendorId], item)
+	}
+
+	var failed []CheckoutFailure
+	for _, vendorId := range vendors {
+		purchase := &PurchaseData{
+			ShipTo:               data.ShipTo,
+			Address:              data.Address,
+			City:                 data.City,
+			State:                data.State,
+			PostalCode:           data.PostalCode,
+			CountryCode:          data.CountryCode,
+			AddressNotes:         data.AddressNotes,
+			AlternateContactInfo: data.AlternateContactInfo,
+			RefundAddress:        data.RefundAddress,
+			Moderator:            data.Moderators[vendorId],
+		}
+		for _, ci := range vendorItems[vendorId] {
+			purchase.Items = append(purchase.Items, cartPurchaseItem(ci))
+		}
+		orderId, paymentAddress, amount, _, err := n.Purchase(purchase)
+		if err != nil {
+			log.Errorf("Error checking out the cart for vendor %s: %s", vendorId, err.Error())
+			failed = append(failed, CheckoutFailure{VendorId: vendorId, Error: err.Error()})
+			continue
+		}
+		group.Orders = append(group.Orders, repo.CheckoutGroupOrder{
+			OrderId:        orderId,
+			VendorId:       vendorId,
+			PaymentAddress: paymentAddress,
+			Amount:         amount,
+		})
+		for _, ci := range vendorItems[vendorId] {
+			if err := n.Datastore.Cart().Delete(ci.Id); err != nil {
+				log.Errorf("Error removing item %s from the cart: %s", ci.Id, err.Error())
+			}
+		}
+	}
+	if len(group.Orders) == 0 {
+		return group, failed, errors.New("No orders could be placed")
+	}
+	if err := n.Datastore.CheckoutGroups().Put(group); err != nil {
+		return group, failed, err
+	}
+	return group, failed, nil
+}
+
+/* Pay every unfunded order in a checkout group. The orders must all be paid in the
+   same currency. They're paid with a single transaction if the wallet can pay several
+   addresses at once and with a transaction per order if it can't. Returns the txids of
+   the payments made. */
+func (n *OpenBazaarNode) FundCheckoutGroup(groupId string, feeLevel spvwallet.FeeLevel) ([]string, error) {
+	checkoutFundingLock.Lock()
+	if checkoutFunding[groupId] {
+		checkoutFundingLock.Unlock()
+		return nil, ErrCheckoutGroupFunding
+	}
+	checkoutFunding[groupId] = true
+	checkoutFundingLock.Unlock()
+	defer func() {
+		checkoutFundingLock.Lock()
+		delete(checkoutFunding, groupId)
+		checkoutFundingLock.Unlock()
+	}()
+
+	group, err := n.Datastore.CheckoutGroups().Get(groupId)
+	if err != nil {
+		return nil, err
+	}
+	var wal bitcoin.BitcoinWallet
+	var orders []repo.CheckoutGroupOrder
+	var outputs []bitcoin.SpendOutput
+	for _, o := range group.Orders {
+		if o.FundingTxid != "" {
+			continue
+		}
+		contract, _, funded, _, _, err := n.Datastore.Purchases().GetByOrderId(o.OrderId)
+		if err != nil {
+			return nil, fmt.Errorf("Order %s not found", o.OrderId)
+		}
+		if funded {
+			continue
+		}
+		w, err := n.WalletForContract(contract)
+		if err != nil {
+			return nil, err
+		}
+		if wal == nil {
+			wal = w
+		} else if w.CurrencyCode() != wal.CurrencyCode() {
+			return nil, errors.New("Orders paid in different currencies can't be funded together")
+		}
+		addr, err := btc.DecodeAddress(o.PaymentAddress, wal.Params())
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, o)
+		outputs = append(outputs, bitcoin.SpendOutput{Address: addr, Amount: int64(o.Amount)})
+	}
+	if len(outputs) == 0 {
+		return nil, ErrCheckoutGroupFunded
+	}
+	if spender, ok := wal.(bitcoin.MultiSpender); ok {
+		txid, err := spender.SpendMulti(outputs, feeLevel)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range orders {
+			if err := n.Datastore.CheckoutGroups().SetFundingTxid(o.OrderId, txid.String()); err != nil {
+				return nil, err
+			}
+		}
+		return []string{txid.String()}, nil
+	}
+
+	// Each payment is recorded as it's made so a failure part way through can be retried without paying twice
+	var txids []string
+	for i, o := range orders {
+		txid, err := wal.Spend(outputs[i].Amount, outputs[i].Address, feeLevel)
+		if err != nil {
+			return txids, err
+		}
+		txids = append(txids, txid.String())
+		if err := n.Datastore.CheckoutGroups().SetFundingTxid(o.OrderId, txid.String()); err != nil {
+			return txids, err
+		}
+	}
+	return txids, nil
+}
+
+func cartPurchaseItem(ci repo.CartItem) item {
+	i := item{
+		ListingHash: ci.ListingHash,
+		Quantity:    ci.Quantity,
+		Shipping:    shippingOption{Name: ci.Shipping.Name, Service: ci.Shipping.Service},
+		Memo:        ci.Memo,
+		Coupons:     ci.Coupons,
+	}
+	for _, o := range ci.Options {
+		i.Options = append(i.Options, option{Name: o.Name, Value: o.Value})
+	}
+	return i
+}
@@ -0,0 +1,296 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	libp2p "gx/ipfs/QmPGxZ1DP2w45WcogpW1h43BvseXbfke9N91qotpoQcUeS/go-libp2p-crypto"
+	ma "gx/ipfs/QmSWLfmj5frN9xVLMMN846dMDriy5wN5jeghUm7aTW3DAG/go-multiaddr"
+	peer "gx/ipfs/QmWUswjn261LSyVxWAEpMVtPdy8zmKBJJfBpG3Qdpa8ZsE/go-libp2p-peer"
+	multihash "gx/ipfs/QmbZ6Cee2uHjG7hf19qLHppgKDRtaG4CVtMzdmK9VCVqLu/go-multihash"
+	"io"
+	"io/ioutil"
+	gonet "net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	rep "github.com/OpenBazaar/openbazaar-go/net/repointer"
+	"github.com/ipfs/go-ipfs/routing/dht"
+	"golang.org/x/net/context"
+)
+
+const (
+	// Channel pointers are published under the full key like moderator pointers
+	ChannelPointerPrefixLength = 64
+	ChannelNameMaxCharacters   = WordMaxCharacters
+	ChannelTextMaxCharacters   = 1000
+
+	// How far in the future a post can be dated to allow for the poster's clock being ahead
+	ChannelPostClockSkew = 5 * time.Minute
+
+	/* The largest stored post that will be read. A character of text takes up to six
+	   bytes once escaped in JSON and the post is base64 encoded in the signed post,
+	   leaving eight bytes a character. The rest covers the other fields and signature. */
+	channelPostMaxSize = 8*ChannelTextMaxCharacters + 4096
+
+	// How many posts are fetched at once
+	channelPostFetchers = 8
+)
+
+var ErrChannelUnavailable = errors.New("Channels need the DHT")
+
+// A post to a channel. It's signed by the node which made it.
+type ChannelPost struct {
+	Channel     string    `json:"channel"`
+	PeerId      string    `json:"peerId"`
+	ListingHash string    `json:"listingHash,omitempty"`
+	Text        string    `json:"text"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+/* A post as it's stored. The post is kept as the exact bytes which were signed along
+   with the public key of its author so it can be checked without a key lookup. */
+type signedChannelPost struct {
+	Post      []byte `json:"post"`
+	Pubkey    []byte `json:"pubkey"`
+	Signature []byte `json:"signature"`
+}
+
+// Channel names are matched ignoring case and surrounding whitespace
+func NormalizeChannel(name string) string {
+	return NormalizeTag(name)
+}
+
+// Return the ID pointers to posts in the channel are published under
+func ChannelPointerID(name string) (multihash.Multihash, error) {
+	h := sha256.Sum256([]byte("channel:" + NormalizeChannel(name)))
+	encoded, err := multihash.Encode(h[:], multihash.SHA2_256)
+	if err != nil {
+		return nil, err
+	}
+	return multihash.Cast(encoded)
+}
+
+/* Sign a post, save it with the offline message storage and announce it with a
+   channel pointer. The pointer is republished until the post is older than
+   ChannelPointerTTL. */
+func (n *OpenBazaarNode) PostToChannel(channel, listingHash, text string) (ChannelPost, error) {
+	channel = NormalizeChannel(channel)
+	post := ChannelPost{
+		Channel:     channel,
+		PeerId:      n.IpfsNode.Identity.Pretty(),
+		ListingHash: listingHash,
+		Text:        text,
+		Timestamp:   time.Now().UTC(),
+	}
+	if channel == "" || len(channel) > ChannelNameMaxCharacters {
+		return post, fmt.Errorf("A channel name must be given and be no longer than %d characters", ChannelNameMaxCharacters)
+	}
+	if text == "" && listingHash == "" {
+		return post, errors.New("A post needs text or a listing hash")
+	}
+	if len(text) > ChannelTextMaxCharacters {
+		return post, fmt.Errorf("Post text is longer than the max of %d characters", ChannelTextMaxCharacters)
+	}
+	if listingHash != "" {
+		if _, err := multihash.FromB58String(listingHash); err != nil {
+			return post, errors.New("Listing hash is not a valid multihash")
+		}
+	}
+	if _, ok := n.IpfsNode.Routing.(*dht.IpfsDHT); !ok {
+		return post, ErrChannelUnavailable
+	}
+	signed, err := signChannelPost(n.IpfsNode.PrivateKey, post)
+	if err != nil {
+		return post, err
+	}
+	addr, err := n.MessageStorage.Store(n.IpfsNode.Identity, signed)
+	if err != nil {
+		return post, err
+	}
+	id, err := ChannelPointerID(channel)
+	if err != nil {
+		return post, err
+	}
+	pointer, err := ipfs.PublishPointer(n.IpfsNode, context.Background(), id, ChannelPointerPrefixLength, addr)
+	if err != nil {
+		return post, err
+	}
+	pointer.Purpose = ipfs.CHANNEL
+	return post, n.Datastore.Pointers().Put(pointer)
+}
+
+/* Return the posts in a channel from the last ChannelPointerTTL, newest first. Posts
+   with a bad signature, from another channel or by a banned peer are dropped. */
+func (n *OpenBazaarNode) GetChannelPosts(channel string, limit int) ([]ChannelPost, error) {
+	channel = NormalizeChannel(channel)
+	posts := []ChannelPost{}
+	ipfsDHT, ok := n.IpfsNode.Routing.(*dht.IpfsDHT)
+	if !ok {
+		return posts, ErrChannelUnavailable
+	}
+	id, err := ChannelPointerID(channel)
+	if err != nil {
+		return posts, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	var (
+		lock     sync.Mutex
+		wg       sync.WaitGroup
+		seen     = make(map[string]bool)
+		fetchers = make(chan struct{}, channelPostFetchers)
+	)
+	for p := range ipfs.FindPointersAsync(ipfsDHT, ctx, id, ChannelPointerPrefixLength) {
+		if len(p.Addrs) == 0 || seen[p.Addrs[0].String()] {
+			continue
+		}
+		seen[p.Addrs[0].String()] = true
+		wg.Add(1)
+		fetchers <- struct{}{}
+		go func(addr ma.Multiaddr) {
+			defer func() {
+				<-fetchers
+				wg.Done()
+			}()
+			data, err := n.fetchChannelPost(addr)
+			if err != nil {
+				return
+			}
+			post, err := verifyChannelPost(data, channel)
+			if err != nil {
+				log.Debugf("Dropping channel post at %s: %s", addr.String(), err.Error())
+				return
+			}
+			pid, err := peer.IDB58Decode(post.PeerId)
+			if err != nil || (n.BanManager != nil && n.BanManager.IsBanned(pid)) {
+				return
+			}
+			if time.Since(post.Timestamp) > rep.ChannelPointerTTL {
+				return
+			}
+			lock.Lock()
+			posts = append(posts, post)
+			lock.Unlock()
+		}(p.Addrs[0])
+	}
+	wg.Wait()
+	sort.Slice(posts, func(i, j int) bool {
+		return posts[i].Timestamp.After(posts[j].Timestamp)
+	})
+	if limit >= 0 && len(posts) > limit {
+		posts = posts[:limit]
+	}
+	return posts, nil
+}
+
+// Fetch a post from the location in its pointer. It's either on IPFS or an HTTPS URL encoded in the multiaddr.
+func (n *OpenBazaarNode) fetchChannelPost(addr ma.Multiaddr) ([]byte, error) {
+	protocols := addr.Protocols()
+	if len(protocols) == 1 && protocols[0].Code == ma.P_IPFS {
+		data, err := ipfs.Cat(n.Context, addr.String())
+		if err == nil && len(data) > channelPostMaxSize {
+			return nil, errors.New("Channel post is too large")
+		}
+		return data, err
+	}
+	if len(protocols) != 2 || protocols[0].Code != ma.P_IPFS || protocols[1].Code != ma.P_HTTPS {
+		return nil, errors.New("Unknown channel post address")
+	}
+	enc, err := addr.ValueForProtocol(ma.P_IPFS)
+	if err != nil {
+		return nil, err
+	}
+	mh, err := multihash.FromB58String(enc)
+	if err != nil {
+		return nil, err
+	}
+	d, err := multihash.Decode(mh)
+	if err != nil {
+		return nil, err
+	}
+	dial := gonet.Dial
+	if n.TorDialer != nil {
+		dial = n.TorDialer.Dial
+	}
+	client := &http.Client{Transport: &http.Transport{Dial: dial}, Timeout: time.Minute}
+	resp, err := client.Get(string(d.Digest))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return readChannelPost(resp)
+}
+
+// Read a post fetched over HTTPS. Posts are small so a larger body isn't read past the limit.
+func readChannelPost(resp *http.Response) ([]byte, error) {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("Fetching channel post returned status %d", resp.StatusCode)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, channelPostMaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > channelPostMaxSize {
+		return nil, errors.New("Channel post is too large")
+	}
+	return data, nil
+}
+
+func signChannelPost(key libp2p.PrivKey, post ChannelPost) ([]byte, error) {
+	ser, err := json.Marshal(post)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := key.Sign(ser)
+	if err != nil {
+		return nil, err
+	}
+	pubkey, err := key.GetPublic().Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(signedChannelPost{ser, pubkey, sig})
+}
+
+// Check the signature on a stored post and that it was made by the peer it names in the given channel
+func verifyChannelPost(data []byte, channel string) (ChannelPost, error) {
+	var signed signedChannelPost
+	var post ChannelPost
+	if err := json.Unmarshal(data, &signed); err != nil {
+		return post, err
+	}
+	pubkey, err := libp2p.UnmarshalPublicKey(signed.Pubkey)
+	if err != nil {
+		return post, err
+	}
+	valid, err := pubkey.Verify(signed.Post, signed.Signature)
+	if err != nil {
+		return post, err
+	}
+	if !valid {
+		return post, invalidSigError{}
+	}
+	if err := json.Unmarshal(signed.Post, &post); err != nil {
+		return post, err
+	}
+	pid, err := peer.IDB58Decode(post.PeerId)
+	if err != nil {
+		return post, err
+	}
+	if !pid.MatchesPublicKey(pubkey) {
+		return post, matchKeyError{}
+	}
+	if post.Channel != channel {
+		return post, errors.New("Post is for another channel")
+	}
+	// A post dated in the future would stay at the top of the channel until that time
+	if post.Timestamp.After(time.Now().Add(ChannelPostClockSkew)) {
+		return post, errors.New("Post is dated in the future")
+	}
+	return post, nil
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	libp2p "gx/ipfs/QmPGxZ1DP2w45WcogpW1h43BvseXbfke9N91qotpoQcUeS/go-libp2p-crypto"
+	peer "gx/ipfs/QmWUswjn261LSyVxWAEpMVtPdy8zmKBJJfBpG3Qdpa8ZsE/go-libp2p-peer"
+)
+
+func TestChannelPostSignatures(t *testing.T) {
+	priv, pub, err := libp2p.GenerateKeyPair(libp2p.Ed25519, 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, err := peer.IDFromPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	post := ChannelPost{Channel: "shirts", PeerId: pid.Pretty(), Text: "New stock", Timestamp: time.Now().UTC()}
+	signed, err := signChannelPost(priv, post)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret, err := verifyChannelPost(signed, "shirts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.Text != post.Text || ret.PeerId != post.PeerId {
+		t.Error("Returned wrong post")
+	}
+	if _, err := verifyChannelPost(signed, "hats"); err == nil {
+		t.Error("Accepted a post from another channel")
+	}
+
+	// A post naming a peer other than the signer is rejected
+	other, _, err := libp2p.GenerateKeyPair(libp2p.Ed25519, 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, err := signChannelPost(other, post)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifyChannelPost(forged, "shirts"); err == nil {
+		t.Error("Accepted a post signed by another peer")
+	}
+
+	// Posts can be a little ahead of our clock but not dated further in the future
+	post.Timestamp = time.Now().Add(time.Minute)
+	skewed, err := signChannelPost(priv, post)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifyChannelPost(skewed, "shirts"); err != nil {
+		t.Error(err)
+	}
+	post.Timestamp = time.Now().Add(time.Hour)
+	future, err := signChannelPost(priv, post)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifyChannelPost(future, "shirts"); err == nil {
+		t.Error("Accepted a post dated in the future")
+	}
+}
+
+func TestChannelPointerID(t *testing.T) {
+	a, err := ChannelPointerID("Shirts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := TagPointerID("shirts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.B58String() == b.B58String() {
+		t.Error("Channel and tag pointer IDs collide")
+	}
+}
+
+func TestReadChannelPost(t *testing.T) {
+	priv, pub, err := libp2p.GenerateKeyPair(libp2p.Ed25519, 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, err := peer.IDFromPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every character of this text is escaped in JSON so it's the largest a valid post can be
+	post := ChannelPost{Channel: "shirts", PeerId: pid.Pretty(), Text: strings.Repeat("<", ChannelTextMaxCharacters), Timestamp: time.Now()}
+	data, err := signChannelPost(priv, post)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := func(status int, body []byte) *http.Response {
+		return &http.Response{StatusCode: status, Body: ioutil.NopCloser(bytes.NewReader(body))}
+	}
+	read, err := readChannelPost(response(200, data))
+	if err != nil || !bytes.Equal(read, data) {
+		t.Error("Failed to read the largest valid post", err)
+	}
+	if _, err := readChannelPost(response(404, data)); err == nil {
+		t.Error("Read a post from an error response")
+	}
+	if _, err := readChannelPost(response(200, make([]byte, channelPostMaxSize+1))); err == nil {
+		t.Error("Read a post over the size limit")
+	}
+}
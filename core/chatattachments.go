@@ -0,0 +1,178 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	multihash "gx/ipfs/QmbZ6Cee2uHjG7hf19qLHppgKDRtaG4CVtMzdmK9VCVqLu/go-multihash"
+	"image"
+	"image/jpeg"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/net"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+const (
+	// Attachments are fetched over IPFS when they're viewed so are kept small
+	ChatAttachmentMaxBytes = 10 << 20
+	ChatMaxAttachments     = 5
+	ChatFilenameMaxLength  = 255
+
+	// Thumbnails are scaled to cover this size like small product images
+	ChatThumbnailWidth  = 228
+	ChatThumbnailHeight = 228
+
+	// Images with more pixels than this don't get a thumbnail as decoding them takes too much memory
+	chatThumbnailMaxPixels = 50000000
+)
+
+var ErrNoThumbnail = errors.New("Attachment has no thumbnail")
+
+/* Encrypt a file with a new random key and add it to IPFS. Images also get a thumbnail
+   encrypted with the same key. The returned attachment carries the key so it must only
+   be sent inside an encrypted message. */
+func (n *OpenBazaarNode) AddChatAttachment(filename string, data []byte) (*pb.Chat_Attachment, error) {
+	if len(data) == 0 {
+		return nil, errors.New("Attachment is empty")
+	}
+	if len(data) > ChatAttachmentMaxBytes {
+		return nil, fmt.Errorf("Attachments can be at most %d bytes", ChatAttachmentMaxBytes)
+	}
+	filename = path.Base(filename)
+	if len(filename) > ChatFilenameMaxLength {
+		return nil, errors.New("Filename is too long")
+	}
+	key, err := net.NewSymmetricKey()
+	if err != nil {
+		return nil, err
+	}
+	hash, err := n.addEncryptedFile(key, data)
+	if err != nil {
+		return nil, err
+	}
+	attachment := &pb.Chat_Attachment{
+		Hash:      hash,
+		Key:       key,
+		Filename:  filename,
+		MediaType: http.DetectContentType(data),
+		Size:      uint64(len(data)),
+	}
+	if strings.HasPrefix(attachment.MediaType, "image/") {
+		thumbnail, err := chatThumbnail(data)
+		if err != nil {
+			log.Warningf("Error creating thumbnail for %s: %s", filename, err.Error())
+			return attachment, nil
+		}
+		attachment.Thumbnail, err = n.addEncryptedFile(key, thumbnail)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return attachment, nil
+}
+
+// Check the attachments of a chat message we've been sent are ones we'd have sent ourselves
+func ValidateChatAttachments(attachments []*pb.Chat_Attachment) error {
+	if len(attachments) > ChatMaxAttachments {
+		return fmt.Errorf("A message can have at most %d attachments", ChatMaxAttachments)
+	}
+	for _, a := range attachments {
+		if _, err := multihash.FromB58String(a.Hash); err != nil {
+			return errors.New("Invalid attachment hash")
+		}
+		if a.Thumbnail != "" {
+			if _, err := multihash.FromB58String(a.Thumbnail); err != nil {
+				return errors.New("Invalid attachment thumbnail hash")
+			}
+		}
+		if len(a.Key) != net.SymmetricKeyBytes {
+			return errors.New("Invalid attachment key")
+		}
+		if a.Size > ChatAttachmentMaxBytes {
+			return errors.New("Attachment is too large")
+		}
+		if len(a.Filename) > ChatFilenameMaxLength {
+			return errors.New("Attachment filename is too long")
+		}
+	}
+	return nil
+}
+
+// Save the attachments of a chat message so they can be fetched later
+func (n *OpenBazaarNode) SaveChatAttachments(messageId string, attachments []*pb.Chat_Attachment) error {
+	for _, a := range attachments {
+		err := n.Datastore.Chat().PutAttachment(repo.ChatAttachment{
+			Id:        a.Hash,
+			MessageId: messageId,
+			Filename:  a.Filename,
+			MediaType: a.MediaType,
+			Size:      int64(a.Size),
+			Thumbnail: a.Thumbnail,
+			Key:       a.Key,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Fetch an attachment or its thumbnail from IPFS and decrypt it
+func (n *OpenBazaarNode) GetChatAttachment(attachment repo.ChatAttachment, thumbnail bool) ([]byte, error) {
+	hash := attachment.Id
+	if thumbnail {
+		if attachment.Thumbnail == "" {
+			return nil, ErrNoThumbnail
+		}
+		hash = attachment.Thumbnail
+	}
+	ciphertext, err := ipfs.Cat(n.Context, hash)
+	if err != nil {
+		return nil, err
+	}
+	return net.DecryptSymmetric(attachment.Key, ciphertext)
+}
+
+func (n *OpenBazaarNode) addEncryptedFile(key, data []byte) (string, error) {
+	ciphertext, err := net.EncryptSymmetric(key, data)
+	if err != nil {
+		return "", err
+	}
+	f, err := ioutil.TempFile("", "attachment")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(ciphertext)
+	f.Close()
+	if err != nil {
+		return "", err
+	}
+	return ipfs.AddFile(n.Context, f.Name())
+}
+
+func chatThumbnail(data []byte) ([]byte, error) {
+	imgCfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if imgCfg.Width*imgCfg.Height > chatThumbnailMaxPixels {
+		return nil, errors.New("Image is too large")
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, resizeImage(img, &imgCfg, ChatThumbnailWidth, ChatThumbnailHeight), nil); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
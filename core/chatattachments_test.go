@@ -0,0 +1,66 @@
+package core
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+)
+
+func TestChatThumbnail(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 800, 400))
+	for x := 0; x < 800; x++ {
+		img.Set(x, x%400, color.RGBA{255, 0, 0, 255})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	thumbnail, err := chatThumbnail(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(thumbnail))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "jpeg" || cfg.Height != ChatThumbnailHeight || cfg.Width != ChatThumbnailHeight*2 {
+		t.Errorf("Thumbnail is a %dx%d %s", cfg.Width, cfg.Height, format)
+	}
+	if _, err := chatThumbnail([]byte("not an image")); err == nil {
+		t.Error("Created a thumbnail for something which isn't an image")
+	}
+}
+
+func TestValidateChatAttachments(t *testing.T) {
+	valid := &pb.Chat_Attachment{
+		Hash:     "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG",
+		Key:      make([]byte, 32),
+		Filename: "photo.jpg",
+		Size:     1024,
+	}
+	if err := ValidateChatAttachments([]*pb.Chat_Attachment{valid}); err != nil {
+		t.Error(err)
+	}
+	badHash := *valid
+	badHash.Hash = "not a hash"
+	shortKey := *valid
+	shortKey.Key = shortKey.Key[:16]
+	tooLarge := *valid
+	tooLarge.Size = ChatAttachmentMaxBytes + 1
+	for _, a := range []*pb.Chat_Attachment{&badHash, &shortKey, &tooLarge} {
+		if err := ValidateChatAttachments([]*pb.Chat_Attachment{a}); err == nil {
+			t.Errorf("Accepted invalid attachment %v", a)
+		}
+	}
+	tooMany := make([]*pb.Chat_Attachment, ChatMaxAttachments+1)
+	for i := range tooMany {
+		tooMany[i] = valid
+	}
+	if err := ValidateChatAttachments(tooMany); err == nil {
+		t.Error("Accepted too many attachments")
+	}
+}
@@ -19,6 +19,10 @@ import (
 )
 
 func (n *OpenBazaarNode) NewOrderConfirmation(contract *pb.RicardianContract, addressRequest bool) (*pb.RicardianContract, error) {
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return nil, err
+	}
 	oc := new(pb.OrderConfirmation)
 	// Calculate order ID
 	orderID, err := n.CalcOrderId(contract.BuyerOrder)
@@ -27,7 +31,7 @@ func (n *OpenBazaarNode) NewOrderConfirmation(contract *pb.RicardianContract, ad
 	}
 	oc.OrderID = orderID
 	if addressRequest {
-		addr := n.Wallet.NewAddress(spvwallet.EXTERNAL)
+		addr := wal.NewAddress(spvwallet.EXTERNAL)
 		oc.PaymentAddress = addr.EncodeAddress()
 	}
 
@@ -65,7 +69,7 @@ func (n *OpenBazaarNode) NewOrderConfirmation(contract *pb.RicardianContract, ad
 			oc.RatingSignatures = append(oc.RatingSignatures, rs)
 		}
 		oc.PaymentAddress = contract.BuyerOrder.Payment.Address
-		oc.PayoutFee = n.Wallet.GetFeePerByte(spvwallet.NORMAL)
+		oc.PayoutFee = wal.GetFeePerByte(spvwallet.NORMAL)
 	}
 
 	oc.RequestedAmount, err = n.CalculateOrderTotal(contract)
@@ -81,7 +85,11 @@ func (n *OpenBazaarNode) NewOrderConfirmation(contract *pb.RicardianContract, ad
 }
 
 func (n *OpenBazaarNode) ConfirmOfflineOrder(contract *pb.RicardianContract, records []*spvwallet.TransactionRecord) error {
-	contract, err := n.NewOrderConfirmation(contract, false)
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return err
+	}
+	contract, err = n.NewOrderConfirmation(contract, false)
 	if err != nil {
 		return err
 	}
@@ -112,7 +120,7 @@ func (n *OpenBazaarNode) ConfirmOfflineOrder(contract *pb.RicardianContract, rec
 			return err
 		}
 		parentFP := []byte{0x00, 0x00, 0x00, 0x00}
-		mPrivKey := n.Wallet.MasterPrivateKey()
+		mPrivKey := wal.MasterPrivateKey()
 		if err != nil {
 			return err
 		}
@@ -121,7 +129,7 @@ func (n *OpenBazaarNode) ConfirmOfflineOrder(contract *pb.RicardianContract, rec
 			return err
 		}
 		hdKey := hd.NewExtendedKey(
-			n.Wallet.Params().HDPrivateKeyID[:],
+			wal.Params().HDPrivateKeyID[:],
 			mECKey.Serialize(),
 			chaincode,
 			parentFP,
@@ -134,7 +142,7 @@ func (n *OpenBazaarNode) ConfirmOfflineOrder(contract *pb.RicardianContract, rec
 			return err
 		}
 		redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
-		_, err = n.Wallet.SweepAddress(utxos, nil, vendorKey, &redeemScript, spvwallet.NORMAL)
+		_, err = wal.SweepAddress(utxos, nil, vendorKey, &redeemScript, spvwallet.NORMAL)
 		if err != nil {
 			return err
 		}
@@ -144,10 +152,15 @@ func (n *OpenBazaarNode) ConfirmOfflineOrder(contract *pb.RicardianContract, rec
 		return err
 	}
 	n.Datastore.Sales().Put(contract.VendorOrderConfirmation.OrderID, *contract, pb.OrderState_FUNDED, false)
+	go n.OnSaleFunded(contract.VendorOrderConfirmation.OrderID)
 	return nil
 }
 
 func (n *OpenBazaarNode) RejectOfflineOrder(contract *pb.RicardianContract, records []*spvwallet.TransactionRecord) error {
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return err
+	}
 	orderId, err := n.CalcOrderId(contract.BuyerOrder)
 	if err != nil {
 		return err
@@ -173,7 +186,7 @@ func (n *OpenBazaarNode) RejectOfflineOrder(contract *pb.RicardianContract, reco
 			}
 		}
 
-		refundAddress, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, n.Wallet.Params())
+		refundAddress, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, wal.Params())
 		if err != nil {
 			return err
 		}
@@ -191,7 +204,7 @@ func (n *OpenBazaarNode) RejectOfflineOrder(contract *pb.RicardianContract, reco
 			return err
 		}
 		parentFP := []byte{0x00, 0x00, 0x00, 0x00}
-		mPrivKey := n.Wallet.MasterPrivateKey()
+		mPrivKey := wal.MasterPrivateKey()
 		if err != nil {
 			return err
 		}
@@ -200,7 +213,7 @@ func (n *OpenBazaarNode) RejectOfflineOrder(contract *pb.RicardianContract, reco
 			return err
 		}
 		hdKey := hd.NewExtendedKey(
-			n.Wallet.Params().HDPrivateKeyID[:],
+			wal.Params().HDPrivateKeyID[:],
 			mECKey.Serialize(),
 			chaincode,
 			parentFP,
@@ -214,7 +227,7 @@ func (n *OpenBazaarNode) RejectOfflineOrder(contract *pb.RicardianContract, reco
 		}
 		redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
 
-		signatures, err := n.Wallet.CreateMultisigSignature(ins, []spvwallet.TransactionOutput{output}, vendorKey, redeemScript, contract.BuyerOrder.RefundFee)
+		signatures, err := wal.CreateMultisigSignature(ins, []spvwallet.TransactionOutput{output}, vendorKey, redeemScript, contract.BuyerOrder.RefundFee)
 		if err != nil {
 			return err
 		}
@@ -234,6 +247,10 @@ func (n *OpenBazaarNode) RejectOfflineOrder(contract *pb.RicardianContract, reco
 }
 
 func (n *OpenBazaarNode) ValidateOrderConfirmation(contract *pb.RicardianContract, validateAddress bool) error {
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return err
+	}
 	orderID, err := n.CalcOrderId(contract.BuyerOrder)
 	if err != nil {
 		return err
@@ -289,7 +306,7 @@ func (n *OpenBazaarNode) ValidateOrderConfirmation(contract *pb.RicardianContrac
 		}
 	}
 	if validateAddress {
-		_, err = btcutil.DecodeAddress(contract.VendorOrderConfirmation.PaymentAddress, n.Wallet.Params())
+		_, err = btcutil.DecodeAddress(contract.VendorOrderConfirmation.PaymentAddress, wal.Params())
 		if err != nil {
 			return err
 		}
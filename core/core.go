@@ -66,6 +66,9 @@ type OpenBazaarNode struct {
 	// Bitcoin wallet implementation
 	Wallet bitcoin.BitcoinWallet
 
+	// A wallet for each cryptocurrency we accept, keyed by currency code. Wallet is the default.
+	Wallets *bitcoin.WalletRegistry
+
 	// Storage for our outgoing messages
 	MessageStorage sto.OfflineMessagingStorage
 
@@ -81,6 +84,9 @@ type OpenBazaarNode struct {
 	// A service that periodically fetches and caches the bitcoin exchange rates
 	ExchangeRates bitcoin.ExchangeRates
 
+	// Exchange rates for the coins of the other wallets, keyed by the wallet's currency code
+	CoinExchangeRates map[string]bitcoin.ExchangeRates
+
 	// An optional gateway URL where we can crosspost data to ensure persistence
 	CrosspostGateways []*url.URL
 
@@ -94,6 +100,17 @@ type OpenBazaarNode struct {
 	BanManager *net.BanManager
 }
 
+// Cleanly disconnect from every wallet
+func (n *OpenBazaarNode) CloseWallets() {
+	if n.Wallets == nil {
+		n.Wallet.Close()
+		return
+	}
+	for _, wal := range n.Wallets.All() {
+		wal.Close()
+	}
+}
+
 // Unpin the current node repo, re-add it, then publish to IPNS
 func (n *OpenBazaarNode) SeedNode() error {
 	ipfs.UnPinDir(n.Context, n.RootHash)
@@ -0,0 +1,275 @@
+package core
+
+import (
+	"errors"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+	"github.com/golang/protobuf/ptypes"
+	"golang.org/x/net/context"
+	crypto "gx/ipfs/QmPGxZ1DP2w45WcogpW1h43BvseXbfke9N91qotpoQcUeS/go-libp2p-crypto"
+	peer "gx/ipfs/QmWUswjn261LSyVxWAEpMVtPdy8zmKBJJfBpG3Qdpa8ZsE/go-libp2p-peer"
+	"time"
+)
+
+const (
+	CampaignActive    = "ACTIVE"
+	CampaignSucceeded = "SUCCEEDED"
+	CampaignFailed    = "FAILED"
+)
+
+type Campaign struct {
+	Slug     string        `json:"slug"`
+	Goal     uint64        `json:"goal"`
+	Raised   uint64        `json:"raised"`
+	Deadline time.Time     `json:"deadline"`
+	State    string        `json:"state"`
+	Pledges  []repo.Pledge `json:"pledges"`
+}
+
+func isCrowdfund(contract *pb.RicardianContract) bool {
+	for _, listing := range contract.VendorListings {
+		if listing.Metadata.ContractType == pb.Listing_Metadata_CROWD_FUND {
+			return true
+		}
+	}
+	return false
+}
+
+/* A direct pledge can't use an address request as the funds must stay out of the
+   vendor's wallet until the campaign ends. Instead we generate a 1 of 2 address
+   which the vendor sweeps if the goal is met or refunds to us if it isn't. */
+func (n *OpenBazaarNode) pledge(contract *pb.RicardianContract) (orderId string, paymentAddress string, paymentAmount uint64, vendorOnline bool, err error) {
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return "", "", 0, false, err
+	}
+	payment, err := n.newMultisigPayment(contract, "")
+	if err != nil {
+		return "", "", 0, false, err
+	}
+	total, rates, err := n.CalculateOrderTotalWithRates(contract)
+	if err != nil {
+		return "", "", 0, false, err
+	}
+	payment.Amount = total
+	contract.BuyerOrder.Payment = payment
+	contract, err = n.SignOrder(contract)
+	if err != nil {
+		return "", "", 0, false, err
+	}
+	orderId, err = n.CalcOrderId(contract.BuyerOrder)
+	if err != nil {
+		return "", "", 0, false, err
+	}
+
+	addr, err := btcutil.DecodeAddress(payment.Address, wal.Params())
+	if err != nil {
+		return "", "", 0, false, err
+	}
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return "", "", 0, false, err
+	}
+	wal.AddWatchedScript(script)
+
+	// Send to the vendor, falling back to offline messaging
+	peerId, err := peer.IDB58Decode(contract.VendorListings[0].VendorID.Guid)
+	if err != nil {
+		return "", "", 0, false, err
+	}
+	any, err := ptypes.MarshalAny(contract)
+	if err != nil {
+		return "", "", 0, false, err
+	}
+	m := pb.Message{
+		MessageType: pb.Message_ORDER,
+		Payload:     any,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	vendorOnline = true
+	if err := n.Service.SendMessage(ctx, peerId, &m); err != nil {
+		log.Warningf("Vendor %s is offline, sending offline order message", contract.VendorListings[0].VendorID.Guid)
+		vendorOnline = false
+		k, err := crypto.UnmarshalPublicKey(contract.VendorListings[0].VendorID.Pubkeys.Guid)
+		if err != nil {
+			return "", "", 0, false, err
+		}
+		err = n.SendOfflineMessage(peerId, &k, &m)
+		if err != nil {
+			return "", "", 0, false, err
+		}
+	}
+	n.Datastore.Purchases().Put(orderId, *contract, pb.OrderState_PENDING, false)
+	n.Datastore.Purchases().PutExchangeRates(orderId, rates)
+	return orderId, payment.Address, payment.Amount, vendorOnline, nil
+}
+
+// Validate a pledge received on one of our crowdfund listings
+func (n *OpenBazaarNode) ValidatePledge(contract *pb.RicardianContract) error {
+	if len(contract.VendorListings) != 1 {
+		return errors.New("A pledge must be for a single campaign")
+	}
+	listing := contract.VendorListings[0]
+	if listing.Metadata.Expiry == nil || time.Unix(listing.Metadata.Expiry.Seconds, 0).Before(time.Now()) {
+		return errors.New("Campaign has ended")
+	}
+	var err error
+	switch contract.BuyerOrder.Payment.Method {
+	case pb.Order_Payment_DIRECT:
+		err = n.ValidateDirectPaymentAddress(contract)
+	case pb.Order_Payment_MODERATED:
+		err = n.ValidateModeratedPaymentAddress(contract)
+	default:
+		err = errors.New("Pledges must include a direct or moderated payment address")
+	}
+	if err != nil {
+		return err
+	}
+	total, err := n.CalculateOrderTotal(contract)
+	if err != nil {
+		return err
+	}
+	if !n.ValidatePaymentAmount(total, contract.BuyerOrder.Payment.Amount) {
+		return errors.New("Calculated a different payment amount")
+	}
+	return nil
+}
+
+// Return the funding goal, amount raised and pledges for one of our campaigns
+func (n *OpenBazaarNode) CampaignProgress(slug string) (*Campaign, error) {
+	contract, err := n.GetListingFromSlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	listing := contract.VendorListings[0]
+	if listing.Metadata.ContractType != pb.Listing_Metadata_CROWD_FUND {
+		return nil, errors.New("Listing is not a crowdfund")
+	}
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return nil, err
+	}
+	goal, err := n.getPriceInSatoshi(wal, listing.Metadata.PricingCurrency, listing.Metadata.FundingGoal)
+	if err != nil {
+		return nil, err
+	}
+	pledges, err := n.Datastore.Pledges().GetBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	if pledges == nil {
+		pledges = []repo.Pledge{}
+	}
+	campaign := &Campaign{
+		Slug:     slug,
+		Goal:     goal,
+		Raised:   raised(pledges),
+		Deadline: time.Unix(listing.Metadata.Expiry.Seconds, 0),
+		State:    CampaignActive,
+		Pledges:  pledges,
+	}
+	if campaign.Deadline.Before(time.Now()) {
+		if campaign.Raised >= campaign.Goal {
+			campaign.State = CampaignSucceeded
+		} else {
+			campaign.State = CampaignFailed
+		}
+	}
+	return campaign, nil
+}
+
+func raised(pledges []repo.Pledge) uint64 {
+	var total uint64
+	for _, p := range pledges {
+		if p.Funded {
+			total += p.Amount
+		}
+	}
+	return total
+}
+
+/* CloseExpiredCampaigns settles the open pledges on each crowdfund which has passed
+   its deadline. If the goal was met the funded pledges are claimed, otherwise they
+   are refunded to the buyers. Pledges which were never funded are canceled. */
+func (n *OpenBazaarNode) CloseExpiredCampaigns() {
+	index, err := n.getListingIndex()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, ld := range index {
+		if ld.ContractType != pb.Listing_Metadata_CROWD_FUND.String() {
+			continue
+		}
+		pledges, err := n.Datastore.Pledges().GetBySlug(ld.Slug)
+		if err != nil {
+			log.Errorf("Error loading pledges to %s: %s", ld.Slug, err.Error())
+			continue
+		}
+		var pending []repo.Pledge
+		for _, p := range pledges {
+			if p.State == repo.PledgePending.String() {
+				pending = append(pending, p)
+			}
+		}
+		if len(pending) == 0 {
+			continue
+		}
+		contract, err := n.GetListingFromSlug(ld.Slug)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		listing := contract.VendorListings[0]
+		if listing.Metadata.Expiry == nil || time.Unix(listing.Metadata.Expiry.Seconds, 0).After(time.Now()) {
+			continue
+		}
+		wal, err := n.WalletForContract(contract)
+		if err != nil {
+			log.Errorf("Error closing campaign %s: %s", ld.Slug, err.Error())
+			continue
+		}
+		goal, err := n.getPriceInSatoshi(wal, listing.Metadata.PricingCurrency, listing.Metadata.FundingGoal)
+		if err != nil {
+			log.Errorf("Error closing campaign %s: %s", ld.Slug, err.Error())
+			continue
+		}
+		n.settleCampaign(pending, raised(pledges) >= goal)
+	}
+}
+
+func (n *OpenBazaarNode) settleCampaign(pledges []repo.Pledge, goalMet bool) {
+	for _, p := range pledges {
+		if err := n.settlePledge(p, goalMet); err != nil {
+			log.Errorf("Error settling pledge %s: %s", p.OrderId, err.Error())
+		}
+	}
+}
+
+func (n *OpenBazaarNode) settlePledge(p repo.Pledge, goalMet bool) error {
+	if !p.Funded {
+		return n.Datastore.Pledges().UpdateState(p.OrderId, repo.PledgeCanceled)
+	}
+	contract, state, _, records, _, err := n.Datastore.Sales().GetByOrderId(p.OrderId)
+	if err != nil {
+		return err
+	}
+	if !goalMet {
+		err = n.RefundOrder(contract, records)
+		if err != nil {
+			return err
+		}
+		return n.Datastore.Pledges().UpdateState(p.OrderId, repo.PledgeRefunded)
+	}
+	// Direct and offline moderated pledges were never confirmed
+	if state == pb.OrderState_PENDING {
+		err = n.ConfirmOfflineOrder(contract, records)
+		if err != nil {
+			return err
+		}
+	}
+	return n.Datastore.Pledges().UpdateState(p.OrderId, repo.PledgeClaimed)
+}
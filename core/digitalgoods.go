@@ -0,0 +1,218 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	crypto "gx/ipfs/QmPGxZ1DP2w45WcogpW1h43BvseXbfke9N91qotpoQcUeS/go-libp2p-crypto"
+	multihash "gx/ipfs/QmbZ6Cee2uHjG7hf19qLHppgKDRtaG4CVtMzdmK9VCVqLu/go-multihash"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/net"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+const (
+	// Each order gets its own encrypted copy of the file so it's kept in memory while it's encrypted
+	DigitalAssetMaxBytes = 50 << 20
+
+	DigitalFilenameMaxLength = 255
+	LicenseKeyMaxLength      = 1000
+)
+
+var (
+	ErrListingNotFound = errors.New("Listing not found")
+	ErrNotDigitalGood  = errors.New("Listing is not a digital good")
+	ErrDeliveryMissing = errors.New("Digital delivery not found")
+)
+
+// A digital delivery with its license key decrypted
+type DigitalDelivery struct {
+	Slug       string `json:"slug"`
+	Url        string `json:"url,omitempty"`
+	Password   string `json:"password,omitempty"`
+	Filename   string `json:"filename,omitempty"`
+	MediaType  string `json:"mediaType,omitempty"`
+	Size       uint64 `json:"size,omitempty"`
+	LicenseKey string `json:"licenseKey,omitempty"`
+}
+
+// Save the file delivered automatically to buyers of a digital listing
+func (n *OpenBazaarNode) SetDigitalAsset(slug, filename string, data []byte) (repo.DigitalAsset, error) {
+	if err := n.checkDigitalListing(slug); err != nil {
+		return repo.DigitalAsset{}, err
+	}
+	if len(data) == 0 {
+		return repo.DigitalAsset{}, errors.New("File is empty")
+	}
+	if len(data) > DigitalAssetMaxBytes {
+		return repo.DigitalAsset{}, fmt.Errorf("Files can be at most %d bytes", DigitalAssetMaxBytes)
+	}
+	filename = path.Base(filename)
+	if filename == "." || filename == "/" || len(filename) > DigitalFilenameMaxLength {
+		return repo.DigitalAsset{}, errors.New("Invalid filename")
+	}
+	asset := repo.DigitalAsset{
+		Slug:      slug,
+		Filename:  filename,
+		MediaType: http.DetectContentType(data),
+		Size:      int64(len(data)),
+		Data:      data,
+		Added:     time.Now(),
+	}
+	return asset, n.Datastore.DigitalAssets().Put(asset)
+}
+
+// Add license keys to a digital listing's pool. Blank lines are skipped. Returns the number of unused keys.
+func (n *OpenBazaarNode) AddLicenseKeys(slug string, keys []string) (int, error) {
+	if err := n.checkDigitalListing(slug); err != nil {
+		return 0, err
+	}
+	var add []string
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		if len(key) > LicenseKeyMaxLength {
+			return 0, fmt.Errorf("License keys can be at most %d characters", LicenseKeyMaxLength)
+		}
+		add = append(add, key)
+	}
+	if len(add) == 0 {
+		return 0, errors.New("No license keys given")
+	}
+	if err := n.Datastore.LicenseKeys().Put(slug, add); err != nil {
+		return 0, err
+	}
+	counts, err := n.Datastore.LicenseKeys().GetAll()
+	if err != nil {
+		return 0, err
+	}
+	return counts[slug], nil
+}
+
+func (n *OpenBazaarNode) checkDigitalListing(slug string) error {
+	contract, err := n.GetListingFromSlug(slug)
+	if err != nil {
+		return ErrListingNotFound
+	}
+	if contract.VendorListings[0].Metadata.ContractType != pb.Listing_Metadata_DIGITAL_GOOD {
+		return ErrNotDigitalGood
+	}
+	return nil
+}
+
+/* Build the automatic delivery of a listing's file and license key for an order. The file is
+   encrypted with a new key for each order and the key is encrypted to the buyer. A license
+   key is taken from the listing's pool if it has one. */
+func (n *OpenBazaarNode) newDigitalDelivery(orderId, slug string, asset *repo.DigitalAsset, pooled bool, buyerKey crypto.PubKey) (*pb.OrderFulfillment_DigitalDelivery, error) {
+	delivery := new(pb.OrderFulfillment_DigitalDelivery)
+	if asset != nil {
+		key, err := net.NewSymmetricKey()
+		if err != nil {
+			return nil, err
+		}
+		delivery.Hash, err = n.addEncryptedFile(key, asset.Data)
+		if err != nil {
+			return nil, err
+		}
+		delivery.EncryptedKey, err = net.Encrypt(buyerKey, key)
+		if err != nil {
+			return nil, err
+		}
+		delivery.Filename = asset.Filename
+		delivery.MediaType = asset.MediaType
+		delivery.Size = uint64(asset.Size)
+	}
+	if pooled {
+		licenseKey, err := n.Datastore.LicenseKeys().Assign(slug, orderId)
+		if err == repo.ErrNoLicenseKeys {
+			return nil, fmt.Errorf("No license keys left for %s", slug)
+		} else if err != nil {
+			return nil, err
+		}
+		delivery.LicenseKey, err = net.Encrypt(buyerKey, []byte(licenseKey))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return delivery, nil
+}
+
+// Check the automatic deliveries in a fulfillment we've been sent are well formed
+func validateDigitalDelivery(fulfillment *pb.OrderFulfillment) error {
+	for _, d := range fulfillment.DigitalDelivery {
+		if d.Hash != "" {
+			if _, err := multihash.FromB58String(d.Hash); err != nil {
+				return errors.New("Invalid digital delivery hash")
+			}
+			if len(d.EncryptedKey) == 0 {
+				return errors.New("Digital delivery is missing its key")
+			}
+			if len(d.Filename) > DigitalFilenameMaxLength {
+				return errors.New("Digital delivery filename is too long")
+			}
+		}
+	}
+	return nil
+}
+
+// Return the digital deliveries for a purchase with their license keys decrypted
+func (n *OpenBazaarNode) GetDigitalDeliveries(contract *pb.RicardianContract) ([]DigitalDelivery, error) {
+	ret := []DigitalDelivery{}
+	for _, fulfillment := range contract.VendorOrderFulfillment {
+		for _, d := range fulfillment.DigitalDelivery {
+			delivery := DigitalDelivery{
+				Slug:      fulfillment.Slug,
+				Url:       d.Url,
+				Password:  d.Password,
+				Filename:  d.Filename,
+				MediaType: d.MediaType,
+				Size:      d.Size,
+			}
+			if len(d.LicenseKey) > 0 {
+				licenseKey, err := net.Decrypt(n.IpfsNode.PrivateKey, d.LicenseKey)
+				if err != nil {
+					return nil, err
+				}
+				delivery.LicenseKey = string(licenseKey)
+			}
+			ret = append(ret, delivery)
+		}
+	}
+	return ret, nil
+}
+
+/* Fetch the file delivered for one of the listings in a purchase and decrypt it.
+   Returns ErrDeliveryMissing if no file was delivered for the listing. */
+func (n *OpenBazaarNode) GetDigitalDeliveryFile(contract *pb.RicardianContract, slug string) (*pb.OrderFulfillment_DigitalDelivery, []byte, error) {
+	for _, fulfillment := range contract.VendorOrderFulfillment {
+		if fulfillment.Slug != slug {
+			continue
+		}
+		for _, d := range fulfillment.DigitalDelivery {
+			if d.Hash == "" {
+				continue
+			}
+			key, err := net.Decrypt(n.IpfsNode.PrivateKey, d.EncryptedKey)
+			if err != nil {
+				return nil, nil, err
+			}
+			ciphertext, err := ipfs.Cat(n.Context, d.Hash)
+			if err != nil {
+				return nil, nil, err
+			}
+			data, err := net.DecryptSymmetric(key, ciphertext)
+			if err != nil {
+				return nil, nil, err
+			}
+			return d, data, nil
+		}
+	}
+	return nil, nil, ErrDeliveryMissing
+}
@@ -0,0 +1,34 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+)
+
+func TestValidateDigitalDelivery(t *testing.T) {
+	delivery := &pb.OrderFulfillment_DigitalDelivery{
+		Hash:         "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG",
+		EncryptedKey: []byte("key"),
+		Filename:     "book.pdf",
+	}
+	fulfillment := &pb.OrderFulfillment{DigitalDelivery: []*pb.OrderFulfillment_DigitalDelivery{delivery}}
+	if err := validateDigitalDelivery(fulfillment); err != nil {
+		t.Error(err)
+	}
+	delivery.EncryptedKey = nil
+	if err := validateDigitalDelivery(fulfillment); err == nil {
+		t.Error("Validated a delivery without a key")
+	}
+	delivery.EncryptedKey = []byte("key")
+	delivery.Hash = "not a hash"
+	if err := validateDigitalDelivery(fulfillment); err == nil {
+		t.Error("Validated a delivery with an invalid hash")
+	}
+
+	// Deliveries the vendor entered themselves only have a url and password
+	manual := &pb.OrderFulfillment{DigitalDelivery: []*pb.OrderFulfillment_DigitalDelivery{{Url: "https://example.com", Password: "secret"}}}
+	if err := validateDigitalDelivery(manual); err != nil {
+		t.Error(err)
+	}
+}
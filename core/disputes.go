@@ -29,6 +29,10 @@ var DisputeWg = new(sync.WaitGroup)
 var ErrCaseNotFound = errors.New("Case not found")
 
 func (n *OpenBazaarNode) OpenDispute(orderID string, contract *pb.RicardianContract, records []*spvwallet.TransactionRecord, claim string) error {
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return err
+	}
 	var isPurchase bool
 	if n.IpfsNode.Identity.Pretty() == contract.BuyerOrder.BuyerID.Guid {
 		isPurchase = true
@@ -57,7 +61,7 @@ func (n *OpenBazaarNode) OpenDispute(orderID string, contract *pb.RicardianContr
 	dispute.Outpoints = outpoints
 
 	// Add payout address
-	dispute.PayoutAddress = n.Wallet.CurrentAddress(spvwallet.EXTERNAL).EncodeAddress()
+	dispute.PayoutAddress = wal.CurrentAddress(spvwallet.EXTERNAL).EncodeAddress()
 
 	// Serialize contract
 	ser, err := proto.Marshal(contract)
@@ -187,6 +191,10 @@ func (n *OpenBazaarNode) ProcessDisputeOpen(rc *pb.RicardianContract, peerID str
 	if len(contract.VendorListings) == 0 || contract.BuyerOrder == nil || contract.BuyerOrder.Payment == nil {
 		return errors.New("Serialized contract is malformatted")
 	}
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return err
+	}
 
 	orderId, err := n.CalcOrderId(contract.BuyerOrder)
 	if err != nil {
@@ -240,7 +248,7 @@ func (n *OpenBazaarNode) ProcessDisputeOpen(rc *pb.RicardianContract, peerID str
 		}
 		update.SerializedContract = ser
 		update.OrderId = orderId
-		update.PayoutAddress = n.Wallet.CurrentAddress(spvwallet.EXTERNAL).EncodeAddress()
+		update.PayoutAddress = wal.CurrentAddress(spvwallet.EXTERNAL).EncodeAddress()
 
 		var outpoints []*pb.Outpoint
 		for _, r := range records {
@@ -289,7 +297,7 @@ func (n *OpenBazaarNode) ProcessDisputeOpen(rc *pb.RicardianContract, peerID str
 		}
 		update.SerializedContract = ser
 		update.OrderId = orderId
-		update.PayoutAddress = n.Wallet.CurrentAddress(spvwallet.EXTERNAL).EncodeAddress()
+		update.PayoutAddress = wal.CurrentAddress(spvwallet.EXTERNAL).EncodeAddress()
 
 		var outpoints []*pb.Outpoint
 		for _, r := range records {
@@ -343,6 +351,15 @@ func (n *OpenBazaarNode) CloseDispute(orderId string, buyerPercentage, vendorPer
 		return errors.New("A dispute for this order is not open")
 	}
 
+	contract := buyerContract
+	if contract == nil {
+		contract = vendorContract
+	}
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return err
+	}
+
 	d := new(pb.DisputeResolution)
 
 	// Add timestamp
@@ -396,7 +413,7 @@ func (n *OpenBazaarNode) CloseDispute(orderId string, buyerPercentage, vendorPer
 		if len(vendorContract.VendorOrderFulfillment) > 0 && vendorContract.VendorOrderFulfillment[0].Payout != nil {
 			feePerByte = vendorContract.VendorOrderFulfillment[0].Payout.PayoutFeePerByte
 		} else {
-			feePerByte = n.Wallet.GetFeePerByte(spvwallet.NORMAL)
+			feePerByte = wal.GetFeePerByte(spvwallet.NORMAL)
 		}
 		buyerId = vendorContract.BuyerOrder.BuyerID.Guid
 		buyerKey, err = libp2p.UnmarshalPublicKey(vendorContract.BuyerOrder.BuyerID.Pubkeys.Guid)
@@ -417,7 +434,7 @@ func (n *OpenBazaarNode) CloseDispute(orderId string, buyerPercentage, vendorPer
 		if len(vendorContract.VendorOrderFulfillment) > 0 && vendorContract.VendorOrderFulfillment[0].Payout != nil {
 			feePerByte = vendorContract.VendorOrderFulfillment[0].Payout.PayoutFeePerByte
 		} else {
-			feePerByte = n.Wallet.GetFeePerByte(spvwallet.NORMAL)
+			feePerByte = wal.GetFeePerByte(spvwallet.NORMAL)
 		}
 		buyerId = vendorContract.BuyerOrder.BuyerID.Guid
 		buyerKey, err = libp2p.UnmarshalPublicKey(vendorContract.BuyerOrder.BuyerID.Pubkeys.Guid)
@@ -458,8 +475,8 @@ func (n *OpenBazaarNode) CloseDispute(orderId string, buyerPercentage, vendorPer
 	var outputs []spvwallet.TransactionOutput
 	var modAddr btcutil.Address
 	var modValue uint64
-	modAddr = n.Wallet.CurrentAddress(spvwallet.EXTERNAL)
-	modValue, err = n.GetModeratorFee(totalOut)
+	modAddr = wal.CurrentAddress(spvwallet.EXTERNAL)
+	modValue, err = n.GetModeratorFee(totalOut, wal)
 	var modOutputScript []byte
 	if err != nil {
 		return err
@@ -481,7 +498,7 @@ func (n *OpenBazaarNode) CloseDispute(orderId string, buyerPercentage, vendorPer
 	var buyerValue uint64
 	var buyerOutputScript []byte
 	if buyerPayout {
-		buyerAddr, err = btcutil.DecodeAddress(buyerPayoutAddress, n.Wallet.Params())
+		buyerAddr, err = btcutil.DecodeAddress(buyerPayoutAddress, wal.Params())
 		if err != nil {
 			return err
 		}
@@ -500,7 +517,7 @@ func (n *OpenBazaarNode) CloseDispute(orderId string, buyerPercentage, vendorPer
 	var vendorValue uint64
 	var vendorOutputScript []byte
 	if vendorPayout {
-		vendorAddr, err = btcutil.DecodeAddress(vendorPayoutAddress, n.Wallet.Params())
+		vendorAddr, err = btcutil.DecodeAddress(vendorPayoutAddress, wal.Params())
 		if err != nil {
 			return err
 		}
@@ -539,7 +556,7 @@ func (n *OpenBazaarNode) CloseDispute(orderId string, buyerPercentage, vendorPer
 	}
 
 	// Calculate total fee
-	txFee := n.Wallet.EstimateFee(inputs, outputs, feePerByte)
+	txFee := wal.EstimateFee(inputs, outputs, feePerByte)
 	feePerOutput := txFee / uint64(len(outputs))
 
 	// Subtract fee from each output
@@ -559,7 +576,7 @@ func (n *OpenBazaarNode) CloseDispute(orderId string, buyerPercentage, vendorPer
 	if err != nil {
 		return err
 	}
-	mPrivKey := n.Wallet.MasterPrivateKey()
+	mPrivKey := wal.MasterPrivateKey()
 	if err != nil {
 		return err
 	}
@@ -568,7 +585,7 @@ func (n *OpenBazaarNode) CloseDispute(orderId string, buyerPercentage, vendorPer
 		return err
 	}
 	hdKey := hd.NewExtendedKey(
-		n.Wallet.Params().HDPrivateKeyID[:],
+		wal.Params().HDPrivateKeyID[:],
 		mECKey.Serialize(),
 		chaincodeBytes,
 		parentFP,
@@ -586,7 +603,7 @@ func (n *OpenBazaarNode) CloseDispute(orderId string, buyerPercentage, vendorPer
 	if err != nil {
 		return err
 	}
-	sigs, err := n.Wallet.CreateMultisigSignature(inputs, outs, moderatorKey, redeemScriptBytes, 0)
+	sigs, err := wal.CreateMultisigSignature(inputs, outs, moderatorKey, redeemScriptBytes, 0)
 	if err != nil {
 		return err
 	}
@@ -677,6 +694,11 @@ func (n *OpenBazaarNode) ValidateCaseContract(contract *pb.RicardianContract) []
 		validationErrors = append(validationErrors, "The listing is missing the buyer ID information. Unable to validate any signatures.")
 		return validationErrors
 	}
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		validationErrors = append(validationErrors, err.Error())
+		return validationErrors
+	}
 
 	vendorPubkey := contract.VendorListings[0].VendorID.Pubkeys.Guid
 	vendorGuid := contract.VendorListings[0].VendorID.Guid
@@ -799,13 +821,13 @@ func (n *OpenBazaarNode) ValidateCaseContract(contract *pb.RicardianContract) []
 			return validationErrors
 		}
 		parentFP := []byte{0x00, 0x00, 0x00, 0x00}
-		mECKey, err := n.Wallet.MasterPublicKey().ECPubKey()
+		mECKey, err := wal.MasterPublicKey().ECPubKey()
 		if err != nil {
 			validationErrors = append(validationErrors, "Error validating bitcoin address and redeem script")
 			return validationErrors
 		}
 		hdKey := hd.NewExtendedKey(
-			n.Wallet.Params().HDPublicKeyID[:],
+			wal.Params().HDPublicKeyID[:],
 			mECKey.SerializeCompressed(),
 			chaincode,
 			parentFP,
@@ -819,7 +841,7 @@ func (n *OpenBazaarNode) ValidateCaseContract(contract *pb.RicardianContract) []
 		}
 
 		hdKey = hd.NewExtendedKey(
-			n.Wallet.Params().HDPublicKeyID[:],
+			wal.Params().HDPublicKeyID[:],
 			contract.BuyerOrder.BuyerID.Pubkeys.Bitcoin,
 			chaincode,
 			parentFP,
@@ -833,7 +855,7 @@ func (n *OpenBazaarNode) ValidateCaseContract(contract *pb.RicardianContract) []
 			return validationErrors
 		}
 		hdKey = hd.NewExtendedKey(
-			n.Wallet.Params().HDPublicKeyID[:],
+			wal.Params().HDPublicKeyID[:],
 			contract.VendorListings[0].VendorID.Pubkeys.Bitcoin,
 			chaincode,
 			parentFP,
@@ -845,7 +867,7 @@ func (n *OpenBazaarNode) ValidateCaseContract(contract *pb.RicardianContract) []
 			validationErrors = append(validationErrors, "Error validating bitcoin address and redeem script")
 			return validationErrors
 		}
-		addr, redeemScript, err := n.Wallet.GenerateMultisigScript([]hd.ExtendedKey{*buyerKey, *vendorKey, *moderatorKey}, 2)
+		addr, redeemScript, err := wal.GenerateMultisigScript([]hd.ExtendedKey{*buyerKey, *vendorKey, *moderatorKey}, 2)
 
 		if contract.BuyerOrder.Payment.Address != addr.EncodeAddress() {
 			validationErrors = append(validationErrors, "The calculated bitcoin address doesn't match the address in the order")
@@ -860,7 +882,11 @@ func (n *OpenBazaarNode) ValidateCaseContract(contract *pb.RicardianContract) []
 }
 
 func (n *OpenBazaarNode) ValidateDisputeResolution(contract *pb.RicardianContract) error {
-	err := n.verifySignatureOnDisputeResolution(contract)
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return err
+	}
+	err = n.verifySignatureOnDisputeResolution(contract)
 	if err != nil {
 		return err
 	}
@@ -872,11 +898,11 @@ func (n *OpenBazaarNode) ValidateDisputeResolution(contract *pb.RicardianContrac
 		if err != nil {
 			return err
 		}
-		_, addrs, _, err := txscript.ExtractPkScriptAddrs(scriptBytes, n.Wallet.Params())
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(scriptBytes, wal.Params())
 		if err != nil {
 			return err
 		}
-		if !n.Wallet.HasKey(addrs[0]) {
+		if !wal.HasKey(addrs[0]) {
 			return errors.New("Moderator payout sends coins to an address we don't control")
 		}
 		return nil
@@ -930,6 +956,10 @@ func (n *OpenBazaarNode) verifySignatureOnDisputeResolution(contract *pb.Ricardi
 }
 
 func (n *OpenBazaarNode) ReleaseFunds(contract *pb.RicardianContract, records []*spvwallet.TransactionRecord) error {
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return err
+	}
 	// Create inputs
 	var inputs []spvwallet.TransactionInput
 	for _, o := range contract.DisputeResolution.Payout.Inputs {
@@ -990,7 +1020,7 @@ func (n *OpenBazaarNode) ReleaseFunds(contract *pb.RicardianContract, records []
 	if err != nil {
 		return err
 	}
-	mPrivKey := n.Wallet.MasterPrivateKey()
+	mPrivKey := wal.MasterPrivateKey()
 	if err != nil {
 		return err
 	}
@@ -999,7 +1029,7 @@ func (n *OpenBazaarNode) ReleaseFunds(contract *pb.RicardianContract, records []
 		return err
 	}
 	hdKey := hd.NewExtendedKey(
-		n.Wallet.Params().HDPrivateKeyID[:],
+		wal.Params().HDPrivateKeyID[:],
 		mECKey.Serialize(),
 		chaincodeBytes,
 		parentFP,
@@ -1017,7 +1047,7 @@ func (n *OpenBazaarNode) ReleaseFunds(contract *pb.RicardianContract, records []
 	if err != nil {
 		return err
 	}
-	mySigs, err := n.Wallet.CreateMultisigSignature(inputs, outputs, signingKey, redeemScriptBytes, 0)
+	mySigs, err := wal.CreateMultisigSignature(inputs, outputs, signingKey, redeemScriptBytes, 0)
 	if err != nil {
 		return err
 	}
@@ -1031,7 +1061,7 @@ func (n *OpenBazaarNode) ReleaseFunds(contract *pb.RicardianContract, records []
 		moderatorSigs = append(moderatorSigs, s)
 	}
 
-	err = n.Wallet.Multisign(inputs, outputs, mySigs, moderatorSigs, redeemScriptBytes, 0)
+	err = wal.Multisign(inputs, outputs, mySigs, moderatorSigs, redeemScriptBytes, 0)
 	if err != nil {
 		return err
 	}
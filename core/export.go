@@ -0,0 +1,297 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/OpenBazaar/spvwallet"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// Rates are recorded in this currency if the user hasn't set a local currency
+const DefaultAccountingCurrency = "USD"
+
+// A wallet transaction with its fee and fiat value at the time it was first seen
+type TransactionExportRow struct {
+	Txid         string    `json:"txid"`
+	Timestamp    time.Time `json:"timestamp"`
+	Value        int64     `json:"value"`
+	Fee          *int64    `json:"fee"`
+	Height       int32     `json:"height"`
+	Address      string    `json:"address"`
+	Memo         string    `json:"memo"`
+	OrderId      string    `json:"orderId"`
+	Currency     string    `json:"currency"`
+	ExchangeRate float64   `json:"exchangeRate"`
+	FiatValue    float64   `json:"fiatValue"`
+}
+
+/* A purchase or sale with the total from its contract. The fee and fiat value come
+   from the transactions that paid for the order. */
+type OrderExportRow struct {
+	OrderId      string    `json:"orderId"`
+	Timestamp    time.Time `json:"timestamp"`
+	Title        string    `json:"title"`
+	PeerId       string    `json:"peerId"`
+	Handle       string    `json:"handle"`
+	State        string    `json:"state"`
+	Total        uint64    `json:"total"`
+	Funded       bool      `json:"funded"`
+	Txids        []string  `json:"txids"`
+	Memo         string    `json:"memo"`
+	Fee          *int64    `json:"fee"`
+	Currency     string    `json:"currency"`
+	ExchangeRate float64   `json:"exchangeRate"`
+	FiatValue    float64   `json:"fiatValue"`
+
+	// The rates used to convert the listing prices when the order was made
+	OrderExchangeRates map[string]float64 `json:"orderExchangeRates"`
+}
+
+// How long to wait for a wallet to store a transaction after calling back on it
+const txStoreTimeout = 10 * time.Second
+
+/* Return a transaction listener for a wallet which records the fee of each transaction
+   the first time the wallet sees it. The exchange rate is only recorded for transactions
+   seen before they confirm. Transactions which are already confirmed, such as those found
+   by a resync, may have been made long before so their rate is left blank. */
+func (n *OpenBazaarNode) TransactionRecorder(wal bitcoin.BitcoinWallet) func(spvwallet.TransactionCallback) {
+	return func(cb spvwallet.TransactionCallback) {
+		txid, err := chainhash.NewHash(cb.Txid)
+		if err != nil {
+			return
+		}
+		record := repo.TxAccountingRecord{
+			Txid:      txid.String(),
+			Fee:       transactionFee(cb),
+			Timestamp: time.Now(),
+		}
+		if err := n.Datastore.TxAccounting().Put(record); err != nil {
+			log.Error(err)
+			return
+		}
+		// Wallets call their listeners before storing the transaction
+		go n.recordExchangeRate(wal, *txid)
+	}
+}
+
+// Record the current exchange rate for a transaction once the wallet has stored it, if it's unconfirmed
+func (n *OpenBazaarNode) recordExchangeRate(wal bitcoin.BitcoinWallet, txid chainhash.Hash) {
+	rates := n.exchangeRatesFor(wal)
+	if rates == nil {
+		return
+	}
+	for deadline := time.Now().Add(txStoreTimeout); time.Now().Before(deadline); time.Sleep(time.Second / 2) {
+		confirmations, err := wal.GetConfirmations(txid)
+		if err != nil {
+			continue
+		}
+		if confirmations > 0 {
+			return
+		}
+		currency := n.accountingCurrency()
+		rate, err := rates.GetExchangeRate(currency)
+		if err != nil {
+			log.Errorf("Failed to get the %s exchange rate for transaction %s: %s", currency, txid.String(), err)
+			return
+		}
+		if err := n.Datastore.TxAccounting().SetExchangeRate(txid.String(), currency, rate); err != nil {
+			log.Error(err)
+		}
+		return
+	}
+}
+
+/* Our inputs are the only ones with a known value. When they cover the outputs we
+   funded the transaction and the difference is the fee. With no inputs of ours the
+   fee was paid by someone else and is unknown. */
+func transactionFee(cb spvwallet.TransactionCallback) *int64 {
+	if len(cb.Inputs) == 0 {
+		return nil
+	}
+	var fee int64
+	for _, in := range cb.Inputs {
+		fee += in.Value
+	}
+	for _, out := range cb.Outputs {
+		fee -= out.Value
+	}
+	if fee < 0 {
+		return nil
+	}
+	return &fee
+}
+
+func (n *OpenBazaarNode) accountingCurrency() string {
+	settings, err := n.Datastore.Settings().Get()
+	if err != nil || settings.LocalCurrency == nil || *settings.LocalCurrency == "" {
+		return DefaultAccountingCurrency
+	}
+	return strings.ToUpper(*settings.LocalCurrency)
+}
+
+func (n *OpenBazaarNode) fiatValue(value int64, rate float64) float64 {
+	unitsPerCoin := 100000000
+	if n.ExchangeRates != nil {
+		unitsPerCoin = n.ExchangeRates.UnitsPerCoin()
+	}
+	return float64(value) / float64(unitsPerCoin) * rate
+}
+
+// Return the transactions in the wallet joined with their metadata and accounting records
+func (n *OpenBazaarNode) ExportTransactions(wallet bitcoin.BitcoinWallet) ([]TransactionExportRow, error) {
+	transactions, err := wallet.Transactions()
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := n.Datastore.TxMetadata().GetAll()
+	if err != nil {
+		return nil, err
+	}
+	records, err := n.Datastore.TxAccounting().GetAll()
+	if err != nil {
+		return nil, err
+	}
+	var rows []TransactionExportRow
+	for _, t := range transactions {
+		row := TransactionExportRow{
+			Txid:      t.Txid,
+			Timestamp: t.Timestamp,
+			Value:     t.Value,
+			Height:    t.Height,
+		}
+		if m, ok := metadata[t.Txid]; ok {
+			row.Address = m.Address
+			row.Memo = m.Memo
+			row.OrderId = m.OrderId
+		}
+		if record, ok := records[t.Txid]; ok {
+			row.Fee = record.Fee
+			row.Currency = record.Currency
+			row.ExchangeRate = record.ExchangeRate
+			row.FiatValue = n.fiatValue(t.Value, record.ExchangeRate)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Return every sale along with its payment details
+func (n *OpenBazaarNode) ExportSales() ([]OrderExportRow, error) {
+	sales, err := n.Datastore.Sales().GetAll("", -1)
+	if err != nil {
+		return nil, err
+	}
+	var rows []OrderExportRow
+	for _, s := range sales {
+		contract, _, funded, records, _, err := n.Datastore.Sales().GetByOrderId(s.OrderId)
+		if err != nil {
+			return nil, err
+		}
+		row := OrderExportRow{
+			OrderId:   s.OrderId,
+			Timestamp: s.Timestamp,
+			Title:     s.Title,
+			PeerId:    s.BuyerId,
+			Handle:    s.BuyerHandle,
+			State:     s.State,
+			Total:     s.Total,
+			Funded:    funded,
+		}
+		if err := n.addPaymentDetails(&row, contract, records); err != nil {
+			return nil, err
+		}
+		row.OrderExchangeRates, err = n.Datastore.Sales().GetExchangeRates(s.OrderId)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Return every purchase along with its payment details
+func (n *OpenBazaarNode) ExportPurchases() ([]OrderExportRow, error) {
+	purchases, err := n.Datastore.Purchases().GetAll("", -1)
+	if err != nil {
+		return nil, err
+	}
+	var rows []OrderExportRow
+	for _, p := range purchases {
+		contract, _, funded, records, _, err := n.Datastore.Purchases().GetByOrderId(p.OrderId)
+		if err != nil {
+			return nil, err
+		}
+		row := OrderExportRow{
+			OrderId:   p.OrderId,
+			Timestamp: p.Timestamp,
+			Title:     p.Title,
+			PeerId:    p.VendorId,
+			Handle:    p.VendorHandle,
+			State:     p.State,
+			Total:     p.Total,
+			Funded:    funded,
+		}
+		if err := n.addPaymentDetails(&row, contract, records); err != nil {
+			return nil, err
+		}
+		row.OrderExchangeRates, err = n.Datastore.Purchases().GetExchangeRates(p.OrderId)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+/* Fill in the payment details of an order from the transactions that funded it. The
+   total comes from the contract and is valued at the rate recorded for the first
+   payment. The fee is the sum of the fees we paid, if any. */
+func (n *OpenBazaarNode) addPaymentDetails(row *OrderExportRow, contract *pb.RicardianContract, records []*spvwallet.TransactionRecord) error {
+	if contract == nil || contract.BuyerOrder == nil || contract.BuyerOrder.Payment == nil {
+		return errors.New("Order " + row.OrderId + " is missing its payment")
+	}
+	row.Total = contract.BuyerOrder.Payment.Amount
+	row.Txids = []string{}
+	var fee int64
+	feeKnown := false
+	for _, r := range records {
+		if r.Value <= 0 || contains(row.Txids, r.Txid) {
+			continue
+		}
+		row.Txids = append(row.Txids, r.Txid)
+		if m, err := n.Datastore.TxMetadata().Get(r.Txid); err == nil && row.Memo == "" {
+			row.Memo = m.Memo
+		}
+		record, err := n.Datastore.TxAccounting().Get(r.Txid)
+		if err != nil {
+			continue
+		}
+		if row.Currency == "" {
+			row.Currency = record.Currency
+			row.ExchangeRate = record.ExchangeRate
+			row.FiatValue = n.fiatValue(int64(row.Total), record.ExchangeRate)
+		}
+		if record.Fee != nil {
+			fee += *record.Fee
+			feeKnown = true
+		}
+	}
+	if feeKnown {
+		row.Fee = &fee
+	}
+	return nil
+}
+
+func contains(s []string, e string) bool {
+	for _, a := range s {
+		if a == e {
+			return true
+		}
+	}
+	return false
+}
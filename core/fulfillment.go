@@ -17,11 +17,15 @@ import (
 )
 
 func (n *OpenBazaarNode) FulfillOrder(fulfillment *pb.OrderFulfillment, contract *pb.RicardianContract, records []*spvwallet.TransactionRecord) error {
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return err
+	}
 	rc := new(pb.RicardianContract)
 	if contract.BuyerOrder.Payment.Method == pb.Order_Payment_MODERATED {
 		payout := new(pb.OrderFulfillment_Payout)
-		payout.PayoutAddress = n.Wallet.CurrentAddress(spvwallet.EXTERNAL).EncodeAddress()
-		payout.PayoutFeePerByte = n.Wallet.GetFeePerByte(spvwallet.NORMAL)
+		payout.PayoutAddress = wal.CurrentAddress(spvwallet.EXTERNAL).EncodeAddress()
+		payout.PayoutFeePerByte = wal.GetFeePerByte(spvwallet.NORMAL)
 		var ins []spvwallet.TransactionInput
 		var outValue int64
 		for _, r := range records {
@@ -36,7 +40,7 @@ func (n *OpenBazaarNode) FulfillOrder(fulfillment *pb.OrderFulfillment, contract
 			}
 		}
 
-		refundAddress, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, n.Wallet.Params())
+		refundAddress, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, wal.Params())
 		if err != nil {
 			return err
 		}
@@ -54,7 +58,7 @@ func (n *OpenBazaarNode) FulfillOrder(fulfillment *pb.OrderFulfillment, contract
 			return err
 		}
 		parentFP := []byte{0x00, 0x00, 0x00, 0x00}
-		mPrivKey := n.Wallet.MasterPrivateKey()
+		mPrivKey := wal.MasterPrivateKey()
 		if err != nil {
 			return err
 		}
@@ -63,7 +67,7 @@ func (n *OpenBazaarNode) FulfillOrder(fulfillment *pb.OrderFulfillment, contract
 			return err
 		}
 		hdKey := hd.NewExtendedKey(
-			n.Wallet.Params().HDPrivateKeyID[:],
+			wal.Params().HDPrivateKeyID[:],
 			mECKey.Serialize(),
 			chaincode,
 			parentFP,
@@ -77,7 +81,7 @@ func (n *OpenBazaarNode) FulfillOrder(fulfillment *pb.OrderFulfillment, contract
 		}
 		redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
 
-		signatures, err := n.Wallet.CreateMultisigSignature(ins, []spvwallet.TransactionOutput{output}, vendorKey, redeemScript, payout.PayoutFeePerByte)
+		signatures, err := wal.CreateMultisigSignature(ins, []spvwallet.TransactionOutput{output}, vendorKey, redeemScript, payout.PayoutFeePerByte)
 		if err != nil {
 			return err
 		}
@@ -164,6 +168,10 @@ func (n *OpenBazaarNode) SignOrderFulfillment(contract *pb.RicardianContract) (*
 }
 
 func (n *OpenBazaarNode) ValidateOrderFulfillment(fulfillment *pb.OrderFulfillment, contract *pb.RicardianContract) error {
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return err
+	}
 	if err := verifySignaturesOnOrderFulfilment(contract); err != nil {
 		return err
 	}
@@ -210,11 +218,15 @@ func (n *OpenBazaarNode) ValidateOrderFulfillment(fulfillment *pb.OrderFulfillme
 		return errors.New("Failed to verify signature on rating keys")
 	}
 
+	if err := validateDigitalDelivery(fulfillment); err != nil {
+		return err
+	}
+
 	if contract.BuyerOrder.Payment.Method == pb.Order_Payment_MODERATED {
 		if fulfillment.Payout == nil {
 			return errors.New("Payout object for multisig is nil")
 		}
-		_, err := btcutil.DecodeAddress(fulfillment.Payout.PayoutAddress, n.Wallet.Params())
+		_, err := btcutil.DecodeAddress(fulfillment.Payout.PayoutAddress, wal.Params())
 		if err != nil {
 			return errors.New("Invalid payout address")
 		}
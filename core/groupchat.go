@@ -0,0 +1,398 @@
+package core
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	libp2p "gx/ipfs/QmPGxZ1DP2w45WcogpW1h43BvseXbfke9N91qotpoQcUeS/go-libp2p-crypto"
+	routing "gx/ipfs/QmUc6twRJRE9MNrUGd8eo9WjHHxebGppdZfptGCASkR7fF/go-libp2p-routing"
+	peer "gx/ipfs/QmWUswjn261LSyVxWAEpMVtPdy8zmKBJJfBpG3Qdpa8ZsE/go-libp2p-peer"
+	multihash "gx/ipfs/QmbZ6Cee2uHjG7hf19qLHppgKDRtaG4CVtMzdmK9VCVqLu/go-multihash"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"golang.org/x/net/context"
+)
+
+// Enough for the parties to a deal, their moderator and a few others
+const GroupChatMaxMembers = 32
+
+var (
+	ErrNotGroupCreator = errors.New("Only the creator of a group can change its members")
+	ErrNotGroupMember  = errors.New("Not a member of the group")
+)
+
+/* Start a group conversation with the given peers. We're its creator and the only
+   one who can change its members. The members are sent the signed member list so
+   the group shows up for them before anything is said in it. */
+func (n *OpenBazaarNode) CreateGroupChat(subject string, members []string) (repo.GroupChat, error) {
+	var group repo.GroupChat
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return group, err
+	}
+	pubkey, err := n.IpfsNode.PrivateKey.GetPublic().Bytes()
+	if err != nil {
+		return group, err
+	}
+	groupId, err := GroupChatId(pubkey, nonce)
+	if err != nil {
+		return group, err
+	}
+	group, err = n.signGroupChat(groupId, nonce, subject, members)
+	if err != nil {
+		return group, err
+	}
+	if err := n.Datastore.GroupChats().Put(group); err != nil {
+		return group, err
+	}
+	n.sendGroupChat(group, group.Members, &pb.GroupChat{GroupId: group.GroupId})
+	return group, nil
+}
+
+/* Change the subject and members of a group we created. Both the old and the new
+   members are sent the new list so removed members know to stop writing to it. */
+func (n *OpenBazaarNode) UpdateGroupChat(groupId, subject string, members []string) (repo.GroupChat, error) {
+	current, err := n.Datastore.GroupChats().Get(groupId)
+	if err != nil {
+		return current, err
+	}
+	if current.Creator != n.IpfsNode.Identity.Pretty() {
+		return current, ErrNotGroupCreator
+	}
+	signed := new(pb.SignedGroupMembers)
+	if err := proto.Unmarshal(current.SignedMembers, signed); err != nil {
+		return current, err
+	}
+	currentMembers, err := verifyGroupMembers(signed)
+	if err != nil {
+		return current, err
+	}
+	group, err := n.signGroupChat(groupId, currentMembers.Nonce, subject, members)
+	if err != nil {
+		return current, err
+	}
+	if err := n.Datastore.GroupChats().Put(group); err != nil {
+		return current, err
+	}
+	recipients := group.Members
+	for _, m := range current.Members {
+		if !contains(recipients, m) {
+			recipients = append(recipients, m)
+		}
+	}
+	n.sendGroupChat(group, recipients, &pb.GroupChat{GroupId: group.GroupId})
+	return group, nil
+}
+
+/* Send a message to every other member of a group. Each copy is encrypted to its
+   recipient and stored offline for members who can't be reached. An error is only
+   returned if no member could be sent the message. */
+func (n *OpenBazaarNode) SendGroupChat(groupId, message string) (repo.GroupChatMessage, error) {
+	var msg repo.GroupChatMessage
+	if message == "" {
+		return msg, errors.New("Message is empty")
+	}
+	if len(message) > CHAT_MESSAGE_MAX_CHARACTERS {
+		return msg, errors.New("Chat message over max characters")
+	}
+	group, err := n.Datastore.GroupChats().Get(groupId)
+	if err != nil {
+		return msg, err
+	}
+	if !group.IsMember(n.IpfsNode.Identity.Pretty()) {
+		return msg, ErrNotGroupMember
+	}
+	timestamp := time.Now()
+	ts, err := ptypes.TimestampProto(timestamp)
+	if err != nil {
+		return msg, err
+	}
+	ts.Nanos = 0
+	msgId, err := GroupChatMessageId(groupId, n.IpfsNode.Identity.Pretty(), message, ts.Seconds)
+	if err != nil {
+		return msg, err
+	}
+	msg = repo.GroupChatMessage{
+		MessageId: msgId,
+		GroupId:   groupId,
+		PeerId:    n.IpfsNode.Identity.Pretty(),
+		Message:   message,
+		Read:      true,
+		Outgoing:  true,
+		Timestamp: timestamp,
+	}
+	if err := n.Datastore.GroupChats().PutMessage(msg); err != nil {
+		return msg, err
+	}
+	chat := &pb.GroupChat{
+		MessageId: msgId,
+		GroupId:   groupId,
+		Message:   message,
+		Timestamp: ts,
+	}
+	if failed := n.sendGroupChat(group, group.Members, chat); failed > 0 && failed == len(group.Members)-1 {
+		return msg, errors.New("Message could not be sent to any member of the group")
+	}
+	return msg, nil
+}
+
+/* The ID of a group is the hash of its creator's public key and a random nonce so
+   nobody else can sign a member list for it. */
+func GroupChatId(creatorPubkey, nonce []byte) (string, error) {
+	return hashToB58(append(append([]byte{}, creatorPubkey...), nonce...))
+}
+
+// The ID of a group message is the hash of the group, its sender, the message and the second it was sent
+func GroupChatMessageId(groupId, peerId, message string, timestamp int64) (string, error) {
+	return hashToB58([]byte(groupId + peerId + message + strconv.Itoa(int(timestamp))))
+}
+
+func hashToB58(b []byte) (string, error) {
+	h := sha256.Sum256(b)
+	encoded, err := multihash.Encode(h[:], multihash.SHA2_256)
+	if err != nil {
+		return "", err
+	}
+	id, err := multihash.Cast(encoded)
+	if err != nil {
+		return "", err
+	}
+	return id.B58String(), nil
+}
+
+/* Save the member list sent with a group message if it's the first we've seen for
+   the group or was signed after ours. The creator of a group can't change so a
+   list signed by anyone else is rejected. Returns the group as it now stands and
+   whether it changed. */
+func (n *OpenBazaarNode) UpdateGroupMembers(signed *pb.SignedGroupMembers, groupId string) (repo.GroupChat, bool, error) {
+	members, err := verifyGroupMembers(signed)
+	if err != nil {
+		return repo.GroupChat{}, false, err
+	}
+	if members.GroupId != groupId {
+		return repo.GroupChat{}, false, errors.New("Member list is for another group")
+	}
+	signedAt, err := ptypes.Timestamp(members.Timestamp)
+	if err != nil {
+		return repo.GroupChat{}, false, err
+	}
+	current, err := n.Datastore.GroupChats().Get(groupId)
+	switch {
+	case err == repo.ErrGroupChatNotFound:
+		// We only learn of groups we've been added to
+		if !contains(members.Members, n.IpfsNode.Identity.Pretty()) {
+			return current, false, ErrNotGroupMember
+		}
+	case err != nil:
+		return current, false, err
+	default:
+		if current.Creator != members.Creator {
+			return current, false, ErrNotGroupCreator
+		}
+		currentSigned := new(pb.SignedGroupMembers)
+		if err := proto.Unmarshal(current.SignedMembers, currentSigned); err == nil {
+			if currentMembers, err := verifyGroupMembers(currentSigned); err == nil {
+				if currentAt, err := ptypes.Timestamp(currentMembers.Timestamp); err == nil && !signedAt.After(currentAt) {
+					return current, false, nil
+				}
+			}
+		}
+	}
+	ser, err := proto.Marshal(signed)
+	if err != nil {
+		return current, false, err
+	}
+	group := repo.GroupChat{
+		GroupId:       members.GroupId,
+		Creator:       members.Creator,
+		Subject:       members.Subject,
+		Members:       members.Members,
+		SignedMembers: ser,
+		Updated:       signedAt,
+	}
+	if err := n.Datastore.GroupChats().Put(group); err != nil {
+		return current, false, err
+	}
+	return group, true, nil
+}
+
+// Build and sign a member list for a group we created. We're always one of its members.
+func (n *OpenBazaarNode) signGroupChat(groupId string, nonce []byte, subject string, members []string) (repo.GroupChat, error) {
+	var group repo.GroupChat
+	if len(subject) > CHAT_SUBJECT_MAX_CHARACTERS {
+		return group, errors.New("Chat subject over max characters")
+	}
+	self := n.IpfsNode.Identity.Pretty()
+	list := []string{self}
+	for _, m := range members {
+		pid, err := peer.IDB58Decode(m)
+		if err != nil {
+			return group, errors.New("Invalid member " + m)
+		}
+		if !contains(list, pid.Pretty()) {
+			list = append(list, pid.Pretty())
+		}
+	}
+	if len(list) < 2 {
+		return group, errors.New("A group needs at least one other member")
+	}
+	if len(list) > GroupChatMaxMembers {
+		return group, errors.New("A group can have at most " + strconv.Itoa(GroupChatMaxMembers) + " members")
+	}
+	now := time.Now()
+	ts, err := ptypes.TimestampProto(now)
+	if err != nil {
+		return group, err
+	}
+	signed, err := signGroupMembers(n.IpfsNode.PrivateKey, &pb.GroupMembers{
+		GroupId:   groupId,
+		Creator:   self,
+		Subject:   subject,
+		Members:   list,
+		Timestamp: ts,
+		Nonce:     nonce,
+	})
+	if err != nil {
+		return group, err
+	}
+	ser, err := proto.Marshal(signed)
+	if err != nil {
+		return group, err
+	}
+	return repo.GroupChat{
+		GroupId:       groupId,
+		Creator:       self,
+		Subject:       subject,
+		Members:       list,
+		SignedMembers: ser,
+		Updated:       now,
+	}, nil
+}
+
+func signGroupMembers(key libp2p.PrivKey, members *pb.GroupMembers) (*pb.SignedGroupMembers, error) {
+	ser, err := proto.Marshal(members)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := key.Sign(ser)
+	if err != nil {
+		return nil, err
+	}
+	pubkey, err := key.GetPublic().Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SignedGroupMembers{SerializedMembers: ser, CreatorPubkey: pubkey, Signature: sig}, nil
+}
+
+// Check a member list was signed by the creator it names and the group is theirs
+func verifyGroupMembers(signed *pb.SignedGroupMembers) (*pb.GroupMembers, error) {
+	if signed == nil {
+		return nil, errors.New("Member list is missing")
+	}
+	pubkey, err := libp2p.UnmarshalPublicKey(signed.CreatorPubkey)
+	if err != nil {
+		return nil, err
+	}
+	valid, err := pubkey.Verify(signed.SerializedMembers, signed.Signature)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, invalidSigError{}
+	}
+	members := new(pb.GroupMembers)
+	if err := proto.Unmarshal(signed.SerializedMembers, members); err != nil {
+		return nil, err
+	}
+	pid, err := peer.IDB58Decode(members.Creator)
+	if err != nil {
+		return nil, err
+	}
+	if !pid.MatchesPublicKey(pubkey) {
+		return nil, matchKeyError{}
+	}
+	groupId, err := GroupChatId(signed.CreatorPubkey, members.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if members.GroupId != groupId {
+		return nil, errors.New("Group was not created by the signer")
+	}
+	if !contains(members.Members, members.Creator) {
+		return nil, errors.New("Creator is not a member of the group")
+	}
+	if len(members.Members) > GroupChatMaxMembers {
+		return nil, errors.New("Group has too many members")
+	}
+	return members, nil
+}
+
+/* Attach the group's member list to a message and send every recipient but us a
+   copy encrypted to their key. Members who can't be reached directly have their
+   copy stored offline. Returns how many recipients couldn't be sent the message. */
+func (n *OpenBazaarNode) sendGroupChat(group repo.GroupChat, recipients []string, chat *pb.GroupChat) int {
+	signed := new(pb.SignedGroupMembers)
+	if err := proto.Unmarshal(group.SignedMembers, signed); err != nil {
+		log.Errorf("Error reading group members: %s", err.Error())
+		return len(recipients)
+	}
+	chat.Members = signed
+	ser, err := proto.Marshal(chat)
+	if err != nil {
+		log.Errorf("Error serializing group message: %s", err.Error())
+		return len(recipients)
+	}
+	var (
+		wg     sync.WaitGroup
+		lock   sync.Mutex
+		failed int
+	)
+	for _, member := range recipients {
+		if member == n.IpfsNode.Identity.Pretty() {
+			continue
+		}
+		wg.Add(1)
+		go func(member string) {
+			defer wg.Done()
+			if err := n.sendGroupChatTo(member, ser); err != nil {
+				log.Errorf("Error sending group message to %s: %s", member, err.Error())
+				lock.Lock()
+				failed++
+				lock.Unlock()
+			}
+		}(member)
+	}
+	wg.Wait()
+	return failed
+}
+
+func (n *OpenBazaarNode) sendGroupChatTo(member string, ser []byte) error {
+	pid, err := peer.IDB58Decode(member)
+	if err != nil {
+		return err
+	}
+	// Look the key up once as it's needed again if the message has to be stored offline
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	pubkey, err := routing.GetPublicKey(n.IpfsNode.Routing, ctx, []byte(pid))
+	cancel()
+	if err != nil {
+		return err
+	}
+	ciphertext, err := n.EncryptMessage(pid, &pubkey, ser)
+	if err != nil {
+		return err
+	}
+	m := pb.Message{
+		MessageType: pb.Message_GROUP_CHAT,
+		Payload:     &any.Any{Value: ciphertext},
+	}
+	return n.sendMessage(member, &pubkey, m)
+}
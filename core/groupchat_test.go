@@ -0,0 +1,113 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	libp2p "gx/ipfs/QmPGxZ1DP2w45WcogpW1h43BvseXbfke9N91qotpoQcUeS/go-libp2p-crypto"
+	peer "gx/ipfs/QmWUswjn261LSyVxWAEpMVtPdy8zmKBJJfBpG3Qdpa8ZsE/go-libp2p-peer"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/golang/protobuf/ptypes"
+)
+
+func TestGroupMemberSignatures(t *testing.T) {
+	priv, pub, err := libp2p.GenerateKeyPair(libp2p.Ed25519, 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, err := peer.IDFromPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubkey, err := pub.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := []byte("nonce")
+	groupId, err := GroupChatId(pubkey, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	members := &pb.GroupMembers{
+		GroupId: groupId,
+		Creator: pid.Pretty(),
+		Subject: "dispute",
+		Members: []string{pid.Pretty(), "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG"},
+		Nonce:   nonce,
+	}
+	members.Timestamp, err = ptypes.TimestampProto(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := signGroupMembers(priv, members)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret, err := verifyGroupMembers(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.GroupId != groupId || ret.Creator != pid.Pretty() || len(ret.Members) != 2 {
+		t.Error("Returned wrong members")
+	}
+
+	// A list naming a creator other than the signer is rejected
+	other, _, err := libp2p.GenerateKeyPair(libp2p.Ed25519, 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, err := signGroupMembers(other, members)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifyGroupMembers(forged); err == nil {
+		t.Error("Accepted a member list signed by another peer")
+	}
+
+	// Another peer can't take over the group by signing a list naming themselves
+	otherPid, err := peer.IDFromPrivateKey(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hijacked := *members
+	hijacked.Creator = otherPid.Pretty()
+	hijacked.Members = []string{otherPid.Pretty()}
+	forged, err = signGroupMembers(other, &hijacked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifyGroupMembers(forged); err == nil {
+		t.Error("Accepted a member list for a group the signer didn't create")
+	}
+
+	// As is one which was changed after it was signed
+	signed.SerializedMembers = append(signed.SerializedMembers, 0)
+	if _, err := verifyGroupMembers(signed); err == nil {
+		t.Error("Accepted a member list which doesn't match its signature")
+	}
+
+	// The creator has to be a member
+	members.Members = members.Members[1:]
+	signed, err = signGroupMembers(priv, members)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifyGroupMembers(signed); err == nil {
+		t.Error("Accepted a member list without its creator")
+	}
+}
+
+func TestGroupChatMessageId(t *testing.T) {
+	a, err := GroupChatMessageId("QmGroup", "QmPeer1", "hello", 1500000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := GroupChatMessageId("QmGroup", "QmPeer2", "hello", 1500000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b {
+		t.Error("Messages from different members have the same ID")
+	}
+}
@@ -159,9 +159,13 @@ func (n *OpenBazaarNode) addImage(img image.Image, imgPath string) (string, erro
 }
 
 func (n *OpenBazaarNode) addResizedImage(img image.Image, imgCfg *image.Config, w, h uint, imgPath string) (string, error) {
+	return n.addImage(resizeImage(img, imgCfg, w, h), imgPath)
+}
+
+// Scale an image to cover w by h keeping its aspect ratio
+func resizeImage(img image.Image, imgCfg *image.Config, w, h uint) image.Image {
 	width, height := getImageAttributes(w, h, uint(imgCfg.Width), uint(imgCfg.Height))
-	newImg := resize.Resize(width, height, img, resize.Lanczos3)
-	return n.addImage(newImg, imgPath)
+	return resize.Resize(width, height, img, resize.Lanczos3)
 }
 
 func decodeImageData(base64ImageData string) (image.Image, *image.Config, error) {
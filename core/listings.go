@@ -39,6 +39,7 @@ const (
 	CouponTitleMaxCharacters = 70
 	PolicyMaxCharacters      = 10000
 	MaxCountryCodes          = 255
+	ListingCloserInterval    = time.Minute * 10
 )
 
 type price struct {
@@ -64,6 +65,7 @@ type listingData struct {
 	Language      string    `json:"language"`
 	AverageRating float32   `json:"averageRating"`
 	RatingCount   uint32    `json:"ratingCount"`
+	Tags          []string  `json:"tags"`
 }
 
 func (n *OpenBazaarNode) GenerateSlug(title string) (string, error) {
@@ -135,8 +137,12 @@ func (n *OpenBazaarNode) SignListing(listing *pb.Listing) (*pb.RicardianContract
 	sig, err := ecPrivKey.Sign([]byte(id.Guid))
 	id.BitcoinSig = sig.Serialize()
 
-	// Set crypto currency
-	listing.Metadata.AcceptedCurrency = n.Wallet.CurrencyCode()
+	// Set crypto currency. Listings may accept any currency we have a wallet for.
+	wal, err := n.WalletForCurrency(listing.Metadata.AcceptedCurrency)
+	if err != nil {
+		return c, err
+	}
+	listing.Metadata.AcceptedCurrency = wal.CurrencyCode()
 
 	// Update coupon db
 	n.Datastore.Coupons().Delete(listing.Slug)
@@ -274,6 +280,7 @@ func (n *OpenBazaarNode) extractListingData(contract *pb.RicardianContract) (lis
 		ShipsTo:      shipsTo,
 		FreeShipping: freeShipping,
 		Language:     contract.VendorListings[0].Metadata.Language,
+		Tags:         contract.VendorListings[0].Item.Tags,
 	}
 	return ld, nil
 }
@@ -340,9 +347,34 @@ func (n *OpenBazaarNode) updateListingOnDisk(index []listingData, ld listingData
 	if werr != nil {
 		return werr
 	}
+	n.indexListings(index)
+	go n.updateTagPointers(index)
 	return nil
 }
 
+// Replace the listings in the search index with those in the listing index
+func (n *OpenBazaarNode) IndexListings() error {
+	index, err := n.getListingIndex()
+	if err != nil {
+		return err
+	}
+	return n.indexListings(index)
+}
+
+func (n *OpenBazaarNode) indexListings(index []listingData) error {
+	var listings []repo.SearchListing
+	for _, ld := range index {
+		listings = append(listings, repo.SearchListing{Slug: ld.Slug, Title: ld.Title, Tags: ld.Tags})
+	}
+	err := n.Datastore.Search().IndexListings(listings)
+	if err == repo.ErrSearchUnavailable {
+		return nil
+	} else if err != nil {
+		log.Error("Failed to update the search index:", err)
+	}
+	return err
+}
+
 func (n *OpenBazaarNode) updateRatingInListingIndex(rating *pb.OrderCompletion_Rating) error {
 	index, err := n.getListingIndex()
 	if err != nil {
@@ -478,7 +510,7 @@ func (n *OpenBazaarNode) IsItemForSale(listing *pb.Listing) bool {
 	return false
 }
 
-// Deletes the listing directory, removes the listing from the index, and deletes the inventory
+// Deletes the listing directory, removes the listing from the index, and deletes the inventory and digital goods
 func (n *OpenBazaarNode) DeleteListing(slug string) error {
 	toDelete := path.Join(n.RepoPath, "root", "listings", slug+".json")
 	err := os.Remove(toDelete)
@@ -528,6 +560,8 @@ func (n *OpenBazaarNode) DeleteListing(slug string) error {
 	if werr != nil {
 		return werr
 	}
+	n.indexListings(index)
+	go n.updateTagPointers(index)
 
 	// Delete inventory for listing
 	err = n.Datastore.Inventory().DeleteAll(slug)
@@ -535,6 +569,16 @@ func (n *OpenBazaarNode) DeleteListing(slug string) error {
 		return err
 	}
 
+	// Delete anything left to deliver automatically
+	err = n.Datastore.DigitalAssets().Delete(slug)
+	if err != nil && err != repo.ErrDigitalAssetNotFound {
+		return err
+	}
+	err = n.Datastore.LicenseKeys().DeleteUnused(slug)
+	if err != nil {
+		return err
+	}
+
 	return n.updateProfileCounts()
 }
 
@@ -653,7 +697,7 @@ func validateListing(listing *pb.Listing) (err error) {
 	if listing.Metadata == nil {
 		return errors.New("Missing required field: Metadata")
 	}
-	if listing.Metadata.ContractType > pb.Listing_Metadata_SERVICE {
+	if listing.Metadata.ContractType > pb.Listing_Metadata_CROWD_FUND {
 		return errors.New("Invalid contract type")
 	}
 	if listing.Metadata.Format > pb.Listing_Metadata_AUCTION {
@@ -934,6 +978,26 @@ func validateListing(listing *pb.Listing) (err error) {
 		}
 	}
 
+	// Auction
+	if listing.Metadata.Format == pb.Listing_Metadata_AUCTION {
+		if len(listing.Item.Options) > 0 {
+			return errors.New("Auction listings cannot have options")
+		}
+		if len(listing.Coupons) > 0 {
+			return errors.New("Auction listings cannot have coupons")
+		}
+	}
+
+	// Crowdfund
+	if listing.Metadata.ContractType == pb.Listing_Metadata_CROWD_FUND {
+		if listing.Metadata.Format != pb.Listing_Metadata_FIXED_PRICE {
+			return errors.New("Crowdfund listings must use a fixed price")
+		}
+		if listing.Metadata.FundingGoal == 0 {
+			return errors.New("Crowdfund listings must have a funding goal")
+		}
+	}
+
 	// Moderators
 	if len(listing.Moderators) > MaxListItems {
 		return fmt.Errorf("Number of moderators is greater than the max of %d", MaxListItems)
@@ -996,3 +1060,18 @@ func verifySignaturesOnListing(contract *pb.RicardianContract) error {
 	}
 	return nil
 }
+
+// Periodically settle any of our auctions and crowdfund campaigns which have ended
+func (n *OpenBazaarNode) StartListingCloser() {
+	tick := time.NewTicker(ListingCloserInterval)
+	defer tick.Stop()
+	go n.closeExpiredListings()
+	for range tick.C {
+		go n.closeExpiredListings()
+	}
+}
+
+func (n *OpenBazaarNode) closeExpiredListings() {
+	n.CloseExpiredAuctions()
+	n.CloseExpiredCampaigns()
+}
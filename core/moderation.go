@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"errors"
 	"github.com/OpenBazaar/jsonpb"
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
 	"github.com/OpenBazaar/openbazaar-go/pb"
 	"golang.org/x/net/context"
@@ -117,7 +118,7 @@ func (n *OpenBazaarNode) RemoveSelfAsModerator() error {
 	return nil
 }
 
-func (n *OpenBazaarNode) GetModeratorFee(transactionTotal uint64) (uint64, error) {
+func (n *OpenBazaarNode) GetModeratorFee(transactionTotal uint64, wal bitcoin.BitcoinWallet) (uint64, error) {
 	file, err := ioutil.ReadFile(path.Join(n.RepoPath, "root", "profile"))
 	if err != nil {
 		return 0, err
@@ -132,13 +133,13 @@ func (n *OpenBazaarNode) GetModeratorFee(transactionTotal uint64) (uint64, error
 	case pb.Moderator_Fee_PERCENTAGE:
 		return uint64(float64(transactionTotal) * (float64(profile.ModeratorInfo.Fee.Percentage) / 100)), nil
 	case pb.Moderator_Fee_FIXED:
-		if strings.ToLower(profile.ModeratorInfo.Fee.FixedFee.CurrencyCode) == "btc" {
+		if strings.EqualFold(profile.ModeratorInfo.Fee.FixedFee.CurrencyCode, wal.CurrencyCode()) {
 			if profile.ModeratorInfo.Fee.FixedFee.Amount >= transactionTotal {
 				return 0, errors.New("Fixed moderator fee exceeds transaction amount")
 			}
 			return profile.ModeratorInfo.Fee.FixedFee.Amount, nil
 		} else {
-			fee, err := n.getPriceInSatoshi(profile.ModeratorInfo.Fee.FixedFee.CurrencyCode, profile.ModeratorInfo.Fee.FixedFee.Amount)
+			fee, err := n.getPriceInSatoshi(wal, profile.ModeratorInfo.Fee.FixedFee.CurrencyCode, profile.ModeratorInfo.Fee.FixedFee.Amount)
 			if err != nil {
 				return 0, err
 			} else if fee >= transactionTotal {
@@ -148,10 +149,10 @@ func (n *OpenBazaarNode) GetModeratorFee(transactionTotal uint64) (uint64, error
 		}
 	case pb.Moderator_Fee_FIXED_PLUS_PERCENTAGE:
 		var fixed uint64
-		if strings.ToLower(profile.ModeratorInfo.Fee.FixedFee.CurrencyCode) == "btc" {
+		if strings.EqualFold(profile.ModeratorInfo.Fee.FixedFee.CurrencyCode, wal.CurrencyCode()) {
 			fixed = profile.ModeratorInfo.Fee.FixedFee.Amount
 		} else {
-			fixed, err = n.getPriceInSatoshi(profile.ModeratorInfo.Fee.FixedFee.CurrencyCode, profile.ModeratorInfo.Fee.FixedFee.Amount)
+			fixed, err = n.getPriceInSatoshi(wal, profile.ModeratorInfo.Fee.FixedFee.CurrencyCode, profile.ModeratorInfo.Fee.FixedFee.Amount)
 			if err != nil {
 				return 0, err
 			}
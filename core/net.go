@@ -7,6 +7,7 @@ import (
 
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
 	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/any"
@@ -28,62 +29,73 @@ func (n *OpenBazaarNode) sendMessage(peerId string, k *libp2p.PubKey, message pb
 	defer cancel()
 	err = n.Service.SendMessage(ctx, p, &message)
 	if err != nil {
-		if err := n.SendOfflineMessage(p, k, &message); err != nil {
-			return err
-		}
+		return n.SendOfflineMessage(p, k, &message)
 	}
+	n.recordOutgoingMessage(p, &message, repo.OutgoingMessageSentDirect, "", nil)
 	return nil
 }
 
-// Supply of a public key is optional, if nil is instead provided n.EncryptMessage does a lookup
+/* Supply of a public key is optional, if nil is instead provided n.EncryptMessage does a lookup.
+   The outcome is recorded in the outgoing message queue. */
 func (n *OpenBazaarNode) SendOfflineMessage(p peer.ID, k *libp2p.PubKey, m *pb.Message) error {
+	pointerId, err := n.storeOfflineMessage(p, k, m)
+	if err != nil {
+		n.recordOutgoingMessage(p, m, repo.OutgoingMessageFailed, "", err)
+		return err
+	}
+	n.recordOutgoingMessage(p, m, repo.OutgoingMessageStoredOffline, pointerId, nil)
+	return nil
+}
+
+// Save a message with the offline message storage and publish a pointer to it. Returns the pointer's ID.
+func (n *OpenBazaarNode) storeOfflineMessage(p peer.ID, k *libp2p.PubKey, m *pb.Message) (string, error) {
 	log.Debugf("Sending offline message to %s", p.Pretty())
 	pubKeyBytes, err := n.IpfsNode.PrivateKey.GetPublic().Bytes()
 	if err != nil {
-		return err
+		return "", err
 	}
 	ser, err := proto.Marshal(m)
 	if err != nil {
-		return err
+		return "", err
 	}
 	sig, err := n.IpfsNode.PrivateKey.Sign(ser)
 	if err != nil {
-		return err
+		return "", err
 	}
 	env := pb.Envelope{Message: m, Pubkey: pubKeyBytes, Signature: sig}
 	messageBytes, merr := proto.Marshal(&env)
 	if merr != nil {
-		return merr
+		return "", merr
 	}
 	ciphertext, cerr := n.EncryptMessage(p, k, messageBytes)
 	if cerr != nil {
-		return cerr
+		return "", cerr
 	}
 	addr, aerr := n.MessageStorage.Store(p, ciphertext)
 	if aerr != nil {
-		return aerr
+		return "", aerr
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	mh, mherr := multihash.FromB58String(p.Pretty())
 	if mherr != nil {
-		return mherr
+		return "", mherr
 	}
 	/* TODO: We are just using a default prefix length for now. Eventually we will want to customize this,
 	   but we will need some way to get the recipient's desired prefix length. Likely will be in profile. */
 	pointer, err := ipfs.PublishPointer(n.IpfsNode, ctx, mh, DefaultPointerPrefixLength, addr)
 	if err != nil {
-		return err
+		return "", err
 	}
 	if m.MessageType != pb.Message_OFFLINE_ACK {
 		pointer.Purpose = ipfs.MESSAGE
 		pointer.CancelID = &p
 		err = n.Datastore.Pointers().Put(pointer)
 		if err != nil {
-			return err
+			return "", err
 		}
 	}
-	return nil
+	return pointer.Value.ID.Pretty(), nil
 }
 
 func (n *OpenBazaarNode) SendOfflineAck(peerId string, pointerID peer.ID) error {
@@ -318,21 +330,45 @@ func (n *OpenBazaarNode) SendChat(peerId string, chatMessage *pb.Chat) error {
 		Payload:     a,
 	}
 
+	if chatMessage.Flag != pb.Chat_TYPING {
+		return n.sendMessage(peerId, nil, m)
+	}
+
+	// Typing indicators are only worth sending while the peer is online
 	p, err := peer.IDB58Decode(peerId)
 	if err != nil {
 		return err
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	err = n.Service.SendMessage(ctx, p, &m)
-	if err != nil && chatMessage.Flag != pb.Chat_TYPING {
-		if err := n.SendOfflineMessage(p, nil, &m); err != nil {
-			return err
-		}
-	}
+	n.Service.SendMessage(ctx, p, &m)
 	return nil
 }
 
+func (n *OpenBazaarNode) SendBid(peerId string, k *libp2p.PubKey, bidMessage *pb.RicardianContract) error {
+	a, err := ptypes.MarshalAny(bidMessage)
+	if err != nil {
+		return err
+	}
+	m := pb.Message{
+		MessageType: pb.Message_BID,
+		Payload:     a,
+	}
+	return n.sendMessage(peerId, k, m)
+}
+
+func (n *OpenBazaarNode) SendBidAccept(peerId string, k *libp2p.PubKey, bidMessage *pb.RicardianContract) error {
+	a, err := ptypes.MarshalAny(bidMessage)
+	if err != nil {
+		return err
+	}
+	m := pb.Message{
+		MessageType: pb.Message_BID_ACCEPT,
+		Payload:     a,
+	}
+	return n.sendMessage(peerId, k, m)
+}
+
 func (n *OpenBazaarNode) SendModeratorAdd(peerId string) error {
 	m := pb.Message{MessageType: pb.Message_MODERATOR_ADD}
 	err := n.sendMessage(peerId, nil, m)
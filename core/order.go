@@ -6,8 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"github.com/OpenBazaar/jsonpb"
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
 	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/pricing"
 	"github.com/OpenBazaar/spvwallet"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
@@ -59,176 +61,28 @@ type PurchaseData struct {
 }
 
 func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderId string, paymentAddress string, paymentAmount uint64, vendorOnline bool, err error) {
-	contract := new(pb.RicardianContract)
-	order := new(pb.Order)
-	if data.RefundAddress != nil {
-		order.RefundAddress = *(data.RefundAddress)
-	} else {
-		order.RefundAddress = n.Wallet.CurrentAddress(spvwallet.INTERNAL).EncodeAddress()
-	}
-	shipping := &pb.Order_Shipping{
-		ShipTo:     data.ShipTo,
-		Address:    data.Address,
-		City:       data.City,
-		State:      data.State,
-		PostalCode: data.PostalCode,
-		Country:    pb.CountryCode(pb.CountryCode_value[data.CountryCode]),
-	}
-	order.Shipping = shipping
-
-	id := new(pb.ID)
-	profile, err := n.GetProfile()
-	if err == nil {
-		id.BlockchainID = profile.Handle
-	}
-
-	id.Guid = n.IpfsNode.Identity.Pretty()
-	pubkey, err := n.IpfsNode.PrivateKey.GetPublic().Bytes()
+	contract, err := n.createContractWithOrder(data)
 	if err != nil {
 		return "", "", 0, false, err
 	}
-	keys := new(pb.ID_Pubkeys)
-	keys.Guid = pubkey
-	ecPubKey, err := n.Wallet.MasterPublicKey().ECPubKey()
+	wal, err := n.WalletForContract(contract)
 	if err != nil {
 		return "", "", 0, false, err
 	}
-	keys.Bitcoin = ecPubKey.SerializeCompressed()
-	id.Pubkeys = keys
-	// Sign the GUID with the Bitcoin key
-	ecPrivKey, err := n.Wallet.MasterPrivateKey().ECPrivKey()
-	if err != nil {
-		return "", "", 0, false, err
-	}
-	sig, err := ecPrivKey.Sign([]byte(id.Guid))
-	id.BitcoinSig = sig.Serialize()
-	order.BuyerID = id
-
-	ts := new(timestamp.Timestamp)
-	ts.Seconds = time.Now().Unix()
-	ts.Nanos = 0
-	order.Timestamp = ts
-	order.AlternateContactInfo = data.AlternateContactInfo
-
-	var ratingKeys [][]byte
-	for range data.Items {
-		// FIXME: bug here. This should use a different key for each item. This code doesn't look like it will do that.
-		// Also the fix for this will also need to be included in the rating signing code.
-		ratingKey, err := n.Wallet.MasterPublicKey().Child(uint32(ts.Seconds))
-		if err != nil {
-			return "", "", 0, false, err
-		}
-		ecRatingKey, err := ratingKey.ECPubKey()
-		if err != nil {
-			return "", "", 0, false, err
+	for _, listing := range contract.VendorListings {
+		if listing.Metadata.Format == pb.Listing_Metadata_AUCTION {
+			return "", "", 0, false, fmt.Errorf("Listing %s is an auction and must be bid on", listing.Slug)
 		}
-		ratingKeys = append(ratingKeys, ecRatingKey.SerializeCompressed())
 	}
-	order.RatingKeys = ratingKeys
-
-	addedListings := make(map[string]*pb.Listing)
-	for _, item := range data.Items {
-		i := new(pb.Order_Item)
-
-		/* It is possible that multiple items could refer to the same listing if the buyer is ordering
-		   multiple items with different variants. If it is multiple items of the same variant they can just
-		   use the quantity field. But different variants require two separate item entries. However,
-		   in this case we do not need to add the listing to the contract twice. Just once is sufficient.
-		   So let's check to see if that's the case here and handle it. */
-		_, exists := addedListings[item.ListingHash]
-
-		listing := new(pb.Listing)
-		if !exists {
-			// Let's fetch the listing, should be cached
-			b, err := ipfs.Cat(n.Context, item.ListingHash)
-			if err != nil {
-				return "", "", 0, false, err
-			}
-			rc := new(pb.RicardianContract)
-			err = jsonpb.UnmarshalString(string(b), rc)
-			if err != nil {
-				return "", "", 0, false, err
-			}
-			if err := validateVersionNumber(rc); err != nil {
-				return "", "", 0, false, err
-			}
-			if err := validateVendorID(rc); err != nil {
-				return "", "", 0, false, err
-			}
-			if err := validateListing(rc.VendorListings[0]); err != nil {
-				return "", "", 0, false, fmt.Errorf("Listing failed to validate, reason: %q", err.Error())
-			}
-			if err := verifySignaturesOnListing(rc); err != nil {
-				return "", "", 0, false, err
-			}
-			contract.VendorListings = append(contract.VendorListings, rc.VendorListings[0])
-			contract.Signatures = append(contract.Signatures, rc.Signatures[0])
-			addedListings[item.ListingHash] = rc.VendorListings[0]
-			listing = rc.VendorListings[0]
-		} else {
-			listing = addedListings[item.ListingHash]
-		}
-
-		if strings.ToLower(listing.Metadata.AcceptedCurrency) != strings.ToLower(n.Wallet.CurrencyCode()) {
-			return "", "", 0, false, fmt.Errorf("Contract only accepts %s, our wallet uses %s", listing.Metadata.AcceptedCurrency, n.Wallet.CurrencyCode())
-		}
-
-		// Remove any duplicate coupons
-		couponMap := make(map[string]bool)
-		var coupons []string
-		for _, c := range item.Coupons {
-			if !couponMap[c] {
-				couponMap[c] = true
-				coupons = append(coupons, c)
-			}
-		}
-
-		// Validate the selected options
-		listingOptions := make(map[string]*pb.Listing_Item_Option)
-		for _, opt := range listing.Item.Options {
-			listingOptions[strings.ToLower(opt.Name)] = opt
-		}
-		for _, uopt := range item.Options {
-			_, ok := listingOptions[strings.ToLower(uopt.Name)]
-			if !ok {
-				return "", "", 0, false, errors.New("Selected variant not in listing")
-			}
-			delete(listingOptions, strings.ToLower(uopt.Name))
-		}
-		if len(listingOptions) > 0 {
-			return "", "", 0, false, errors.New("Not all options were selected")
-		}
-
-		ser, err := proto.Marshal(listing)
-		if err != nil {
-			return "", "", 0, false, err
-		}
-		listingMH, err := EncodeMultihash(ser)
-		if err != nil {
-			return "", "", 0, false, err
+	if isCrowdfund(contract) {
+		if len(contract.VendorListings) != 1 {
+			return "", "", 0, false, errors.New("A pledge must be for a single campaign")
 		}
-		i.ListingHash = listingMH.B58String()
-		i.Quantity = uint32(item.Quantity)
-
-		for _, option := range item.Options {
-			o := &pb.Order_Item_Option{
-				Name:  option.Name,
-				Value: option.Value,
-			}
-			i.Options = append(i.Options, o)
+		if data.Moderator == "" {
+			return n.pledge(contract)
 		}
-		so := &pb.Order_Item_ShippingOption{
-			Name:    item.Shipping.Name,
-			Service: item.Shipping.Service,
-		}
-		i.ShippingOption = so
-		i.Memo = item.Memo
-		i.CouponCodes = coupons
-		order.Items = append(order.Items, i)
 	}
 
-	contract.BuyerOrder = order
-
 	// Add payment data and send to vendor
 	if data.Moderator != "" { // Moderated payment
 		payment := new(pb.Order_Payment)
@@ -248,11 +102,11 @@ func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderId string, paymentAd
 		if err != nil {
 			return "", "", 0, false, err
 		}
-		total, err := n.CalculateOrderTotal(contract)
+		breakdown, err := n.CalculateOrderBreakdown(contract)
 		if err != nil {
 			return "", "", 0, false, err
 		}
-		payment.Amount = total
+		payment.Amount = breakdown.Total
 
 		/* Generate a payment address using the first child key derived from the buyers's,
 		   vendors's and moderator's masterPubKey and a random chaincode. */
@@ -263,7 +117,7 @@ func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderId string, paymentAd
 		}
 		parentFP := []byte{0x00, 0x00, 0x00, 0x00}
 		hdKey := hd.NewExtendedKey(
-			n.Wallet.Params().HDPublicKeyID[:],
+			wal.Params().HDPublicKeyID[:],
 			contract.VendorListings[0].VendorID.Pubkeys.Bitcoin,
 			chaincode,
 			parentFP,
@@ -276,7 +130,7 @@ func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderId string, paymentAd
 			return "", "", 0, false, err
 		}
 		hdKey = hd.NewExtendedKey(
-			n.Wallet.Params().HDPublicKeyID[:],
+			wal.Params().HDPublicKeyID[:],
 			contract.BuyerOrder.BuyerID.Pubkeys.Bitcoin,
 			chaincode,
 			parentFP,
@@ -289,7 +143,7 @@ func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderId string, paymentAd
 			return "", "", 0, false, err
 		}
 		hdKey = hd.NewExtendedKey(
-			n.Wallet.Params().HDPublicKeyID[:],
+			wal.Params().HDPublicKeyID[:],
 			moderatorKeyBytes,
 			chaincode,
 			parentFP,
@@ -302,7 +156,7 @@ func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderId string, paymentAd
 			return "", "", 0, false, err
 		}
 
-		addr, redeemScript, err := n.Wallet.GenerateMultisigScript([]hd.ExtendedKey{*buyerKey, *vendorKey, *moderatorKey}, 2)
+		addr, redeemScript, err := wal.GenerateMultisigScript([]hd.ExtendedKey{*buyerKey, *vendorKey, *moderatorKey}, 2)
 		if err != nil {
 			return "", "", 0, false, err
 		}
@@ -310,13 +164,13 @@ func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderId string, paymentAd
 		payment.RedeemScript = hex.EncodeToString(redeemScript)
 		payment.Chaincode = hex.EncodeToString(chaincode)
 		contract.BuyerOrder.Payment = payment
-		contract.BuyerOrder.RefundFee = n.Wallet.GetFeePerByte(spvwallet.NORMAL)
+		contract.BuyerOrder.RefundFee = wal.GetFeePerByte(spvwallet.NORMAL)
 
 		script, err := txscript.PayToAddrScript(addr)
 		if err != nil {
 			return "", "", 0, false, err
 		}
-		n.Wallet.AddWatchedScript(script)
+		wal.AddWatchedScript(script)
 
 		contract, err = n.SignOrder(contract)
 		if err != nil {
@@ -353,6 +207,7 @@ func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderId string, paymentAd
 				return "", "", 0, false, err
 			}
 			n.Datastore.Purchases().Put(orderId, *contract, pb.OrderState_PENDING, false)
+			n.Datastore.Purchases().PutExchangeRates(orderId, breakdown.ExchangeRates)
 			return orderId, contract.BuyerOrder.Payment.Address, contract.BuyerOrder.Payment.Amount, false, err
 		} else { // Vendor responded
 			if resp.MessageType == pb.Message_ERROR {
@@ -384,16 +239,17 @@ func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderId string, paymentAd
 				return "", "", 0, false, err
 			}
 			n.Datastore.Purchases().Put(orderId, *contract, pb.OrderState_CONFIRMED, true)
+			n.Datastore.Purchases().PutExchangeRates(orderId, breakdown.ExchangeRates)
 			return orderId, contract.VendorOrderConfirmation.PaymentAddress, contract.BuyerOrder.Payment.Amount, true, nil
 		}
 	} else { // Direct payment
 		payment := new(pb.Order_Payment)
 		payment.Method = pb.Order_Payment_ADDRESS_REQUEST
-		total, err := n.CalculateOrderTotal(contract)
+		breakdown, err := n.CalculateOrderBreakdown(contract)
 		if err != nil {
 			return "", "", 0, false, err
 		}
-		payment.Amount = total
+		payment.Amount = breakdown.Total
 		contract.BuyerOrder.Payment = payment
 		contract, err = n.SignOrder(contract)
 		if err != nil {
@@ -415,7 +271,7 @@ func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderId string, paymentAd
 			}
 			parentFP := []byte{0x00, 0x00, 0x00, 0x00}
 			hdKey := hd.NewExtendedKey(
-				n.Wallet.Params().HDPublicKeyID[:],
+				wal.Params().HDPublicKeyID[:],
 				contract.VendorListings[0].VendorID.Pubkeys.Bitcoin,
 				chaincode,
 				parentFP,
@@ -428,7 +284,7 @@ func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderId string, paymentAd
 				return "", "", 0, false, err
 			}
 			hdKey = hd.NewExtendedKey(
-				n.Wallet.Params().HDPublicKeyID[:],
+				wal.Params().HDPublicKeyID[:],
 				contract.BuyerOrder.BuyerID.Pubkeys.Bitcoin,
 				chaincode,
 				parentFP,
@@ -440,7 +296,7 @@ func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderId string, paymentAd
 			if err != nil {
 				return "", "", 0, false, err
 			}
-			addr, redeemScript, err := n.Wallet.GenerateMultisigScript([]hd.ExtendedKey{*buyerKey, *vendorKey}, 1)
+			addr, redeemScript, err := wal.GenerateMultisigScript([]hd.ExtendedKey{*buyerKey, *vendorKey}, 1)
 			if err != nil {
 				return "", "", 0, false, err
 			}
@@ -452,7 +308,7 @@ func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderId string, paymentAd
 			if err != nil {
 				return "", "", 0, false, err
 			}
-			n.Wallet.AddWatchedScript(script)
+			wal.AddWatchedScript(script)
 
 			contract, err = n.SignOrder(contract)
 			if err != nil {
@@ -486,6 +342,7 @@ func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderId string, paymentAd
 				return "", "", 0, false, err
 			}
 			n.Datastore.Purchases().Put(orderId, *contract, pb.OrderState_PENDING, false)
+			n.Datastore.Purchases().PutExchangeRates(orderId, breakdown.ExchangeRates)
 			return orderId, contract.BuyerOrder.Payment.Address, contract.BuyerOrder.Payment.Amount, false, err
 		} else { // Vendor responded
 			if resp.MessageType == pb.Message_ERROR {
@@ -514,17 +371,242 @@ func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderId string, paymentAd
 				return "", "", 0, false, err
 			}
 			n.Datastore.Purchases().Put(orderId, *contract, pb.OrderState_CONFIRMED, true)
+			n.Datastore.Purchases().PutExchangeRates(orderId, breakdown.ExchangeRates)
 			return orderId, contract.VendorOrderConfirmation.PaymentAddress, contract.BuyerOrder.Payment.Amount, true, nil
 		}
 	}
 }
 
+// Price an order without placing it so the buyer can see what they'd pay
+func (n *OpenBazaarNode) EstimateOrderTotal(data *PurchaseData) (*pricing.Breakdown, error) {
+	contract, err := n.quoteContract(data)
+	if err != nil {
+		return nil, err
+	}
+	return n.CalculateOrderBreakdown(contract)
+}
+
+func (n *OpenBazaarNode) createContractWithOrder(data *PurchaseData) (*pb.RicardianContract, error) {
+	contract := new(pb.RicardianContract)
+	order := new(pb.Order)
+	shipping := &pb.Order_Shipping{
+		ShipTo:     data.ShipTo,
+		Address:    data.Address,
+		City:       data.City,
+		State:      data.State,
+		PostalCode: data.PostalCode,
+		Country:    pb.CountryCode(pb.CountryCode_value[data.CountryCode]),
+	}
+	order.Shipping = shipping
+
+	id := new(pb.ID)
+	profile, err := n.GetProfile()
+	if err == nil {
+		id.BlockchainID = profile.Handle
+	}
+
+	id.Guid = n.IpfsNode.Identity.Pretty()
+	pubkey, err := n.IpfsNode.PrivateKey.GetPublic().Bytes()
+	if err != nil {
+		return nil, err
+	}
+	keys := new(pb.ID_Pubkeys)
+	keys.Guid = pubkey
+	ecPubKey, err := n.Wallet.MasterPublicKey().ECPubKey()
+	if err != nil {
+		return nil, err
+	}
+	keys.Bitcoin = ecPubKey.SerializeCompressed()
+	id.Pubkeys = keys
+	// Sign the GUID with the Bitcoin key
+	ecPrivKey, err := n.Wallet.MasterPrivateKey().ECPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := ecPrivKey.Sign([]byte(id.Guid))
+	id.BitcoinSig = sig.Serialize()
+	order.BuyerID = id
+
+	ts := new(timestamp.Timestamp)
+	ts.Seconds = time.Now().Unix()
+	ts.Nanos = 0
+	order.Timestamp = ts
+	order.AlternateContactInfo = data.AlternateContactInfo
+
+	var ratingKeys [][]byte
+	for range data.Items {
+		// FIXME: bug here. This should use a different key for each item. This code doesn't look like it will do that.
+		// Also the fix for this will also need to be included in the rating signing code.
+		ratingKey, err := n.Wallet.MasterPublicKey().Child(uint32(ts.Seconds))
+		if err != nil {
+			return nil, err
+		}
+		ecRatingKey, err := ratingKey.ECPubKey()
+		if err != nil {
+			return nil, err
+		}
+		ratingKeys = append(ratingKeys, ecRatingKey.SerializeCompressed())
+	}
+	order.RatingKeys = ratingKeys
+
+	if err := n.addOrderItems(contract, order, data.Items); err != nil {
+		return nil, err
+	}
+
+	if data.RefundAddress != nil {
+		order.RefundAddress = *(data.RefundAddress)
+	} else {
+		wal, err := n.WalletForContract(contract)
+		if err != nil {
+			return nil, err
+		}
+		order.RefundAddress = wal.CurrentAddress(spvwallet.INTERNAL).EncodeAddress()
+	}
+
+	contract.BuyerOrder = order
+	return contract, nil
+}
+
+/* Fetch the listing of each item being ordered, add it to the contract and add the
+   item to the order */
+func (n *OpenBazaarNode) addOrderItems(contract *pb.RicardianContract, order *pb.Order, items []item) error {
+	addedListings := make(map[string]*pb.Listing)
+	for _, item := range items {
+		i := new(pb.Order_Item)
+
+		/* It is possible that multiple items could refer to the same listing if the buyer is ordering
+		   multiple items with different variants. If it is multiple items of the same variant they can just
+		   use the quantity field. But different variants require two separate item entries. However,
+		   in this case we do not need to add the listing to the contract twice. Just once is sufficient.
+		   So let's check to see if that's the case here and handle it. */
+		_, exists := addedListings[item.ListingHash]
+
+		listing := new(pb.Listing)
+		if !exists {
+			// Let's fetch the listing, should be cached
+			b, err := ipfs.Cat(n.Context, item.ListingHash)
+			if err != nil {
+				return err
+			}
+			rc := new(pb.RicardianContract)
+			err = jsonpb.UnmarshalString(string(b), rc)
+			if err != nil {
+				return err
+			}
+			if err := validateVersionNumber(rc); err != nil {
+				return err
+			}
+			if err := validateVendorID(rc); err != nil {
+				return err
+			}
+			if err := validateListing(rc.VendorListings[0]); err != nil {
+				return fmt.Errorf("Listing failed to validate, reason: %q", err.Error())
+			}
+			if err := verifySignaturesOnListing(rc); err != nil {
+				return err
+			}
+			contract.VendorListings = append(contract.VendorListings, rc.VendorListings[0])
+			contract.Signatures = append(contract.Signatures, rc.Signatures[0])
+			addedListings[item.ListingHash] = rc.VendorListings[0]
+			listing = rc.VendorListings[0]
+		} else {
+			listing = addedListings[item.ListingHash]
+		}
+
+		if strings.ToLower(listing.Metadata.AcceptedCurrency) != strings.ToLower(contract.VendorListings[0].Metadata.AcceptedCurrency) {
+			return errors.New("All listings in an order must accept the same currency")
+		}
+		if _, err := n.WalletForContract(contract); err != nil {
+			return fmt.Errorf("Contract only accepts %s, we don't have a wallet for it", listing.Metadata.AcceptedCurrency)
+		}
+
+		// Remove any duplicate coupons
+		couponMap := make(map[string]bool)
+		var coupons []string
+		for _, c := range item.Coupons {
+			if !couponMap[c] {
+				couponMap[c] = true
+				coupons = append(coupons, c)
+			}
+		}
+
+		// Validate the selected options
+		listingOptions := make(map[string]*pb.Listing_Item_Option)
+		for _, opt := range listing.Item.Options {
+			listingOptions[strings.ToLower(opt.Name)] = opt
+		}
+		for _, uopt := range item.Options {
+			_, ok := listingOptions[strings.ToLower(uopt.Name)]
+			if !ok {
+				return errors.New("Selected variant not in listing")
+			}
+			delete(listingOptions, strings.ToLower(uopt.Name))
+		}
+		if len(listingOptions) > 0 {
+			return errors.New("Not all options were selected")
+		}
+
+		ser, err := proto.Marshal(listing)
+		if err != nil {
+			return err
+		}
+		listingMH, err := EncodeMultihash(ser)
+		if err != nil {
+			return err
+		}
+		i.ListingHash = listingMH.B58String()
+		i.Quantity = uint32(item.Quantity)
+
+		for _, option := range item.Options {
+			o := &pb.Order_Item_Option{
+				Name:  option.Name,
+				Value: option.Value,
+			}
+			i.Options = append(i.Options, o)
+		}
+		so := &pb.Order_Item_ShippingOption{
+			Name:    item.Shipping.Name,
+			Service: item.Shipping.Service,
+		}
+		i.ShippingOption = so
+		i.Memo = item.Memo
+		i.CouponCodes = coupons
+		order.Items = append(order.Items, i)
+	}
+	return nil
+}
+
 func (n *OpenBazaarNode) CancelOfflineOrder(contract *pb.RicardianContract, records []*spvwallet.TransactionRecord) error {
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return err
+	}
 	orderId, err := n.CalcOrderId(contract.BuyerOrder)
 	if err != nil {
 		return err
 	}
-	// Sweep the temp address into our wallet
+	refundAddress, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, wal.Params())
+	if err != nil {
+		return err
+	}
+	err = n.sweepPaymentAddress(contract, records, &refundAddress)
+	if err != nil {
+		return err
+	}
+	err = n.SendCancel(contract.VendorListings[0].VendorID.Guid, orderId)
+	if err != nil {
+		return err
+	}
+	n.Datastore.Purchases().Put(orderId, *contract, pb.OrderState_CANCELED, true)
+	return nil
+}
+
+// Sweep the unspent outputs of a 1 of 2 payment address to the given address
+func (n *OpenBazaarNode) sweepPaymentAddress(contract *pb.RicardianContract, records []*spvwallet.TransactionRecord, address *btcutil.Address) error {
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return err
+	}
 	var utxos []spvwallet.Utxo
 	for _, r := range records {
 		if !r.Spent && r.Value > 0 {
@@ -550,7 +632,7 @@ func (n *OpenBazaarNode) CancelOfflineOrder(contract *pb.RicardianContract, reco
 		return err
 	}
 	parentFP := []byte{0x00, 0x00, 0x00, 0x00}
-	mPrivKey := n.Wallet.MasterPrivateKey()
+	mPrivKey := wal.MasterPrivateKey()
 	if err != nil {
 		return err
 	}
@@ -559,7 +641,7 @@ func (n *OpenBazaarNode) CancelOfflineOrder(contract *pb.RicardianContract, reco
 		return err
 	}
 	hdKey := hd.NewExtendedKey(
-		n.Wallet.Params().HDPrivateKeyID[:],
+		wal.Params().HDPrivateKeyID[:],
 		mECKey.Serialize(),
 		chaincode,
 		parentFP,
@@ -567,25 +649,40 @@ func (n *OpenBazaarNode) CancelOfflineOrder(contract *pb.RicardianContract, reco
 		0,
 		true)
 
-	buyerKey, err := hdKey.Child(0)
+	key, err := hdKey.Child(0)
 	if err != nil {
 		return err
 	}
 	redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
-	refundAddress, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, n.Wallet.Params())
 	if err != nil {
 		return err
 	}
-	_, err = n.Wallet.SweepAddress(utxos, &refundAddress, buyerKey, &redeemScript, spvwallet.NORMAL)
-	if err != nil {
-		return err
+	_, err = wal.SweepAddress(utxos, address, key, &redeemScript, spvwallet.NORMAL)
+	return err
+}
+
+// Return the wallet for the currency accepted by the listings in the contract
+func (n *OpenBazaarNode) WalletForContract(contract *pb.RicardianContract) (bitcoin.BitcoinWallet, error) {
+	if len(contract.VendorListings) == 0 || contract.VendorListings[0].Metadata == nil {
+		return n.Wallet, nil
 	}
-	err = n.SendCancel(contract.VendorListings[0].VendorID.Guid, orderId)
-	if err != nil {
-		return err
+	return n.WalletForCurrency(contract.VendorListings[0].Metadata.AcceptedCurrency)
+}
+
+// Return the wallet for the given currency code. An empty code returns the default wallet.
+func (n *OpenBazaarNode) WalletForCurrency(currencyCode string) (bitcoin.BitcoinWallet, error) {
+	if n.Wallets == nil || currencyCode == "" {
+		return n.Wallet, nil
 	}
-	n.Datastore.Purchases().Put(orderId, *contract, pb.OrderState_CANCELED, true)
-	return nil
+	return n.Wallets.Get(currencyCode)
+}
+
+// Return the exchange rates for the wallet's coin or nil if there are none
+func (n *OpenBazaarNode) exchangeRatesFor(wal bitcoin.BitcoinWallet) bitcoin.ExchangeRates {
+	if wal == n.Wallet {
+		return n.ExchangeRates
+	}
+	return n.CoinExchangeRates[strings.ToLower(wal.CurrencyCode())]
 }
 
 func (n *OpenBazaarNode) CalcOrderId(order *pb.Order) (string, error) {
@@ -601,252 +698,79 @@ func (n *OpenBazaarNode) CalcOrderId(order *pb.Order) (string, error) {
 }
 
 func (n *OpenBazaarNode) CalculateOrderTotal(contract *pb.RicardianContract) (uint64, error) {
-	if n.ExchangeRates != nil {
-		n.ExchangeRates.GetLatestRate("") // Refresh the exchange rates
+	total, _, err := n.CalculateOrderTotalWithRates(contract)
+	return total, err
+}
+
+/* CalculateOrderTotalWithRates also returns the exchange rate used for each currency
+   the order is priced in. They're saved with the order so its fiat values can be
+   audited later. */
+func (n *OpenBazaarNode) CalculateOrderTotalWithRates(contract *pb.RicardianContract) (uint64, map[string]float64, error) {
+	breakdown, err := n.CalculateOrderBreakdown(contract)
+	if err != nil {
+		return 0, nil, err
 	}
-	var total uint64
-	physicalGoods := make(map[string]*pb.Listing)
+	return breakdown.Total, breakdown.ExchangeRates, nil
+}
+
+// Price each item in the order and itemize the total the buyer owes
+func (n *OpenBazaarNode) CalculateOrderBreakdown(contract *pb.RicardianContract) (*pricing.Breakdown, error) {
+	order, err := pricingOrder(contract)
+	if err != nil {
+		return nil, err
+	}
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return nil, err
+	}
+	if rates := n.exchangeRatesFor(wal); rates != nil {
+		rates.GetLatestRate("") // Refresh the exchange rates
+	}
+	return pricing.Calculate(order, n.newConverter(wal))
+}
 
-	// Calculate the price of each item
+// Return a converter to the wallet's currency using the exchange rates for its coin
+func (n *OpenBazaarNode) newConverter(wal bitcoin.BitcoinWallet) *pricing.Converter {
+	return pricing.NewConverter(wal.CurrencyCode(), n.exchangeRatesFor(wal))
+}
+
+// Collect the listing and selections of each item in the order for the pricing engine
+func pricingOrder(contract *pb.RicardianContract) (pricing.Order, error) {
+	var order pricing.Order
+	if contract.BuyerOrder.Shipping != nil {
+		order.Country = contract.BuyerOrder.Shipping.Country
+	}
 	for _, item := range contract.BuyerOrder.Items {
-		var itemTotal uint64
 		l, err := GetListingFromHash(item.ListingHash, contract)
 		if err != nil {
-			return 0, fmt.Errorf("Listing not found in contract for item %s", item.ListingHash)
+			return order, fmt.Errorf("Listing not found in contract for item %s", item.ListingHash)
 		}
-		if l.Metadata.ContractType == pb.Listing_Metadata_PHYSICAL_GOOD {
-			physicalGoods[item.ListingHash] = l
+		line := pricing.Line{
+			ListingHash:    item.ListingHash,
+			Listing:        l,
+			Quantity:       item.Quantity,
+			Sku:            -1,
+			BidAmount:      item.BidAmount,
+			ShippingOption: item.ShippingOption,
 		}
-		satoshis, err := n.getPriceInSatoshi(l.Metadata.PricingCurrency, l.Item.Price)
-		if err != nil {
-			return 0, err
+		if line.Sku, err = GetSelectedSku(l, item.Options); err != nil {
+			return order, err
 		}
-		itemTotal += satoshis
-		selectedSku, err := GetSelectedSku(l, item.Options)
-		if err != nil {
-			return 0, err
-		}
-		skuExists := false
-		for i, sku := range l.Item.Skus {
-			if selectedSku == i {
-				skuExists = true
-				if sku.Surcharge != 0 {
-					satoshis, err := n.getPriceInSatoshi(l.Metadata.PricingCurrency, uint64(sku.Surcharge))
-					if err != nil {
-						return 0, err
-					}
-					if sku.Surcharge < 0 {
-						satoshis = -satoshis
-					}
-					itemTotal += satoshis
-				}
-				if !skuExists {
-					return 0, errors.New("Selected variant not found in listing")
-				}
-				break
-			}
-		}
-		// Subtract any coupons
 		for _, couponCode := range item.CouponCodes {
-			for _, vendorCoupon := range l.Coupons {
-				multihash, err := EncodeMultihash([]byte(couponCode))
-				if err != nil {
-					return 0, err
-				}
-				if multihash.B58String() == vendorCoupon.GetHash() {
-					if discount := vendorCoupon.GetPriceDiscount(); discount > 0 {
-						itemTotal -= discount
-					} else if discount := vendorCoupon.GetPercentDiscount(); discount > 0 {
-						itemTotal -= uint64((float32(itemTotal) * (discount / 100)))
-					}
-				}
-			}
-		}
-		// Apply tax
-		for _, tax := range l.Taxes {
-			for _, taxRegion := range tax.TaxRegions {
-				if contract.BuyerOrder.Shipping.Country == taxRegion {
-					itemTotal += uint64((float32(itemTotal) * (tax.Percentage / 100)))
-				}
-			}
-		}
-		itemTotal *= uint64(item.Quantity)
-		total += itemTotal
-	}
-
-	// Add in shipping costs
-	type combinedShipping struct {
-		quantity int
-		price    uint64
-		add      bool
-		modifier uint64
-	}
-	var combinedOptions []combinedShipping
-
-	var shippingTotal uint64
-	for listingHash, listing := range physicalGoods {
-		for _, item := range contract.BuyerOrder.Items {
-			if item.ListingHash == listingHash {
-				var itemShipping uint64
-				// Check selected option exists
-				var option *pb.Listing_ShippingOption
-				for _, shippingOption := range listing.ShippingOptions {
-					if shippingOption.Name == item.ShippingOption.Name {
-						option = shippingOption
-						break
-					}
-				}
-				if option == nil {
-					return 0, errors.New("Shipping option not found in listing")
-				}
-
-				// Check that this option ships to us
-				shipsToMe := false
-				for _, country := range option.Regions {
-					if country == contract.BuyerOrder.Shipping.Country || country == pb.CountryCode_ALL {
-						shipsToMe = true
-						break
-					}
-				}
-				if !shipsToMe {
-					return 0, errors.New("Listing does ship to selected country")
-				}
-
-				// Check service exists
-				var service *pb.Listing_ShippingOption_Service
-				for _, shippingService := range option.Services {
-					if strings.ToLower(shippingService.Name) == strings.ToLower(item.ShippingOption.Service) {
-						service = shippingService
-					}
-				}
-				if service == nil {
-					return 0, errors.New("Shipping service not found in listing")
-				}
-				shippingSatoshi, err := n.getPriceInSatoshi(listing.Metadata.PricingCurrency, service.Price)
-				if err != nil {
-					return 0, err
-				}
-				shippingPrice := uint64(item.Quantity) * shippingSatoshi
-				itemShipping += shippingPrice
-				shippingTaxPercentage := float32(0)
-
-				// Calculate tax percentage
-				for _, tax := range listing.Taxes {
-					for _, taxRegion := range tax.TaxRegions {
-						if contract.BuyerOrder.Shipping.Country == taxRegion && tax.TaxShipping {
-							shippingTaxPercentage = tax.Percentage / 100
-						}
-					}
-				}
-
-				// Apply shipping rules
-				if option.ShippingRules != nil {
-					for _, rule := range option.ShippingRules.Rules {
-						switch option.ShippingRules.RuleType {
-						case pb.Listing_ShippingOption_ShippingRules_QUANTITY_DISCOUNT:
-							if item.Quantity >= rule.MinRange && item.Quantity <= rule.MaxRange {
-								rulePrice, err := n.getPriceInSatoshi(listing.Metadata.PricingCurrency, rule.Price)
-								if err != nil {
-									return 0, err
-								}
-								itemShipping -= rulePrice
-							}
-						case pb.Listing_ShippingOption_ShippingRules_FLAT_FEE_QUANTITY_RANGE:
-							if item.Quantity >= rule.MinRange && item.Quantity <= rule.MaxRange {
-								itemShipping -= shippingPrice
-								rulePrice, err := n.getPriceInSatoshi(listing.Metadata.PricingCurrency, rule.Price)
-								if err != nil {
-									return 0, err
-								}
-								itemShipping += rulePrice
-							}
-						case pb.Listing_ShippingOption_ShippingRules_FLAT_FEE_WEIGHT_RANGE:
-							weight := listing.Item.Grams * float32(item.Quantity)
-							if uint32(weight) >= rule.MinRange && uint32(weight) <= rule.MaxRange {
-								itemShipping -= shippingPrice
-								rulePrice, err := n.getPriceInSatoshi(listing.Metadata.PricingCurrency, rule.Price)
-								if err != nil {
-									return 0, err
-								}
-								itemShipping += rulePrice
-							}
-						case pb.Listing_ShippingOption_ShippingRules_COMBINED_SHIPPING_ADD:
-							itemShipping -= shippingPrice
-							rulePrice, err := n.getPriceInSatoshi(listing.Metadata.PricingCurrency, rule.Price)
-							rulePrice += uint64(float32(rulePrice) * shippingTaxPercentage)
-							shippingSatoshi += uint64(float32(shippingSatoshi) * shippingTaxPercentage)
-							if err != nil {
-								return 0, err
-							}
-							cs := combinedShipping{
-								quantity: int(item.Quantity),
-								price:    shippingSatoshi,
-								add:      true,
-								modifier: rulePrice,
-							}
-							combinedOptions = append(combinedOptions, cs)
-
-						case pb.Listing_ShippingOption_ShippingRules_COMBINED_SHIPPING_SUBTRACT:
-							itemShipping -= shippingPrice
-							rulePrice, err := n.getPriceInSatoshi(listing.Metadata.PricingCurrency, rule.Price)
-							rulePrice += uint64(float32(rulePrice) * shippingTaxPercentage)
-							shippingSatoshi += uint64(float32(shippingSatoshi) * shippingTaxPercentage)
-							if err != nil {
-								return 0, err
-							}
-							cs := combinedShipping{
-								quantity: int(item.Quantity),
-								price:    shippingSatoshi,
-								add:      false,
-								modifier: rulePrice,
-							}
-							combinedOptions = append(combinedOptions, cs)
-						}
-					}
-				}
-				// Apply tax
-				itemShipping += uint64(float32(itemShipping) * shippingTaxPercentage)
-				shippingTotal += itemShipping
-			}
-		}
-	}
-
-	// Process combined shipping rules
-	if len(combinedOptions) > 0 {
-		lowestPrice := int64(-1)
-		for _, v := range combinedOptions {
-			if int64(v.price) < lowestPrice || lowestPrice == -1 {
-				lowestPrice = int64(v.price)
-			}
-		}
-		shippingTotal += uint64(lowestPrice)
-		for _, o := range combinedOptions {
-			modifier := o.modifier
-			modifier *= (uint64(o.quantity) - 1)
-			if o.add {
-				shippingTotal += modifier
-			} else {
-				shippingTotal -= modifier
+			multihash, err := EncodeMultihash([]byte(couponCode))
+			if err != nil {
+				return order, err
 			}
+			line.CouponHashes = append(line.CouponHashes, multihash.B58String())
 		}
+		order.Lines = append(order.Lines, line)
 	}
-
-	total += shippingTotal
-	return total, nil
+	return order, nil
 }
 
-func (n *OpenBazaarNode) getPriceInSatoshi(currencyCode string, amount uint64) (uint64, error) {
-	if strings.ToLower(currencyCode) == strings.ToLower(n.Wallet.CurrencyCode()) {
-		return amount, nil
-	}
-	exchangeRate, err := n.ExchangeRates.GetExchangeRate(currencyCode)
-	if err != nil {
-		return 0, err
-	}
-	formatedAmount := float64(amount) / 100
-	btc := formatedAmount / exchangeRate
-	satoshis := btc * float64(n.ExchangeRates.UnitsPerCoin())
-	return uint64(satoshis), nil
+// Convert an amount to the smallest unit of the wallet's currency
+func (n *OpenBazaarNode) getPriceInSatoshi(wal bitcoin.BitcoinWallet, currencyCode string, amount uint64) (uint64, error) {
+	return n.newConverter(wal).ToSatoshis(currencyCode, amount)
 }
 
 func verifySignaturesOnOrder(contract *pb.RicardianContract) error {
@@ -884,7 +808,21 @@ func verifySignaturesOnOrder(contract *pb.RicardianContract) error {
 	return nil
 }
 
+/* Validate an order we've been sent. Orders for auction listings are only accepted
+   for a bid which won as the price is the winning bid. */
 func (n *OpenBazaarNode) ValidateOrder(contract *pb.RicardianContract) error {
+	if err := n.validateOrder(contract); err != nil {
+		return err
+	}
+	if isAuction(contract) {
+		if _, err := n.WinningBid(contract); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *OpenBazaarNode) validateOrder(contract *pb.RicardianContract) error {
 	listingMap := make(map[string]*pb.Listing)
 
 	// Check order contains all required fields
@@ -894,7 +832,7 @@ func (n *OpenBazaarNode) ValidateOrder(contract *pb.RicardianContract) error {
 	if contract.BuyerOrder.Payment == nil {
 		return errors.New("Order doesn't contain a payment")
 	}
-	if contract.BuyerOrder.BuyerID == nil {
+	if contract.BuyerOrder.BuyerID == nil || contract.BuyerOrder.BuyerID.Pubkeys == nil {
 		return errors.New("Order doesn't contain a buyer ID")
 	}
 	if len(contract.BuyerOrder.Items) == 0 {
@@ -911,6 +849,11 @@ func (n *OpenBazaarNode) ValidateOrder(contract *pb.RicardianContract) error {
 	if contract.BuyerOrder.Timestamp == nil {
 		return errors.New("Order is missing a timestamp")
 	}
+
+	// Validate the buyers's signature on the order before doing anything else with it
+	if err := verifySignaturesOnOrder(contract); err != nil {
+		return err
+	}
 	if contract.BuyerOrder.Payment.Method == pb.Order_Payment_MODERATED {
 		_, err := mh.FromB58String(contract.BuyerOrder.Payment.Moderator)
 		if err != nil {
@@ -1114,26 +1057,34 @@ collectListings:
 		}
 	}
 
-	// Validate the buyers's signature on the order
-	err := verifySignaturesOnOrder(contract)
+	// Validate the order can be priced
+	order, err := pricingOrder(contract)
 	if err != nil {
 		return err
 	}
+	if err := pricing.Validate(order); err != nil {
+		return err
+	}
 	return nil
 }
 
-func (n *OpenBazaarNode) ValidateDirectPaymentAddress(order *pb.Order) error {
+func (n *OpenBazaarNode) ValidateDirectPaymentAddress(contract *pb.RicardianContract) error {
+	order := contract.BuyerOrder
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return err
+	}
 	chaincode, err := hex.DecodeString(order.Payment.Chaincode)
 	if err != nil {
 		return err
 	}
 	parentFP := []byte{0x00, 0x00, 0x00, 0x00}
-	mECKey, err := n.Wallet.MasterPublicKey().ECPubKey()
+	mECKey, err := wal.MasterPublicKey().ECPubKey()
 	if err != nil {
 		return err
 	}
 	hdKey := hd.NewExtendedKey(
-		n.Wallet.Params().HDPublicKeyID[:],
+		wal.Params().HDPublicKeyID[:],
 		mECKey.SerializeCompressed(),
 		chaincode,
 		parentFP,
@@ -1146,7 +1097,7 @@ func (n *OpenBazaarNode) ValidateDirectPaymentAddress(order *pb.Order) error {
 		return err
 	}
 	hdKey = hd.NewExtendedKey(
-		n.Wallet.Params().HDPublicKeyID[:],
+		wal.Params().HDPublicKeyID[:],
 		order.BuyerID.Pubkeys.Bitcoin,
 		chaincode,
 		parentFP,
@@ -1158,7 +1109,7 @@ func (n *OpenBazaarNode) ValidateDirectPaymentAddress(order *pb.Order) error {
 	if err != nil {
 		return err
 	}
-	addr, redeemScript, err := n.Wallet.GenerateMultisigScript([]hd.ExtendedKey{*buyerKey, *vendorKey}, 1)
+	addr, redeemScript, err := wal.GenerateMultisigScript([]hd.ExtendedKey{*buyerKey, *vendorKey}, 1)
 	if order.Payment.Address != addr.EncodeAddress() {
 		return errors.New("Invalid payment address")
 	}
@@ -1168,7 +1119,12 @@ func (n *OpenBazaarNode) ValidateDirectPaymentAddress(order *pb.Order) error {
 	return nil
 }
 
-func (n *OpenBazaarNode) ValidateModeratedPaymentAddress(order *pb.Order) error {
+func (n *OpenBazaarNode) ValidateModeratedPaymentAddress(contract *pb.RicardianContract) error {
+	order := contract.BuyerOrder
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return err
+	}
 	ipnsPath := ipfspath.FromString(order.Payment.Moderator + "/profile")
 	profileBytes, err := ipfs.ResolveThenCat(n.Context, ipnsPath)
 	if err != nil {
@@ -1189,12 +1145,12 @@ func (n *OpenBazaarNode) ValidateModeratedPaymentAddress(order *pb.Order) error
 		return err
 	}
 	parentFP := []byte{0x00, 0x00, 0x00, 0x00}
-	mECKey, err := n.Wallet.MasterPublicKey().ECPubKey()
+	mECKey, err := wal.MasterPublicKey().ECPubKey()
 	if err != nil {
 		return err
 	}
 	hdKey := hd.NewExtendedKey(
-		n.Wallet.Params().HDPublicKeyID[:],
+		wal.Params().HDPublicKeyID[:],
 		mECKey.SerializeCompressed(),
 		chaincode,
 		parentFP,
@@ -1207,7 +1163,7 @@ func (n *OpenBazaarNode) ValidateModeratedPaymentAddress(order *pb.Order) error
 		return err
 	}
 	hdKey = hd.NewExtendedKey(
-		n.Wallet.Params().HDPublicKeyID[:],
+		wal.Params().HDPublicKeyID[:],
 		order.BuyerID.Pubkeys.Bitcoin,
 		chaincode,
 		parentFP,
@@ -1220,7 +1176,7 @@ func (n *OpenBazaarNode) ValidateModeratedPaymentAddress(order *pb.Order) error
 		return err
 	}
 	hdKey = hd.NewExtendedKey(
-		n.Wallet.Params().HDPublicKeyID[:],
+		wal.Params().HDPublicKeyID[:],
 		moderatorBytes,
 		chaincode,
 		parentFP,
@@ -1232,7 +1188,7 @@ func (n *OpenBazaarNode) ValidateModeratedPaymentAddress(order *pb.Order) error
 	if err != nil {
 		return err
 	}
-	addr, redeemScript, err := n.Wallet.GenerateMultisigScript([]hd.ExtendedKey{*buyerKey, *vendorKey, *ModeratorKey}, 2)
+	addr, redeemScript, err := wal.GenerateMultisigScript([]hd.ExtendedKey{*buyerKey, *vendorKey, *ModeratorKey}, 2)
 	if order.Payment.Address != addr.EncodeAddress() {
 		return errors.New("Invalid payment address")
 	}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
+)
+
+type testWallet struct {
+	bitcoin.BitcoinWallet
+	code string
+}
+
+func (w *testWallet) CurrencyCode() string {
+	return w.code
+}
+
+type testRates map[string]float64
+
+func (r testRates) GetExchangeRate(currencyCode string) (float64, error) {
+	rate, ok := r[currencyCode]
+	if !ok {
+		return 0, errors.New("Currency not tracked")
+	}
+	return rate, nil
+}
+
+func (r testRates) GetLatestRate(currencyCode string) (float64, error) {
+	return r.GetExchangeRate(currencyCode)
+}
+
+func (r testRates) GetAllRates() (map[string]float64, error) {
+	return r, nil
+}
+
+func (r testRates) UnitsPerCoin() int {
+	return 100000000
+}
+
+func TestNewConverter(t *testing.T) {
+	btc := &testWallet{code: "BTC"}
+	ltc := &testWallet{code: "LTC"}
+	node := &OpenBazaarNode{
+		Wallet:            btc,
+		ExchangeRates:     testRates{"USD": 5000},
+		CoinExchangeRates: map[string]bitcoin.ExchangeRates{"ltc": testRates{"USD": 50}},
+	}
+	satoshis, err := node.getPriceInSatoshi(btc, "USD", 1000)
+	if err != nil || satoshis != 200000 {
+		t.Error("Returned incorrect bitcoin price", satoshis, err)
+	}
+	satoshis, err = node.getPriceInSatoshi(ltc, "USD", 1000)
+	if err != nil || satoshis != 20000000 {
+		t.Error("Returned incorrect litecoin price", satoshis, err)
+	}
+	if _, err := node.getPriceInSatoshi(&testWallet{code: "BCH"}, "USD", 1000); err == nil {
+		t.Error("Priced in a coin without exchange rates")
+	}
+}
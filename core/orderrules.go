@@ -0,0 +1,242 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	crypto "gx/ipfs/QmPGxZ1DP2w45WcogpW1h43BvseXbfke9N91qotpoQcUeS/go-libp2p-crypto"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+var ErrNotAutoFulfilled = errors.New("Order can't be fulfilled automatically")
+
+// Stops an order being handled twice when it's funded and confirmed at the same time
+var orderRulesLock sync.Mutex
+
+// Return the vendor's rule for a listing, falling back to the default rule
+func (n *OpenBazaarNode) OrderRule(slug string) repo.OrderRule {
+	settings, err := n.Datastore.Settings().Get()
+	if err != nil {
+		return repo.OrderRule{}
+	}
+	return orderRule(settings, slug)
+}
+
+func orderRule(settings repo.SettingsData, slug string) repo.OrderRule {
+	if settings.OrderRules == nil {
+		return repo.OrderRule{}
+	}
+	if rule, ok := (*settings.OrderRules)[slug]; ok {
+		return rule
+	}
+	return (*settings.OrderRules)[repo.DefaultOrderRule]
+}
+
+/* Return why an order should be rejected under the vendor's rules, or an empty string if
+   it shouldn't be. The order must have been validated first. */
+func (n *OpenBazaarNode) RejectedByRules(contract *pb.RicardianContract) string {
+	order := contract.BuyerOrder
+	if order.Shipping == nil {
+		return ""
+	}
+	settings, err := n.Datastore.Settings().Get()
+	if err != nil {
+		return ""
+	}
+	for _, listing := range contract.VendorListings {
+		if listing.Metadata == nil || listing.Metadata.ContractType != pb.Listing_Metadata_PHYSICAL_GOOD {
+			continue
+		}
+		if !orderRule(settings, listing.Slug).RejectOutsideShippingRegions || shipsTo(listing, order.Shipping.Country) {
+			continue
+		}
+		return fmt.Sprintf("%s doesn't ship to %s", listing.Slug, order.Shipping.Country)
+	}
+	return ""
+}
+
+// Whether any of a listing's shipping options go to a country
+func shipsTo(listing *pb.Listing, country pb.CountryCode) bool {
+	for _, option := range listing.ShippingOptions {
+		for _, region := range option.Regions {
+			if region == country || region == pb.CountryCode_ALL {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+/* Called when a sale is funded. Offline orders are rejected or confirmed if the vendor's
+   rules say so and confirmed orders are fulfilled if they can be. */
+func (n *OpenBazaarNode) OnSaleFunded(orderId string) {
+	_, state, _, _, _, err := n.Datastore.Sales().GetByOrderId(orderId)
+	if err != nil {
+		log.Errorf("Error loading funded sale %s: %s", orderId, err.Error())
+		return
+	}
+	switch state {
+	case pb.OrderState_PENDING:
+		if err := n.applyConfirmationRules(orderId); err != nil {
+			log.Errorf("Error applying order rules to %s: %s", orderId, err.Error())
+		}
+	case pb.OrderState_FUNDED:
+		if err := n.AutoFulfillOrder(orderId); err != nil && err != ErrNotAutoFulfilled {
+			log.Errorf("Error fulfilling order %s automatically: %s", orderId, err.Error())
+		}
+	}
+}
+
+/* Reject a funded offline order the rules don't allow or confirm it if every listing
+   allows its payment to be confirmed without the vendor. Confirming it fulfills it too
+   if it can be. */
+func (n *OpenBazaarNode) applyConfirmationRules(orderId string) error {
+	orderRulesLock.Lock()
+	defer orderRulesLock.Unlock()
+	contract, state, funded, records, _, err := n.Datastore.Sales().GetByOrderId(orderId)
+	if err != nil {
+		return err
+	}
+	if state != pb.OrderState_PENDING || !funded {
+		return nil
+	}
+	if reason := n.RejectedByRules(contract); reason != "" {
+		if err := n.RejectOfflineOrder(contract, records); err != nil {
+			return err
+		}
+		n.RecordOrderEvent(orderId, repo.OrderActionRejected, reason)
+		log.Infof("Rejected order %s: %s", orderId, reason)
+		return nil
+	}
+	settings, err := n.Datastore.Settings().Get()
+	if err != nil {
+		return err
+	}
+	var limit uint64
+	for i, listing := range contract.VendorListings {
+		if listing.Metadata.ContractType == pb.Listing_Metadata_CROWD_FUND {
+			return nil
+		}
+		max := orderRule(settings, listing.Slug).AutoConfirmMax
+		if i == 0 || max < limit {
+			limit = max
+		}
+	}
+	amount := contract.BuyerOrder.Payment.Amount
+	if limit == 0 || amount > limit {
+		return nil
+	}
+	total, err := n.CalculateOrderTotal(contract)
+	if err != nil {
+		return err
+	}
+	if !n.ValidatePaymentAmount(total, amount) {
+		log.Infof("Not confirming order %s automatically as its payment doesn't match its total", orderId)
+		return nil
+	}
+	if err := n.ConfirmOfflineOrder(contract, records); err != nil {
+		return err
+	}
+	n.RecordOrderEvent(orderId, repo.OrderActionConfirmed, fmt.Sprintf("Payment of %d is within the limit of %d", amount, limit))
+	log.Infof("Confirmed order %s", orderId)
+	return nil
+}
+
+/* Fulfill a funded sale without the vendor. Every listing in the order must be a digital good
+   with a file, a license key pool or a rule to fulfill it, or a service with a rule to fulfill
+   it. Otherwise ErrNotAutoFulfilled is returned and the vendor fulfills it themselves. */
+func (n *OpenBazaarNode) AutoFulfillOrder(orderId string) error {
+	orderRulesLock.Lock()
+	defer orderRulesLock.Unlock()
+	contract, state, _, records, _, err := n.Datastore.Sales().GetByOrderId(orderId)
+	if err != nil {
+		return err
+	}
+	if state != pb.OrderState_FUNDED {
+		return ErrNotAutoFulfilled
+	}
+	settings, err := n.Datastore.Settings().Get()
+	if err != nil {
+		return err
+	}
+	pools, err := n.Datastore.LicenseKeys().GetAll()
+	if err != nil {
+		return err
+	}
+
+	// Check every listing can be fulfilled before any files are added or keys assigned
+	assets := make(map[string]*repo.DigitalAsset)
+	for _, listing := range contract.VendorListings {
+		rule := orderRule(settings, listing.Slug)
+		switch listing.Metadata.ContractType {
+		case pb.Listing_Metadata_DIGITAL_GOOD:
+			asset, err := n.Datastore.DigitalAssets().Get(listing.Slug)
+			if err == nil {
+				assets[listing.Slug] = &asset
+			} else if err != repo.ErrDigitalAssetNotFound {
+				return err
+			}
+			if _, ok := pools[listing.Slug]; !ok && assets[listing.Slug] == nil && !rule.AutoFulfill {
+				return ErrNotAutoFulfilled
+			}
+		case pb.Listing_Metadata_SERVICE:
+			if !rule.AutoFulfill {
+				return ErrNotAutoFulfilled
+			}
+		default:
+			return ErrNotAutoFulfilled
+		}
+	}
+	buyerKey, err := crypto.UnmarshalPublicKey(contract.BuyerOrder.BuyerID.Pubkeys.Guid)
+	if err != nil {
+		return err
+	}
+
+	// Prepare every fulfillment before sending any so an empty key pool doesn't leave the order part fulfilled
+	fulfillments := make([]*pb.OrderFulfillment, len(contract.VendorListings))
+	for i, listing := range contract.VendorListings {
+		fulfillment := &pb.OrderFulfillment{
+			OrderId: orderId,
+			Slug:    listing.Slug,
+		}
+		if listing.Metadata.ContractType == pb.Listing_Metadata_DIGITAL_GOOD {
+			_, pooled := pools[listing.Slug]
+			delivery, err := n.newDigitalDelivery(orderId, listing.Slug, assets[listing.Slug], pooled, buyerKey)
+			if err != nil {
+				return err
+			}
+			if rule := orderRule(settings, listing.Slug); rule.AutoFulfill {
+				delivery.Url = rule.DeliveryUrl
+				delivery.Password = rule.DeliveryPassword
+			}
+			if delivery.Hash != "" || len(delivery.LicenseKey) > 0 || delivery.Url != "" || delivery.Password != "" {
+				fulfillment.DigitalDelivery = []*pb.OrderFulfillment_DigitalDelivery{delivery}
+			}
+		}
+		fulfillments[i] = fulfillment
+	}
+	for _, fulfillment := range fulfillments {
+		if err := n.FulfillOrder(fulfillment, contract, records); err != nil {
+			return err
+		}
+	}
+	n.RecordOrderEvent(orderId, repo.OrderActionFulfilled, "")
+	log.Infof("Fulfilled order %s automatically", orderId)
+	return nil
+}
+
+// Add something done to an order on the vendor's behalf to its history
+func (n *OpenBazaarNode) RecordOrderEvent(orderId, action, detail string) {
+	event := repo.OrderEvent{
+		OrderId:   orderId,
+		Action:    action,
+		Detail:    detail,
+		Timestamp: time.Now(),
+	}
+	if err := n.Datastore.OrderHistory().Put(event); err != nil {
+		log.Errorf("Error recording %s for order %s: %s", action, orderId, err.Error())
+	}
+}
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+func TestOrderRule(t *testing.T) {
+	if rule := orderRule(repo.SettingsData{}, "ebook"); rule != (repo.OrderRule{}) {
+		t.Error("Returned a rule when none are set")
+	}
+	rules := map[string]repo.OrderRule{
+		repo.DefaultOrderRule: {AutoConfirmMax: 1000},
+		"ebook":               {AutoFulfill: true, DeliveryUrl: "https://example.com/ebook"},
+	}
+	settings := repo.SettingsData{OrderRules: &rules}
+	if rule := orderRule(settings, "ebook"); !rule.AutoFulfill || rule.AutoConfirmMax != 0 {
+		t.Error("Listing's own rule wasn't used")
+	}
+	if rule := orderRule(settings, "tshirt"); rule.AutoConfirmMax != 1000 {
+		t.Error("Default rule wasn't used")
+	}
+}
+
+func TestShipsTo(t *testing.T) {
+	listing := &pb.Listing{
+		ShippingOptions: []*pb.Listing_ShippingOption{
+			{Name: "domestic", Regions: []pb.CountryCode{pb.CountryCode_UNITED_STATES}},
+			{Name: "europe", Regions: []pb.CountryCode{pb.CountryCode_GERMANY, pb.CountryCode_FRANCE}},
+		},
+	}
+	if !shipsTo(listing, pb.CountryCode_FRANCE) {
+		t.Error("Listing should ship to a country in any of its options")
+	}
+	if shipsTo(listing, pb.CountryCode_CANADA) {
+		t.Error("Listing shouldn't ship outside its regions")
+	}
+	listing.ShippingOptions[1].Regions = append(listing.ShippingOptions[1].Regions, pb.CountryCode_ALL)
+	if !shipsTo(listing, pb.CountryCode_CANADA) {
+		t.Error("Listing should ship everywhere with ALL in its regions")
+	}
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"sort"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	rep "github.com/OpenBazaar/openbazaar-go/net/repointer"
+	sto "github.com/OpenBazaar/openbazaar-go/storage"
+	ma "gx/ipfs/QmSWLfmj5frN9xVLMMN846dMDriy5wN5jeghUm7aTW3DAG/go-multiaddr"
+)
+
+const OutboxJanitorInterval = time.Hour
+
+// An offline message which its recipient hasn't acknowledged yet
+type OutboxMessage struct {
+	PointerId string    `json:"pointerId"`
+	Recipient string    `json:"recipient"`
+	Address   string    `json:"address"`
+	Timestamp time.Time `json:"timestamp"`
+	Age       int64     `json:"age"`
+}
+
+// Return the offline messages waiting to be acknowledged, oldest first. Age is in seconds.
+func (n *OpenBazaarNode) GetOutbox() ([]OutboxMessage, error) {
+	pointers, err := n.Datastore.Pointers().GetByPurpose(ipfs.MESSAGE)
+	if err != nil {
+		return nil, err
+	}
+	outbox := []OutboxMessage{}
+	for _, p := range pointers {
+		m := OutboxMessage{
+			PointerId: p.Value.ID.Pretty(),
+			Timestamp: p.Timestamp,
+			Age:       int64(time.Since(p.Timestamp) / time.Second),
+		}
+		if p.CancelID != nil {
+			m.Recipient = p.CancelID.Pretty()
+		}
+		if len(p.Value.Addrs) > 0 {
+			m.Address = p.Value.Addrs[0].String()
+		}
+		outbox = append(outbox, m)
+	}
+	sort.Slice(outbox, func(i, j int) bool {
+		return outbox[i].Timestamp.Before(outbox[j].Timestamp)
+	})
+	return outbox, nil
+}
+
+// Periodically remove expired messages and any stored messages no longer pointed to
+func (n *OpenBazaarNode) StartOutboxJanitor() {
+	tick := time.NewTicker(OutboxJanitorInterval)
+	defer tick.Stop()
+	go n.CleanOutbox()
+	for range tick.C {
+		go n.CleanOutbox()
+	}
+}
+
+/* Delete offline messages and channel posts which have expired along with their
+   pointers. If the storage can sweep, whatever it holds that no pointer refers to is
+   removed too, which catches messages whose delete failed when they were acknowledged.
+   Outgoing message records are pruned once they're acked or expired. Returns how many
+   messages were removed. */
+func (n *OpenBazaarNode) CleanOutbox() (int, error) {
+	return n.cleanOutbox(time.Now())
+}
+
+func (n *OpenBazaarNode) cleanOutbox(now time.Time) (int, error) {
+	pointers, err := n.Datastore.Pointers().GetAll()
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	var inUse []ma.Multiaddr
+	for _, p := range pointers {
+		age := now.Sub(p.Timestamp)
+		expired := (p.Purpose == ipfs.MESSAGE && age > rep.MessagePointerTTL) || (p.Purpose == ipfs.CHANNEL && age > rep.ChannelPointerTTL)
+		if !expired {
+			inUse = append(inUse, p.Value.Addrs...)
+			continue
+		}
+		if len(p.Value.Addrs) > 0 {
+			if err := n.MessageStorage.Delete(p.Value.Addrs[0]); err != nil {
+				// Keep the pointer so the delete is retried next time
+				log.Errorf("Error deleting expired message from storage: %s", err.Error())
+				inUse = append(inUse, p.Value.Addrs...)
+				continue
+			}
+		}
+		if err := n.Datastore.Pointers().Delete(p.Value.ID); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	if _, err := n.Datastore.OutgoingMessages().Prune(now.Add(-OutgoingAckedRetention), now.Add(-rep.MessagePointerTTL)); err != nil {
+		log.Errorf("Error pruning outgoing messages: %s", err.Error())
+	}
+	if sweeper, ok := n.MessageStorage.(sto.Sweeper); ok {
+		swept, err := sweeper.Sweep(inUse)
+		removed += swept
+		if err != nil {
+			log.Errorf("Error sweeping message storage: %s", err.Error())
+			return removed, err
+		}
+	}
+	if removed > 0 {
+		log.Infof("Removed %d messages from the outbox", removed)
+	}
+	return removed, nil
+}
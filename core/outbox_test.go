@@ -0,0 +1,88 @@
+package core
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/OpenBazaar/openbazaar-go/repo/db"
+	ma "gx/ipfs/QmSWLfmj5frN9xVLMMN846dMDriy5wN5jeghUm7aTW3DAG/go-multiaddr"
+	peer "gx/ipfs/QmWUswjn261LSyVxWAEpMVtPdy8zmKBJJfBpG3Qdpa8ZsE/go-libp2p-peer"
+	multihash "gx/ipfs/QmbZ6Cee2uHjG7hf19qLHppgKDRtaG4CVtMzdmK9VCVqLu/go-multihash"
+	ps "gx/ipfs/Qme1g4e3m2SmdiSGGU3vSWmUStwUjc5oECnEriaK9Xa1HU/go-libp2p-peerstore"
+)
+
+type memoryStorage struct {
+	deleted []string
+	fail    bool
+}
+
+func (s *memoryStorage) Store(peerID peer.ID, ciphertext []byte) (ma.Multiaddr, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *memoryStorage) Delete(addr ma.Multiaddr) error {
+	if s.fail {
+		return errors.New("storage unavailable")
+	}
+	s.deleted = append(s.deleted, addr.String())
+	return nil
+}
+
+func TestCleanOutbox(t *testing.T) {
+	repoPath, err := ioutil.TempDir("", "outbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(repoPath)
+	os.MkdirAll(path.Join(repoPath, "datastore"), os.ModePerm)
+	datastore, err := db.Create(repoPath, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer datastore.Close()
+	if err := datastore.Config().Init("", []byte{}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	recipient, _ := peer.IDB58Decode("QmWUswjn261LSyVxWAEpMVtPdy8zmKBJJfBpG3Qdpa8ZsE")
+	pointerID, _ := peer.IDB58Decode("QmPGxZ1DP2w45WcogpW1h43BvseXbfke9N91qotpoQcUeS")
+	addr, _ := ma.NewMultiaddr("/ipfs/QmbZ6Cee2uHjG7hf19qLHppgKDRtaG4CVtMzdmK9VCVqLu/")
+	key, err := ipfs.PointerKey(multihash.Multihash(recipient), DefaultPointerPrefixLength)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = datastore.Pointers().Put(ipfs.Pointer{Cid: key, Value: ps.PeerInfo{ID: pointerID, Addrs: []ma.Multiaddr{addr}}, Purpose: ipfs.MESSAGE, CancelID: &recipient})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storage := &memoryStorage{}
+	node := &OpenBazaarNode{Datastore: datastore, MessageStorage: storage}
+	outbox, err := node.GetOutbox()
+	if err != nil || len(outbox) != 1 || outbox[0].Recipient != recipient.Pretty() || outbox[0].Address != addr.String() {
+		t.Fatal("Returned wrong outbox", err)
+	}
+
+	// Messages which haven't expired are kept
+	if n, err := node.cleanOutbox(time.Now()); err != nil || n != 0 || len(storage.deleted) != 0 {
+		t.Error("Removed a message which hasn't expired", err)
+	}
+
+	// A failed delete leaves the pointer to retry later
+	storage.fail = true
+	if n, _ := node.cleanOutbox(time.Now().Add(31 * 24 * time.Hour)); n != 0 {
+		t.Error("Removed a message storage failed to delete")
+	}
+	storage.fail = false
+	if n, err := node.cleanOutbox(time.Now().Add(31 * 24 * time.Hour)); err != nil || n != 1 || len(storage.deleted) != 1 || storage.deleted[0] != addr.String() {
+		t.Error("Expired message was not removed", err)
+	}
+	if outbox, _ := node.GetOutbox(); len(outbox) != 0 {
+		t.Error("Expired message is still in the outbox")
+	}
+}
@@ -0,0 +1,237 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	ma "gx/ipfs/QmSWLfmj5frN9xVLMMN846dMDriy5wN5jeghUm7aTW3DAG/go-multiaddr"
+	inet "gx/ipfs/QmVtMT3fD7DzQNW7hdm6Xe6KPstzcggrhNpeVZ4422UpKK/go-libp2p-net"
+	peer "gx/ipfs/QmWUswjn261LSyVxWAEpMVtPdy8zmKBJJfBpG3Qdpa8ZsE/go-libp2p-peer"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/api/notifications"
+	rep "github.com/OpenBazaar/openbazaar-go/net/repointer"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"golang.org/x/net/context"
+)
+
+const (
+	// How often direct delivery of messages stored offline is retried for peers we haven't seen connect
+	OutgoingRetryInterval = time.Minute * 15
+
+	// Acked messages are kept this long so their delivery can still be looked up
+	OutgoingAckedRetention = time.Hour * 24 * 7
+)
+
+var (
+	outgoingRetryLock sync.Mutex
+	outgoingRetrying  = make(map[string]bool)
+)
+
+/* Save how far a message sent to a peer got. Offline acks aren't tracked. Messages
+   stored offline are kept serialized so direct delivery can be retried, except for
+   orders as a vendor treats an order which arrives directly as one the buyer is
+   waiting on a response to. */
+func (n *OpenBazaarNode) recordOutgoingMessage(p peer.ID, m *pb.Message, state, pointerId string, sendErr error) {
+	if m.MessageType == pb.Message_OFFLINE_ACK {
+		return
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		log.Errorf("Error saving outgoing message: %s", err.Error())
+		return
+	}
+	now := time.Now()
+	out := repo.OutgoingMessage{
+		Id:          hex.EncodeToString(b),
+		PeerId:      p.Pretty(),
+		MessageType: m.MessageType.String(),
+		Reference:   n.messageReference(m),
+		State:       state,
+		PointerId:   pointerId,
+		Attempts:    1,
+		Timestamp:   now,
+		Updated:     now,
+	}
+	if sendErr != nil {
+		out.Error = sendErr.Error()
+	}
+	if state == repo.OutgoingMessageStoredOffline && m.MessageType != pb.Message_ORDER {
+		ser, err := proto.Marshal(m)
+		if err == nil {
+			out.Message = ser
+		}
+	}
+	if err := n.Datastore.OutgoingMessages().Put(out); err != nil {
+		log.Errorf("Error saving outgoing message: %s", err.Error())
+	}
+}
+
+// Return the id of the chat message or order a message is about, if any
+func (n *OpenBazaarNode) messageReference(m *pb.Message) string {
+	if m.Payload == nil {
+		return ""
+	}
+	switch m.MessageType {
+	case pb.Message_CHAT:
+		chat := new(pb.Chat)
+		if err := ptypes.UnmarshalAny(m.Payload, chat); err == nil && chat.Flag == pb.Chat_MESSAGE {
+			return chat.MessageId
+		}
+	case pb.Message_ORDER_CANCEL:
+		return string(m.Payload.Value)
+	case pb.Message_ORDER_REJECT:
+		reject := new(pb.OrderReject)
+		if err := ptypes.UnmarshalAny(m.Payload, reject); err == nil {
+			return reject.OrderID
+		}
+	case pb.Message_DISPUTE_UPDATE:
+		update := new(pb.DisputeUpdate)
+		if err := ptypes.UnmarshalAny(m.Payload, update); err == nil {
+			return update.OrderId
+		}
+	case pb.Message_ORDER, pb.Message_ORDER_CONFIRMATION, pb.Message_REFUND, pb.Message_ORDER_FULFILLMENT,
+		pb.Message_ORDER_COMPLETION, pb.Message_DISPUTE_OPEN, pb.Message_DISPUTE_CLOSE, pb.Message_BID, pb.Message_BID_ACCEPT:
+		contract := new(pb.RicardianContract)
+		if err := ptypes.UnmarshalAny(m.Payload, contract); err == nil && contract.BuyerOrder != nil {
+			if orderId, err := n.CalcOrderId(contract.BuyerOrder); err == nil {
+				return orderId
+			}
+		}
+	}
+	return ""
+}
+
+/* Retry direct delivery of the messages stored offline for a peer, oldest first. When
+   one gets through its offline copy is withdrawn so the peer doesn't get it twice.
+   Retrying stops at the first failure as the peer is most likely unreachable. */
+func (n *OpenBazaarNode) RetryOutgoingMessages(p peer.ID) {
+	outgoingRetryLock.Lock()
+	if outgoingRetrying[p.Pretty()] {
+		outgoingRetryLock.Unlock()
+		return
+	}
+	outgoingRetrying[p.Pretty()] = true
+	outgoingRetryLock.Unlock()
+	defer func() {
+		outgoingRetryLock.Lock()
+		delete(outgoingRetrying, p.Pretty())
+		outgoingRetryLock.Unlock()
+	}()
+
+	messages, err := n.Datastore.OutgoingMessages().GetUndelivered(p.Pretty(), time.Now().Add(-rep.MessagePointerTTL))
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, out := range messages {
+		if len(out.Message) == 0 {
+			continue
+		}
+		m := new(pb.Message)
+		if err := proto.Unmarshal(out.Message, m); err != nil {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		err := n.Service.SendMessage(ctx, p, m)
+		cancel()
+		out.Attempts++
+		out.Updated = time.Now()
+		if err != nil {
+			out.Error = err.Error()
+			n.Datastore.OutgoingMessages().UpdateUnlessAcked(out)
+			return
+		}
+		n.withdrawOfflineMessage(out.PointerId)
+		out.State = repo.OutgoingMessageSentDirect
+		out.Message = nil
+		out.Error = ""
+		// The peer may have acked the offline copy while we were sending
+		saved, err := n.Datastore.OutgoingMessages().UpdateUnlessAcked(out)
+		if err != nil {
+			log.Errorf("Error saving outgoing message: %s", err.Error())
+			continue
+		} else if !saved {
+			continue
+		}
+		if n.Broadcast != nil {
+			n.Broadcast <- notifications.MessageDelivery{
+				Id:        out.Id,
+				PeerId:    out.PeerId,
+				Reference: out.Reference,
+				State:     out.State,
+			}
+		}
+	}
+}
+
+// Delete the pointer to a message stored offline and ask the storage to drop its copy
+func (n *OpenBazaarNode) withdrawOfflineMessage(pointerId string) {
+	pid, err := peer.IDB58Decode(pointerId)
+	if err != nil {
+		return
+	}
+	pointer, err := n.Datastore.Pointers().Get(pid)
+	if err != nil {
+		return
+	}
+	if err := n.Datastore.Pointers().Delete(pid); err != nil {
+		log.Errorf("Error deleting offline message pointer: %s", err.Error())
+		return
+	}
+	if n.MessageStorage != nil && len(pointer.Value.Addrs) > 0 {
+		if err := n.MessageStorage.Delete(pointer.Value.Addrs[0]); err != nil {
+			log.Errorf("Error deleting offline message from storage: %s", err.Error())
+		}
+	}
+}
+
+/* Retry delivery of messages stored offline whenever their recipient connects to us
+   and otherwise every OutgoingRetryInterval. Messages are retried until they're
+   acked or older than the offline message pointer TTL. */
+func (n *OpenBazaarNode) StartOutgoingRetries() {
+	n.IpfsNode.PeerHost.Network().Notify(&outgoingNotifiee{n})
+	tick := time.NewTicker(OutgoingRetryInterval)
+	defer tick.Stop()
+	for range tick.C {
+		n.retryAllOutgoingMessages()
+	}
+}
+
+func (n *OpenBazaarNode) retryAllOutgoingMessages() {
+	messages, err := n.Datastore.OutgoingMessages().GetUndelivered("", time.Now().Add(-rep.MessagePointerTTL))
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	tried := make(map[string]bool)
+	for _, out := range messages {
+		if tried[out.PeerId] || len(out.Message) == 0 {
+			continue
+		}
+		tried[out.PeerId] = true
+		p, err := peer.IDB58Decode(out.PeerId)
+		if err != nil {
+			continue
+		}
+		n.RetryOutgoingMessages(p)
+	}
+}
+
+// Retries delivery to peers as they connect
+type outgoingNotifiee struct {
+	node *OpenBazaarNode
+}
+
+func (o *outgoingNotifiee) Connected(_ inet.Network, c inet.Conn) {
+	go o.node.RetryOutgoingMessages(c.RemotePeer())
+}
+
+func (o *outgoingNotifiee) Listen(inet.Network, ma.Multiaddr)      {}
+func (o *outgoingNotifiee) ListenClose(inet.Network, ma.Multiaddr) {}
+func (o *outgoingNotifiee) Disconnected(inet.Network, inet.Conn)   {}
+func (o *outgoingNotifiee) OpenedStream(inet.Network, inet.Stream) {}
+func (o *outgoingNotifiee) ClosedStream(inet.Network, inet.Stream) {}
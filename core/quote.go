@@ -0,0 +1,126 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/pricing"
+)
+
+// An amount in a listing's pricing currency along with what it comes to in satoshis
+type QuoteAmount struct {
+	Currency string `json:"currency,omitempty"`
+	Amount   uint64 `json:"amount"`
+	Satoshis uint64 `json:"satoshis"`
+}
+
+// An item's subtotal includes its coupons and tax but not its shipping
+type QuoteItem struct {
+	ListingHash string      `json:"listingHash"`
+	Slug        string      `json:"slug"`
+	Quantity    uint32      `json:"quantity"`
+	Subtotal    QuoteAmount `json:"subtotal"`
+	Shipping    QuoteAmount `json:"shipping"`
+}
+
+/* OrderQuote is what an order would cost. Combined shipping is only included in the
+   order's shipping. The order's shipping and total are only given in a pricing currency
+   when every listing in it is priced in the same one. */
+type OrderQuote struct {
+	Items         []QuoteItem        `json:"items"`
+	Shipping      QuoteAmount        `json:"shipping"`
+	Total         QuoteAmount        `json:"total"`
+	ExchangeRates map[string]float64 `json:"exchangeRates"`
+}
+
+/* Price an order without placing it. The listings are fetched and validated like they
+   are for a purchase but nothing is signed, sent or saved. */
+func (n *OpenBazaarNode) QuoteOrder(data *PurchaseData) (*OrderQuote, error) {
+	if len(data.Items) == 0 {
+		return nil, errors.New("Order has no items")
+	}
+	contract, err := n.quoteContract(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, listing := range contract.VendorListings {
+		if listing.Metadata.Format == pb.Listing_Metadata_AUCTION {
+			return nil, fmt.Errorf("Listing %s is an auction and must be bid on", listing.Slug)
+		}
+	}
+	breakdown, err := n.CalculateOrderBreakdown(contract)
+	if err != nil {
+		return nil, err
+	}
+	order, err := pricingOrder(contract)
+	if err != nil {
+		return nil, err
+	}
+	quote := &OrderQuote{
+		Items:         make([]QuoteItem, len(breakdown.Items)),
+		Shipping:      QuoteAmount{Satoshis: breakdown.Shipping},
+		Total:         QuoteAmount{Satoshis: breakdown.Total},
+		ExchangeRates: breakdown.ExchangeRates,
+	}
+	for i, item := range breakdown.Items {
+		quote.Items[i] = QuoteItem{
+			ListingHash: item.ListingHash,
+			Slug:        order.Lines[i].Listing.Slug,
+			Quantity:    item.Quantity,
+			Subtotal:    QuoteAmount{Satoshis: item.Total - item.Shipping},
+			Shipping:    QuoteAmount{Satoshis: item.Shipping},
+		}
+	}
+
+	// Price the items again in their pricing currencies, which needs no exchange rates
+	var currencies []string
+	groups := make(map[string][]int)
+	for i, line := range order.Lines {
+		currency := strings.ToUpper(line.Listing.Metadata.PricingCurrency)
+		if _, ok := groups[currency]; !ok {
+			currencies = append(currencies, currency)
+		}
+		groups[currency] = append(groups[currency], i)
+	}
+	for _, currency := range currencies {
+		group := pricing.Order{Country: order.Country}
+		for _, i := range groups[currency] {
+			group.Lines = append(group.Lines, order.Lines[i])
+		}
+		b, err := pricing.Calculate(group, pricing.NewConverter(currency, nil))
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range groups[currency] {
+			item := b.Items[j]
+			quote.Items[i].Subtotal.Currency = currency
+			quote.Items[i].Subtotal.Amount = item.Total - item.Shipping
+			quote.Items[i].Shipping.Currency = currency
+			quote.Items[i].Shipping.Amount = item.Shipping
+		}
+		if len(currencies) == 1 {
+			quote.Shipping.Currency = currency
+			quote.Shipping.Amount = b.Shipping
+			quote.Total.Currency = currency
+			quote.Total.Amount = b.Total
+		}
+	}
+	return quote, nil
+}
+
+// Build an order for pricing. Unlike a purchase it has no buyer ID, keys or signatures.
+func (n *OpenBazaarNode) quoteContract(data *PurchaseData) (*pb.RicardianContract, error) {
+	contract := new(pb.RicardianContract)
+	order := &pb.Order{
+		Shipping: &pb.Order_Shipping{
+			Country: pb.CountryCode(pb.CountryCode_value[data.CountryCode]),
+		},
+	}
+	if err := n.addOrderItems(contract, order, data.Items); err != nil {
+		return nil, err
+	}
+	contract.BuyerOrder = order
+	return contract, nil
+}
@@ -15,6 +15,10 @@ import (
 )
 
 func (n *OpenBazaarNode) RefundOrder(contract *pb.RicardianContract, records []*spvwallet.TransactionRecord) error {
+	wal, err := n.WalletForContract(contract)
+	if err != nil {
+		return err
+	}
 	refundMsg := new(pb.Refund)
 	orderId, err := n.CalcOrderId(contract.BuyerOrder)
 	if err != nil {
@@ -40,7 +44,7 @@ func (n *OpenBazaarNode) RefundOrder(contract *pb.RicardianContract, records []*
 			}
 		}
 
-		refundAddress, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, n.Wallet.Params())
+		refundAddress, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, wal.Params())
 		if err != nil {
 			return err
 		}
@@ -58,7 +62,7 @@ func (n *OpenBazaarNode) RefundOrder(contract *pb.RicardianContract, records []*
 			return err
 		}
 		parentFP := []byte{0x00, 0x00, 0x00, 0x00}
-		mPrivKey := n.Wallet.MasterPrivateKey()
+		mPrivKey := wal.MasterPrivateKey()
 		if err != nil {
 			return err
 		}
@@ -67,7 +71,7 @@ func (n *OpenBazaarNode) RefundOrder(contract *pb.RicardianContract, records []*
 			return err
 		}
 		hdKey := hd.NewExtendedKey(
-			n.Wallet.Params().HDPrivateKeyID[:],
+			wal.Params().HDPrivateKeyID[:],
 			mECKey.Serialize(),
 			chaincode,
 			parentFP,
@@ -81,7 +85,7 @@ func (n *OpenBazaarNode) RefundOrder(contract *pb.RicardianContract, records []*
 		}
 		redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
 
-		signatures, err := n.Wallet.CreateMultisigSignature(ins, []spvwallet.TransactionOutput{output}, vendorKey, redeemScript, contract.BuyerOrder.RefundFee)
+		signatures, err := wal.CreateMultisigSignature(ins, []spvwallet.TransactionOutput{output}, vendorKey, redeemScript, contract.BuyerOrder.RefundFee)
 		if err != nil {
 			return err
 		}
@@ -91,6 +95,16 @@ func (n *OpenBazaarNode) RefundOrder(contract *pb.RicardianContract, records []*
 			sigs = append(sigs, pbSig)
 		}
 		refundMsg.Sigs = sigs
+	} else if isCrowdfund(contract) && contract.BuyerOrder.Payment.Method == pb.Order_Payment_DIRECT {
+		// Pledges are held in a 1 of 2 address until the campaign ends rather than in our wallet
+		refundAddr, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, wal.Params())
+		if err != nil {
+			return err
+		}
+		err = n.sweepPaymentAddress(contract, records, &refundAddr)
+		if err != nil {
+			return err
+		}
 	} else {
 		var outValue int64
 		for _, r := range records {
@@ -99,11 +113,11 @@ func (n *OpenBazaarNode) RefundOrder(contract *pb.RicardianContract, records []*
 				outValue += r.Value
 			}
 		}
-		refundAddr, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, n.Wallet.Params())
+		refundAddr, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, wal.Params())
 		if err != nil {
 			return err
 		}
-		_, err = n.Wallet.Spend(outValue, refundAddr, spvwallet.NORMAL)
+		_, err = wal.Spend(outValue, refundAddr, spvwallet.NORMAL)
 		if err != nil {
 			return err
 		}
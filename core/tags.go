@@ -0,0 +1,113 @@
+package core
+
+import (
+	"crypto/sha256"
+	"strings"
+	"sync"
+
+	"github.com/OpenBazaar/openbazaar-go/ipfs"
+	"github.com/ipfs/go-ipfs/routing/dht"
+	"golang.org/x/net/context"
+	ma "gx/ipfs/QmSWLfmj5frN9xVLMMN846dMDriy5wN5jeghUm7aTW3DAG/go-multiaddr"
+	multihash "gx/ipfs/QmbZ6Cee2uHjG7hf19qLHppgKDRtaG4CVtMzdmK9VCVqLu/go-multihash"
+)
+
+// Tag pointers are published under the full key like moderator pointers
+const TagPointerPrefixLength = 64
+
+var tagPointerLock sync.Mutex
+
+// Tags are matched ignoring case and surrounding whitespace
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// Return the ID pointers to vendors with listings carrying the tag are published under
+func TagPointerID(tag string) (multihash.Multihash, error) {
+	h := sha256.Sum256([]byte("tag:" + NormalizeTag(tag)))
+	encoded, err := multihash.Encode(h[:], multihash.SHA2_256)
+	if err != nil {
+		return nil, err
+	}
+	return multihash.Cast(encoded)
+}
+
+// Publish a pointer for each tag on our listings and remove those for tags no longer used
+func (n *OpenBazaarNode) PublishTagPointers() error {
+	index, err := n.getListingIndex()
+	if err != nil {
+		return err
+	}
+	return n.updateTagPointers(index)
+}
+
+/* Bring our tag pointers in line with the listing index. Pointers already published
+   are left to the PointerRepublisher, so only new tags are published here. */
+func (n *OpenBazaarNode) updateTagPointers(index []listingData) error {
+	if _, ok := n.IpfsNode.Routing.(*dht.IpfsDHT); !ok {
+		return nil
+	}
+	tagPointerLock.Lock()
+	defer tagPointerLock.Unlock()
+
+	wanted := make(map[string]multihash.Multihash)
+	for _, ld := range index {
+		for _, tag := range ld.Tags {
+			if NormalizeTag(tag) == "" {
+				continue
+			}
+			id, err := TagPointerID(tag)
+			if err != nil {
+				return err
+			}
+			k, err := ipfs.PointerKey(id, TagPointerPrefixLength)
+			if err != nil {
+				return err
+			}
+			wanted[k.String()] = id
+		}
+	}
+
+	pointers, err := n.Datastore.Pointers().GetByPurpose(ipfs.TAG)
+	if err != nil {
+		return err
+	}
+	for _, p := range pointers {
+		if _, ok := wanted[p.Cid.String()]; !ok {
+			if err := n.Datastore.Pointers().Delete(p.Value.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		delete(wanted, p.Cid.String())
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+
+	b, err := multihash.Encode([]byte(n.IpfsNode.Identity.Pretty()), multihash.SHA1)
+	if err != nil {
+		return err
+	}
+	mhc, err := multihash.Cast(b)
+	if err != nil {
+		return err
+	}
+	addr, err := ma.NewMultiaddr("/ipfs/" + mhc.B58String())
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	for _, id := range wanted {
+		pointer, err := ipfs.PublishPointer(n.IpfsNode, ctx, id, TagPointerPrefixLength, addr)
+		if err != nil {
+			log.Error("Failed to publish tag pointer:", err)
+			continue
+		}
+		pointer.Purpose = ipfs.TAG
+		if err := n.Datastore.Pointers().Put(pointer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package core
+
+import "testing"
+
+func TestTagPointerID(t *testing.T) {
+	a, err := TagPointerID("Shirts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := TagPointerID(" shirts ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.B58String() != b.B58String() {
+		t.Error("Tags differing only in case and whitespace have different pointer IDs")
+	}
+	c, err := TagPointerID("hats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.B58String() == c.B58String() {
+		t.Error("Different tags have the same pointer ID")
+	}
+	if a.B58String() == ModeratorPointerID.B58String() {
+		t.Error("Tag pointer ID collides with the moderator pointer ID")
+	}
+}
@@ -49,8 +49,7 @@ type Pointer struct {
 }
 
 func PublishPointer(node *core.IpfsNode, ctx context.Context, mhKey multihash.Multihash, prefixLen int, addr ma.Multiaddr) (Pointer, error) {
-	keyhash := createKey(mhKey, prefixLen)
-	k, err := cid.Decode(keyhash.B58String())
+	k, err := PointerKey(mhKey, prefixLen)
 	if err != nil {
 		return Pointer{}, err
 	}
@@ -66,6 +65,12 @@ func PublishPointer(node *core.IpfsNode, ctx context.Context, mhKey multihash.Mu
 	return Pointer{Cid: k, Value: pi}, addPointer(node, ctx, k, pi)
 }
 
+// Return the DHT key a pointer to mhKey is published under. This is the Cid of the saved Pointer.
+func PointerKey(mhKey multihash.Multihash, prefixLen int) (*cid.Cid, error) {
+	keyhash := createKey(mhKey, prefixLen)
+	return cid.Decode(keyhash.B58String())
+}
+
 func RePublishPointer(node *core.IpfsNode, ctx context.Context, pointer Pointer) error {
 	return addPointer(node, ctx, pointer.Cid, pointer.Value)
 }
@@ -41,6 +41,9 @@ const (
 
 	// Length of nacl ephemeral public key
 	EphemeralPublicKeyBytes = 32
+
+	// Length of the keys used by EncryptSymmetric
+	SymmetricKeyBytes = 32
 )
 
 var (
@@ -53,6 +56,9 @@ var (
 	// Nacl box decryption failed
 	BoxDecryptionError = errors.New("Failed to decrypt curve25519")
 
+	// The key given to EncryptSymmetric or DecryptSymmetric is the wrong length
+	ErrSymmetricKeyLength = errors.New("Symmetric key is the wrong length")
+
 	// Satic salt used in the hdkf
 	Salt = []byte("OpenBazaar Encryption Algorithm")
 )
@@ -258,3 +264,48 @@ func decryptRSA(privKey *libp2p.RsaPrivateKey, ciphertext []byte) ([]byte, error
 func getCipherTextVersion(ciphertext []byte) uint32 {
 	return binary.BigEndian.Uint32(ciphertext[:CiphertextVersionBytes])
 }
+
+// Return a new random key for EncryptSymmetric
+func NewSymmetricKey() ([]byte, error) {
+	key := make([]byte, SymmetricKeyBytes)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+/* Encrypt data with AES-256-GCM for when the key is shared some other way, as with
+   chat attachments. The random nonce is prepended to the ciphertext. */
+func EncryptSymmetric(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func DecryptSymmetric(key, ciphertext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, ErrShortCiphertext
+	}
+	return gcm.Open(nil, ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():], nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != SymmetricKeyBytes {
+		return nil, ErrSymmetricKeyLength
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
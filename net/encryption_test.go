@@ -155,3 +155,37 @@ func TestDecryptCurve25519(t *testing.T) {
 		t.Error("Failed to catch curve25519 drcyption error")
 	}
 }
+
+func TestEncryptSymmetric(t *testing.T) {
+	key, err := NewSymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext := "Hello World!!!"
+	ciphertext, err := EncryptSymmetric(key, []byte(plaintext))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decryptedPlaintext, err := DecryptSymmetric(key, ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(decryptedPlaintext) != plaintext {
+		t.Error("Result plaintext doesn't match original plaintext")
+	}
+
+	otherKey, err := NewSymmetricKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DecryptSymmetric(otherKey, ciphertext); err == nil {
+		t.Error("Decrypted with the wrong key")
+	}
+	ciphertext[len(ciphertext)-1] ^= 1
+	if _, err := DecryptSymmetric(key, ciphertext); err == nil {
+		t.Error("Decrypted a modified ciphertext")
+	}
+	if _, err := EncryptSymmetric(key[:16], []byte(plaintext)); err != ErrSymmetricKeyLength {
+		t.Error("Encrypted with a short key")
+	}
+}
@@ -9,6 +9,14 @@ import (
 	"golang.org/x/net/context"
 )
 
+const (
+	// Offline messages are only republished for this long before they're treated as undeliverable
+	MessagePointerTTL = time.Hour * 24 * 30
+
+	// Channel posts are only republished for this long after they're made
+	ChannelPointerTTL = time.Hour * 24 * 7
+)
+
 type PointerRepublisher struct {
 	ipfsNode    *core.IpfsNode
 	db          repo.Datastore
@@ -32,22 +40,25 @@ func (r *PointerRepublisher) Run() {
 	}
 }
 
+/* Republish our pointers so they stay in the DHT. Expired message and channel pointers
+   are skipped and left for the outbox janitor to delete along with what they point to. */
 func (r *PointerRepublisher) Republish() {
-	republishModerator := r.isModerator()
 	pointers, err := r.db.Pointers().GetAll()
 	if err != nil {
 		return
 	}
 	ctx := context.Background()
 	for _, p := range pointers {
-		if p.Purpose != ipfs.MESSAGE {
-			ipfs.RePublishPointer(r.ipfsNode, ctx, p)
-		} else if p.Purpose != ipfs.MODERATOR || republishModerator {
-			if time.Now().Sub(p.Timestamp) > time.Hour*24*30 {
-				r.db.Pointers().Delete(p.Value.ID)
-			} else {
-				ipfs.RePublishPointer(r.ipfsNode, ctx, p)
+		switch p.Purpose {
+		case ipfs.MESSAGE:
+			if time.Now().Sub(p.Timestamp) > MessagePointerTTL {
+				continue
+			}
+		case ipfs.CHANNEL:
+			if time.Now().Sub(p.Timestamp) > ChannelPointerTTL {
+				continue
 			}
 		}
+		ipfs.RePublishPointer(r.ipfsNode, ctx, p)
 	}
 }
@@ -2,6 +2,7 @@ package net
 
 import (
 	"context"
+	"fmt"
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
 	"github.com/OpenBazaar/openbazaar-go/net"
 	"github.com/OpenBazaar/openbazaar-go/pb"
@@ -25,61 +26,145 @@ import (
 
 var log = logging.MustGetLogger("retriever")
 
+const (
+	// The first wait before fetching from a storage address which failed. It doubles with each further failure.
+	backoffBase = time.Minute * 5
+	backoffMax  = time.Hour * 24
+)
+
+/* How the last fetch went. PointersFound counts the pointers to messages we hadn't
+   seen, Failures the messages which couldn't be downloaded and BackedOff those
+   skipped because their storage address failed recently. */
+type RetrieverStatus struct {
+	Running           bool      `json:"running"`
+	Interval          string    `json:"interval"`
+	LastRun           time.Time `json:"lastRun"`
+	PointersFound     int       `json:"pointersFound"`
+	MessagesDecrypted int       `json:"messagesDecrypted"`
+	Failures          int       `json:"failures"`
+	BackedOff         int       `json:"backedOff"`
+}
+
+// A storage address which failed and when it may next be tried
+type backoff struct {
+	failures int
+	next     time.Time
+}
+
 type MessageRetriever struct {
-	db           repo.Datastore
-	node         *core.IpfsNode
-	bm           *net.BanManager
-	ctx          commands.Context
-	service      net.NetworkService
-	prefixLen    int
-	sendAck      func(peerId string, pointerID peer.ID) error
-	messageQueue []pb.Envelope
-	httpClient   *http.Client
-	queueLock    *sync.Mutex
+	db            repo.Datastore
+	node          *core.IpfsNode
+	bm            *net.BanManager
+	ctx           commands.Context
+	service       net.NetworkService
+	prefixLen     int
+	interval      time.Duration
+	sendAck       func(peerId string, pointerID peer.ID) error
+	messageQueue  []pb.Envelope
+	httpClient    *http.Client
+	queueLock     *sync.Mutex
+	fetchRequests chan chan struct{}
+	status        RetrieverStatus
+	backoffs      map[string]backoff
+	statusLock    *sync.Mutex
 	*sync.WaitGroup
 }
 
-func NewMessageRetriever(db repo.Datastore, ctx commands.Context, node *core.IpfsNode, bm *net.BanManager, service net.NetworkService, prefixLen int, dialer proxy.Dialer, sendAck func(peerId string, pointerID peer.ID) error) *MessageRetriever {
+func NewMessageRetriever(db repo.Datastore, ctx commands.Context, node *core.IpfsNode, bm *net.BanManager, service net.NetworkService, prefixLen int, interval time.Duration, dialer proxy.Dialer, sendAck func(peerId string, pointerID peer.ID) error) *MessageRetriever {
 	dial := gonet.Dial
 	if dialer != nil {
 		dial = dialer.Dial
 	}
 	tbTransport := &http.Transport{Dial: dial}
 	client := &http.Client{Transport: tbTransport, Timeout: time.Second * 10}
-	mr := MessageRetriever{db, node, bm, ctx, service, prefixLen, sendAck, nil, client, new(sync.Mutex), new(sync.WaitGroup)}
+	mr := MessageRetriever{
+		db:            db,
+		node:          node,
+		bm:            bm,
+		ctx:           ctx,
+		service:       service,
+		prefixLen:     prefixLen,
+		interval:      interval,
+		sendAck:       sendAck,
+		httpClient:    client,
+		queueLock:     new(sync.Mutex),
+		fetchRequests: make(chan chan struct{}),
+		status:        RetrieverStatus{Interval: interval.String()},
+		backoffs:      make(map[string]backoff),
+		statusLock:    new(sync.Mutex),
+		WaitGroup:     new(sync.WaitGroup),
+	}
 	// Add one for initial wait at start up
 	mr.Add(1)
 	return &mr
 }
 
+// Fetch messages at start up, every interval and whenever FetchNow is called. Only one fetch runs at a time.
 func (m *MessageRetriever) Run() {
-	tick := time.NewTicker(time.Hour)
+	tick := time.NewTicker(m.interval)
 	defer tick.Stop()
-	go m.fetchPointers()
+	m.fetchPointers()
 	for {
 		select {
 		case <-tick.C:
-			go m.fetchPointers()
+			m.fetchPointers()
+		case done := <-m.fetchRequests:
+			m.fetchPointers()
+			close(done)
 		}
 	}
 }
 
+// Fetch messages without waiting for the next interval. Returns the status once the fetch is done.
+func (m *MessageRetriever) FetchNow() RetrieverStatus {
+	done := make(chan struct{})
+	m.fetchRequests <- done
+	<-done
+	return m.Status()
+}
+
+func (m *MessageRetriever) Status() RetrieverStatus {
+	m.statusLock.Lock()
+	defer m.statusLock.Unlock()
+	return m.status
+}
+
 func (m *MessageRetriever) fetchPointers() {
+	m.statusLock.Lock()
+	m.status = RetrieverStatus{Running: true, Interval: m.interval.String(), LastRun: time.Now()}
+	// Forget addresses whose pointers have gone
+	for addr, b := range m.backoffs {
+		if time.Since(b.next) > backoffMax {
+			delete(m.backoffs, addr)
+		}
+	}
+	m.statusLock.Unlock()
+	defer func() {
+		m.statusLock.Lock()
+		m.status.Running = false
+		m.statusLock.Unlock()
+	}()
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	wg := new(sync.WaitGroup)
 	wg.Add(1)
-	downloaded := 0
 	mh, _ := multihash.FromB58String(m.node.Identity.Pretty())
 	peerOut := ipfs.FindPointersAsync(m.node.Routing.(*routing.IpfsDHT), ctx, mh, m.prefixLen)
 
 	// Iterate over the pointers, adding 1 to the waitgroup for each pointer found
 	for p := range peerOut {
 		if len(p.Addrs) > 0 && !m.db.OfflineMessages().Has(p.Addrs[0].String()) {
+			m.statusLock.Lock()
+			m.status.PointersFound++
+			m.statusLock.Unlock()
+			if !m.shouldFetch(p.Addrs[0].String()) {
+				continue
+			}
+
 			// IPFS
 			if len(p.Addrs[0].Protocols()) == 1 && p.Addrs[0].Protocols()[0].Code == ma.P_IPFS {
 				wg.Add(1)
-				downloaded++
 				go m.fetchIPFS(p.ID, m.ctx, p.Addrs[0], wg)
 			}
 
@@ -98,7 +183,6 @@ func (m *MessageRetriever) fetchPointers() {
 					continue
 				}
 				wg.Add(1)
-				downloaded++
 				go m.fetchHTTPS(p.ID, string(d.Digest), p.Addrs[0], wg)
 			}
 		}
@@ -124,9 +208,10 @@ func (m *MessageRetriever) fetchIPFS(pid peer.ID, ctx commands.Context, addr ma.
 	defer wg.Done()
 	ciphertext, err := ipfs.Cat(ctx, addr.String())
 	if err != nil {
-		log.Errorf("Error retrieving offline message: %s", err.Error())
+		m.fetchFailed(addr.String(), err)
 		return
 	}
+	m.fetched(addr.String())
 	m.attemptDecrypt(ciphertext, pid)
 	m.db.OfflineMessages().Put(addr.String())
 }
@@ -135,24 +220,68 @@ func (m *MessageRetriever) fetchHTTPS(pid peer.ID, url string, addr ma.Multiaddr
 	defer wg.Done()
 	resp, err := m.httpClient.Get(url)
 	if err != nil {
-		log.Errorf("Error retrieving offline message: %s", err.Error())
+		m.fetchFailed(addr.String(), err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		m.fetchFailed(addr.String(), fmt.Errorf("storage returned %s", resp.Status))
 		return
 	}
 	ciphertext, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		log.Errorf("Error retrieving offline message: %s", err.Error())
+		m.fetchFailed(addr.String(), err)
 		return
 	}
+	m.fetched(addr.String())
 	m.attemptDecrypt(ciphertext, pid)
 	m.db.OfflineMessages().Put(addr.String())
 }
 
+// Returns whether a storage address is due to be fetched from, counting it as backed off if not
+func (m *MessageRetriever) shouldFetch(addr string) bool {
+	m.statusLock.Lock()
+	defer m.statusLock.Unlock()
+	if b, ok := m.backoffs[addr]; ok && time.Now().Before(b.next) {
+		m.status.BackedOff++
+		return false
+	}
+	return true
+}
+
+// Count a failed fetch and double the wait before the address is tried again
+func (m *MessageRetriever) fetchFailed(addr string, err error) {
+	log.Errorf("Error retrieving offline message: %s", err.Error())
+	m.statusLock.Lock()
+	defer m.statusLock.Unlock()
+	m.status.Failures++
+	b := m.backoffs[addr]
+	b.failures++
+	wait := backoffMax
+	if b.failures < 20 {
+		if d := backoffBase * time.Duration(1<<uint(b.failures-1)); d < backoffMax {
+			wait = d
+		}
+	}
+	b.next = time.Now().Add(wait)
+	m.backoffs[addr] = b
+}
+
+func (m *MessageRetriever) fetched(addr string) {
+	m.statusLock.Lock()
+	defer m.statusLock.Unlock()
+	delete(m.backoffs, addr)
+}
+
 func (m *MessageRetriever) attemptDecrypt(ciphertext []byte, pid peer.ID) {
 	// Decrypt and unmarshal plaintext
 	plaintext, err := net.Decrypt(m.node.PrivateKey, ciphertext)
 	if err != nil {
 		return
 	}
+	m.statusLock.Lock()
+	m.status.MessagesDecrypted++
+	m.statusLock.Unlock()
 
 	// Unmarshal plaintext
 	env := pb.Envelope{}
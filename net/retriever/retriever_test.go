@@ -0,0 +1,40 @@
+package net
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-ipfs/commands"
+)
+
+func TestBackoff(t *testing.T) {
+	m := NewMessageRetriever(nil, commands.Context{}, nil, nil, nil, 14, time.Hour, nil, nil)
+	addr := "/ipfs/QmNoSuchMessage"
+	if !m.shouldFetch(addr) {
+		t.Fatal("Backed off an address which never failed")
+	}
+	m.fetchFailed(addr, errors.New("unreachable"))
+	if m.shouldFetch(addr) {
+		t.Error("Fetched an address which just failed")
+	}
+	first := m.backoffs[addr].next
+	m.fetchFailed(addr, errors.New("unreachable"))
+	if wait := m.backoffs[addr].next.Sub(first); wait < backoffBase-time.Second {
+		t.Error("Backoff did not double, waited an extra", wait)
+	}
+	for i := 0; i < 30; i++ {
+		m.fetchFailed(addr, errors.New("unreachable"))
+	}
+	if wait := m.backoffs[addr].next.Sub(time.Now()); wait > backoffMax {
+		t.Error("Backoff exceeded the max", wait)
+	}
+	status := m.Status()
+	if status.Failures != 32 || status.BackedOff != 1 || status.Interval != "1h0m0s" {
+		t.Error("Returned wrong status", status)
+	}
+	m.fetched(addr)
+	if !m.shouldFetch(addr) {
+		t.Error("Backed off an address which was fetched")
+	}
+}
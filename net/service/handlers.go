@@ -9,6 +9,7 @@ import (
 	"github.com/OpenBazaar/openbazaar-go/core"
 	"github.com/OpenBazaar/openbazaar-go/net"
 	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
 	"github.com/OpenBazaar/spvwallet"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
@@ -59,10 +60,16 @@ func (service *OpenBazaarService) HandlerForMsgType(t pb.Message_MessageType) fu
 		return service.handleDisputeClose
 	case pb.Message_CHAT:
 		return service.handleChat
+	case pb.Message_GROUP_CHAT:
+		return service.handleGroupChat
 	case pb.Message_MODERATOR_ADD:
 		return service.handleModeratorAdd
 	case pb.Message_MODERATOR_REMOVE:
 		return service.handleModeratorRemove
+	case pb.Message_BID:
+		return service.handleBid
+	case pb.Message_BID_ACCEPT:
+		return service.handleBidAccept
 	default:
 		return nil
 	}
@@ -103,6 +110,23 @@ func (service *OpenBazaarService) handleOfflineAck(p peer.ID, pmes *pb.Message,
 	if err != nil {
 		return nil, err
 	}
+	// Mark the message as acked before the pointer is checked as a direct retry may have already withdrawn it
+	out, err := service.datastore.OutgoingMessages().GetByPointerId(pid.Pretty())
+	if err == nil && out.PeerId == p.Pretty() && out.State != repo.OutgoingMessageAcked {
+		out.State = repo.OutgoingMessageAcked
+		out.Message = nil
+		out.Error = ""
+		out.Updated = time.Now()
+		if err := service.datastore.OutgoingMessages().Put(out); err != nil {
+			return nil, err
+		}
+		service.broadcast <- notifications.MessageDelivery{
+			Id:        out.Id,
+			PeerId:    out.PeerId,
+			Reference: out.Reference,
+			State:     out.State,
+		}
+	}
 	pointer, err := service.datastore.Pointers().Get(pid)
 	if err != nil {
 		return nil, err
@@ -110,6 +134,14 @@ func (service *OpenBazaarService) handleOfflineAck(p peer.ID, pmes *pb.Message,
 	if pointer.CancelID == nil || pointer.CancelID.Pretty() != p.Pretty() {
 		return nil, errors.New("Peer is not authorized to delete pointer")
 	}
+	// The message has been received so storage can drop its copy. If this fails the pointer
+	// is kept so the outbox janitor retries the delete once the pointer expires.
+	if service.node.MessageStorage != nil && len(pointer.Value.Addrs) > 0 {
+		if err := service.node.MessageStorage.Delete(pointer.Value.Addrs[0]); err != nil {
+			log.Errorf("Error deleting acknowledged message from storage: %s", err.Error())
+			return nil, nil
+		}
+	}
 	err = service.datastore.Pointers().Delete(pid)
 	if err != nil {
 		return nil, err
@@ -194,8 +226,52 @@ func (service *OpenBazaarService) handleOrder(peer peer.ID, pmes *pb.Message, op
 		return errorResponse(err.Error()), nil
 	}
 
+	/* Orders the vendor's rules reject are refused straight away if the buyer is waiting for
+	   a confirmation. Offline orders are saved so they can be rejected and refunded once
+	   they're funded. */
+	reason := service.node.RejectedByRules(contract)
+	pending := contract.BuyerOrder.Payment.Method == pb.Order_Payment_DIRECT || (contract.BuyerOrder.Payment.Method == pb.Order_Payment_MODERATED && offline)
+	if reason != "" && !pending {
+		if orderId, err := service.node.CalcOrderId(contract.BuyerOrder); err == nil {
+			service.node.RecordOrderEvent(orderId, repo.OrderActionRejected, reason)
+		}
+		log.Infof("Rejected order from %s: %s", peer.Pretty(), reason)
+		return errorResponse(reason), nil
+	}
+	if contract.VendorListings[0].Metadata.Format == pb.Listing_Metadata_AUCTION {
+		// Price the order from our copy of the winning bid rather than what the buyer sent
+		contract, err = service.node.WinningBid(contract)
+		if err != nil {
+			log.Error(err)
+			return errorResponse(err.Error()), nil
+		}
+	}
+	wal, err := service.node.WalletForContract(contract)
+	if err != nil {
+		log.Error(err)
+		return errorResponse(err.Error()), nil
+	}
+
+	if contract.VendorListings[0].Metadata.ContractType == pb.Listing_Metadata_CROWD_FUND {
+		err = service.node.ValidatePledge(contract)
+		if err != nil {
+			log.Error(err)
+			return errorResponse(err.Error()), nil
+		}
+		orderId, err := service.node.CalcOrderId(contract.BuyerOrder)
+		if err != nil {
+			log.Error(err)
+			return errorResponse(err.Error()), err
+		}
+		err = service.node.Datastore.Pledges().Put(orderId, *contract, repo.PledgePending)
+		if err != nil {
+			log.Error(err)
+			return errorResponse(err.Error()), err
+		}
+	}
+
 	if contract.BuyerOrder.Payment.Method == pb.Order_Payment_ADDRESS_REQUEST {
-		total, err := service.node.CalculateOrderTotal(contract)
+		total, rates, err := service.node.CalculateOrderTotalWithRates(contract)
 		if err != nil {
 			log.Error("Error calculating payment amount")
 			return errorResponse("Error calculating payment amount"), nil
@@ -215,18 +291,19 @@ func (service *OpenBazaarService) handleOrder(peer peer.ID, pmes *pb.Message, op
 			return errorResponse("Error building order confirmation"), nil
 		}
 		service.node.Datastore.Sales().Put(contract.VendorOrderConfirmation.OrderID, *contract, pb.OrderState_CONFIRMED, false)
+		service.node.Datastore.Sales().PutExchangeRates(contract.VendorOrderConfirmation.OrderID, rates)
 		m := pb.Message{
 			MessageType: pb.Message_ORDER_CONFIRMATION,
 			Payload:     a,
 		}
 		return &m, nil
 	} else if contract.BuyerOrder.Payment.Method == pb.Order_Payment_DIRECT {
-		err := service.node.ValidateDirectPaymentAddress(contract.BuyerOrder)
+		err := service.node.ValidateDirectPaymentAddress(contract)
 		if err != nil {
 			log.Error(err)
 			return errorResponse(err.Error()), err
 		}
-		addr, err := btcutil.DecodeAddress(contract.BuyerOrder.Payment.Address, service.node.Wallet.Params())
+		addr, err := btcutil.DecodeAddress(contract.BuyerOrder.Payment.Address, wal.Params())
 		if err != nil {
 			log.Error(err)
 			return errorResponse(err.Error()), err
@@ -236,7 +313,7 @@ func (service *OpenBazaarService) handleOrder(peer peer.ID, pmes *pb.Message, op
 			log.Error(err)
 			return errorResponse(err.Error()), err
 		}
-		service.node.Wallet.AddWatchedScript(script)
+		wal.AddWatchedScript(script)
 		orderId, err := service.node.CalcOrderId(contract.BuyerOrder)
 		if err != nil {
 			log.Error(err)
@@ -245,7 +322,7 @@ func (service *OpenBazaarService) handleOrder(peer peer.ID, pmes *pb.Message, op
 		service.node.Datastore.Sales().Put(orderId, *contract, pb.OrderState_PENDING, false)
 		return nil, nil
 	} else if contract.BuyerOrder.Payment.Method == pb.Order_Payment_MODERATED && !offline {
-		total, err := service.node.CalculateOrderTotal(contract)
+		total, rates, err := service.node.CalculateOrderTotalWithRates(contract)
 		if err != nil {
 			log.Error("Error calculating payment amount")
 			return errorResponse("Error calculating payment amount"), nil
@@ -254,12 +331,12 @@ func (service *OpenBazaarService) handleOrder(peer peer.ID, pmes *pb.Message, op
 			log.Error("Calculated a different payment amount")
 			return errorResponse("Calculated a different payment amount"), nil
 		}
-		err = service.node.ValidateModeratedPaymentAddress(contract.BuyerOrder)
+		err = service.node.ValidateModeratedPaymentAddress(contract)
 		if err != nil {
 			log.Error(err)
 			return errorResponse(err.Error()), err
 		}
-		addr, err := btcutil.DecodeAddress(contract.BuyerOrder.Payment.Address, service.node.Wallet.Params())
+		addr, err := btcutil.DecodeAddress(contract.BuyerOrder.Payment.Address, wal.Params())
 		if err != nil {
 			log.Error(err)
 			return errorResponse(err.Error()), err
@@ -269,7 +346,7 @@ func (service *OpenBazaarService) handleOrder(peer peer.ID, pmes *pb.Message, op
 			log.Error(err)
 			return errorResponse(err.Error()), err
 		}
-		service.node.Wallet.AddWatchedScript(script)
+		wal.AddWatchedScript(script)
 		contract, err = service.node.NewOrderConfirmation(contract, false)
 		if err != nil {
 			log.Error(err)
@@ -281,18 +358,19 @@ func (service *OpenBazaarService) handleOrder(peer peer.ID, pmes *pb.Message, op
 			return errorResponse("Error building order confirmation"), nil
 		}
 		service.node.Datastore.Sales().Put(contract.VendorOrderConfirmation.OrderID, *contract, pb.OrderState_CONFIRMED, false)
+		service.node.Datastore.Sales().PutExchangeRates(contract.VendorOrderConfirmation.OrderID, rates)
 		m := pb.Message{
 			MessageType: pb.Message_ORDER_CONFIRMATION,
 			Payload:     a,
 		}
 		return &m, nil
 	} else if contract.BuyerOrder.Payment.Method == pb.Order_Payment_MODERATED && offline {
-		err := service.node.ValidateModeratedPaymentAddress(contract.BuyerOrder)
+		err := service.node.ValidateModeratedPaymentAddress(contract)
 		if err != nil {
 			log.Error(err)
 			return errorResponse(err.Error()), err
 		}
-		addr, err := btcutil.DecodeAddress(contract.BuyerOrder.Payment.Address, service.node.Wallet.Params())
+		addr, err := btcutil.DecodeAddress(contract.BuyerOrder.Payment.Address, wal.Params())
 		if err != nil {
 			log.Error(err)
 			return errorResponse(err.Error()), err
@@ -302,7 +380,7 @@ func (service *OpenBazaarService) handleOrder(peer peer.ID, pmes *pb.Message, op
 			log.Error(err)
 			return errorResponse(err.Error()), err
 		}
-		service.node.Wallet.AddWatchedScript(script)
+		wal.AddWatchedScript(script)
 		orderId, err := service.node.CalcOrderId(contract.BuyerOrder)
 		if err != nil {
 			log.Error(err)
@@ -389,6 +467,10 @@ func (service *OpenBazaarService) handleReject(p peer.ID, pmes *pb.Message, opti
 	if err != nil {
 		return nil, err
 	}
+	wal, err := service.node.WalletForContract(contract)
+	if err != nil {
+		return nil, err
+	}
 
 	if contract.BuyerOrder.Payment.Method != pb.Order_Payment_MODERATED {
 		// Sweep the address into our wallet
@@ -417,7 +499,7 @@ func (service *OpenBazaarService) handleReject(p peer.ID, pmes *pb.Message, opti
 			return nil, err
 		}
 		parentFP := []byte{0x00, 0x00, 0x00, 0x00}
-		mPrivKey := service.node.Wallet.MasterPrivateKey()
+		mPrivKey := wal.MasterPrivateKey()
 		if err != nil {
 			return nil, err
 		}
@@ -426,7 +508,7 @@ func (service *OpenBazaarService) handleReject(p peer.ID, pmes *pb.Message, opti
 			return nil, err
 		}
 		hdKey := hd.NewExtendedKey(
-			service.node.Wallet.Params().HDPrivateKeyID[:],
+			wal.Params().HDPrivateKeyID[:],
 			mECKey.Serialize(),
 			chaincode,
 			parentFP,
@@ -439,11 +521,11 @@ func (service *OpenBazaarService) handleReject(p peer.ID, pmes *pb.Message, opti
 			return nil, err
 		}
 		redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
-		refundAddress, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, service.node.Wallet.Params())
+		refundAddress, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, wal.Params())
 		if err != nil {
 			return nil, err
 		}
-		_, err = service.node.Wallet.SweepAddress(utxos, &refundAddress, buyerKey, &redeemScript, spvwallet.NORMAL)
+		_, err = wal.SweepAddress(utxos, &refundAddress, buyerKey, &redeemScript, spvwallet.NORMAL)
 		if err != nil {
 			return nil, err
 		}
@@ -462,7 +544,7 @@ func (service *OpenBazaarService) handleReject(p peer.ID, pmes *pb.Message, opti
 			}
 		}
 
-		refundAddress, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, service.node.Wallet.Params())
+		refundAddress, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, wal.Params())
 		if err != nil {
 			return nil, err
 		}
@@ -479,7 +561,7 @@ func (service *OpenBazaarService) handleReject(p peer.ID, pmes *pb.Message, opti
 			return nil, err
 		}
 		parentFP := []byte{0x00, 0x00, 0x00, 0x00}
-		mPrivKey := service.node.Wallet.MasterPrivateKey()
+		mPrivKey := wal.MasterPrivateKey()
 		if err != nil {
 			return nil, err
 		}
@@ -488,7 +570,7 @@ func (service *OpenBazaarService) handleReject(p peer.ID, pmes *pb.Message, opti
 			return nil, err
 		}
 		hdKey := hd.NewExtendedKey(
-			service.node.Wallet.Params().HDPrivateKeyID[:],
+			wal.Params().HDPrivateKeyID[:],
 			mECKey.Serialize(),
 			chaincode,
 			parentFP,
@@ -502,7 +584,7 @@ func (service *OpenBazaarService) handleReject(p peer.ID, pmes *pb.Message, opti
 		}
 		redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
 
-		buyerSignatures, err := service.node.Wallet.CreateMultisigSignature(ins, []spvwallet.TransactionOutput{output}, buyerKey, redeemScript, contract.BuyerOrder.RefundFee)
+		buyerSignatures, err := wal.CreateMultisigSignature(ins, []spvwallet.TransactionOutput{output}, buyerKey, redeemScript, contract.BuyerOrder.RefundFee)
 		if err != nil {
 			return nil, err
 		}
@@ -511,7 +593,7 @@ func (service *OpenBazaarService) handleReject(p peer.ID, pmes *pb.Message, opti
 			sig := spvwallet.Signature{InputIndex: s.InputIndex, Signature: s.Signature}
 			vendorSignatures = append(vendorSignatures, sig)
 		}
-		err = service.node.Wallet.Multisign(ins, []spvwallet.TransactionOutput{output}, buyerSignatures, vendorSignatures, redeemScript, contract.BuyerOrder.RefundFee)
+		err = wal.Multisign(ins, []spvwallet.TransactionOutput{output}, buyerSignatures, vendorSignatures, redeemScript, contract.BuyerOrder.RefundFee)
 		if err != nil {
 			return nil, err
 		}
@@ -545,6 +627,10 @@ func (service *OpenBazaarService) handleRefund(p peer.ID, pmes *pb.Message, opti
 	if err != nil {
 		return nil, err
 	}
+	wal, err := service.node.WalletForContract(contract)
+	if err != nil {
+		return nil, err
+	}
 
 	if contract.BuyerOrder.Payment.Method == pb.Order_Payment_MODERATED {
 		var ins []spvwallet.TransactionInput
@@ -561,7 +647,7 @@ func (service *OpenBazaarService) handleRefund(p peer.ID, pmes *pb.Message, opti
 			}
 		}
 
-		refundAddress, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, service.node.Wallet.Params())
+		refundAddress, err := btcutil.DecodeAddress(contract.BuyerOrder.RefundAddress, wal.Params())
 		if err != nil {
 			return nil, err
 		}
@@ -578,7 +664,7 @@ func (service *OpenBazaarService) handleRefund(p peer.ID, pmes *pb.Message, opti
 			return nil, err
 		}
 		parentFP := []byte{0x00, 0x00, 0x00, 0x00}
-		mPrivKey := service.node.Wallet.MasterPrivateKey()
+		mPrivKey := wal.MasterPrivateKey()
 		if err != nil {
 			return nil, err
 		}
@@ -587,7 +673,7 @@ func (service *OpenBazaarService) handleRefund(p peer.ID, pmes *pb.Message, opti
 			return nil, err
 		}
 		hdKey := hd.NewExtendedKey(
-			service.node.Wallet.Params().HDPrivateKeyID[:],
+			wal.Params().HDPrivateKeyID[:],
 			mECKey.Serialize(),
 			chaincode,
 			parentFP,
@@ -604,7 +690,7 @@ func (service *OpenBazaarService) handleRefund(p peer.ID, pmes *pb.Message, opti
 			return nil, err
 		}
 
-		buyerSignatures, err := service.node.Wallet.CreateMultisigSignature(ins, []spvwallet.TransactionOutput{output}, buyerKey, redeemScript, contract.BuyerOrder.RefundFee)
+		buyerSignatures, err := wal.CreateMultisigSignature(ins, []spvwallet.TransactionOutput{output}, buyerKey, redeemScript, contract.BuyerOrder.RefundFee)
 		if err != nil {
 			return nil, err
 		}
@@ -613,7 +699,7 @@ func (service *OpenBazaarService) handleRefund(p peer.ID, pmes *pb.Message, opti
 			sig := spvwallet.Signature{InputIndex: s.InputIndex, Signature: s.Signature}
 			vendorSignatures = append(vendorSignatures, sig)
 		}
-		err = service.node.Wallet.Multisign(ins, []spvwallet.TransactionOutput{output}, buyerSignatures, vendorSignatures, redeemScript, contract.BuyerOrder.RefundFee)
+		err = wal.Multisign(ins, []spvwallet.TransactionOutput{output}, buyerSignatures, vendorSignatures, redeemScript, contract.BuyerOrder.RefundFee)
 		if err != nil {
 			return nil, err
 		}
@@ -689,6 +775,10 @@ func (service *OpenBazaarService) handleOrderCompletion(p peer.ID, pmes *pb.Mess
 	if err != nil {
 		return nil, err
 	}
+	wal, err := service.node.WalletForContract(contract)
+	if err != nil {
+		return nil, err
+	}
 
 	contract.BuyerOrderCompletion = rc.BuyerOrderCompletion
 	for _, sig := range rc.Signatures {
@@ -716,7 +806,7 @@ func (service *OpenBazaarService) handleOrderCompletion(p peer.ID, pmes *pb.Mess
 			}
 		}
 
-		payoutAddress, err := btcutil.DecodeAddress(contract.VendorOrderFulfillment[0].Payout.PayoutAddress, service.node.Wallet.Params())
+		payoutAddress, err := btcutil.DecodeAddress(contract.VendorOrderFulfillment[0].Payout.PayoutAddress, wal.Params())
 		if err != nil {
 			return nil, err
 		}
@@ -744,7 +834,7 @@ func (service *OpenBazaarService) handleOrderCompletion(p peer.ID, pmes *pb.Mess
 			buyerSignatures = append(buyerSignatures, sig)
 		}
 
-		err = service.node.Wallet.Multisign(ins, []spvwallet.TransactionOutput{output}, buyerSignatures, vendorSignatures, redeemScript, contract.VendorOrderFulfillment[0].Payout.PayoutFeePerByte)
+		err = wal.Multisign(ins, []spvwallet.TransactionOutput{output}, buyerSignatures, vendorSignatures, redeemScript, contract.VendorOrderFulfillment[0].Payout.PayoutFeePerByte)
 		if err != nil {
 			return nil, err
 		}
@@ -932,6 +1022,9 @@ func (service *OpenBazaarService) handleChat(p peer.ID, pmes *pb.Message, option
 	if len(chat.Message) > core.CHAT_MESSAGE_MAX_CHARACTERS {
 		return nil, errors.New("Chat message over max characters")
 	}
+	if err := core.ValidateChatAttachments(chat.Attachments); err != nil {
+		return nil, err
+	}
 
 	// Use correct timestamp
 	offline, _ := options.(bool)
@@ -964,6 +1057,10 @@ func (service *OpenBazaarService) handleChat(p peer.ID, pmes *pb.Message, option
 	if err != nil {
 		return nil, err
 	}
+	err = service.node.SaveChatAttachments(chat.MessageId, chat.Attachments)
+	if err != nil {
+		return nil, err
+	}
 
 	// Push to websocket
 	n := notifications.ChatMessage{
@@ -973,10 +1070,104 @@ func (service *OpenBazaarService) handleChat(p peer.ID, pmes *pb.Message, option
 		Message:   chat.Message,
 		Timestamp: t,
 	}
+	for _, a := range chat.Attachments {
+		n.Attachments = append(n.Attachments, notifications.ChatAttachment{
+			Id:        a.Hash,
+			Filename:  a.Filename,
+			MediaType: a.MediaType,
+			Size:      int64(a.Size),
+			Thumbnail: a.Thumbnail,
+		})
+	}
 	service.broadcast <- n
 	return nil, nil
 }
 
+func (service *OpenBazaarService) handleGroupChat(p peer.ID, pmes *pb.Message, options interface{}) (*pb.Message, error) {
+	log.Debugf("Received GROUP_CHAT message from %s", p.Pretty())
+
+	// Decrypt and unmarshall
+	if pmes.Payload == nil {
+		return nil, errors.New("Payload is nil")
+	}
+	plaintext, err := net.Decrypt(service.node.IpfsNode.PrivateKey, pmes.Payload.Value)
+	if err != nil {
+		return nil, err
+	}
+	chat := new(pb.GroupChat)
+	err = proto.Unmarshal(plaintext, chat)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the members if the creator has signed a newer list
+	group, changed, err := service.node.UpdateGroupMembers(chat.Members, chat.GroupId)
+	if err != nil {
+		return nil, err
+	}
+	if !group.IsMember(p.Pretty()) {
+		return nil, errors.New("Sender is not a member of the group")
+	}
+	if changed {
+		service.broadcast <- notifications.GroupChatUpdate{
+			GroupId: group.GroupId,
+			Creator: group.Creator,
+			Subject: group.Subject,
+			Members: group.Members,
+		}
+	}
+
+	// Messages with no text only carry the member list
+	if chat.Message == "" {
+		return nil, nil
+	}
+
+	// Validate
+	if len(chat.Message) > core.CHAT_MESSAGE_MAX_CHARACTERS {
+		return nil, errors.New("Chat message over max characters")
+	}
+	if chat.Timestamp == nil {
+		return nil, errors.New("Invalid timestamp")
+	}
+	msgId, err := core.GroupChatMessageId(chat.GroupId, p.Pretty(), chat.Message, chat.Timestamp.Seconds)
+	if err != nil {
+		return nil, err
+	}
+	if msgId != chat.MessageId {
+		return nil, errors.New("Invalid message ID")
+	}
+
+	// Use correct timestamp
+	offline, _ := options.(bool)
+	t := time.Now()
+	if offline {
+		t = time.Unix(chat.Timestamp.Seconds, 0)
+	}
+
+	// Put to database
+	err = service.datastore.GroupChats().PutMessage(repo.GroupChatMessage{
+		MessageId: chat.MessageId,
+		GroupId:   chat.GroupId,
+		PeerId:    p.Pretty(),
+		Message:   chat.Message,
+		Timestamp: t,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Push to websocket
+	service.broadcast <- notifications.GroupChatMessage{
+		MessageId: chat.MessageId,
+		GroupId:   chat.GroupId,
+		PeerId:    p.Pretty(),
+		Subject:   group.Subject,
+		Message:   chat.Message,
+		Timestamp: t,
+	}
+	return nil, nil
+}
+
 func (service *OpenBazaarService) handleModeratorAdd(peer peer.ID, pmes *pb.Message, options interface{}) (*pb.Message, error) {
 	log.Debugf("Received MODERATOR_ADD message from %s", peer.Pretty())
 	err := service.datastore.ModeratedStores().Put(peer.Pretty())
@@ -1000,3 +1191,98 @@ func (service *OpenBazaarService) handleModeratorRemove(peer peer.ID, pmes *pb.M
 	service.datastore.Notifications().Put(n, time.Now())
 	return nil, nil
 }
+
+func (service *OpenBazaarService) handleBid(p peer.ID, pmes *pb.Message, options interface{}) (*pb.Message, error) {
+	log.Debugf("Received BID message from %s", p.Pretty())
+
+	// Unmarshall
+	contract := new(pb.RicardianContract)
+	err := ptypes.UnmarshalAny(pmes.Payload, contract)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate
+	err = service.node.ValidateBid(contract)
+	if err != nil {
+		return nil, err
+	}
+	if contract.BuyerOrder.BuyerID.Guid != p.Pretty() {
+		return nil, errors.New("Bid was not sent by the buyer")
+	}
+	orderId, err := service.node.CalcOrderId(contract.BuyerOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	// Put to database
+	err = service.datastore.Bids().Put(orderId, *contract, repo.BidPending, false)
+	if err != nil {
+		return nil, err
+	}
+
+	n := notifications.BidNotification{
+		Title:             contract.VendorListings[0].Item.Title,
+		Slug:              contract.VendorListings[0].Slug,
+		BuyerGuid:         contract.BuyerOrder.BuyerID.Guid,
+		BuyerBlockchainId: contract.BuyerOrder.BuyerID.BlockchainID,
+		Amount:            contract.BuyerOrder.Items[0].BidAmount,
+		OrderId:           orderId,
+	}
+	service.broadcast <- n
+	service.datastore.Notifications().Put(n, time.Now())
+	return nil, nil
+}
+
+func (service *OpenBazaarService) handleBidAccept(p peer.ID, pmes *pb.Message, options interface{}) (*pb.Message, error) {
+	log.Debugf("Received BID_ACCEPT message from %s", p.Pretty())
+
+	// Unmarshall
+	rc := new(pb.RicardianContract)
+	err := ptypes.UnmarshalAny(pmes.Payload, rc)
+	if err != nil {
+		return nil, err
+	}
+	if rc.BuyerOrder == nil {
+		return nil, errors.New("Bid accept message doesn't contain an order")
+	}
+
+	// Load the bid we sent
+	orderId, err := service.node.CalcOrderId(rc.BuyerOrder)
+	if err != nil {
+		return nil, err
+	}
+	contract, state, outgoing, err := service.datastore.Bids().GetByOrderId(orderId)
+	if err != nil {
+		return nil, err
+	}
+	if !outgoing {
+		return nil, errors.New("Received bid accept for a bid we did not place")
+	}
+	if contract.VendorListings[0].VendorID.Guid != p.Pretty() {
+		return nil, errors.New("Bid accept was not sent by the vendor")
+	}
+	if state != repo.BidPending {
+		return nil, nil
+	}
+
+	// The winning bid is now a regular purchase awaiting funding
+	err = service.datastore.Purchases().Put(orderId, *contract, pb.OrderState_PENDING, false)
+	if err != nil {
+		return nil, err
+	}
+	err = service.datastore.Bids().UpdateState(orderId, repo.BidWon)
+	if err != nil {
+		return nil, err
+	}
+
+	n := notifications.AuctionWonNotification{
+		Title:          contract.VendorListings[0].Item.Title,
+		OrderId:        orderId,
+		PaymentAddress: contract.BuyerOrder.Payment.Address,
+		PaymentAmount:  contract.BuyerOrder.Payment.Amount,
+	}
+	service.broadcast <- n
+	service.datastore.Notifications().Put(n, time.Now())
+	return nil, nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	libp2p "gx/ipfs/QmPGxZ1DP2w45WcogpW1h43BvseXbfke9N91qotpoQcUeS/go-libp2p-crypto"
+	peer "gx/ipfs/QmWUswjn261LSyVxWAEpMVtPdy8zmKBJJfBpG3Qdpa8ZsE/go-libp2p-peer"
+
+	"github.com/OpenBazaar/openbazaar-go/core"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo/db"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+func TestHandleInvalidOrder(t *testing.T) {
+	repoPath, err := ioutil.TempDir("", "service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(repoPath)
+	os.MkdirAll(path.Join(repoPath, "datastore"), os.ModePerm)
+	datastore, err := db.Create(repoPath, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer datastore.Close()
+	if err := datastore.Config().Init("", []byte{}, ""); err != nil {
+		t.Fatal(err)
+	}
+	service := &OpenBazaarService{datastore: datastore, node: &core.OpenBazaarNode{Datastore: datastore}}
+
+	_, pub, err := libp2p.GenerateKeyPair(libp2p.Ed25519, 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubkey, err := pub.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, err := peer.IDFromPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ratingKey := make([]byte, 33)
+	order := &pb.Order{
+		BuyerID:    &pb.ID{Guid: pid.Pretty(), Pubkeys: &pb.ID_Pubkeys{Guid: pubkey}},
+		Items:      []*pb.Order_Item{{ListingHash: "QmListing", Quantity: 1}},
+		RatingKeys: [][]byte{ratingKey},
+		Timestamp:  &timestamp.Timestamp{Seconds: 1500000000},
+	}
+	orders := map[string]*pb.RicardianContract{
+		// An order without a payment used to be dereferenced before it was validated
+		"no payment": {
+			VendorListings: []*pb.Listing{{Slug: "book", Metadata: &pb.Listing_Metadata{}}},
+			BuyerOrder:     order,
+		},
+		"bad signature": {
+			VendorListings: []*pb.Listing{{Slug: "book", Metadata: &pb.Listing_Metadata{}}},
+			BuyerOrder:     withPayment(order),
+			Signatures:     []*pb.Signature{{Section: pb.Signature_ORDER, SignatureBytes: []byte("forged")}},
+		},
+	}
+	for name, contract := range orders {
+		payload, err := ptypes.MarshalAny(contract)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, offline := range []bool{false, true} {
+			resp, err := service.handleOrder(pid, &pb.Message{MessageType: pb.Message_ORDER, Payload: payload}, offline)
+			if err != nil {
+				t.Error(name, err)
+			}
+			if resp == nil || resp.MessageType != pb.Message_ERROR {
+				t.Errorf("Order with %s was not refused", name)
+			}
+		}
+	}
+	sales, err := datastore.Sales().GetAll("", -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sales) != 0 {
+		t.Error("Invalid order was saved")
+	}
+}
+
+func withPayment(order *pb.Order) *pb.Order {
+	o := *order
+	o.Payment = &pb.Order_Payment{Method: pb.Order_Payment_MODERATED, Moderator: "QmModerator"}
+	return &o
+}
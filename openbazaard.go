@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"crypto/sha256"
 	"encoding/hex"
@@ -31,6 +32,7 @@ import (
 	"github.com/OpenBazaar/openbazaar-go/api"
 	"github.com/OpenBazaar/openbazaar-go/bitcoin"
 	"github.com/OpenBazaar/openbazaar-go/bitcoin/bitcoind"
+	"github.com/OpenBazaar/openbazaar-go/bitcoin/electrum"
 	"github.com/OpenBazaar/openbazaar-go/bitcoin/exchange"
 	lis "github.com/OpenBazaar/openbazaar-go/bitcoin/listeners"
 	"github.com/OpenBazaar/openbazaar-go/core"
@@ -41,8 +43,10 @@ import (
 	"github.com/OpenBazaar/openbazaar-go/net/service"
 	"github.com/OpenBazaar/openbazaar-go/repo"
 	"github.com/OpenBazaar/openbazaar-go/repo/db"
+	"github.com/OpenBazaar/openbazaar-go/repo/postgres"
 	sto "github.com/OpenBazaar/openbazaar-go/storage"
 	"github.com/OpenBazaar/openbazaar-go/storage/dropbox"
+	"github.com/OpenBazaar/openbazaar-go/storage/s3"
 	"github.com/OpenBazaar/openbazaar-go/storage/selfhosted"
 	"github.com/OpenBazaar/spvwallet"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -96,6 +100,7 @@ type Init struct {
 	Mnemonic string `short:"m" long:"mnemonic" description:"speficy a mnemonic seed to use to derive the keychain"`
 	Testnet  bool   `short:"t" long:"testnet" description:"use the test network"`
 	Force    bool   `short:"f" long:"force" description:"force overwrite existing repo (dangerous!)"`
+	DB       string `long:"db" description:"store the node's data in the Postgres database at this connection URL instead of SQLite"`
 }
 type Status struct {
 	DataDir string `short:"d" long:"datadir" description:"specify the data directory to be used"`
@@ -117,7 +122,30 @@ type Start struct {
 	DualStack            bool     `long:"dualstack" description:"Automatically configure the daemon to run as a Tor hidden service IN ADDITION to using the clear internet. Requires Tor to be running. WARNING: this mode is not private"`
 	DisableWallet        bool     `long:"disablewallet" description:"disable the wallet functionality of the node"`
 	DisableExchangeRates bool     `long:"disableexchangerates" description:"disable the exchange rate service to prevent api queries"`
-	Storage              string   `long:"storage" description:"set the outgoing message storage option [self-hosted, dropbox] default=self-hosted"`
+	Storage              string   `long:"storage" description:"set the outgoing message storage option [self-hosted, dropbox, s3] default=self-hosted"`
+	DB                   string   `long:"db" description:"use the Postgres database at this connection URL instead of SQLite"`
+	Wallets              []string `long:"wallet" description:"also run an electrum wallet for another coin, given as coin=server (ex. LTC=ssl://electrum.example.com:50002)"`
+}
+type MigrateDB struct {
+	Password string `short:"p" long:"password" description:"the encryption password if the SQLite database is encrypted"`
+	DataDir  string `short:"d" long:"datadir" description:"specify the data directory to be used"`
+	Testnet  bool   `short:"t" long:"testnet" description:"use the test network"`
+	DB       string `long:"db" required:"true" description:"connection URL of the empty Postgres database to copy into"`
+}
+type Migrate struct {
+	Password string `short:"p" long:"password" description:"the encryption password if the database is encrypted"`
+	DataDir  string `short:"d" long:"datadir" description:"specify the data directory to be used"`
+	Testnet  bool   `short:"t" long:"testnet" description:"use the test network"`
+	DryRun   bool   `long:"dry-run" description:"print the pending migrations without applying them"`
+}
+type APIToken struct {
+	Password string   `short:"p" long:"password" description:"the encryption password if the database is encrypted"`
+	DataDir  string   `short:"d" long:"datadir" description:"specify the data directory to be used"`
+	Testnet  bool     `short:"t" long:"testnet" description:"use the test network"`
+	DB       string   `long:"db" description:"use the Postgres database at this connection URL instead of SQLite"`
+	Create   string   `long:"create" description:"create a token with this name and print it"`
+	Scopes   []string `long:"scope" description:"grant the new token a scope [read-only, orders, wallet, admin], may be repeated"`
+	Revoke   string   `long:"revoke" description:"revoke the token with this name"`
 }
 type Stop struct{}
 type Restart struct{}
@@ -130,6 +158,9 @@ var stopServer Stop
 var restartServer Restart
 var encryptDatabase EncryptDatabase
 var decryptDatabase DecryptDatabase
+var migrateDB MigrateDB
+var migrate Migrate
+var apiToken APIToken
 var setAPICreds SetAPICreds
 var status Status
 
@@ -148,7 +179,7 @@ func main() {
 				core.Node.Datastore.Close()
 				repoLockFile := filepath.Join(core.Node.RepoPath, lockfile.LockFile)
 				os.Remove(repoLockFile)
-				core.Node.Wallet.Close()
+				core.Node.CloseWallets()
 				core.Node.IpfsNode.Close()
 			}
 			os.Exit(1)
@@ -187,12 +218,154 @@ func main() {
 		"decrypt your database",
 		"This command decrypts the database containing your bitcoin private keys, identity key, and contracts.\n [Warning] doing so may put your bitcoins at risk.",
 		&decryptDatabase)
+	parser.AddCommand("migrate",
+		"upgrade your database schema",
+		"This command applies any pending schema migrations to the database. Start does the same, so this is only needed to check what will change with --dry-run or to upgrade without starting the node. A backup of the database is saved in the datastore folder first.",
+		&migrate)
+	parser.AddCommand("migratedb",
+		"copy your database into Postgres",
+		"This command copies the SQLite database of an existing repo into an empty Postgres database. Afterwards start the node with the same --db option to use it.",
+		&migrateDB)
+	parser.AddCommand("apitoken",
+		"manage API tokens",
+		"This command lists the API tokens, or creates or revokes one. A token is sent as an Authorization: Bearer header and only allows the requests its scopes cover. It's printed once when created as only its hash is saved.",
+		&apiToken)
 
 	if _, err := parser.Parse(); err != nil {
 		os.Exit(1)
 	}
 }
 
+func (x *Migrate) Execute(args []string) error {
+	repoPath, err := getRepoPath(x.Testnet)
+	if err != nil {
+		return err
+	}
+	if x.DataDir != "" {
+		repoPath = x.DataDir
+	}
+	if !fsrepo.IsInitialized(repoPath) {
+		return fmt.Errorf("No repo found at %s", repoPath)
+	}
+	if x.Password != "" {
+		x.Password = strings.Replace(x.Password, "'", "''", -1)
+	}
+	pending, version, err := db.PendingMigrations(repoPath, x.Password, x.Testnet)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Database schema version: %d\n", version)
+	if len(pending) == 0 {
+		fmt.Println("No pending migrations")
+		return nil
+	}
+	for i, m := range pending {
+		fmt.Printf("  %d: %s\n", version+i+1, m.Description)
+	}
+	if x.DryRun {
+		return nil
+	}
+	sqliteDB, err := db.Create(repoPath, x.Password, x.Testnet)
+	if err != nil {
+		return err
+	}
+	sqliteDB.Close()
+	fmt.Printf("Database migrated to version %d\n", db.LatestSchemaVersion())
+	return nil
+}
+
+func (x *MigrateDB) Execute(args []string) error {
+	repoPath, err := getRepoPath(x.Testnet)
+	if err != nil {
+		return err
+	}
+	if x.DataDir != "" {
+		repoPath = x.DataDir
+	}
+	if !fsrepo.IsInitialized(repoPath) {
+		return fmt.Errorf("No repo found at %s", repoPath)
+	}
+	dbPath := path.Join(repoPath, "datastore", "mainnet.db")
+	if x.Testnet {
+		dbPath = path.Join(repoPath, "datastore", "testnet.db")
+	}
+	if x.Password != "" {
+		x.Password = strings.Replace(x.Password, "'", "''", -1)
+	}
+	pgDB, err := postgres.Open(x.DB)
+	if err != nil {
+		return err
+	}
+	defer pgDB.Close()
+	if err := postgres.MigrateFromSQLite(dbPath, x.Password, pgDB); err != nil {
+		return err
+	}
+	fmt.Printf("Copied %s into Postgres\n", dbPath)
+	return nil
+}
+
+func (x *APIToken) Execute(args []string) error {
+	repoPath, err := getRepoPath(x.Testnet)
+	if err != nil {
+		return err
+	}
+	if x.DataDir != "" {
+		repoPath = x.DataDir
+	}
+	if !fsrepo.IsInitialized(repoPath) {
+		return fmt.Errorf("No repo found at %s", repoPath)
+	}
+	var datastore repo.Datastore
+	if x.DB != "" {
+		pgDB, err := postgres.Open(x.DB)
+		if err != nil {
+			return err
+		}
+		datastore = pgDB
+	} else {
+		if x.Password != "" {
+			x.Password = strings.Replace(x.Password, "'", "''", -1)
+		}
+		sqliteDB, err := db.Create(repoPath, x.Password, x.Testnet)
+		if err != nil {
+			return err
+		}
+		if sqliteDB.Config().IsEncrypted() {
+			sqliteDB.Close()
+			return encryptedDatabaseError
+		}
+		datastore = sqliteDB
+	}
+	defer datastore.Close()
+
+	switch {
+	case x.Create != "":
+		token, _, err := repo.NewAPIToken(datastore.APITokens(), x.Create, x.Scopes)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created token %s with scopes %s\n", x.Create, strings.Join(x.Scopes, ", "))
+		fmt.Println(token)
+	case x.Revoke != "":
+		if err := datastore.APITokens().Delete(x.Revoke); err != nil {
+			return err
+		}
+		fmt.Printf("Revoked token %s\n", x.Revoke)
+	default:
+		tokens, err := datastore.APITokens().GetAll()
+		if err != nil {
+			return err
+		}
+		if len(tokens) == 0 {
+			fmt.Println("No API tokens")
+		}
+		for _, t := range tokens {
+			fmt.Printf("%s\t%s\t%s\n", t.Name, strings.Join(t.Scopes, ","), t.Created.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
 func (x *EncryptDatabase) Execute(args []string) error {
 	return db.Encrypt()
 }
@@ -335,14 +508,14 @@ func (x *Init) Execute(args []string) error {
 		x.Password = strings.Replace(x.Password, "'", "''", -1)
 	}
 
-	_, err = initializeRepo(repoPath, x.Password, x.Mnemonic, x.Testnet)
+	_, err = initializeRepo(repoPath, x.Password, x.Mnemonic, x.Testnet, x.DB)
 	if err == repo.ErrRepoExists && x.Force {
 		reader := bufio.NewReader(os.Stdin)
 		fmt.Print("Force overwriting the db will destroy your existing keys and history. Are you really, really sure you want to continue? (y/n): ")
 		resp, _ := reader.ReadString('\n')
 		if strings.ToLower(resp) == "y\n" || strings.ToLower(resp) == "yes\n" {
 			os.RemoveAll(repoPath)
-			_, err = initializeRepo(repoPath, x.Password, x.Mnemonic, x.Testnet)
+			_, err = initializeRepo(repoPath, x.Password, x.Mnemonic, x.Testnet, x.DB)
 			if err != nil {
 				return err
 			}
@@ -387,7 +560,7 @@ func (x *Start) Execute(args []string) error {
 	repoLockFile := filepath.Join(repoPath, lockfile.LockFile)
 	os.Remove(repoLockFile)
 
-	sqliteDB, err := initializeRepo(repoPath, x.Password, "", isTestnet)
+	datastore, err := initializeRepo(repoPath, x.Password, "", isTestnet, x.DB)
 	if err != nil && err != repo.ErrRepoExists {
 		return err
 	}
@@ -415,17 +588,17 @@ func (x *Start) Execute(args []string) error {
 	ipfslogging.Output(w2)()
 
 	// If the database cannot be decrypted, exit
-	if sqliteDB.Config().IsEncrypted() {
-		sqliteDB.Close()
+	if datastore.Config().IsEncrypted() {
+		datastore.Close()
 		fmt.Print("Database is encrypted, enter your password: ")
 		bytePassword, _ := terminal.ReadPassword(int(syscall.Stdin))
 		fmt.Println("")
 		pw := string(bytePassword)
-		sqliteDB, err = initializeRepo(repoPath, pw, "", isTestnet)
+		datastore, err = initializeRepo(repoPath, pw, "", isTestnet, x.DB)
 		if err != nil && err != repo.ErrRepoExists {
 			return err
 		}
-		if sqliteDB.Config().IsEncrypted() {
+		if datastore.Config().IsEncrypted() {
 			log.Error("Invalid password")
 			os.Exit(3)
 		}
@@ -480,7 +653,7 @@ func (x *Start) Execute(args []string) error {
 		return err
 	}
 
-	identityKey, err := sqliteDB.Config().GetIdentityKey()
+	identityKey, err := datastore.Config().GetIdentityKey()
 	if err != nil {
 		log.Error(err)
 		return err
@@ -657,7 +830,7 @@ func (x *Start) Execute(args []string) error {
 	proto.Unmarshal(dhtrec.GetValue(), e)
 
 	// Wallet
-	mn, err := sqliteDB.Config().GetMnemonic()
+	mn, err := datastore.Config().GetMnemonic()
 	if err != nil {
 		log.Error(err)
 		return err
@@ -687,7 +860,7 @@ func (x *Start) Execute(args []string) error {
 	ml := logging.MultiLogger(bitcoinFileFormatter)
 	var wallet bitcoin.BitcoinWallet
 	if strings.ToLower(walletCfg.Type) == "spvwallet" {
-		wallet, err = spvwallet.NewSPVWallet(mn, &params, uint64(walletCfg.MaxFee), uint64(walletCfg.LowFeeDefault), uint64(walletCfg.MediumFeeDefault), uint64(walletCfg.HighFeeDefault), walletCfg.FeeAPI, repoPath, sqliteDB, "OpenBazaar", walletCfg.TrustedPeer, torDialer, ml)
+		wallet, err = spvwallet.NewSPVWallet(mn, &params, uint64(walletCfg.MaxFee), uint64(walletCfg.LowFeeDefault), uint64(walletCfg.MediumFeeDefault), uint64(walletCfg.HighFeeDefault), walletCfg.FeeAPI, repoPath, datastore, "OpenBazaar", walletCfg.TrustedPeer, torDialer, ml)
 		if err != nil {
 			log.Error(err)
 			return err
@@ -701,9 +874,51 @@ func (x *Start) Execute(args []string) error {
 			usetor = true
 		}
 		wallet = bitcoind.NewBitcoindWallet(mn, &params, repoPath, walletCfg.TrustedPeer, walletCfg.Binary, walletCfg.RPCUser, walletCfg.RPCPassword, usetor, controlPort)
+	} else if strings.ToLower(walletCfg.Type) == "electrum" {
+		if walletCfg.TrustedPeer == "" {
+			return errors.New("The address of an Electrum server must be specified as the TrustedPeer in the config file when using electrum")
+		}
+		wallet, err = electrum.NewElectrumWallet(mn, &params, walletCfg.TrustedPeer, uint64(walletCfg.MaxFee), uint64(walletCfg.LowFeeDefault), uint64(walletCfg.MediumFeeDefault), uint64(walletCfg.HighFeeDefault), datastore, torDialer)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
 	} else {
 		log.Fatal("Unknown wallet type")
 	}
+	// Wallets for other coins from the config and the command line
+	coinWalletCfgs, err := repo.GetCoinWalletConfigs(path.Join(repoPath, "config"))
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	for _, w := range x.Wallets {
+		coinCfg, err := parseWalletOption(w, *walletCfg)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		coinWalletCfgs = append(coinWalletCfgs, coinCfg)
+	}
+	wallets, err := bitcoin.NewWalletRegistry(wallet)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	// The coin each wallet's currency code is for, so it can be given exchange rates
+	walletCoins := make(map[string]string)
+	for _, coinCfg := range coinWalletCfgs {
+		w, err := newCoinWallet(coinCfg, mn, x.Testnet, x.Regtest, datastore, torDialer)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		if err := wallets.Register(w); err != nil {
+			log.Error(err)
+			return err
+		}
+		walletCoins[strings.ToLower(w.CurrencyCode())] = coinCfg.Currency
+	}
 
 	// Crosspost gateway
 	gatewayUrlStrings, err := repo.GetCrosspostGateway(path.Join(repoPath, "config"))
@@ -770,6 +985,19 @@ func (x *Start) Execute(args []string) error {
 			log.Error(err)
 			return err
 		}
+	} else if x.Storage == "s3" {
+		s3Config, err := repo.GetS3Config(path.Join(repoPath, "config"))
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		s3Storage, err := s3.NewS3Storage(*s3Config, torDialer)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		go s3Storage.Run()
+		storage = s3Storage
 	} else {
 		err = errors.New("Invalid storage option")
 		log.Error(err)
@@ -783,13 +1011,24 @@ func (x *Start) Execute(args []string) error {
 		return err
 	}
 
+	retrieverInterval, err := repo.GetMessageRetrieverInterval(path.Join(repoPath, "config"))
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
 	var exchangeRates bitcoin.ExchangeRates
+	coinExchangeRates := make(map[string]bitcoin.ExchangeRates)
 	if !x.DisableExchangeRates {
-		exchangeRates = exchange.NewBitcoinPriceFetcher(torDialer)
+		bitcoinRates := exchange.NewBitcoinPriceFetcher(torDialer, datastore.ExchangeRateHistory())
+		exchangeRates = bitcoinRates
+		for code, coin := range walletCoins {
+			coinExchangeRates[code] = exchange.NewCoinPriceFetcher(coin, bitcoinRates)
+		}
 	}
 
 	// Set up the ban manager
-	settings, err := sqliteDB.Settings().Get()
+	settings, err := datastore.Settings().Get()
 	if err != nil && err != db.SettingsNotSetError {
 		log.Error(err)
 		return err
@@ -812,16 +1051,20 @@ func (x *Start) Execute(args []string) error {
 		IpfsNode:          nd,
 		RootHash:          ipath.Path(e.Value).String(),
 		RepoPath:          repoPath,
-		Datastore:         sqliteDB,
+		Datastore:         datastore,
 		Wallet:            wallet,
+		Wallets:           wallets,
 		MessageStorage:    storage,
 		Resolver:          bstk.NewBlockStackClient(resolverUrl, torDialer),
 		ExchangeRates:     exchangeRates,
+		CoinExchangeRates: coinExchangeRates,
 		CrosspostGateways: gatewayUrls,
 		TorDialer:         torDialer,
 		UserAgent:         core.USERAGENT,
 		BanManager:        bm,
 	}
+	// Listings are stored on disk rather than in the database so the search index is rebuilt from them
+	core.Node.IndexListings()
 
 	if len(cfg.Addresses.Gateway) <= 0 {
 		return ErrNoGateways
@@ -837,19 +1080,26 @@ func (x *Start) Execute(args []string) error {
 	}
 
 	go func() {
-		core.Node.Service = service.New(core.Node, ctx, sqliteDB)
-		MR := ret.NewMessageRetriever(sqliteDB, ctx, nd, bm, core.Node.Service, 14, torDialer, core.Node.SendOfflineAck)
+		core.Node.Service = service.New(core.Node, ctx, datastore)
+		MR := ret.NewMessageRetriever(datastore, ctx, nd, bm, core.Node.Service, 14, retrieverInterval, torDialer, core.Node.SendOfflineAck)
 		go MR.Run()
 		core.Node.MessageRetriever = MR
-		PR := rep.NewPointerRepublisher(nd, sqliteDB, core.Node.IsModerator)
+		PR := rep.NewPointerRepublisher(nd, datastore, core.Node.IsModerator)
 		go PR.Run()
 		core.Node.PointerRepublisher = PR
+		go core.Node.PublishTagPointers()
+		go core.Node.StartOutboxJanitor()
+		go core.Node.StartOutgoingRetries()
 		if !x.DisableWallet {
 			MR.Wait()
-			TL := lis.NewTransactionListener(core.Node.Datastore, core.Node.Broadcast, core.Node.Wallet.Params())
-			wallet.AddTransactionListener(TL.OnTransactionReceived)
-			log.Info("Starting bitcoin wallet")
-			go wallet.Start()
+			for _, w := range wallets.All() {
+				TL := lis.NewTransactionListener(core.Node.Datastore, core.Node.Broadcast, w.Params(), core.Node.OnSaleFunded)
+				w.AddTransactionListener(TL.OnTransactionReceived)
+				w.AddTransactionListener(core.Node.TransactionRecorder(w))
+				log.Infof("Starting %s wallet", w.CurrencyCode())
+				go w.Start()
+			}
+			go core.Node.StartListingCloser()
 		}
 		core.Node.UpdateFollow()
 		core.Node.SeedNode()
@@ -864,19 +1114,69 @@ func (x *Start) Execute(args []string) error {
 	return nil
 }
 
-func initializeRepo(dataDir, password, mnemonic string, testnet bool) (*db.SQLiteDatastore, error) {
-	// Database
-	sqliteDB, err := db.Create(dataDir, password, testnet)
+/* The node's datastore also holds the wallet's keys and transactions, and those of
+   the wallets for other coins in tables of their own. */
+type nodeDatastore interface {
+	repo.Datastore
+	spvwallet.Datastore
+	WalletDatastore(coin string) (spvwallet.Datastore, error)
+}
+
+/* Parse a --wallet option of the form coin=server. The wallet uses the fee settings
+   of the default wallet. */
+func parseWalletOption(option string, defaults repo.WalletConfig) (repo.CoinWalletConfig, error) {
+	parts := strings.SplitN(option, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return repo.CoinWalletConfig{}, fmt.Errorf("Invalid wallet %s, expected coin=server", option)
+	}
+	defaults.Type = "electrum"
+	defaults.TrustedPeer = parts[1]
+	return repo.CoinWalletConfig{Currency: parts[0], WalletConfig: defaults}, nil
+}
+
+/* Create the wallet for another coin. Only electrum wallets can be run for coins other
+   than bitcoin as the SPV and bitcoind wallets only support bitcoin. */
+func newCoinWallet(cfg repo.CoinWalletConfig, mnemonic string, testnet, regtest bool, datastore nodeDatastore, dialer proxy.Dialer) (bitcoin.BitcoinWallet, error) {
+	if strings.ToLower(cfg.Type) != "electrum" {
+		return nil, fmt.Errorf("The %s wallet must be of the electrum type", cfg.Currency)
+	}
+	if cfg.TrustedPeer == "" {
+		return nil, fmt.Errorf("The address of an Electrum server must be specified as the TrustedPeer of the %s wallet", cfg.Currency)
+	}
+	params, err := bitcoin.CoinParams(cfg.Currency, testnet, regtest)
 	if err != nil {
-		return sqliteDB, err
+		return nil, err
+	}
+	walletDB, err := datastore.WalletDatastore(cfg.Currency)
+	if err != nil {
+		return nil, err
+	}
+	return electrum.NewElectrumWallet(mnemonic, params, cfg.TrustedPeer, uint64(cfg.MaxFee), uint64(cfg.LowFeeDefault), uint64(cfg.MediumFeeDefault), uint64(cfg.HighFeeDefault), walletDB, dialer)
+}
+
+func initializeRepo(dataDir, password, mnemonic string, testnet bool, dataSource string) (nodeDatastore, error) {
+	// Database
+	var datastore nodeDatastore
+	if dataSource != "" {
+		pgDB, err := postgres.Open(dataSource)
+		if err != nil {
+			return nil, err
+		}
+		datastore = pgDB
+	} else {
+		sqliteDB, err := db.Create(dataDir, password, testnet)
+		if err != nil {
+			return nil, err
+		}
+		datastore = sqliteDB
 	}
 
 	// Initialize the IPFS repo if it does not already exist
-	err = repo.DoInit(dataDir, 4096, testnet, password, mnemonic, sqliteDB.Config().Init)
+	err := repo.DoInit(dataDir, 4096, testnet, password, mnemonic, datastore.Config().Init)
 	if err != nil {
-		return sqliteDB, err
+		return datastore, err
 	}
-	return sqliteDB, nil
+	return datastore, nil
 }
 
 // Prints the addresses of the host
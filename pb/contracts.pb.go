@@ -360,6 +360,7 @@ type Listing_Metadata struct {
 	AcceptedCurrency string                        `protobuf:"bytes,5,opt,name=acceptedCurrency" json:"acceptedCurrency,omitempty"`
 	PricingCurrency  string                        `protobuf:"bytes,6,opt,name=pricingCurrency" json:"pricingCurrency,omitempty"`
 	Language         string                        `protobuf:"bytes,7,opt,name=language" json:"language,omitempty"`
+	FundingGoal      uint64                        `protobuf:"varint,8,opt,name=fundingGoal" json:"fundingGoal,omitempty"`
 }
 
 func (m *Listing_Metadata) Reset()                    { *m = Listing_Metadata{} }
@@ -416,6 +417,13 @@ func (m *Listing_Metadata) GetLanguage() string {
 	return ""
 }
 
+func (m *Listing_Metadata) GetFundingGoal() uint64 {
+	if m != nil {
+		return m.FundingGoal
+	}
+	return 0
+}
+
 type Listing_Item struct {
 	Title          string                 `protobuf:"bytes,1,opt,name=title" json:"title,omitempty"`
 	Description    string                 `protobuf:"bytes,2,opt,name=description" json:"description,omitempty"`
@@ -1179,6 +1187,7 @@ type Order_Item struct {
 	ShippingOption *Order_Item_ShippingOption `protobuf:"bytes,4,opt,name=shippingOption" json:"shippingOption,omitempty"`
 	Memo           string                     `protobuf:"bytes,5,opt,name=memo" json:"memo,omitempty"`
 	CouponCodes    []string                   `protobuf:"bytes,6,rep,name=couponCodes" json:"couponCodes,omitempty"`
+	BidAmount      uint64                     `protobuf:"varint,7,opt,name=bidAmount" json:"bidAmount,omitempty"`
 }
 
 func (m *Order_Item) Reset()                    { *m = Order_Item{} }
@@ -1228,6 +1237,13 @@ func (m *Order_Item) GetCouponCodes() []string {
 	return nil
 }
 
+func (m *Order_Item) GetBidAmount() uint64 {
+	if m != nil {
+		return m.BidAmount
+	}
+	return 0
+}
+
 type Order_Item_Option struct {
 	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
 	Value string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
@@ -1607,6 +1623,14 @@ func (m *OrderFulfillment_PhysicalDelivery) GetTrackingNumber() string {
 type OrderFulfillment_DigitalDelivery struct {
 	Url      string `protobuf:"bytes,1,opt,name=url" json:"url,omitempty"`
 	Password string `protobuf:"bytes,2,opt,name=password" json:"password,omitempty"`
+	// Delivered automatically by the vendor's node. The file is added to IPFS
+	// encrypted with a key of its own and the keys are encrypted to the buyer.
+	Hash         string `protobuf:"bytes,3,opt,name=hash" json:"hash,omitempty"`
+	EncryptedKey []byte `protobuf:"bytes,4,opt,name=encryptedKey,proto3" json:"encryptedKey,omitempty"`
+	Filename     string `protobuf:"bytes,5,opt,name=filename" json:"filename,omitempty"`
+	MediaType    string `protobuf:"bytes,6,opt,name=mediaType" json:"mediaType,omitempty"`
+	Size         uint64 `protobuf:"varint,7,opt,name=size" json:"size,omitempty"`
+	LicenseKey   []byte `protobuf:"bytes,8,opt,name=licenseKey,proto3" json:"licenseKey,omitempty"`
 }
 
 func (m *OrderFulfillment_DigitalDelivery) Reset()         { *m = OrderFulfillment_DigitalDelivery{} }
@@ -1630,6 +1654,48 @@ func (m *OrderFulfillment_DigitalDelivery) GetPassword() string {
 	return ""
 }
 
+func (m *OrderFulfillment_DigitalDelivery) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *OrderFulfillment_DigitalDelivery) GetEncryptedKey() []byte {
+	if m != nil {
+		return m.EncryptedKey
+	}
+	return nil
+}
+
+func (m *OrderFulfillment_DigitalDelivery) GetFilename() string {
+	if m != nil {
+		return m.Filename
+	}
+	return ""
+}
+
+func (m *OrderFulfillment_DigitalDelivery) GetMediaType() string {
+	if m != nil {
+		return m.MediaType
+	}
+	return ""
+}
+
+func (m *OrderFulfillment_DigitalDelivery) GetSize() uint64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *OrderFulfillment_DigitalDelivery) GetLicenseKey() []byte {
+	if m != nil {
+		return m.LicenseKey
+	}
+	return nil
+}
+
 type OrderFulfillment_Payout struct {
 	Sigs             []*BitcoinSignature `protobuf:"bytes,1,rep,name=sigs" json:"sigs,omitempty"`
 	PayoutAddress    string              `protobuf:"bytes,2,opt,name=payoutAddress" json:"payoutAddress,omitempty"`
@@ -2222,196 +2288,200 @@ func init() {
 func init() { proto.RegisterFile("contracts.proto", fileDescriptor1) }
 
 var fileDescriptor1 = []byte{
-	// 3043 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x59, 0x4d, 0x70, 0x1b, 0x49,
-	0xf5, 0xcf, 0xe8, 0x5b, 0xcf, 0xb2, 0x2d, 0xf7, 0x3a, 0x89, 0x76, 0xfe, 0x7f, 0x36, 0x89, 0x2a,
-	0x09, 0x21, 0x9b, 0x9d, 0xdd, 0x98, 0x03, 0x29, 0xa0, 0x60, 0x65, 0x8d, 0x1c, 0xcf, 0xc6, 0xb1,
-	0xb4, 0x2d, 0x99, 0x65, 0xf7, 0xe2, 0x6a, 0xcf, 0xb4, 0xe5, 0x21, 0xa3, 0x19, 0xed, 0x7c, 0x78,
-	0x2d, 0x6e, 0xdc, 0x28, 0x2e, 0x5c, 0xa0, 0x96, 0x23, 0x47, 0xee, 0xdc, 0x28, 0xaa, 0xa8, 0xe2,
-	0xc8, 0x91, 0x2a, 0xaa, 0xe0, 0x40, 0x15, 0xc5, 0x99, 0x33, 0x17, 0x0e, 0x50, 0xfd, 0x35, 0x9a,
-	0x19, 0xd9, 0xd9, 0x04, 0x8a, 0xe2, 0xa4, 0x79, 0xbf, 0xf7, 0x7a, 0xd4, 0xfd, 0xbe, 0x5f, 0x0f,
-	0x6c, 0xda, 0x81, 0x1f, 0x87, 0xc4, 0x8e, 0x23, 0x63, 0x1e, 0x06, 0x71, 0xa0, 0x23, 0x3b, 0x48,
-	0xfc, 0x38, 0x5c, 0xd8, 0x81, 0x43, 0x15, 0x76, 0x6b, 0x1a, 0x04, 0x53, 0x8f, 0xbe, 0xcb, 0xa9,
-	0x93, 0xe4, 0xf4, 0xdd, 0xd8, 0x9d, 0xd1, 0x28, 0x26, 0xb3, 0xb9, 0x10, 0xe8, 0xfe, 0xb3, 0x0c,
-	0x5b, 0xd8, 0xb5, 0x49, 0xe8, 0xb8, 0xc4, 0xef, 0xcb, 0x37, 0xa2, 0xf7, 0x60, 0xe3, 0x9c, 0xfa,
-	0x4e, 0x10, 0x1e, 0xb8, 0x51, 0xec, 0xfa, 0xd3, 0xa8, 0xa3, 0xdd, 0x2e, 0x3f, 0x58, 0xdb, 0x69,
-	0x18, 0x12, 0xc0, 0x05, 0x3e, 0xba, 0x0f, 0x70, 0x92, 0x2c, 0x68, 0x38, 0x0c, 0x1d, 0x1a, 0x76,
-	0x4a, 0xb7, 0xb5, 0x07, 0x6b, 0x3b, 0x35, 0x83, 0x53, 0x38, 0xc3, 0x41, 0x07, 0x70, 0x53, 0xac,
-	0xe4, 0x64, 0x3f, 0xf0, 0x4f, 0xdd, 0x70, 0x46, 0x62, 0x37, 0xf0, 0x3b, 0x65, 0xbe, 0x08, 0x19,
-	0x2b, 0x1c, 0x7c, 0xd5, 0x12, 0x64, 0xc1, 0x8d, 0x0c, 0x6b, 0x2f, 0xf1, 0x4e, 0x5d, 0xcf, 0x9b,
-	0x51, 0x3f, 0xee, 0x54, 0xf8, 0x7e, 0xb7, 0x8c, 0x22, 0x03, 0x5f, 0xb1, 0x00, 0x99, 0xb0, 0xbd,
-	0xdc, 0x66, 0x3f, 0x98, 0xcd, 0x3d, 0xca, 0x77, 0x55, 0xe5, 0xbb, 0x6a, 0x1b, 0x05, 0x1c, 0x5f,
-	0x2a, 0x8d, 0xba, 0x50, 0x77, 0xdc, 0x68, 0x9e, 0xc4, 0xb4, 0x53, 0xe3, 0x0b, 0x1b, 0x86, 0x29,
-	0x68, 0xac, 0x18, 0xe8, 0x7d, 0xd8, 0x92, 0x8f, 0x98, 0x46, 0x81, 0x97, 0xf0, 0xbf, 0xa9, 0xcb,
-	0xc3, 0x9b, 0x45, 0x0e, 0x5e, 0x15, 0x46, 0xb7, 0xa0, 0x16, 0xd2, 0xd3, 0xc4, 0x77, 0x3a, 0x0d,
-	0xbe, 0xac, 0x6e, 0x60, 0x4e, 0x62, 0x09, 0xa3, 0x87, 0x00, 0x91, 0x3b, 0xf5, 0x49, 0x9c, 0x84,
-	0x34, 0xea, 0x34, 0xb9, 0x2e, 0xc0, 0x18, 0x2b, 0x08, 0x67, 0xb8, 0xdd, 0x3f, 0x5d, 0x87, 0xba,
-	0x34, 0x23, 0x42, 0x50, 0x89, 0xbc, 0x64, 0xda, 0xd1, 0x6e, 0x6b, 0x0f, 0x9a, 0x98, 0x3f, 0xa3,
-	0x5b, 0xd0, 0x10, 0x2a, 0xb3, 0x4c, 0x69, 0xd7, 0xb2, 0x61, 0x99, 0x38, 0x05, 0xd1, 0x3b, 0xd0,
-	0x98, 0xd1, 0x98, 0x38, 0x24, 0x26, 0xd2, 0x86, 0x5b, 0xca, 0x4d, 0x8c, 0xe7, 0x92, 0x81, 0x53,
-	0x11, 0x74, 0x07, 0x2a, 0x6e, 0x4c, 0x67, 0x9d, 0x0a, 0x17, 0x5d, 0x4f, 0x45, 0xad, 0x98, 0xce,
-	0x30, 0x67, 0xa1, 0x1e, 0x6c, 0x46, 0x67, 0xee, 0x7c, 0xee, 0xfa, 0xd3, 0xe1, 0x9c, 0x9d, 0x38,
-	0xea, 0x54, 0xf9, 0x19, 0x6e, 0xa6, 0xd2, 0xe3, 0x1c, 0x1f, 0x17, 0xe5, 0x51, 0x17, 0xaa, 0x31,
-	0xb9, 0xa0, 0x51, 0xa7, 0xc6, 0x17, 0xb6, 0xd2, 0x85, 0x13, 0x72, 0x81, 0x05, 0x0b, 0x7d, 0x05,
-	0xea, 0x76, 0x90, 0xcc, 0xd9, 0xeb, 0xeb, 0x5c, 0x6a, 0x33, 0x95, 0xea, 0x73, 0x1c, 0x2b, 0x3e,
-	0x7a, 0x0b, 0x60, 0x16, 0x38, 0x34, 0x24, 0x71, 0x10, 0x46, 0x9d, 0xc6, 0xed, 0xf2, 0x83, 0x26,
-	0xce, 0x20, 0xc8, 0x00, 0x14, 0xd3, 0x70, 0x16, 0xf5, 0x7c, 0xa7, 0x1f, 0xf8, 0x8e, 0x2b, 0x36,
-	0xdd, 0xe4, 0x6a, 0xbc, 0x84, 0x83, 0xba, 0xd0, 0x12, 0xa6, 0x1a, 0x05, 0x9e, 0x6b, 0x2f, 0x3a,
-	0xc0, 0x25, 0x73, 0x98, 0xfe, 0xab, 0x32, 0x34, 0x94, 0xfe, 0x50, 0x07, 0xea, 0xe7, 0x34, 0x8c,
-	0x98, 0xab, 0x30, 0xe3, 0xac, 0x63, 0x45, 0xa2, 0x5d, 0x68, 0xa9, 0x4c, 0x30, 0x59, 0xcc, 0x29,
-	0xb7, 0xd1, 0xc6, 0xce, 0x5b, 0x2b, 0x26, 0x30, 0xfa, 0x19, 0x29, 0x9c, 0x5b, 0x83, 0xde, 0x83,
-	0xda, 0x69, 0xc0, 0x82, 0x8a, 0x1b, 0x70, 0x63, 0xa7, 0xb3, 0xba, 0x7a, 0x8f, 0xf3, 0xb1, 0x94,
-	0x43, 0x3b, 0x50, 0xa3, 0x17, 0x73, 0x37, 0x5c, 0x48, 0x3b, 0xea, 0x86, 0xc8, 0x34, 0x86, 0xca,
-	0x34, 0xc6, 0x44, 0x65, 0x1a, 0x2c, 0x25, 0xd1, 0x43, 0x68, 0x13, 0xdb, 0xa6, 0xf3, 0x98, 0x3a,
-	0xfd, 0x24, 0x0c, 0xa9, 0x6f, 0x2f, 0x78, 0x78, 0x35, 0xf1, 0x0a, 0x8e, 0x1e, 0xc0, 0xe6, 0x3c,
-	0x74, 0x6d, 0xd7, 0x9f, 0xa6, 0xa2, 0x35, 0x2e, 0x5a, 0x84, 0x91, 0x0e, 0x0d, 0x8f, 0xf8, 0xd3,
-	0x84, 0x4c, 0x29, 0x8f, 0xa2, 0x26, 0x4e, 0xe9, 0xee, 0x08, 0x5a, 0xd9, 0x53, 0xa3, 0x2d, 0x58,
-	0x1f, 0xed, 0x7f, 0x3c, 0xb6, 0xfa, 0xbd, 0x83, 0xe3, 0xa7, 0xc3, 0xa1, 0xd9, 0xbe, 0x86, 0xda,
-	0xd0, 0x32, 0xad, 0xa7, 0xd6, 0x44, 0x21, 0x1a, 0x5a, 0x83, 0xfa, 0x78, 0x80, 0xbf, 0x63, 0xf5,
-	0x07, 0xed, 0x12, 0xda, 0x00, 0xe8, 0xe3, 0xe1, 0x47, 0xe6, 0xf1, 0xde, 0xd1, 0xa1, 0xd9, 0x2e,
-	0x77, 0xef, 0x43, 0x4d, 0x68, 0x02, 0x6d, 0xc2, 0xda, 0x9e, 0xf5, 0xdd, 0x81, 0x79, 0x3c, 0xc2,
-	0x4c, 0xf4, 0x1a, 0x5b, 0xd7, 0x3b, 0xea, 0x4f, 0xac, 0xe1, 0x61, 0x5b, 0xd3, 0xff, 0x5e, 0x85,
-	0x0a, 0xf3, 0x68, 0xb4, 0x0d, 0xd5, 0xd8, 0x8d, 0x3d, 0x2a, 0x63, 0x4a, 0x10, 0xe8, 0x36, 0xac,
-	0x39, 0x34, 0xb2, 0x43, 0x97, 0xbb, 0x2b, 0xb7, 0x59, 0x13, 0x67, 0x21, 0x74, 0x1f, 0x36, 0xe6,
-	0x61, 0x60, 0xd3, 0x28, 0x72, 0xfd, 0x29, 0xd3, 0x25, 0x37, 0x4d, 0x13, 0x17, 0x50, 0xf6, 0x7e,
-	0xa6, 0x11, 0xca, 0xed, 0x50, 0xc1, 0x82, 0x60, 0x81, 0xec, 0x47, 0xa7, 0x9f, 0x71, 0xf5, 0x36,
-	0x30, 0x7f, 0x66, 0x58, 0x4c, 0xa6, 0x22, 0x22, 0x9a, 0x98, 0x3f, 0xa3, 0xb7, 0xa1, 0xe6, 0xce,
-	0xc8, 0x94, 0xaa, 0x08, 0x78, 0x23, 0x17, 0x8e, 0x86, 0xc5, 0x78, 0x58, 0x8a, 0xb0, 0x20, 0xb0,
-	0x49, 0x4c, 0xa7, 0x41, 0xe8, 0xd2, 0x34, 0x08, 0x96, 0x08, 0xdb, 0xca, 0x34, 0x24, 0x33, 0xe1,
-	0xf7, 0x25, 0x2c, 0x08, 0xf4, 0xff, 0xd0, 0xb4, 0x95, 0xe3, 0x4b, 0x3f, 0x5f, 0x02, 0xc8, 0x80,
-	0x7a, 0x20, 0x43, 0x7c, 0x8d, 0xef, 0x60, 0x3b, 0xbf, 0x03, 0x19, 0xdf, 0x4a, 0x08, 0xdd, 0x83,
-	0x4a, 0xf4, 0x22, 0x89, 0x3a, 0x2d, 0x99, 0xdf, 0x73, 0xc2, 0xe3, 0x17, 0x09, 0xe6, 0x6c, 0xfd,
-	0x13, 0xa8, 0x89, 0x95, 0x5c, 0x13, 0x64, 0xa6, 0xd4, 0xcf, 0x9f, 0x5f, 0x41, 0xfb, 0x3a, 0x34,
-	0xce, 0x49, 0xe8, 0x12, 0x3f, 0x8e, 0x3a, 0x65, 0x7e, 0xd0, 0x94, 0xd6, 0x7f, 0xa0, 0x41, 0x79,
-	0xfc, 0x22, 0x61, 0x31, 0x2c, 0xb1, 0x7e, 0x30, 0x3b, 0x09, 0x78, 0x89, 0x5c, 0xc7, 0x39, 0x8c,
-	0x1d, 0x7e, 0x1e, 0x06, 0x4e, 0x62, 0xc7, 0x32, 0x7b, 0x36, 0xf1, 0x12, 0x60, 0xdc, 0x28, 0x09,
-	0xed, 0x33, 0x12, 0x4e, 0x85, 0x79, 0xcb, 0x78, 0x09, 0xb0, 0x3d, 0x7c, 0x9a, 0x10, 0x3f, 0x76,
-	0x63, 0x11, 0x64, 0x65, 0x9c, 0xd2, 0xfa, 0xe7, 0x1a, 0x54, 0xb9, 0x71, 0x98, 0xd4, 0xa9, 0xeb,
-	0xd1, 0xcc, 0x19, 0x53, 0x9a, 0xf1, 0x82, 0xd0, 0x9d, 0xba, 0x3e, 0xf1, 0xe4, 0x9f, 0xa7, 0x34,
-	0x33, 0x96, 0x97, 0xfe, 0x6f, 0x13, 0x0b, 0x02, 0xdd, 0x80, 0xda, 0x8c, 0x3a, 0x6e, 0x22, 0xd2,
-	0x73, 0x13, 0x4b, 0x8a, 0x49, 0x47, 0x33, 0xe2, 0x79, 0x32, 0x5e, 0x05, 0xc1, 0x3d, 0xca, 0xf5,
-	0x55, 0x64, 0xf2, 0x67, 0xfd, 0x97, 0x35, 0xd8, 0xc8, 0x27, 0xe7, 0x4b, 0x4d, 0xf0, 0x04, 0x2a,
-	0xf1, 0x32, 0x5b, 0xdd, 0xbd, 0x22, 0xaf, 0xa7, 0x24, 0xcf, 0x59, 0x7c, 0x05, 0xba, 0x0f, 0xf5,
-	0x90, 0x4e, 0xb9, 0xc7, 0x30, 0xcb, 0x6c, 0xec, 0xb4, 0x58, 0xb6, 0x66, 0x8d, 0x4f, 0x3f, 0x70,
-	0x28, 0x56, 0x4c, 0xf4, 0x0c, 0xd6, 0x55, 0x51, 0xc0, 0x89, 0x47, 0x23, 0x99, 0xa8, 0xee, 0x7d,
-	0xd1, 0x5f, 0x71, 0x61, 0x9c, 0x5f, 0x8b, 0xbe, 0x01, 0x8d, 0x88, 0x86, 0xe7, 0xae, 0x4d, 0x55,
-	0x29, 0xba, 0x75, 0xe5, 0x7b, 0x84, 0x1c, 0x4e, 0x17, 0xe8, 0x04, 0xea, 0x12, 0xbc, 0x54, 0x15,
-	0x69, 0x04, 0x97, 0xb2, 0x11, 0xfc, 0x08, 0xb6, 0x68, 0x14, 0xbb, 0x33, 0x12, 0x53, 0xc7, 0xa4,
-	0x9e, 0x7b, 0x4e, 0xc3, 0x85, 0xb4, 0xd5, 0x2a, 0x43, 0xff, 0x51, 0x19, 0xd6, 0x73, 0x07, 0x40,
-	0x1f, 0x40, 0x23, 0x4c, 0x3c, 0xca, 0x4b, 0x82, 0xc6, 0x95, 0x6c, 0xbc, 0xd2, 0xc9, 0x0d, 0x2c,
-	0x57, 0xe1, 0x74, 0x3d, 0x7a, 0x1f, 0xaa, 0x21, 0x57, 0x61, 0x89, 0x1f, 0xfd, 0xe1, 0xab, 0xbf,
-	0x08, 0x8b, 0x85, 0xfa, 0x04, 0x2a, 0x8c, 0x64, 0x1e, 0x39, 0x73, 0x7d, 0x4c, 0xfc, 0x29, 0x95,
-	0x75, 0x2c, 0xa5, 0x39, 0x8f, 0x5c, 0x08, 0x5e, 0x49, 0xf2, 0x24, 0xbd, 0xd4, 0x51, 0x39, 0xa3,
-	0xa3, 0xee, 0x4f, 0x34, 0x68, 0xa8, 0xed, 0xa2, 0xeb, 0xb0, 0xf5, 0xe1, 0x51, 0xef, 0x70, 0x62,
-	0x4d, 0x3e, 0x3e, 0x36, 0xad, 0x71, 0x7f, 0x78, 0x74, 0x38, 0x69, 0x5f, 0x43, 0xff, 0x07, 0x37,
-	0xf7, 0x0e, 0x7a, 0x93, 0xe3, 0xbd, 0xc1, 0xe0, 0x38, 0xe5, 0xe3, 0xde, 0xe1, 0xd3, 0x41, 0x5b,
-	0x43, 0x6f, 0xc2, 0xf5, 0x94, 0xf9, 0xd1, 0xc0, 0x7a, 0xba, 0x3f, 0x91, 0xac, 0x12, 0x63, 0xf5,
-	0x87, 0xcf, 0x77, 0xad, 0xc3, 0x81, 0x79, 0x3c, 0xde, 0xb7, 0x46, 0x23, 0xeb, 0xf0, 0xe9, 0x71,
-	0xcf, 0x34, 0xdb, 0x65, 0xf4, 0x16, 0xe8, 0xab, 0xac, 0xf1, 0xd1, 0xee, 0x04, 0xf7, 0xfa, 0x93,
-	0x76, 0xa5, 0xfb, 0x18, 0x5a, 0x59, 0xbf, 0x65, 0x25, 0xe6, 0x60, 0xc8, 0x4a, 0xce, 0xc8, 0xea,
-	0x3f, 0x3b, 0x1a, 0xb5, 0xaf, 0x15, 0x6b, 0x87, 0xa6, 0xff, 0x58, 0x83, 0xf2, 0x84, 0x5c, 0xb0,
-	0x32, 0x1f, 0x93, 0x8b, 0xd4, 0x68, 0x4d, 0xac, 0x48, 0xf4, 0x08, 0x20, 0x26, 0x17, 0x58, 0x7a,
-	0x7e, 0xe9, 0x12, 0xcf, 0xcf, 0xf0, 0x59, 0x86, 0x8b, 0xc9, 0x85, 0xda, 0x05, 0xd7, 0x5a, 0x03,
-	0x67, 0x21, 0x96, 0xcc, 0xe7, 0x34, 0xb4, 0xa9, 0x1f, 0xb3, 0xc2, 0x59, 0xe1, 0x19, 0x3b, 0x83,
-	0xe8, 0xbf, 0xd5, 0xa0, 0x26, 0xba, 0xa0, 0x2b, 0x4a, 0xd8, 0x36, 0x54, 0xce, 0x48, 0x74, 0x26,
-	0x12, 0xcb, 0xfe, 0x35, 0xcc, 0x29, 0x74, 0x17, 0x5a, 0x8e, 0x1b, 0xf1, 0x49, 0x84, 0x6d, 0x4a,
-	0x78, 0xec, 0xfe, 0x35, 0x9c, 0x43, 0xd1, 0x43, 0xd8, 0x94, 0x7f, 0x65, 0x4a, 0x98, 0x27, 0x96,
-	0xd2, 0xbe, 0x86, 0x8b, 0x0c, 0x74, 0x1f, 0xd6, 0xb9, 0xb5, 0x53, 0x49, 0x96, 0x6d, 0x2a, 0xfb,
-	0x1a, 0xce, 0xc3, 0xbb, 0x35, 0xa8, 0xb0, 0xc9, 0x67, 0x17, 0xa0, 0xa1, 0xfe, 0xab, 0xfb, 0x87,
-	0x26, 0x54, 0xc5, 0xdc, 0x71, 0x17, 0xd6, 0x45, 0x73, 0xd5, 0x73, 0x9c, 0x90, 0x46, 0x91, 0x3c,
-	0x4b, 0x1e, 0x64, 0x09, 0x59, 0x00, 0x7b, 0x54, 0x85, 0xe3, 0x12, 0x40, 0x6f, 0x43, 0x23, 0xca,
-	0x6a, 0x94, 0x35, 0x8c, 0xfc, 0xed, 0x4b, 0xc7, 0x4f, 0x05, 0xd0, 0x97, 0xa0, 0xce, 0x27, 0x04,
-	0xcb, 0x94, 0x89, 0x87, 0x77, 0xcd, 0x0a, 0x43, 0x4f, 0xa0, 0x99, 0x8e, 0x62, 0x72, 0xc6, 0x78,
-	0x59, 0x0b, 0xb5, 0x14, 0x46, 0x77, 0xa0, 0xca, 0x9a, 0x64, 0xd5, 0xd9, 0xae, 0xc9, 0x2d, 0xf0,
-	0xf6, 0x59, 0x70, 0xd0, 0x03, 0xa8, 0xcf, 0xc9, 0x82, 0xcf, 0x41, 0x62, 0xae, 0xd8, 0x90, 0x42,
-	0x23, 0x81, 0x62, 0xc5, 0x66, 0x5e, 0x10, 0x12, 0x16, 0xca, 0xcf, 0xe8, 0x42, 0x94, 0xf4, 0x16,
-	0xce, 0x20, 0x68, 0x07, 0xb6, 0x89, 0x17, 0xd3, 0xd0, 0x27, 0x31, 0x65, 0x9d, 0x14, 0xb1, 0x63,
-	0xcb, 0x3f, 0x0d, 0x64, 0x67, 0x7b, 0x29, 0x4f, 0xff, 0xbd, 0x06, 0x8d, 0xd4, 0xcd, 0x6e, 0x40,
-	0x8d, 0xa9, 0x64, 0x12, 0x48, 0x85, 0x4b, 0x8a, 0x39, 0x3a, 0x91, 0x96, 0x10, 0x95, 0x49, 0x91,
-	0x2c, 0x45, 0xda, 0xac, 0xe4, 0x89, 0x5c, 0xc7, 0x9f, 0x79, 0xf9, 0x89, 0x49, 0x4c, 0x65, 0x55,
-	0x12, 0x04, 0x77, 0xe1, 0x20, 0x8a, 0x89, 0xc7, 0x3d, 0x4d, 0x54, 0xa6, 0x0c, 0xc2, 0x2a, 0x85,
-	0x1c, 0x89, 0xb9, 0xcf, 0xac, 0x54, 0x0a, 0xc9, 0x64, 0x85, 0x5c, 0xfe, 0xf9, 0x61, 0x10, 0xf3,
-	0x56, 0x88, 0x37, 0xe3, 0x59, 0x4c, 0xff, 0x4b, 0x49, 0xf6, 0x73, 0xb7, 0x61, 0xcd, 0x13, 0xd9,
-	0x6f, 0x9f, 0x79, 0xbf, 0x38, 0x55, 0x16, 0xca, 0xd5, 0x6d, 0x99, 0xc7, 0x14, 0x8d, 0x1e, 0x2d,
-	0xdb, 0x9d, 0x32, 0x37, 0x1f, 0xca, 0x98, 0x6f, 0xa5, 0xd9, 0xd9, 0x85, 0x8d, 0xfc, 0x5c, 0x93,
-	0x36, 0xdb, 0x99, 0x45, 0x85, 0x49, 0xa8, 0xb0, 0x82, 0xa9, 0x73, 0x46, 0x67, 0x81, 0x54, 0x0f,
-	0x7f, 0x66, 0x67, 0x10, 0x83, 0x0d, 0xd3, 0x83, 0x6a, 0x08, 0xb3, 0x90, 0xbe, 0xf3, 0xd2, 0xfe,
-	0x69, 0x1b, 0xaa, 0xe7, 0xc4, 0x4b, 0xa8, 0x34, 0x9d, 0x20, 0xf4, 0x6f, 0xbd, 0x52, 0xe1, 0xef,
-	0x40, 0x5d, 0x16, 0x46, 0x65, 0x78, 0x49, 0xea, 0xbf, 0x28, 0x41, 0x5d, 0x3a, 0x28, 0x7a, 0x87,
-	0xf5, 0x21, 0xf1, 0x59, 0xe0, 0xc8, 0xda, 0x75, 0x3d, 0xef, 0xc0, 0x6c, 0x2c, 0x39, 0x0b, 0x1c,
-	0x2c, 0x85, 0x58, 0xdc, 0xa6, 0xc3, 0x98, 0x6a, 0xb3, 0x52, 0x80, 0xf9, 0x20, 0x99, 0xf1, 0xd4,
-	0x21, 0xaa, 0x87, 0xa4, 0x98, 0xdd, 0xe9, 0x85, 0x7d, 0xc6, 0x0a, 0x0c, 0x56, 0xce, 0x55, 0xc1,
-	0x39, 0x8c, 0x77, 0xaf, 0x67, 0xc4, 0xf5, 0x59, 0x6a, 0x91, 0x7d, 0xce, 0x12, 0xc8, 0x7a, 0x71,
-	0x3d, 0xef, 0xc5, 0x7c, 0xc0, 0x73, 0x28, 0x9d, 0x8d, 0x79, 0x4f, 0xc9, 0x07, 0x75, 0x3e, 0xe0,
-	0x2d, 0xb1, 0xee, 0x13, 0xa8, 0x89, 0x73, 0xa0, 0x37, 0x60, 0xb3, 0x67, 0x9a, 0x78, 0x30, 0x1e,
-	0x1f, 0xe3, 0xc1, 0x87, 0x47, 0x83, 0x31, 0xab, 0x5c, 0x00, 0x35, 0xd3, 0xc2, 0x83, 0xfe, 0xa4,
-	0xad, 0xa1, 0x75, 0x68, 0x3e, 0x1f, 0x9a, 0x03, 0xdc, 0x9b, 0x0c, 0xcc, 0x76, 0xa9, 0xfb, 0xd3,
-	0x12, 0x6c, 0xad, 0xde, 0x86, 0x74, 0xa0, 0x1e, 0x30, 0xd0, 0x32, 0x55, 0xf1, 0x90, 0x64, 0x3e,
-	0xdb, 0x94, 0x5e, 0x27, 0xdb, 0xb0, 0x31, 0x44, 0xe8, 0x5c, 0x25, 0x4e, 0x35, 0x86, 0xe4, 0x50,
-	0x36, 0xaf, 0x85, 0xf4, 0xd3, 0x84, 0x46, 0x31, 0x75, 0x7a, 0x42, 0xd9, 0x42, 0x9d, 0x45, 0x98,
-	0xb7, 0xc4, 0x64, 0x11, 0x24, 0x31, 0xcb, 0xb1, 0x55, 0x91, 0x63, 0x53, 0x00, 0x7d, 0x13, 0xda,
-	0x22, 0xfd, 0x8c, 0x97, 0xf7, 0x17, 0x22, 0xd1, 0xb5, 0x0d, 0x9c, 0x67, 0xe0, 0x15, 0xc9, 0xee,
-	0x0f, 0x35, 0x58, 0x13, 0x77, 0x4e, 0xf4, 0x7b, 0xd4, 0x8e, 0xff, 0x2b, 0x1a, 0x61, 0x13, 0x88,
-	0x3b, 0x55, 0xf1, 0xbb, 0x65, 0xec, 0xba, 0xb1, 0x1d, 0xb8, 0xfe, 0x72, 0x5b, 0x9c, 0xdd, 0xfd,
-	0x9b, 0x06, 0x9b, 0x85, 0x0d, 0xa3, 0xf7, 0x33, 0x37, 0x25, 0x1a, 0xff, 0xcf, 0xbb, 0xc5, 0x43,
-	0x19, 0x93, 0x90, 0xf8, 0x11, 0xb1, 0x99, 0x41, 0x2f, 0xb9, 0x3c, 0x61, 0x13, 0x83, 0x12, 0xe5,
-	0xdb, 0x6e, 0xe1, 0x25, 0xa0, 0x2f, 0xe0, 0x8d, 0x4b, 0x96, 0x67, 0x52, 0xd6, 0x78, 0x79, 0xb9,
-	0x93, 0x85, 0x78, 0xdd, 0x53, 0x49, 0x5f, 0xbd, 0x36, 0x05, 0x98, 0x2f, 0xa7, 0xc1, 0xc4, 0x04,
-	0xca, 0x5c, 0x20, 0x87, 0x75, 0x47, 0xd0, 0x2e, 0x2a, 0x82, 0xe5, 0x67, 0xd7, 0x9f, 0x27, 0xb1,
-	0xe5, 0x3b, 0xf4, 0x42, 0xb6, 0x7b, 0x19, 0xe4, 0xe5, 0x87, 0xe9, 0xfe, 0xbc, 0x0a, 0xed, 0x95,
-	0x5b, 0xba, 0xd4, 0xa0, 0x4e, 0xde, 0xa0, 0x4e, 0x7a, 0x75, 0x55, 0xca, 0x5c, 0x5d, 0xe5, 0x8c,
-	0x5c, 0x7e, 0x1d, 0x23, 0x1f, 0x42, 0x7b, 0x7e, 0xb6, 0x88, 0x5c, 0x9b, 0x78, 0x69, 0xf3, 0x2d,
-	0xae, 0x14, 0xbb, 0x2b, 0x57, 0x8a, 0xc6, 0xa8, 0x20, 0x89, 0x57, 0xd6, 0xa2, 0x67, 0xb0, 0xe9,
-	0xb8, 0x53, 0x37, 0xce, 0xbc, 0x4e, 0x8c, 0x11, 0x77, 0x56, 0x5f, 0x67, 0xe6, 0x05, 0x71, 0x71,
-	0x25, 0x7a, 0x0f, 0x6a, 0x22, 0x60, 0xe4, 0x1d, 0x63, 0xe7, 0x92, 0x2d, 0x71, 0x3e, 0x96, 0x72,
-	0xe8, 0xeb, 0xb0, 0x59, 0x88, 0x15, 0xd9, 0x18, 0xac, 0x06, 0x55, 0x51, 0x50, 0x9f, 0x40, 0xbb,
-	0x78, 0x40, 0x9e, 0xc4, 0x59, 0xaa, 0xa7, 0xa1, 0x32, 0x83, 0x24, 0x59, 0xbe, 0x88, 0x43, 0x62,
-	0xbf, 0x70, 0xfd, 0xe9, 0x61, 0x32, 0x3b, 0xa1, 0x2a, 0x1d, 0x17, 0x50, 0xfd, 0xdb, 0xb0, 0x59,
-	0x38, 0x27, 0x6a, 0x43, 0x39, 0x09, 0x3d, 0xf9, 0x42, 0xf6, 0xc8, 0x2a, 0xe9, 0x9c, 0x44, 0xd1,
-	0x67, 0x41, 0xe8, 0xa8, 0xf9, 0x55, 0xd1, 0x6c, 0x0a, 0xaf, 0x89, 0x53, 0xa6, 0x11, 0xa9, 0xbd,
-	0x34, 0x22, 0x59, 0x0b, 0x28, 0xd4, 0xd1, 0xcb, 0x35, 0x1e, 0x79, 0x10, 0x3d, 0x84, 0x76, 0x9a,
-	0x8d, 0x46, 0x34, 0xdc, 0x5d, 0xc4, 0x6a, 0xe8, 0x58, 0xc1, 0xbb, 0x3f, 0xab, 0xc1, 0x66, 0xf1,
-	0x06, 0xf8, 0x6a, 0x0f, 0xfd, 0xf7, 0x53, 0xce, 0x63, 0x00, 0xf1, 0xdf, 0xe3, 0x97, 0x26, 0x9e,
-	0x8c, 0x10, 0x7a, 0x0c, 0x75, 0x61, 0xc8, 0x48, 0xfa, 0xed, 0xcd, 0xe2, 0x0d, 0xb6, 0xb4, 0x3c,
-	0x56, 0x72, 0xfa, 0xef, 0x2a, 0x50, 0x13, 0x18, 0xda, 0x55, 0x6d, 0xa1, 0xb9, 0x4c, 0x55, 0xdd,
-	0x2b, 0x5e, 0x20, 0x7f, 0x98, 0x24, 0xce, 0xac, 0xfa, 0x82, 0x54, 0xf5, 0xe7, 0x32, 0x00, 0xce,
-	0x09, 0x2f, 0x13, 0x90, 0x56, 0x4c, 0x40, 0x5f, 0x78, 0x05, 0x9d, 0x69, 0xb6, 0xcb, 0x97, 0x34,
-	0xdb, 0xf7, 0x60, 0x2d, 0x4d, 0x56, 0xf9, 0x7e, 0x3c, 0x8b, 0x23, 0x03, 0x9a, 0xe2, 0x8d, 0x63,
-	0x77, 0x9a, 0xde, 0xfb, 0x17, 0xe3, 0x63, 0x29, 0x92, 0xcb, 0x8b, 0x6c, 0x49, 0xad, 0x90, 0x17,
-	0x99, 0x4c, 0xce, 0xe8, 0xf5, 0xd7, 0x31, 0x3a, 0x73, 0xa4, 0x73, 0x1a, 0x12, 0xcf, 0xe3, 0xcd,
-	0xc3, 0x3a, 0x56, 0x24, 0xe3, 0x7c, 0x9a, 0x10, 0x8f, 0xf5, 0x97, 0x4d, 0xc1, 0x91, 0x64, 0xf1,
-	0x62, 0x0b, 0x38, 0x37, 0x77, 0xb1, 0x75, 0x17, 0xd6, 0x1d, 0x19, 0x70, 0xe3, 0x39, 0xa5, 0x4e,
-	0x67, 0x8d, 0xcb, 0xe4, 0x41, 0x56, 0xcd, 0xed, 0x24, 0x8a, 0x83, 0x19, 0x0d, 0xe5, 0xcd, 0x45,
-	0xa7, 0xc5, 0xe5, 0x8a, 0x30, 0xeb, 0xad, 0x42, 0x7a, 0xee, 0xd2, 0xcf, 0x3a, 0xeb, 0xa2, 0xbf,
-	0x17, 0x54, 0xf7, 0x8f, 0x1a, 0xd4, 0xe5, 0xb7, 0x8c, 0xbc, 0x0e, 0xb4, 0xd7, 0xd1, 0xc1, 0x36,
-	0x54, 0x6d, 0x8f, 0xb8, 0x33, 0xd5, 0x68, 0x72, 0x62, 0x35, 0x90, 0xcb, 0x97, 0x05, 0xf2, 0x97,
-	0xa1, 0x19, 0x24, 0xf1, 0x3c, 0x70, 0xfd, 0x58, 0xc5, 0x40, 0xd3, 0x18, 0x4a, 0x04, 0x2f, 0x79,
-	0xc8, 0x00, 0x14, 0xd1, 0xd0, 0x25, 0x9e, 0xfb, 0x7d, 0xea, 0xa8, 0xeb, 0x62, 0x6e, 0xff, 0x16,
-	0xbe, 0x84, 0xd3, 0xfd, 0x4d, 0x05, 0xb6, 0x56, 0x3e, 0xd3, 0xfc, 0x07, 0x87, 0xcc, 0x64, 0x8c,
-	0x52, 0x3e, 0x63, 0xb0, 0x01, 0x27, 0x0c, 0xe6, 0x41, 0x44, 0x9d, 0x5d, 0x35, 0x10, 0x65, 0x10,
-	0x3e, 0xbd, 0x2d, 0x3f, 0x21, 0x89, 0xd9, 0x28, 0x83, 0xa0, 0xc7, 0x69, 0xa1, 0x10, 0xde, 0xfc,
-	0xe6, 0xea, 0xe7, 0xa5, 0x42, 0xa5, 0xd0, 0xff, 0x5a, 0x7a, 0xdd, 0xb4, 0x7a, 0x07, 0x6a, 0xbc,
-	0xa6, 0xab, 0xdb, 0xa1, 0x8c, 0x92, 0x25, 0x03, 0xed, 0xc2, 0x9a, 0xf8, 0x5a, 0x96, 0xc4, 0xf3,
-	0x24, 0x96, 0x21, 0x7a, 0xfb, 0xca, 0xcd, 0x18, 0x42, 0x0e, 0x67, 0x17, 0x21, 0x13, 0x5a, 0xf2,
-	0xcb, 0x9d, 0x78, 0x49, 0xe5, 0x15, 0x5f, 0x92, 0x5b, 0x85, 0x3e, 0x80, 0xcd, 0x34, 0x3c, 0xe5,
-	0x8b, 0xaa, 0xaf, 0xf8, 0xa2, 0xe2, 0x42, 0xfd, 0x09, 0xd4, 0xe4, 0x5b, 0xd9, 0x90, 0x2b, 0xda,
-	0x7c, 0x35, 0xe4, 0x72, 0x2a, 0x33, 0x78, 0x94, 0xb2, 0x83, 0x47, 0xf7, 0x03, 0x68, 0x28, 0x1d,
-	0xb1, 0xbe, 0xe5, 0x6c, 0x39, 0x48, 0x8a, 0x4b, 0x94, 0x6d, 0xa8, 0xba, 0xbc, 0x67, 0x12, 0xe3,
-	0xa3, 0x20, 0x96, 0x53, 0x97, 0xbc, 0x03, 0xe3, 0x44, 0xf7, 0x73, 0x0d, 0x6a, 0xe2, 0xeb, 0xdf,
-	0xff, 0xb0, 0xdb, 0x4d, 0xa7, 0xcc, 0xca, 0x72, 0xca, 0xec, 0xfe, 0x5a, 0x83, 0x92, 0x65, 0x32,
-	0xd6, 0x34, 0x71, 0x55, 0x35, 0xe4, 0xcf, 0x2c, 0x73, 0x9e, 0x78, 0x81, 0xfd, 0x82, 0x4f, 0x52,
-	0xe9, 0xcd, 0x78, 0x0e, 0x43, 0xf7, 0xa0, 0x3e, 0x4f, 0x4e, 0x5e, 0xd0, 0x45, 0x24, 0x1d, 0x66,
-	0xcd, 0xb0, 0x4c, 0x63, 0x24, 0x20, 0xac, 0x78, 0x2c, 0x06, 0x4e, 0xd2, 0x3d, 0xf1, 0xff, 0x6f,
-	0xe1, 0x0c, 0xa2, 0x7f, 0x0d, 0xea, 0x72, 0x4d, 0x6e, 0x27, 0x2d, 0xb9, 0x93, 0x0e, 0xd4, 0xa5,
-	0xb0, 0xac, 0x51, 0x8a, 0xec, 0xfe, 0x43, 0x83, 0xe6, 0xb2, 0x97, 0x7d, 0xc4, 0xc6, 0x56, 0xde,
-	0x56, 0xcb, 0x89, 0x14, 0x2d, 0x3f, 0xa7, 0x1a, 0x63, 0xc1, 0xc1, 0x4a, 0x84, 0x75, 0x41, 0x69,
-	0xa9, 0x63, 0x9d, 0x42, 0x24, 0x5f, 0x5e, 0x40, 0x99, 0xf1, 0xea, 0x72, 0x31, 0x5a, 0x83, 0xfa,
-	0x81, 0x35, 0x9e, 0x58, 0x87, 0x4f, 0xdb, 0xd7, 0x50, 0x13, 0xaa, 0x43, 0x6c, 0x0e, 0x70, 0x5b,
-	0x43, 0x37, 0x00, 0xf1, 0xc7, 0xe3, 0xfe, 0xf0, 0x70, 0xcf, 0xc2, 0xcf, 0x7b, 0xfc, 0x0b, 0x53,
-	0x09, 0x5d, 0x87, 0x2d, 0x81, 0xef, 0x1d, 0x1d, 0xec, 0x59, 0x07, 0x07, 0xcf, 0x07, 0x87, 0x93,
-	0x76, 0x19, 0x6d, 0x43, 0x5b, 0x89, 0x3f, 0x1f, 0x1d, 0x0c, 0xb8, 0x70, 0x85, 0xbd, 0xdc, 0xb4,
-	0xc6, 0xa3, 0xa3, 0xc9, 0xa0, 0x5d, 0x65, 0x6f, 0x94, 0xc4, 0x31, 0x1e, 0x8c, 0x87, 0x07, 0x47,
-	0x5c, 0xa8, 0xc6, 0x06, 0x4e, 0x3c, 0xe0, 0xdf, 0xb9, 0xea, 0xbb, 0x95, 0x4f, 0x4a, 0xf3, 0x93,
-	0x93, 0x1a, 0x77, 0x8e, 0xaf, 0xfe, 0x2b, 0x00, 0x00, 0xff, 0xff, 0xa9, 0xd8, 0x3b, 0xd6, 0x6c,
-	0x20, 0x00, 0x00,
+	// 3117 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x59, 0xcb, 0x6f, 0x1c, 0x49,
+	0x19, 0x4f, 0xcf, 0x7b, 0x3e, 0xbf, 0xc6, 0xb5, 0x4e, 0x32, 0x3b, 0xc0, 0xc6, 0x19, 0x25, 0x21,
+	0x64, 0xb3, 0xbd, 0x1b, 0x73, 0x20, 0x02, 0x84, 0x76, 0x3c, 0x3d, 0x8e, 0x7b, 0xe3, 0xd8, 0xb3,
+	0x35, 0x63, 0x96, 0xdd, 0x8b, 0xd5, 0xee, 0x2e, 0x8f, 0x9b, 0xf4, 0x74, 0xcf, 0xf6, 0xc3, 0x6b,
+	0xef, 0x8d, 0x1b, 0xe2, 0xc2, 0x05, 0xb4, 0xfc, 0x09, 0xdc, 0xb9, 0x22, 0x21, 0x71, 0x41, 0xe2,
+	0xc8, 0x89, 0x03, 0x5c, 0x90, 0xb8, 0x71, 0x43, 0xe2, 0xb2, 0x12, 0xa0, 0xaf, 0x1e, 0x3d, 0xdd,
+	0x3d, 0x76, 0x1e, 0x20, 0xc4, 0xad, 0xbe, 0xdf, 0xf7, 0x55, 0x75, 0x55, 0x7d, 0xef, 0x6a, 0x58,
+	0xb3, 0x03, 0x3f, 0x0e, 0x2d, 0x3b, 0x8e, 0xf4, 0x59, 0x18, 0xc4, 0x41, 0x87, 0xd8, 0x41, 0xe2,
+	0xc7, 0xe1, 0x85, 0x1d, 0x38, 0x4c, 0x61, 0xb7, 0x26, 0x41, 0x30, 0xf1, 0xd8, 0xbb, 0x9c, 0x3a,
+	0x4e, 0x4e, 0xde, 0x8d, 0xdd, 0x29, 0x8b, 0x62, 0x6b, 0x3a, 0x13, 0x02, 0xdd, 0x7f, 0x95, 0x61,
+	0x9d, 0xba, 0xb6, 0x15, 0x3a, 0xae, 0xe5, 0xf7, 0xe5, 0x8a, 0xe4, 0x3d, 0x58, 0x3d, 0x63, 0xbe,
+	0x13, 0x84, 0x7b, 0x6e, 0x14, 0xbb, 0xfe, 0x24, 0x6a, 0x6b, 0x9b, 0xe5, 0xfb, 0x4b, 0x5b, 0x0d,
+	0x5d, 0x02, 0xb4, 0xc0, 0x27, 0xf7, 0x00, 0x8e, 0x93, 0x0b, 0x16, 0x1e, 0x84, 0x0e, 0x0b, 0xdb,
+	0xa5, 0x4d, 0xed, 0xfe, 0xd2, 0x56, 0x4d, 0xe7, 0x14, 0xcd, 0x70, 0xc8, 0x1e, 0xdc, 0x14, 0x33,
+	0x39, 0xd9, 0x0f, 0xfc, 0x13, 0x37, 0x9c, 0x5a, 0xb1, 0x1b, 0xf8, 0xed, 0x32, 0x9f, 0x44, 0xf4,
+	0x05, 0x0e, 0xbd, 0x6a, 0x0a, 0x31, 0xe1, 0x46, 0x86, 0xb5, 0x93, 0x78, 0x27, 0xae, 0xe7, 0x4d,
+	0x99, 0x1f, 0xb7, 0x2b, 0x7c, 0xbf, 0xeb, 0x7a, 0x91, 0x41, 0xaf, 0x98, 0x40, 0x0c, 0xd8, 0x98,
+	0x6f, 0xb3, 0x1f, 0x4c, 0x67, 0x1e, 0xe3, 0xbb, 0xaa, 0xf2, 0x5d, 0xb5, 0xf4, 0x02, 0x4e, 0x2f,
+	0x95, 0x26, 0x5d, 0xa8, 0x3b, 0x6e, 0x34, 0x4b, 0x62, 0xd6, 0xae, 0xf1, 0x89, 0x0d, 0xdd, 0x10,
+	0x34, 0x55, 0x0c, 0xf2, 0x3e, 0xac, 0xcb, 0x21, 0x65, 0x51, 0xe0, 0x25, 0xfc, 0x33, 0x75, 0x79,
+	0x78, 0xa3, 0xc8, 0xa1, 0x8b, 0xc2, 0xe4, 0x16, 0xd4, 0x42, 0x76, 0x92, 0xf8, 0x4e, 0xbb, 0xc1,
+	0xa7, 0xd5, 0x75, 0xca, 0x49, 0x2a, 0x61, 0xf2, 0x00, 0x20, 0x72, 0x27, 0xbe, 0x15, 0x27, 0x21,
+	0x8b, 0xda, 0x4d, 0x7e, 0x17, 0xa0, 0x8f, 0x14, 0x44, 0x33, 0xdc, 0xee, 0x97, 0xd7, 0xa1, 0x2e,
+	0xd5, 0x48, 0x08, 0x54, 0x22, 0x2f, 0x99, 0xb4, 0xb5, 0x4d, 0xed, 0x7e, 0x93, 0xf2, 0x31, 0xb9,
+	0x05, 0x0d, 0x71, 0x65, 0xa6, 0x21, 0xf5, 0x5a, 0xd6, 0x4d, 0x83, 0xa6, 0x20, 0x79, 0x07, 0x1a,
+	0x53, 0x16, 0x5b, 0x8e, 0x15, 0x5b, 0x52, 0x87, 0xeb, 0xca, 0x4c, 0xf4, 0x67, 0x92, 0x41, 0x53,
+	0x11, 0x72, 0x1b, 0x2a, 0x6e, 0xcc, 0xa6, 0xed, 0x0a, 0x17, 0x5d, 0x49, 0x45, 0xcd, 0x98, 0x4d,
+	0x29, 0x67, 0x91, 0x1e, 0xac, 0x45, 0xa7, 0xee, 0x6c, 0xe6, 0xfa, 0x93, 0x83, 0x19, 0x9e, 0x38,
+	0x6a, 0x57, 0xf9, 0x19, 0x6e, 0xa6, 0xd2, 0xa3, 0x1c, 0x9f, 0x16, 0xe5, 0x49, 0x17, 0xaa, 0xb1,
+	0x75, 0xce, 0xa2, 0x76, 0x8d, 0x4f, 0x5c, 0x4e, 0x27, 0x8e, 0xad, 0x73, 0x2a, 0x58, 0xe4, 0x1b,
+	0x50, 0xb7, 0x83, 0x64, 0x86, 0xcb, 0xd7, 0xb9, 0xd4, 0x5a, 0x2a, 0xd5, 0xe7, 0x38, 0x55, 0x7c,
+	0xf2, 0x16, 0xc0, 0x34, 0x70, 0x58, 0x68, 0xc5, 0x41, 0x18, 0xb5, 0x1b, 0x9b, 0xe5, 0xfb, 0x4d,
+	0x9a, 0x41, 0x88, 0x0e, 0x24, 0x66, 0xe1, 0x34, 0xea, 0xf9, 0x4e, 0x3f, 0xf0, 0x1d, 0x57, 0x6c,
+	0xba, 0xc9, 0xaf, 0xf1, 0x12, 0x0e, 0xe9, 0xc2, 0xb2, 0x50, 0xd5, 0x30, 0xf0, 0x5c, 0xfb, 0xa2,
+	0x0d, 0x5c, 0x32, 0x87, 0x75, 0xfe, 0x54, 0x86, 0x86, 0xba, 0x3f, 0xd2, 0x86, 0xfa, 0x19, 0x0b,
+	0x23, 0x34, 0x15, 0x54, 0xce, 0x0a, 0x55, 0x24, 0xd9, 0x86, 0x65, 0x15, 0x09, 0xc6, 0x17, 0x33,
+	0xc6, 0x75, 0xb4, 0xba, 0xf5, 0xd6, 0x82, 0x0a, 0xf4, 0x7e, 0x46, 0x8a, 0xe6, 0xe6, 0x90, 0xf7,
+	0xa0, 0x76, 0x12, 0xa0, 0x53, 0x71, 0x05, 0xae, 0x6e, 0xb5, 0x17, 0x67, 0xef, 0x70, 0x3e, 0x95,
+	0x72, 0x64, 0x0b, 0x6a, 0xec, 0x7c, 0xe6, 0x86, 0x17, 0x52, 0x8f, 0x1d, 0x5d, 0x44, 0x1a, 0x5d,
+	0x45, 0x1a, 0x7d, 0xac, 0x22, 0x0d, 0x95, 0x92, 0xe4, 0x01, 0xb4, 0x2c, 0xdb, 0x66, 0xb3, 0x98,
+	0x39, 0xfd, 0x24, 0x0c, 0x99, 0x6f, 0x5f, 0x70, 0xf7, 0x6a, 0xd2, 0x05, 0x9c, 0xdc, 0x87, 0xb5,
+	0x59, 0xe8, 0xda, 0xae, 0x3f, 0x49, 0x45, 0x6b, 0x5c, 0xb4, 0x08, 0x93, 0x0e, 0x34, 0x3c, 0xcb,
+	0x9f, 0x24, 0xd6, 0x84, 0x71, 0x2f, 0x6a, 0xd2, 0x94, 0x26, 0x9b, 0xb0, 0x84, 0x17, 0xea, 0xfa,
+	0x93, 0x27, 0x81, 0xe5, 0x71, 0x6f, 0xa9, 0xd0, 0x2c, 0xd4, 0x1d, 0xc2, 0x72, 0xf6, 0x5e, 0xc8,
+	0x3a, 0xac, 0x0c, 0x77, 0x3f, 0x1e, 0x99, 0xfd, 0xde, 0xde, 0xd1, 0x93, 0x83, 0x03, 0xa3, 0x75,
+	0x8d, 0xb4, 0x60, 0xd9, 0x30, 0x9f, 0x98, 0x63, 0x85, 0x68, 0x64, 0x09, 0xea, 0xa3, 0x01, 0xfd,
+	0xbe, 0xd9, 0x1f, 0xb4, 0x4a, 0x64, 0x15, 0xa0, 0x4f, 0x0f, 0x3e, 0x32, 0x8e, 0x76, 0x0e, 0xf7,
+	0x8d, 0x56, 0xb9, 0x7b, 0x0f, 0x6a, 0xe2, 0xae, 0xc8, 0x1a, 0x2c, 0xed, 0x98, 0x3f, 0x18, 0x18,
+	0x47, 0x43, 0x8a, 0xa2, 0xd7, 0x70, 0x5e, 0xef, 0xb0, 0x3f, 0x36, 0x0f, 0xf6, 0x5b, 0x5a, 0xe7,
+	0x1f, 0x55, 0xa8, 0xa0, 0xcd, 0x93, 0x0d, 0xa8, 0xc6, 0x6e, 0xec, 0x31, 0xe9, 0x75, 0x82, 0xc0,
+	0xad, 0x3b, 0x2c, 0xb2, 0x43, 0x97, 0x1b, 0x34, 0xd7, 0x6a, 0x93, 0x66, 0x21, 0x72, 0x0f, 0x56,
+	0x67, 0x61, 0x60, 0xb3, 0x28, 0x72, 0xfd, 0x09, 0xde, 0x36, 0x57, 0x5e, 0x93, 0x16, 0x50, 0x5c,
+	0x1f, 0xef, 0x8c, 0x71, 0x4d, 0x55, 0xa8, 0x20, 0xd0, 0xd5, 0xfd, 0xe8, 0xe4, 0x33, 0xae, 0x80,
+	0x06, 0xe5, 0x63, 0xc4, 0x62, 0x6b, 0x22, 0x7c, 0xa6, 0x49, 0xf9, 0x98, 0xbc, 0x0d, 0x35, 0x77,
+	0x6a, 0x4d, 0x98, 0xf2, 0x91, 0x37, 0x72, 0x0e, 0xab, 0x9b, 0xc8, 0xa3, 0x52, 0x04, 0xdd, 0xc4,
+	0xb6, 0x62, 0x36, 0x09, 0x42, 0x97, 0xa5, 0x6e, 0x32, 0x47, 0x70, 0x2b, 0x93, 0xd0, 0x9a, 0x0a,
+	0xcf, 0x28, 0x51, 0x41, 0x90, 0xaf, 0x42, 0xd3, 0x56, 0xae, 0x21, 0x3d, 0x61, 0x0e, 0x10, 0x1d,
+	0xea, 0x81, 0x0c, 0x02, 0x4b, 0x7c, 0x07, 0x1b, 0xf9, 0x1d, 0xc8, 0x08, 0xa0, 0x84, 0xc8, 0x5d,
+	0xa8, 0x44, 0xcf, 0x93, 0xa8, 0xbd, 0x2c, 0x33, 0x40, 0x4e, 0x78, 0xf4, 0x3c, 0xa1, 0x9c, 0xdd,
+	0xf9, 0x04, 0x6a, 0x62, 0x26, 0xbf, 0x09, 0x6b, 0xaa, 0xae, 0x9f, 0x8f, 0x5f, 0xe1, 0xf6, 0x3b,
+	0xd0, 0x38, 0xb3, 0x42, 0xd7, 0xf2, 0xe3, 0xa8, 0x5d, 0xe6, 0x07, 0x4d, 0xe9, 0xce, 0x8f, 0x34,
+	0x28, 0x8f, 0x9e, 0x27, 0xe8, 0xe5, 0x12, 0xeb, 0x07, 0xd3, 0xe3, 0x80, 0x27, 0xd1, 0x15, 0x9a,
+	0xc3, 0xf0, 0xf0, 0xb3, 0x30, 0x70, 0x12, 0x3b, 0x96, 0xf1, 0xb5, 0x49, 0xe7, 0x00, 0x72, 0xa3,
+	0x24, 0xb4, 0x4f, 0xad, 0x70, 0x22, 0xd4, 0x5b, 0xa6, 0x73, 0x00, 0xf7, 0xf0, 0x69, 0x62, 0xf9,
+	0xb1, 0x1b, 0x0b, 0x37, 0x2c, 0xd3, 0x94, 0xee, 0x7c, 0xa1, 0x41, 0x95, 0x2b, 0x07, 0xa5, 0x4e,
+	0x5c, 0x8f, 0x65, 0xce, 0x98, 0xd2, 0xc8, 0x0b, 0x42, 0x77, 0xe2, 0xfa, 0x96, 0x27, 0x3f, 0x9e,
+	0xd2, 0xa8, 0x2c, 0x2f, 0xfd, 0x6e, 0x93, 0x0a, 0x82, 0xdc, 0x80, 0xda, 0x94, 0x39, 0x6e, 0x22,
+	0x02, 0x78, 0x93, 0x4a, 0x0a, 0xa5, 0xa3, 0xa9, 0xe5, 0x79, 0xd2, 0xa3, 0x05, 0xc1, 0x2d, 0xca,
+	0xf5, 0x95, 0xef, 0xf2, 0x71, 0xe7, 0x57, 0x35, 0x58, 0xcd, 0x87, 0xef, 0x4b, 0x55, 0xf0, 0x18,
+	0x2a, 0xf1, 0x3c, 0x9e, 0xdd, 0xb9, 0x22, 0xf2, 0xa7, 0x24, 0x8f, 0x6a, 0x7c, 0x06, 0xb9, 0x07,
+	0xf5, 0x90, 0x4d, 0xb8, 0xc5, 0xa0, 0x66, 0x56, 0xb7, 0x96, 0xf5, 0xbe, 0x28, 0x8d, 0xfa, 0x81,
+	0xc3, 0xa8, 0x62, 0x92, 0xa7, 0xb0, 0xa2, 0xd2, 0x06, 0x4d, 0x3c, 0x16, 0xc9, 0x50, 0x76, 0xf7,
+	0x65, 0x9f, 0xe2, 0xc2, 0x34, 0x3f, 0x97, 0x7c, 0x07, 0x1a, 0x11, 0x0b, 0xcf, 0x5c, 0x9b, 0xa9,
+	0x64, 0x75, 0xeb, 0xca, 0x75, 0x84, 0x1c, 0x4d, 0x27, 0x74, 0x2c, 0xa8, 0x4b, 0xf0, 0xd2, 0xab,
+	0x48, 0x3d, 0xb8, 0x94, 0xf5, 0xe0, 0x87, 0xb0, 0xce, 0xa2, 0xd8, 0x9d, 0x5a, 0x31, 0x73, 0x0c,
+	0xe6, 0xb9, 0x67, 0x2c, 0xbc, 0x90, 0xba, 0x5a, 0x64, 0x74, 0x7e, 0x52, 0x86, 0x95, 0xdc, 0x01,
+	0xc8, 0x07, 0xd0, 0x08, 0x13, 0x8f, 0xf1, 0xa4, 0xa1, 0xf1, 0x4b, 0xd6, 0x5f, 0xe9, 0xe4, 0x3a,
+	0x95, 0xb3, 0x68, 0x3a, 0x9f, 0xbc, 0x0f, 0xd5, 0x90, 0x5f, 0x61, 0x89, 0x1f, 0xfd, 0xc1, 0xab,
+	0x2f, 0x44, 0xc5, 0xc4, 0xce, 0x18, 0x2a, 0x48, 0xa2, 0x45, 0x4e, 0x5d, 0x9f, 0x5a, 0xfe, 0x84,
+	0xc9, 0x4c, 0x97, 0xd2, 0x9c, 0x67, 0x9d, 0x0b, 0x5e, 0x49, 0xf2, 0x24, 0x3d, 0xbf, 0xa3, 0x72,
+	0xe6, 0x8e, 0xba, 0x3f, 0xd3, 0xa0, 0xa1, 0xb6, 0x4b, 0xae, 0xc3, 0xfa, 0x87, 0x87, 0xbd, 0xfd,
+	0xb1, 0x39, 0xfe, 0xf8, 0xc8, 0x30, 0x47, 0xfd, 0x83, 0xc3, 0xfd, 0x71, 0xeb, 0x1a, 0xf9, 0x0a,
+	0xdc, 0xdc, 0xd9, 0xeb, 0x8d, 0x8f, 0x76, 0x06, 0x83, 0xa3, 0x94, 0x4f, 0x7b, 0xfb, 0x4f, 0x06,
+	0x2d, 0x8d, 0xbc, 0x09, 0xd7, 0x53, 0xe6, 0x47, 0x03, 0xf3, 0xc9, 0xee, 0x58, 0xb2, 0x4a, 0xc8,
+	0xea, 0x1f, 0x3c, 0xdb, 0x36, 0xf7, 0x07, 0xc6, 0xd1, 0x68, 0xd7, 0x1c, 0x0e, 0xcd, 0xfd, 0x27,
+	0x47, 0x3d, 0xc3, 0x68, 0x95, 0xc9, 0x5b, 0xd0, 0x59, 0x64, 0x8d, 0x0e, 0xb7, 0xc7, 0xb4, 0xd7,
+	0x1f, 0xb7, 0x2a, 0xdd, 0x47, 0xb0, 0x9c, 0xb5, 0x5b, 0x4c, 0x31, 0x7b, 0x07, 0x98, 0x72, 0x86,
+	0x66, 0xff, 0xe9, 0xe1, 0xb0, 0x75, 0xad, 0x98, 0x3b, 0xb4, 0xce, 0x4f, 0x35, 0x28, 0x8f, 0xad,
+	0x73, 0x2c, 0x04, 0x62, 0xeb, 0x3c, 0x55, 0x5a, 0x93, 0x2a, 0x92, 0x3c, 0x04, 0x88, 0xad, 0x73,
+	0x2a, 0x2d, 0xbf, 0x74, 0x89, 0xe5, 0x67, 0xf8, 0x18, 0xe1, 0x62, 0xeb, 0x5c, 0xed, 0x82, 0xdf,
+	0x5a, 0x83, 0x66, 0x21, 0x0c, 0xe6, 0x33, 0x16, 0xda, 0xcc, 0x8f, 0x31, 0xb5, 0x56, 0x78, 0xc4,
+	0xce, 0x20, 0x9d, 0xdf, 0x6a, 0x50, 0x13, 0x75, 0xd2, 0x15, 0x29, 0x6c, 0x03, 0x2a, 0xa7, 0x56,
+	0x74, 0x2a, 0x02, 0xcb, 0xee, 0x35, 0xca, 0x29, 0x72, 0x07, 0x96, 0x1d, 0x37, 0xe2, 0xbd, 0x0a,
+	0x6e, 0x4a, 0x58, 0xec, 0xee, 0x35, 0x9a, 0x43, 0xc9, 0x03, 0x58, 0x93, 0x9f, 0x32, 0x24, 0xcc,
+	0x03, 0x4b, 0x69, 0x57, 0xa3, 0x45, 0x06, 0xb9, 0x07, 0x2b, 0x5c, 0xdb, 0xa9, 0x24, 0x46, 0x9b,
+	0xca, 0xae, 0x46, 0xf3, 0xf0, 0x76, 0x0d, 0x2a, 0xd8, 0x1b, 0x6d, 0x03, 0x34, 0xd4, 0xb7, 0xba,
+	0x7f, 0x6f, 0x42, 0x55, 0x74, 0x26, 0x77, 0x60, 0x45, 0x94, 0x5f, 0x3d, 0xc7, 0x09, 0x59, 0x14,
+	0xc9, 0xb3, 0xe4, 0x41, 0x0c, 0xc8, 0x02, 0xd8, 0x61, 0xca, 0x1d, 0xe7, 0x00, 0x79, 0x1b, 0x1a,
+	0x51, 0xf6, 0x46, 0xb1, 0xa4, 0xe4, 0xab, 0xcf, 0x0d, 0x3f, 0x15, 0x20, 0x5f, 0x83, 0x3a, 0xef,
+	0x21, 0x4c, 0xa3, 0x5d, 0x99, 0xd7, 0xd5, 0x0a, 0x23, 0x8f, 0xa1, 0x99, 0x36, 0x6b, 0xed, 0xea,
+	0x4b, 0x8b, 0xac, 0xb9, 0x30, 0xb9, 0x0d, 0x55, 0x2c, 0xa3, 0x55, 0xed, 0xbb, 0x24, 0xb7, 0xc0,
+	0x0b, 0x6c, 0xc1, 0x21, 0xf7, 0xa1, 0x3e, 0xb3, 0x2e, 0x78, 0xa7, 0x24, 0x3a, 0x8f, 0x55, 0x29,
+	0x34, 0x14, 0x28, 0x55, 0x6c, 0xb4, 0x82, 0xd0, 0x42, 0x57, 0x7e, 0xca, 0x2e, 0x44, 0x4a, 0x5f,
+	0xa6, 0x19, 0x84, 0x6c, 0xc1, 0x86, 0xe5, 0xc5, 0x2c, 0xf4, 0xad, 0x98, 0x61, 0x25, 0x65, 0xd9,
+	0xb1, 0xe9, 0x9f, 0x04, 0xb2, 0xf6, 0xbd, 0x94, 0xd7, 0xf9, 0x83, 0x06, 0x8d, 0xd4, 0xcc, 0x6e,
+	0x40, 0x0d, 0xaf, 0x64, 0x1c, 0xc8, 0x0b, 0x97, 0x14, 0x1a, 0xba, 0x25, 0x35, 0x21, 0x32, 0x93,
+	0x22, 0x31, 0x44, 0xda, 0x98, 0xf2, 0x44, 0xac, 0xe3, 0x63, 0x9e, 0x7e, 0x62, 0x2b, 0x66, 0x32,
+	0x2b, 0x09, 0x82, 0x9b, 0x70, 0x10, 0xc5, 0x96, 0xc7, 0x2d, 0x4d, 0x64, 0xa6, 0x0c, 0x82, 0x99,
+	0x42, 0x36, 0xcd, 0xdc, 0x66, 0x16, 0x32, 0x85, 0x64, 0x62, 0x22, 0x97, 0x1f, 0xdf, 0x0f, 0x62,
+	0x5e, 0x0a, 0xf1, 0x72, 0x3d, 0x8b, 0x75, 0xfe, 0x59, 0x92, 0xf5, 0xdc, 0x26, 0x2c, 0x79, 0x22,
+	0xfa, 0xed, 0xa2, 0xf5, 0x8b, 0x53, 0x65, 0xa1, 0x5c, 0xde, 0x96, 0x71, 0x4c, 0xd1, 0xe4, 0xe1,
+	0xbc, 0xdc, 0x29, 0x6f, 0x96, 0xe7, 0x0d, 0xf1, 0xe5, 0xc5, 0xce, 0x36, 0xac, 0xe6, 0x3b, 0x9f,
+	0xb4, 0x1c, 0xcf, 0x4c, 0x2a, 0xf4, 0x4a, 0x85, 0x19, 0x78, 0x9d, 0x53, 0x36, 0x0d, 0xe4, 0xf5,
+	0xf0, 0x31, 0x9e, 0x41, 0xb4, 0x3e, 0x78, 0x0f, 0xaa, 0x20, 0xcc, 0x42, 0xe8, 0x08, 0xc7, 0xae,
+	0xd3, 0x9b, 0x72, 0x87, 0xab, 0x0b, 0x47, 0x48, 0x81, 0xce, 0xd6, 0x0b, 0xab, 0xab, 0x0d, 0xa8,
+	0x9e, 0x59, 0x5e, 0xc2, 0xa4, 0x62, 0x05, 0xd1, 0xf9, 0xde, 0x2b, 0x95, 0x05, 0x6d, 0xa8, 0xcb,
+	0xb4, 0xa9, 0xcc, 0x42, 0x92, 0x9d, 0x5f, 0x96, 0xa0, 0x2e, 0xcd, 0x97, 0xbc, 0x83, 0x55, 0x4a,
+	0x7c, 0x1a, 0x38, 0x32, 0xb3, 0x5d, 0xcf, 0x9b, 0x37, 0xb6, 0x35, 0xa7, 0x81, 0x43, 0xa5, 0x10,
+	0x1e, 0x26, 0x6d, 0xe6, 0x54, 0x11, 0x96, 0x02, 0x68, 0xa1, 0x96, 0x38, 0xa7, 0xc8, 0x2d, 0x92,
+	0x42, 0xab, 0x60, 0xe7, 0xf6, 0x29, 0xa6, 0x1f, 0xaa, 0x4c, 0xaf, 0x42, 0x73, 0x18, 0xaf, 0x6d,
+	0x4f, 0x2d, 0xd7, 0xc7, 0xc0, 0x23, 0xab, 0xa0, 0x39, 0x90, 0xb5, 0xf1, 0x7a, 0xde, 0xc6, 0x79,
+	0x83, 0xe8, 0x30, 0x36, 0x1d, 0xf1, 0x8a, 0xb3, 0xdd, 0x50, 0x0d, 0xe2, 0x1c, 0xeb, 0x3e, 0x86,
+	0x9a, 0x38, 0x07, 0x79, 0x03, 0xd6, 0x7a, 0x86, 0x41, 0x07, 0xa3, 0xd1, 0x11, 0x1d, 0x7c, 0x78,
+	0x38, 0x18, 0x61, 0x5e, 0x03, 0xa8, 0x19, 0x26, 0x1d, 0xf4, 0xc7, 0x2d, 0x8d, 0xac, 0x40, 0xf3,
+	0xd9, 0x81, 0x31, 0xa0, 0xbd, 0xf1, 0xc0, 0x68, 0x95, 0xba, 0x3f, 0x2f, 0xc1, 0xfa, 0xe2, 0x6b,
+	0x4a, 0x1b, 0xea, 0x01, 0x82, 0xa6, 0xa1, 0x52, 0x8b, 0x24, 0xf3, 0xb1, 0xa8, 0xf4, 0x3a, 0xb1,
+	0x08, 0x9b, 0x14, 0x71, 0xe7, 0x2a, 0xac, 0xaa, 0x26, 0x25, 0x87, 0x62, 0xbf, 0x17, 0xb2, 0x4f,
+	0x13, 0x16, 0xc5, 0x4c, 0x19, 0x95, 0xb8, 0xce, 0x22, 0xcc, 0x0b, 0x66, 0xeb, 0x22, 0x48, 0x62,
+	0x8c, 0xc0, 0x55, 0x61, 0x78, 0x29, 0x40, 0xbe, 0x0b, 0x2d, 0x11, 0x9c, 0x46, 0xf3, 0xf7, 0x0f,
+	0x11, 0x06, 0x5b, 0x3a, 0xcd, 0x33, 0xe8, 0x82, 0x64, 0xf7, 0xc7, 0x1a, 0x2c, 0x89, 0x37, 0x2b,
+	0xf6, 0x43, 0x66, 0xc7, 0xff, 0x93, 0x1b, 0xc1, 0xfe, 0xc4, 0x9d, 0x28, 0xef, 0x5e, 0xd7, 0xb7,
+	0xdd, 0xd8, 0x0e, 0x5c, 0x7f, 0xbe, 0x2d, 0xce, 0xee, 0xfe, 0x4d, 0x83, 0xb5, 0xc2, 0x86, 0xc9,
+	0xfb, 0x99, 0x97, 0x16, 0x8d, 0x7f, 0xf3, 0x4e, 0xf1, 0x50, 0xfa, 0x38, 0xb4, 0xfc, 0xc8, 0xb2,
+	0x51, 0xa1, 0x97, 0x3c, 0xbe, 0x60, 0x3f, 0xa1, 0x44, 0xf9, 0xb6, 0x97, 0xe9, 0x1c, 0xe8, 0x5c,
+	0xc0, 0x1b, 0x97, 0x4c, 0xcf, 0x04, 0xb4, 0xd1, 0xfc, 0x71, 0x28, 0x0b, 0xf1, 0xac, 0xa8, 0x52,
+	0x82, 0x5a, 0x36, 0x05, 0xd0, 0x96, 0x53, 0x67, 0x42, 0x81, 0x32, 0x17, 0xc8, 0x61, 0xdd, 0x21,
+	0xb4, 0x8a, 0x17, 0x81, 0xd1, 0xdb, 0xf5, 0x67, 0x49, 0x6c, 0xfa, 0x0e, 0x3b, 0x97, 0xc5, 0x60,
+	0x06, 0x79, 0xf1, 0x61, 0xba, 0xbf, 0xab, 0x41, 0x6b, 0xe1, 0x95, 0x2f, 0x55, 0xa8, 0x93, 0x57,
+	0xa8, 0x93, 0x3e, 0x7d, 0x95, 0x32, 0x4f, 0x5f, 0x39, 0x25, 0x97, 0x5f, 0x47, 0xc9, 0xfb, 0xd0,
+	0x9a, 0x9d, 0x5e, 0x44, 0xae, 0x6d, 0x79, 0x69, 0x69, 0x2e, 0x9e, 0x24, 0xbb, 0x0b, 0x4f, 0x92,
+	0xfa, 0xb0, 0x20, 0x49, 0x17, 0xe6, 0x92, 0xa7, 0xb0, 0xe6, 0xb8, 0x13, 0x37, 0xce, 0x2c, 0x27,
+	0x9a, 0x8c, 0xdb, 0x8b, 0xcb, 0x19, 0x79, 0x41, 0x5a, 0x9c, 0x89, 0xaf, 0x3d, 0xc2, 0x61, 0xe4,
+	0x1b, 0x65, 0xfb, 0x92, 0x2d, 0x71, 0x3e, 0x95, 0x72, 0xe4, 0xdb, 0xb0, 0x56, 0xf0, 0x15, 0x59,
+	0x36, 0x2c, 0x3a, 0x55, 0x51, 0xb0, 0x33, 0x86, 0x56, 0xf1, 0x80, 0x3c, 0x88, 0x63, 0xa8, 0x67,
+	0xa1, 0x52, 0x83, 0x24, 0x31, 0x5e, 0xe0, 0x63, 0xcc, 0x73, 0xd7, 0x9f, 0xec, 0x27, 0xd3, 0x63,
+	0xa6, 0xc2, 0x71, 0x01, 0xed, 0xfc, 0x55, 0x83, 0xb5, 0xc2, 0x41, 0x49, 0x0b, 0xca, 0x49, 0xe8,
+	0xc9, 0x15, 0x71, 0x88, 0x89, 0x76, 0x66, 0x45, 0xd1, 0x67, 0x41, 0xe8, 0xa8, 0xf6, 0x56, 0xd1,
+	0xa8, 0x70, 0x5e, 0x9d, 0xca, 0x2a, 0x02, 0xc7, 0x3c, 0xa2, 0xfb, 0x76, 0x78, 0x31, 0x8b, 0x99,
+	0x83, 0x96, 0x5a, 0x11, 0x96, 0x9a, 0xc5, 0x72, 0xed, 0x74, 0xb5, 0xd0, 0x4e, 0x63, 0x1e, 0x61,
+	0x8e, 0x6b, 0xf1, 0xf2, 0x5c, 0x46, 0xfb, 0x14, 0xe0, 0x26, 0xe6, 0x7e, 0xce, 0x64, 0xb6, 0xe4,
+	0x63, 0xb4, 0x71, 0xcf, 0xb5, 0x99, 0x1f, 0x31, 0xfc, 0x5e, 0x83, 0x7f, 0x2f, 0x83, 0xe0, 0x53,
+	0x42, 0x4d, 0x28, 0x23, 0x0d, 0x1c, 0xda, 0x0b, 0x03, 0x07, 0xd6, 0xb1, 0x42, 0x6b, 0xbd, 0x5c,
+	0xf5, 0x94, 0x07, 0xf1, 0x2d, 0x2e, 0x0d, 0x9a, 0x43, 0x16, 0x6e, 0x5f, 0xc4, 0xaa, 0x73, 0x5a,
+	0xc0, 0xbb, 0xbf, 0xa8, 0xc1, 0x5a, 0xf1, 0xa1, 0xfb, 0x6a, 0x47, 0xfa, 0xcf, 0x23, 0xe3, 0x23,
+	0x00, 0xf1, 0xed, 0xd1, 0x0b, 0xe3, 0x63, 0x46, 0x88, 0x3c, 0x82, 0xba, 0xb0, 0xb7, 0x48, 0xba,
+	0xd7, 0xcd, 0xe2, 0x43, 0xbd, 0x34, 0x50, 0xaa, 0xe4, 0x3a, 0xbf, 0xaf, 0x40, 0x4d, 0x60, 0x64,
+	0x5b, 0xd5, 0xb6, 0xc6, 0x3c, 0xa2, 0x76, 0xaf, 0x58, 0x40, 0xa7, 0xa9, 0x24, 0xcd, 0xcc, 0x7a,
+	0x49, 0x44, 0xfd, 0x73, 0x19, 0x80, 0xe6, 0x84, 0xe7, 0x71, 0x52, 0x2b, 0xc6, 0xc9, 0x97, 0xbe,
+	0xb4, 0x67, 0x3a, 0x86, 0xf2, 0x25, 0x1d, 0xc3, 0x5d, 0x58, 0x4a, 0x63, 0x6a, 0xbe, 0xa9, 0xc8,
+	0xe2, 0x44, 0x87, 0xa6, 0x58, 0x71, 0xe4, 0x4e, 0xd2, 0xdf, 0x1b, 0x45, 0x37, 0x9e, 0x8b, 0xe4,
+	0xc2, 0x37, 0x4e, 0xa9, 0x15, 0xc2, 0x37, 0xca, 0xe4, 0x94, 0x5e, 0x7f, 0x1d, 0xa5, 0xa3, 0x21,
+	0x9d, 0xb1, 0x10, 0x5f, 0x8e, 0x1a, 0xe2, 0x61, 0x5b, 0x92, 0xc8, 0xf9, 0x34, 0xb1, 0x3c, 0x2c,
+	0x92, 0x9b, 0x82, 0x23, 0xc9, 0xe2, 0xeb, 0x1c, 0x70, 0x6e, 0x16, 0x42, 0x27, 0x70, 0x64, 0x58,
+	0x18, 0xcd, 0x18, 0x73, 0xda, 0x4b, 0x5c, 0x26, 0x0f, 0x62, 0xd1, 0x61, 0x27, 0x51, 0x1c, 0x4c,
+	0x59, 0x28, 0x9f, 0x5f, 0xda, 0xcb, 0x5c, 0xae, 0x08, 0x63, 0x09, 0x18, 0xb2, 0x33, 0x97, 0x7d,
+	0xd6, 0x5e, 0x11, 0x4d, 0x8a, 0xa0, 0xba, 0x7f, 0xd4, 0xa0, 0x2e, 0x7f, 0xd9, 0xe4, 0xef, 0x40,
+	0x7b, 0x9d, 0x3b, 0xd8, 0x80, 0xaa, 0xed, 0x59, 0xee, 0x54, 0xd5, 0xc3, 0x9c, 0x58, 0x74, 0xe4,
+	0xf2, 0x65, 0x8e, 0xfc, 0x75, 0x68, 0x06, 0x49, 0x3c, 0x0b, 0x5c, 0x3f, 0x56, 0x3e, 0xd0, 0xd4,
+	0x0f, 0x24, 0x42, 0xe7, 0x3c, 0xfc, 0x45, 0x11, 0xb1, 0xd0, 0xb5, 0x3c, 0xf7, 0x73, 0xe6, 0xa8,
+	0x37, 0x6f, 0xae, 0xff, 0x65, 0x7a, 0x09, 0xa7, 0xfb, 0x9b, 0x0a, 0xac, 0x2f, 0xfc, 0x8d, 0xfa,
+	0x2f, 0x0e, 0x99, 0x89, 0x18, 0xa5, 0x7c, 0xc4, 0xc0, 0x2e, 0x2d, 0x0c, 0x66, 0x41, 0xc4, 0x9c,
+	0x6d, 0xd5, 0xd5, 0x65, 0x10, 0xe4, 0x87, 0xe9, 0x0e, 0x64, 0x83, 0x97, 0x41, 0xc8, 0xa3, 0x34,
+	0x9f, 0x09, 0x6b, 0x7e, 0x73, 0xf1, 0x2f, 0x5a, 0x21, 0xa1, 0x75, 0xfe, 0x52, 0x7a, 0xdd, 0xb0,
+	0x7a, 0x1b, 0x6a, 0xbc, 0xf4, 0x50, 0x4f, 0x5c, 0x99, 0x4b, 0x96, 0x0c, 0xb2, 0x0d, 0x4b, 0xe2,
+	0xa7, 0x60, 0x12, 0xcf, 0x92, 0x58, 0xba, 0xe8, 0xe6, 0x95, 0x9b, 0xd1, 0x85, 0x1c, 0xcd, 0x4e,
+	0x22, 0x06, 0x2c, 0xcb, 0x1f, 0x94, 0x62, 0x91, 0xca, 0x2b, 0x2e, 0x92, 0x9b, 0x45, 0x3e, 0x80,
+	0xb5, 0xd4, 0x3d, 0xe5, 0x42, 0xd5, 0x57, 0x5c, 0xa8, 0x38, 0xb1, 0xf3, 0x18, 0x6a, 0x72, 0x55,
+	0xec, 0xd4, 0x45, 0x37, 0xa2, 0x3a, 0x75, 0x4e, 0x65, 0xfa, 0xa3, 0x52, 0xb6, 0x3f, 0xea, 0x7e,
+	0x00, 0x0d, 0x75, 0x47, 0x69, 0xb6, 0xd5, 0x32, 0xd9, 0x76, 0x03, 0xaa, 0x2e, 0x2f, 0xed, 0x44,
+	0x0f, 0x2c, 0x88, 0x79, 0x73, 0x28, 0x1f, 0xf2, 0x38, 0xd1, 0xfd, 0x42, 0x83, 0x9a, 0xf8, 0xc9,
+	0xf9, 0x7f, 0x2c, 0xca, 0xd3, 0x56, 0xb9, 0x32, 0x6f, 0x95, 0xbb, 0xbf, 0xd6, 0xa0, 0x64, 0x1a,
+	0xc8, 0x9a, 0x24, 0xae, 0xca, 0x86, 0x7c, 0x8c, 0x91, 0xf3, 0xd8, 0x0b, 0xec, 0xe7, 0xbc, 0xe1,
+	0x4b, 0x9f, 0xf7, 0x73, 0x18, 0xb9, 0x0b, 0xf5, 0x59, 0x72, 0xfc, 0x1c, 0x1f, 0x57, 0x84, 0xc1,
+	0x2c, 0xe9, 0xa6, 0xa1, 0x0f, 0x05, 0x44, 0x15, 0x0f, 0x7d, 0xe0, 0x38, 0xdd, 0x93, 0xac, 0x4b,
+	0x32, 0x48, 0xe7, 0x5b, 0x50, 0x97, 0x73, 0x72, 0x3b, 0x59, 0x96, 0x3b, 0x69, 0x43, 0x5d, 0x0a,
+	0xcb, 0x1c, 0xa5, 0xc8, 0xee, 0x97, 0x1a, 0x34, 0xe7, 0x25, 0xf7, 0x43, 0xec, 0xae, 0x79, 0xf5,
+	0x2f, 0x1b, 0x67, 0x32, 0xff, 0x6b, 0xac, 0x8f, 0x04, 0x87, 0x2a, 0x11, 0x2c, 0xd6, 0xd2, 0x54,
+	0x87, 0x95, 0x42, 0x24, 0x17, 0x2f, 0xa0, 0xa8, 0xbc, 0xba, 0x9c, 0x8c, 0xff, 0xc0, 0xf6, 0xcc,
+	0xd1, 0xd8, 0xdc, 0x7f, 0xd2, 0xba, 0x46, 0xf0, 0xf1, 0x8d, 0x1a, 0x03, 0xda, 0xd2, 0xc8, 0x0d,
+	0x20, 0x7c, 0x78, 0xd4, 0x3f, 0xd8, 0xdf, 0x31, 0xe9, 0xb3, 0x1e, 0xff, 0x4d, 0x56, 0xc2, 0x47,
+	0x5b, 0x81, 0xef, 0x1c, 0xee, 0xed, 0x98, 0x7b, 0x7b, 0xcf, 0x06, 0xfb, 0xe3, 0x56, 0x99, 0x6c,
+	0x40, 0x4b, 0x89, 0x3f, 0x1b, 0xee, 0x0d, 0xb8, 0x70, 0x05, 0x17, 0x37, 0xcc, 0xd1, 0xf0, 0x70,
+	0x3c, 0x68, 0x55, 0x71, 0x45, 0x49, 0x1c, 0xd1, 0xc1, 0xe8, 0x60, 0xef, 0x90, 0x0b, 0xd5, 0xb0,
+	0x2f, 0xa6, 0x03, 0xfe, 0xb3, 0xae, 0xbe, 0x5d, 0xf9, 0xa4, 0x34, 0x3b, 0x3e, 0xae, 0x71, 0xe3,
+	0xf8, 0xe6, 0xbf, 0x07, 0x00, 0x50, 0xc7, 0x3e, 0x03, 0x53, 0x21, 0x00, 0x00,
 }
@@ -36,6 +36,9 @@ const (
 	Message_OFFLINE_RELAY      Message_MessageType = 15
 	Message_MODERATOR_ADD      Message_MessageType = 16
 	Message_MODERATOR_REMOVE   Message_MessageType = 17
+	Message_BID                Message_MessageType = 18
+	Message_BID_ACCEPT         Message_MessageType = 19
+	Message_GROUP_CHAT         Message_MessageType = 20
 	Message_ERROR              Message_MessageType = 500
 )
 
@@ -58,6 +61,9 @@ var Message_MessageType_name = map[int32]string{
 	15:  "OFFLINE_RELAY",
 	16:  "MODERATOR_ADD",
 	17:  "MODERATOR_REMOVE",
+	18:  "BID",
+	19:  "BID_ACCEPT",
+	20:  "GROUP_CHAT",
 	500: "ERROR",
 }
 var Message_MessageType_value = map[string]int32{
@@ -79,6 +85,9 @@ var Message_MessageType_value = map[string]int32{
 	"OFFLINE_RELAY":      15,
 	"MODERATOR_ADD":      16,
 	"MODERATOR_REMOVE":   17,
+	"BID":                18,
+	"BID_ACCEPT":         19,
+	"GROUP_CHAT":         20,
 	"ERROR":              500,
 }
 
@@ -184,11 +193,12 @@ func (m *Envelope) GetSignature() []byte {
 }
 
 type Chat struct {
-	MessageId string                     `protobuf:"bytes,1,opt,name=messageId" json:"messageId,omitempty"`
-	Subject   string                     `protobuf:"bytes,2,opt,name=subject" json:"subject,omitempty"`
-	Message   string                     `protobuf:"bytes,3,opt,name=message" json:"message,omitempty"`
-	Timestamp *google_protobuf.Timestamp `protobuf:"bytes,4,opt,name=timestamp" json:"timestamp,omitempty"`
-	Flag      Chat_Flag                  `protobuf:"varint,5,opt,name=flag,enum=Chat_Flag" json:"flag,omitempty"`
+	MessageId   string                     `protobuf:"bytes,1,opt,name=messageId" json:"messageId,omitempty"`
+	Subject     string                     `protobuf:"bytes,2,opt,name=subject" json:"subject,omitempty"`
+	Message     string                     `protobuf:"bytes,3,opt,name=message" json:"message,omitempty"`
+	Timestamp   *google_protobuf.Timestamp `protobuf:"bytes,4,opt,name=timestamp" json:"timestamp,omitempty"`
+	Flag        Chat_Flag                  `protobuf:"varint,5,opt,name=flag,enum=Chat_Flag" json:"flag,omitempty"`
+	Attachments []*Chat_Attachment         `protobuf:"bytes,6,rep,name=attachments" json:"attachments,omitempty"`
 }
 
 func (m *Chat) Reset()                    { *m = Chat{} }
@@ -231,10 +241,215 @@ func (m *Chat) GetFlag() Chat_Flag {
 	return Chat_MESSAGE
 }
 
+func (m *Chat) GetAttachments() []*Chat_Attachment {
+	if m != nil {
+		return m.Attachments
+	}
+	return nil
+}
+
+// A file added to IPFS encrypted with the key. Images also have an encrypted thumbnail.
+type Chat_Attachment struct {
+	Hash      string `protobuf:"bytes,1,opt,name=hash" json:"hash,omitempty"`
+	Key       []byte `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Filename  string `protobuf:"bytes,3,opt,name=filename" json:"filename,omitempty"`
+	MediaType string `protobuf:"bytes,4,opt,name=mediaType" json:"mediaType,omitempty"`
+	Size      uint64 `protobuf:"varint,5,opt,name=size" json:"size,omitempty"`
+	Thumbnail string `protobuf:"bytes,6,opt,name=thumbnail" json:"thumbnail,omitempty"`
+}
+
+func (m *Chat_Attachment) Reset()                    { *m = Chat_Attachment{} }
+func (m *Chat_Attachment) String() string            { return proto.CompactTextString(m) }
+func (*Chat_Attachment) ProtoMessage()               {}
+func (*Chat_Attachment) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{2, 0} }
+
+func (m *Chat_Attachment) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *Chat_Attachment) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *Chat_Attachment) GetFilename() string {
+	if m != nil {
+		return m.Filename
+	}
+	return ""
+}
+
+func (m *Chat_Attachment) GetMediaType() string {
+	if m != nil {
+		return m.MediaType
+	}
+	return ""
+}
+
+func (m *Chat_Attachment) GetSize() uint64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *Chat_Attachment) GetThumbnail() string {
+	if m != nil {
+		return m.Thumbnail
+	}
+	return ""
+}
+
+type GroupChat struct {
+	MessageId string                     `protobuf:"bytes,1,opt,name=messageId" json:"messageId,omitempty"`
+	GroupId   string                     `protobuf:"bytes,2,opt,name=groupId" json:"groupId,omitempty"`
+	Message   string                     `protobuf:"bytes,3,opt,name=message" json:"message,omitempty"`
+	Timestamp *google_protobuf.Timestamp `protobuf:"bytes,4,opt,name=timestamp" json:"timestamp,omitempty"`
+	Members   *SignedGroupMembers        `protobuf:"bytes,5,opt,name=members" json:"members,omitempty"`
+}
+
+func (m *GroupChat) Reset()                    { *m = GroupChat{} }
+func (m *GroupChat) String() string            { return proto.CompactTextString(m) }
+func (*GroupChat) ProtoMessage()               {}
+func (*GroupChat) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{3} }
+
+func (m *GroupChat) GetMessageId() string {
+	if m != nil {
+		return m.MessageId
+	}
+	return ""
+}
+
+func (m *GroupChat) GetGroupId() string {
+	if m != nil {
+		return m.GroupId
+	}
+	return ""
+}
+
+func (m *GroupChat) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *GroupChat) GetTimestamp() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func (m *GroupChat) GetMembers() *SignedGroupMembers {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+// The groupId is the hash of the creator's public key and the nonce
+type GroupMembers struct {
+	GroupId   string                     `protobuf:"bytes,1,opt,name=groupId" json:"groupId,omitempty"`
+	Creator   string                     `protobuf:"bytes,2,opt,name=creator" json:"creator,omitempty"`
+	Subject   string                     `protobuf:"bytes,3,opt,name=subject" json:"subject,omitempty"`
+	Members   []string                   `protobuf:"bytes,4,rep,name=members" json:"members,omitempty"`
+	Timestamp *google_protobuf.Timestamp `protobuf:"bytes,5,opt,name=timestamp" json:"timestamp,omitempty"`
+	Nonce     []byte                     `protobuf:"bytes,6,opt,name=nonce,proto3" json:"nonce,omitempty"`
+}
+
+func (m *GroupMembers) Reset()                    { *m = GroupMembers{} }
+func (m *GroupMembers) String() string            { return proto.CompactTextString(m) }
+func (*GroupMembers) ProtoMessage()               {}
+func (*GroupMembers) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{4} }
+
+func (m *GroupMembers) GetGroupId() string {
+	if m != nil {
+		return m.GroupId
+	}
+	return ""
+}
+
+func (m *GroupMembers) GetCreator() string {
+	if m != nil {
+		return m.Creator
+	}
+	return ""
+}
+
+func (m *GroupMembers) GetSubject() string {
+	if m != nil {
+		return m.Subject
+	}
+	return ""
+}
+
+func (m *GroupMembers) GetMembers() []string {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+func (m *GroupMembers) GetTimestamp() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func (m *GroupMembers) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
+type SignedGroupMembers struct {
+	SerializedMembers []byte `protobuf:"bytes,1,opt,name=serializedMembers,proto3" json:"serializedMembers,omitempty"`
+	CreatorPubkey     []byte `protobuf:"bytes,2,opt,name=creatorPubkey,proto3" json:"creatorPubkey,omitempty"`
+	Signature         []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *SignedGroupMembers) Reset()                    { *m = SignedGroupMembers{} }
+func (m *SignedGroupMembers) String() string            { return proto.CompactTextString(m) }
+func (*SignedGroupMembers) ProtoMessage()               {}
+func (*SignedGroupMembers) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{5} }
+
+func (m *SignedGroupMembers) GetSerializedMembers() []byte {
+	if m != nil {
+		return m.SerializedMembers
+	}
+	return nil
+}
+
+func (m *SignedGroupMembers) GetCreatorPubkey() []byte {
+	if m != nil {
+		return m.CreatorPubkey
+	}
+	return nil
+}
+
+func (m *SignedGroupMembers) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Message)(nil), "Message")
 	proto.RegisterType((*Envelope)(nil), "Envelope")
 	proto.RegisterType((*Chat)(nil), "Chat")
+	proto.RegisterType((*Chat_Attachment)(nil), "Chat.Attachment")
+	proto.RegisterType((*GroupChat)(nil), "GroupChat")
+	proto.RegisterType((*GroupMembers)(nil), "GroupMembers")
+	proto.RegisterType((*SignedGroupMembers)(nil), "SignedGroupMembers")
 	proto.RegisterEnum("Message_MessageType", Message_MessageType_name, Message_MessageType_value)
 	proto.RegisterEnum("Chat_Flag", Chat_Flag_name, Chat_Flag_value)
 }
@@ -242,41 +457,58 @@ func init() {
 func init() { proto.RegisterFile("message.proto", fileDescriptor3) }
 
 var fileDescriptor3 = []byte{
-	// 573 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x92, 0x4f, 0x6f, 0xd3, 0x4c,
-	0x10, 0xc6, 0xeb, 0xc4, 0xf9, 0x37, 0x4e, 0xdb, 0xed, 0xaa, 0x6f, 0x95, 0xb7, 0x42, 0x25, 0xca,
-	0x29, 0x5c, 0x5c, 0x29, 0x48, 0x88, 0xab, 0xb1, 0xd7, 0xc5, 0x60, 0x7b, 0xa3, 0x89, 0x03, 0x2a,
-	0x97, 0xc8, 0x21, 0xdb, 0x50, 0x48, 0x63, 0x53, 0x3b, 0x48, 0xf9, 0x90, 0x7c, 0x04, 0xbe, 0x05,
-	0x77, 0xd0, 0x6e, 0xbc, 0x24, 0x82, 0x93, 0x77, 0x7e, 0xcf, 0xa3, 0x99, 0xb1, 0x9e, 0x81, 0xe3,
-	0x07, 0x51, 0x14, 0xe9, 0x52, 0xd8, 0xf9, 0x63, 0x56, 0x66, 0x97, 0xff, 0x2f, 0xb3, 0x6c, 0xb9,
-	0x12, 0xd7, 0xaa, 0x9a, 0x6f, 0xee, 0xae, 0xd3, 0xf5, 0xb6, 0x92, 0x9e, 0xfe, 0x2d, 0x95, 0xf7,
-	0x0f, 0xa2, 0x28, 0xd3, 0x87, 0x7c, 0x67, 0x18, 0xfc, 0xaa, 0x43, 0x2b, 0xda, 0x75, 0xa3, 0x2f,
-	0xc0, 0xaa, 0x1a, 0x27, 0xdb, 0x5c, 0xf4, 0x8c, 0xbe, 0x31, 0x3c, 0x19, 0x9d, 0xdb, 0x95, 0xac,
-	0xbf, 0x52, 0xc3, 0x43, 0x23, 0xb5, 0xa1, 0x95, 0xa7, 0xdb, 0x55, 0x96, 0x2e, 0x7a, 0xb5, 0xbe,
-	0x31, 0xb4, 0x46, 0xe7, 0xf6, 0x6e, 0xac, 0xad, 0xc7, 0xda, 0xce, 0x7a, 0x8b, 0xda, 0x44, 0x9f,
-	0x40, 0xe7, 0x51, 0x7c, 0xdd, 0x88, 0xa2, 0x0c, 0x16, 0xbd, 0x7a, 0xdf, 0x18, 0x36, 0x70, 0x0f,
-	0xe8, 0x15, 0xc0, 0x7d, 0x81, 0xa2, 0xc8, 0xb3, 0x75, 0x21, 0x7a, 0x66, 0xdf, 0x18, 0xb6, 0xf1,
-	0x80, 0x0c, 0xbe, 0xd7, 0xc0, 0x3a, 0x58, 0x85, 0xb6, 0xc1, 0x1c, 0x07, 0xf1, 0x0d, 0x39, 0x92,
-	0x2f, 0xf7, 0xb5, 0x93, 0x10, 0x83, 0x02, 0x34, 0x7d, 0x1e, 0x86, 0xfc, 0x3d, 0xa9, 0xd1, 0x2e,
-	0xb4, 0xa7, 0x71, 0x55, 0xd5, 0x69, 0x07, 0x1a, 0x1c, 0x3d, 0x86, 0xc4, 0xa4, 0x04, 0xba, 0xea,
-	0x39, 0x43, 0xf6, 0x86, 0xb9, 0x09, 0x69, 0xec, 0x89, 0xeb, 0xc4, 0x2e, 0x0b, 0x49, 0x93, 0x5e,
-	0x00, 0xad, 0x08, 0x8f, 0xfd, 0x00, 0x23, 0x27, 0x09, 0x78, 0x4c, 0x5a, 0xf4, 0x3f, 0x38, 0xdb,
-	0x71, 0x7f, 0x1a, 0xfa, 0x41, 0x18, 0x46, 0x2c, 0x4e, 0x48, 0x9b, 0x9e, 0x03, 0xd1, 0xf6, 0x68,
-	0x1c, 0x32, 0x65, 0xee, 0xc8, 0xb6, 0x5e, 0x30, 0x19, 0x4f, 0x13, 0x36, 0xe3, 0x63, 0x16, 0x13,
-	0xa0, 0x14, 0x4e, 0x34, 0x99, 0x8e, 0x3d, 0x27, 0x61, 0xc4, 0xa2, 0x67, 0x70, 0xac, 0x99, 0x1b,
-	0xf2, 0x09, 0x23, 0x5d, 0xf9, 0x1b, 0xc8, 0xfc, 0x69, 0xec, 0x91, 0x63, 0x7a, 0x0a, 0x16, 0xf7,
-	0xfd, 0x30, 0x88, 0xd9, 0xcc, 0x71, 0xdf, 0x92, 0x13, 0xe9, 0xd7, 0x00, 0x59, 0xe8, 0xdc, 0x92,
-	0x53, 0x89, 0x22, 0xee, 0x31, 0x74, 0x12, 0x8e, 0x33, 0xc7, 0xf3, 0x08, 0x91, 0x1b, 0xed, 0x11,
-	0xb2, 0x88, 0xbf, 0x63, 0xe4, 0x8c, 0x02, 0x34, 0x18, 0x22, 0x47, 0xf2, 0xb3, 0x3e, 0x58, 0x40,
-	0x9b, 0xad, 0xbf, 0x89, 0x55, 0x96, 0x0b, 0x3a, 0x80, 0x56, 0x15, 0xac, 0x4a, 0xdf, 0x1a, 0xb5,
-	0x75, 0xea, 0xa8, 0x05, 0x7a, 0x01, 0xcd, 0x7c, 0x33, 0xff, 0x22, 0xb6, 0x2a, 0xec, 0x2e, 0x56,
-	0x95, 0x4c, 0xb5, 0xb8, 0x5f, 0xae, 0xd3, 0x72, 0xf3, 0x28, 0x54, 0xaa, 0x5d, 0xdc, 0x83, 0xc1,
-	0x0f, 0x03, 0x4c, 0xf7, 0x53, 0x5a, 0x4a, 0x5b, 0xd5, 0x29, 0x58, 0xa8, 0x21, 0x1d, 0xdc, 0x03,
-	0xda, 0x83, 0x56, 0xb1, 0x99, 0x7f, 0x16, 0x1f, 0x4b, 0xd5, 0xbd, 0x83, 0xba, 0x94, 0x8a, 0x5e,
-	0xad, 0xbe, 0x53, 0xf4, 0x42, 0x2f, 0xa1, 0xf3, 0xe7, 0xaa, 0xd5, 0xbd, 0x58, 0xa3, 0xcb, 0x7f,
-	0x0e, 0x30, 0xd1, 0x0e, 0xdc, 0x9b, 0xe9, 0x15, 0x98, 0x77, 0xab, 0x74, 0xd9, 0x6b, 0xa8, 0x4b,
-	0x07, 0x5b, 0x2e, 0x68, 0xfb, 0xab, 0x74, 0x89, 0x8a, 0x0f, 0x9e, 0x81, 0x29, 0x2b, 0x6a, 0x41,
-	0x2b, 0x62, 0x93, 0x89, 0x73, 0xc3, 0xc8, 0x91, 0x0c, 0x25, 0xb9, 0x55, 0x17, 0x67, 0xc8, 0x8b,
-	0x43, 0xe6, 0x78, 0xa4, 0xf6, 0xca, 0xfc, 0x50, 0xcb, 0xe7, 0xf3, 0xa6, 0x9a, 0xf7, 0xfc, 0x77,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0xaa, 0x0a, 0x22, 0x77, 0xa1, 0x03, 0x00, 0x00,
+	// 841 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x53, 0xcd, 0x8e, 0xe3, 0x44,
+	0x10, 0x5e, 0xc7, 0xce, 0x5f, 0x39, 0x33, 0xdb, 0xd3, 0x1b, 0x56, 0x61, 0x84, 0x96, 0x28, 0xe2,
+	0x10, 0x24, 0xf0, 0x4a, 0x41, 0x42, 0x5c, 0x3d, 0x76, 0x67, 0x30, 0xf8, 0x27, 0xea, 0x38, 0xa0,
+	0xe5, 0x12, 0x39, 0x93, 0x9e, 0xc4, 0x90, 0xd8, 0x26, 0x76, 0x90, 0xb2, 0x0f, 0xc0, 0x63, 0x70,
+	0xe6, 0xcc, 0x43, 0xf0, 0x44, 0x88, 0x33, 0xea, 0xb6, 0x7b, 0x9c, 0x61, 0x10, 0x82, 0xc3, 0xde,
+	0xba, 0xbe, 0xef, 0xeb, 0xea, 0xfa, 0xba, 0xaa, 0xe0, 0x62, 0xcf, 0xf2, 0x3c, 0xda, 0x30, 0x23,
+	0x3b, 0xa4, 0x45, 0x7a, 0xfd, 0xfe, 0x26, 0x4d, 0x37, 0x3b, 0xf6, 0x5a, 0x44, 0xab, 0xe3, 0xfd,
+	0xeb, 0x28, 0x39, 0x55, 0xd4, 0x87, 0x7f, 0xa7, 0x8a, 0x78, 0xcf, 0xf2, 0x22, 0xda, 0x67, 0xa5,
+	0x60, 0xf4, 0xab, 0x06, 0x6d, 0xaf, 0xcc, 0x86, 0x3f, 0x07, 0xbd, 0x4a, 0x1c, 0x9e, 0x32, 0x36,
+	0x50, 0x86, 0xca, 0xf8, 0x72, 0xd2, 0x37, 0x2a, 0xda, 0xf0, 0x6a, 0x8e, 0x9e, 0x0b, 0xb1, 0x01,
+	0xed, 0x2c, 0x3a, 0xed, 0xd2, 0x68, 0x3d, 0x68, 0x0c, 0x95, 0xb1, 0x3e, 0xe9, 0x1b, 0xe5, 0xb3,
+	0x86, 0x7c, 0xd6, 0x30, 0x93, 0x13, 0x95, 0x22, 0xfc, 0x01, 0x74, 0x0f, 0xec, 0xc7, 0x23, 0xcb,
+	0x0b, 0x67, 0x3d, 0x50, 0x87, 0xca, 0xb8, 0x49, 0x6b, 0x00, 0xbf, 0x02, 0x88, 0x73, 0xca, 0xf2,
+	0x2c, 0x4d, 0x72, 0x36, 0xd0, 0x86, 0xca, 0xb8, 0x43, 0xcf, 0x90, 0xd1, 0x9f, 0x0d, 0xd0, 0xcf,
+	0x4a, 0xc1, 0x1d, 0xd0, 0x66, 0x8e, 0x7f, 0x8b, 0x9e, 0xf1, 0x93, 0xf5, 0xa5, 0x19, 0x22, 0x05,
+	0x03, 0xb4, 0xa6, 0x81, 0xeb, 0x06, 0xdf, 0xa2, 0x06, 0xee, 0x41, 0x67, 0xe1, 0x57, 0x91, 0x8a,
+	0xbb, 0xd0, 0x0c, 0xa8, 0x4d, 0x28, 0xd2, 0x30, 0x82, 0x9e, 0x38, 0x2e, 0x29, 0xf9, 0x8a, 0x58,
+	0x21, 0x6a, 0xd6, 0x88, 0x65, 0xfa, 0x16, 0x71, 0x51, 0x0b, 0xbf, 0x04, 0x5c, 0x21, 0x81, 0x3f,
+	0x75, 0xa8, 0x67, 0x86, 0x4e, 0xe0, 0xa3, 0x36, 0x7e, 0x0f, 0xae, 0x4a, 0x7c, 0xba, 0x70, 0xa7,
+	0x8e, 0xeb, 0x7a, 0xc4, 0x0f, 0x51, 0x07, 0xf7, 0x01, 0x49, 0xb9, 0x37, 0x73, 0x89, 0x10, 0x77,
+	0x79, 0x5a, 0xdb, 0x99, 0xcf, 0x16, 0x21, 0x59, 0x06, 0x33, 0xe2, 0x23, 0xc0, 0x18, 0x2e, 0x25,
+	0xb2, 0x98, 0xd9, 0x66, 0x48, 0x90, 0x8e, 0xaf, 0xe0, 0x42, 0x62, 0x96, 0x1b, 0xcc, 0x09, 0xea,
+	0x71, 0x1b, 0x94, 0x4c, 0x17, 0xbe, 0x8d, 0x2e, 0xf0, 0x73, 0xd0, 0x83, 0xe9, 0xd4, 0x75, 0x7c,
+	0xb2, 0x34, 0xad, 0xaf, 0xd1, 0x25, 0xd7, 0x4b, 0x80, 0x12, 0xd7, 0x7c, 0x83, 0x9e, 0x73, 0xc8,
+	0x0b, 0x6c, 0x42, 0xcd, 0x30, 0xa0, 0x4b, 0xd3, 0xb6, 0x11, 0xe2, 0x15, 0xd5, 0x10, 0x25, 0x5e,
+	0xf0, 0x0d, 0x41, 0x57, 0xb8, 0x0d, 0xea, 0x8d, 0x63, 0x23, 0x8c, 0x2f, 0x01, 0x6e, 0x1c, 0x7b,
+	0x69, 0x5a, 0x16, 0x99, 0x85, 0xe8, 0x05, 0x8f, 0x6f, 0x69, 0xb0, 0x98, 0x2d, 0xc5, 0x47, 0xf6,
+	0x31, 0x40, 0x93, 0x50, 0x1a, 0x50, 0xf4, 0x87, 0x3a, 0x5a, 0x43, 0x87, 0x24, 0x3f, 0xb1, 0x5d,
+	0x9a, 0x31, 0x3c, 0x82, 0x76, 0x35, 0x01, 0x62, 0x4c, 0xf4, 0x49, 0x47, 0x8e, 0x07, 0x95, 0x04,
+	0x7e, 0x09, 0xad, 0xec, 0xb8, 0xfa, 0x81, 0x9d, 0xc4, 0x54, 0xf4, 0x68, 0x15, 0xf1, 0xf6, 0xe7,
+	0xf1, 0x26, 0x89, 0x8a, 0xe3, 0x81, 0x89, 0xf6, 0xf7, 0x68, 0x0d, 0x8c, 0x7e, 0x53, 0x41, 0xb3,
+	0xb6, 0x51, 0xc1, 0x65, 0x55, 0x26, 0x67, 0x2d, 0x1e, 0xe9, 0xd2, 0x1a, 0xc0, 0x03, 0x68, 0xe7,
+	0xc7, 0xd5, 0xf7, 0xec, 0xae, 0x10, 0xd9, 0xbb, 0x54, 0x86, 0x9c, 0x91, 0xa5, 0xa9, 0x25, 0x23,
+	0x0b, 0xfa, 0x02, 0xba, 0x0f, 0xe3, 0x2f, 0x06, 0x4b, 0x9f, 0x5c, 0x3f, 0x99, 0xd4, 0x50, 0x2a,
+	0x68, 0x2d, 0xc6, 0xaf, 0x40, 0xbb, 0xdf, 0x45, 0x9b, 0x41, 0x53, 0xac, 0x04, 0x18, 0xbc, 0x40,
+	0x63, 0xba, 0x8b, 0x36, 0x54, 0xe0, 0x78, 0x02, 0x7a, 0x54, 0x14, 0xd1, 0xdd, 0x76, 0xcf, 0x92,
+	0x22, 0x1f, 0xb4, 0x86, 0xea, 0x58, 0x9f, 0xa0, 0x52, 0x66, 0x3e, 0x10, 0xf4, 0x5c, 0x74, 0xfd,
+	0x8b, 0x02, 0x50, 0x73, 0x18, 0x83, 0xb6, 0x8d, 0xf2, 0x6d, 0xe5, 0x54, 0x9c, 0x31, 0x02, 0xb5,
+	0xfe, 0x3e, 0x7e, 0xc4, 0xd7, 0xd0, 0xb9, 0x8f, 0x77, 0x2c, 0x89, 0xf6, 0xd2, 0xdd, 0x43, 0x5c,
+	0x7e, 0xd8, 0x3a, 0x8e, 0xc4, 0xf2, 0x6a, 0xf2, 0xc3, 0x2a, 0x80, 0xe7, 0xcf, 0xe3, 0xb7, 0x4c,
+	0x58, 0xd0, 0xa8, 0x38, 0xf3, 0x1b, 0xc5, 0xf6, 0xb8, 0x5f, 0x25, 0x51, 0xbc, 0x1b, 0xb4, 0xca,
+	0x1b, 0x0f, 0xc0, 0xe8, 0x63, 0xd0, 0xb8, 0x45, 0xac, 0x43, 0xdb, 0x23, 0xf3, 0xb9, 0x79, 0x4b,
+	0xd0, 0x33, 0x3e, 0x92, 0xe1, 0x1b, 0xb1, 0x6f, 0x0a, 0xdf, 0x37, 0x4a, 0x4c, 0x1b, 0x35, 0x46,
+	0xbf, 0x2b, 0xd0, 0xbd, 0x3d, 0xa4, 0xc7, 0xec, 0xbf, 0x75, 0x6e, 0xc3, 0xa5, 0xce, 0x5a, 0x76,
+	0xae, 0x0a, 0xdf, 0x49, 0xe7, 0x3e, 0xe5, 0x39, 0xf7, 0x2b, 0x76, 0xc8, 0x85, 0x73, 0x7d, 0xf2,
+	0xc2, 0x98, 0xc7, 0x9b, 0x84, 0xad, 0x45, 0xb9, 0x5e, 0x49, 0x51, 0xa9, 0xe1, 0x46, 0x7a, 0xe7,
+	0xcc, 0x79, 0xb5, 0xca, 0x93, 0x6a, 0xef, 0x0e, 0x2c, 0x2a, 0xd2, 0x83, 0xf4, 0x51, 0x85, 0xe7,
+	0xb3, 0xa9, 0xfe, 0xc3, 0x6c, 0x96, 0xd5, 0x68, 0x43, 0xb5, 0x74, 0x58, 0xbe, 0xf3, 0xc8, 0x61,
+	0xf3, 0xff, 0x38, 0xec, 0x43, 0x33, 0x49, 0x93, 0x3b, 0x26, 0x1a, 0xd8, 0xa3, 0x65, 0x30, 0xfa,
+	0x59, 0x01, 0xfc, 0xd4, 0x28, 0xfe, 0x04, 0xae, 0x72, 0x76, 0x88, 0xa3, 0x5d, 0xfc, 0x96, 0xad,
+	0x2b, 0x50, 0x18, 0xeb, 0xd1, 0xa7, 0x04, 0xfe, 0x08, 0x2e, 0x2a, 0x4f, 0xb3, 0xf3, 0x45, 0x7e,
+	0x0c, 0xfe, 0xfb, 0x3e, 0xdf, 0x68, 0xdf, 0x35, 0xb2, 0xd5, 0xaa, 0x25, 0x3c, 0x7c, 0xf6, 0xd7,
+	0x00, 0x3f, 0x3a, 0x28, 0x84, 0xba, 0x06, 0x00, 0x00,
 }
@@ -0,0 +1,107 @@
+package pricing
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var ErrNoExchangeRates = errors.New("Exchange rates are not available")
+
+// The exchange rate lookups pricing needs. Rates are quoted in units of the currency per coin.
+type ExchangeRates interface {
+	GetExchangeRate(currencyCode string) (float64, error)
+	UnitsPerCoin() int
+}
+
+/* Converter converts prices to the smallest unit of the wallet's currency. Prices in
+   any other currency are in hundredths of it. The rate used for each currency is
+   recorded so it can be saved with the order. */
+type Converter struct {
+	Currency string
+	Rates    ExchangeRates
+	Used     map[string]float64
+}
+
+func NewConverter(currency string, rates ExchangeRates) *Converter {
+	return &Converter{
+		Currency: currency,
+		Rates:    rates,
+		Used:     make(map[string]float64),
+	}
+}
+
+// Convert the amount to satoshis, rounding down
+func (c *Converter) ToSatoshis(currencyCode string, amount uint64) (uint64, error) {
+	if strings.ToLower(currencyCode) == strings.ToLower(c.Currency) {
+		return amount, nil
+	}
+	if c.Rates == nil {
+		return 0, ErrNoExchangeRates
+	}
+	exchangeRate, err := c.Rates.GetExchangeRate(currencyCode)
+	if err != nil {
+		return 0, err
+	}
+	rate, ok := new(big.Rat).SetString(strconv.FormatFloat(exchangeRate, 'f', -1, 64))
+	if !ok || rate.Sign() <= 0 {
+		return 0, errors.New("Invalid exchange rate for " + strings.ToUpper(currencyCode))
+	}
+	c.Used[strings.ToUpper(currencyCode)] = exchangeRate
+
+	satoshis := new(big.Rat).SetInt(new(big.Int).SetUint64(amount))
+	satoshis.Mul(satoshis, big.NewRat(int64(c.Rates.UnitsPerCoin()), 100))
+	satoshis.Quo(satoshis, rate)
+	return floor(satoshis)
+}
+
+// Parse a percentage from a listing. The shortest decimal form of the float is used as that's what the vendor entered.
+func percentage(f float32) *big.Rat {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(float64(f), 'f', -1, 32))
+	if !ok || r.Sign() < 0 {
+		return new(big.Rat)
+	}
+	return r
+}
+
+// Return the given percentage of the amount, rounding down
+func percentOf(amount uint64, percent *big.Rat) (uint64, error) {
+	r := new(big.Rat).SetInt(new(big.Int).SetUint64(amount))
+	r.Mul(r, percent)
+	r.Quo(r, big.NewRat(100, 1))
+	return floor(r)
+}
+
+func floor(r *big.Rat) (uint64, error) {
+	i := new(big.Int).Quo(r.Num(), r.Denom())
+	if i.Sign() < 0 {
+		return 0, nil
+	}
+	if !i.IsUint64() {
+		return 0, ErrOverflow
+	}
+	return i.Uint64(), nil
+}
+
+func add(a, b uint64) (uint64, error) {
+	if a+b < a {
+		return 0, ErrOverflow
+	}
+	return a + b, nil
+}
+
+func mul(a, b uint64) (uint64, error) {
+	if a != 0 && (a*b)/a != b {
+		return 0, ErrOverflow
+	}
+	return a * b, nil
+}
+
+// Subtract b from a without going below zero
+func sub(a, b uint64) uint64 {
+	if b > a {
+		return 0
+	}
+	return a - b
+}
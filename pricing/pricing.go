@@ -0,0 +1,431 @@
+/* Package pricing works out what a buyer owes for an order. All amounts are integers
+   and percentages are applied as exact rationals so the buyer and vendor always arrive
+   at the same total. Fractions of a satoshi are rounded down each time a price is
+   converted to satoshis and each time a discount or tax percentage is applied. */
+package pricing
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+)
+
+var ErrOverflow = errors.New("Order total is too large")
+
+// An item in an order together with its listing and what the buyer selected
+type Line struct {
+	ListingHash string
+	Listing     *pb.Listing
+	Quantity    uint32
+
+	// Index of the selected sku, or -1 if the listing has no skus
+	Sku int
+
+	// The winning bid for an auction, already in satoshis
+	BidAmount uint64
+
+	// Multihashes of the coupon codes the buyer entered
+	CouponHashes []string
+
+	ShippingOption *pb.Order_Item_ShippingOption
+}
+
+type Order struct {
+	Lines   []Line
+	Country pb.CountryCode
+}
+
+// What the buyer owes. All amounts are in satoshis.
+type Breakdown struct {
+	Items []Item `json:"items"`
+
+	// The sum of the items before discounts and tax
+	Subtotal uint64 `json:"subtotal"`
+	Discount uint64 `json:"discount"`
+	Tax      uint64 `json:"tax"`
+
+	// Shipping includes the tax charged on it, which is also given separately
+	Shipping    uint64 `json:"shipping"`
+	ShippingTax uint64 `json:"shippingTax"`
+
+	Total         uint64             `json:"total"`
+	ExchangeRates map[string]float64 `json:"exchangeRates"`
+}
+
+/* The price of one item in the order. Price, Discount and Tax are per unit. Shipping
+   excludes combined shipping which is charged once for the whole order. */
+type Item struct {
+	ListingHash string `json:"listingHash"`
+	Quantity    uint32 `json:"quantity"`
+	Price       uint64 `json:"price"`
+	Surcharge   int64  `json:"surcharge"`
+	Discount    uint64 `json:"discount"`
+	Tax         uint64 `json:"tax"`
+	Shipping    uint64 `json:"shipping"`
+	ShippingTax uint64 `json:"shippingTax"`
+	Total       uint64 `json:"total"`
+}
+
+type combinedShipping struct {
+	quantity uint32
+	price    uint64
+	priceTax uint64
+	modifier uint64
+	modTax   uint64
+	add      bool
+}
+
+/* Check the order can be priced. This doesn't need exchange rates so it catches
+   everything Calculate would reject other than a missing or bad rate. */
+func Validate(order Order) error {
+	for _, line := range order.Lines {
+		if err := validateLine(line, order.Country); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateLine(line Line, country pb.CountryCode) error {
+	l := line.Listing
+	if l == nil || l.Metadata == nil || l.Item == nil {
+		return errors.New("Listing not found for item " + line.ListingHash)
+	}
+	if line.Quantity == 0 {
+		return errors.New("Item quantity must be at least one")
+	}
+	if l.Metadata.Format == pb.Listing_Metadata_AUCTION {
+		if line.BidAmount == 0 {
+			return errors.New("Auction item is missing its winning bid")
+		}
+	} else if sku := selectedSku(line); sku != nil && sku.Surcharge < 0 && uint64(-sku.Surcharge) > l.Item.Price {
+		return errors.New("Selected variant has a negative price")
+	}
+	if l.Metadata.ContractType == pb.Listing_Metadata_PHYSICAL_GOOD {
+		if _, _, err := shippingService(line, country); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Price each item in the order and add up the total
+func Calculate(order Order, conv *Converter) (*Breakdown, error) {
+	if err := Validate(order); err != nil {
+		return nil, err
+	}
+	b := &Breakdown{ExchangeRates: conv.Used}
+	var combined []combinedShipping
+	for _, line := range order.Lines {
+		item, err := priceItem(line, order.Country, conv)
+		if err != nil {
+			return nil, err
+		}
+		if line.Listing.Metadata.ContractType == pb.Listing_Metadata_PHYSICAL_GOOD {
+			cs, err := priceShipping(line, order.Country, conv, &item)
+			if err != nil {
+				return nil, err
+			}
+			combined = append(combined, cs...)
+		}
+		if err := b.addItem(item); err != nil {
+			return nil, err
+		}
+	}
+	shipping, tax, err := combineShipping(combined)
+	if err != nil {
+		return nil, err
+	}
+	if b.Shipping, err = add(b.Shipping, shipping); err != nil {
+		return nil, err
+	}
+	b.ShippingTax += tax
+
+	total := sub(b.Subtotal, b.Discount)
+	for _, amount := range []uint64{b.Tax, b.Shipping} {
+		if total, err = add(total, amount); err != nil {
+			return nil, err
+		}
+	}
+	b.Total = total
+	return b, nil
+}
+
+func (b *Breakdown) addItem(item Item) error {
+	var err error
+	amounts := []struct {
+		total *uint64
+		unit  uint64
+	}{
+		{&b.Subtotal, item.Price},
+		{&b.Discount, item.Discount},
+		{&b.Tax, item.Tax},
+	}
+	for _, a := range amounts {
+		lineAmount, err := mul(a.unit, uint64(item.Quantity))
+		if err != nil {
+			return err
+		}
+		if *a.total, err = add(*a.total, lineAmount); err != nil {
+			return err
+		}
+	}
+	if b.Shipping, err = add(b.Shipping, item.Shipping); err != nil {
+		return err
+	}
+	b.ShippingTax += item.ShippingTax
+	b.Items = append(b.Items, item)
+	return nil
+}
+
+/* Work out the unit price of an item including its variant surcharge, then take off
+   its coupons one after the other and tax what's left. A negative surcharge can't take
+   the price below zero. */
+func priceItem(line Line, country pb.CountryCode, conv *Converter) (Item, error) {
+	l := line.Listing
+	item := Item{
+		ListingHash: line.ListingHash,
+		Quantity:    line.Quantity,
+	}
+	var err error
+	if l.Metadata.Format == pb.Listing_Metadata_AUCTION {
+		// The winning bid replaces the listing price and is already denominated in satoshis
+		item.Price = line.BidAmount
+	} else {
+		if item.Price, err = conv.ToSatoshis(l.Metadata.PricingCurrency, l.Item.Price); err != nil {
+			return item, err
+		}
+	}
+	if sku := selectedSku(line); sku != nil && sku.Surcharge != 0 {
+		surcharge, err := conv.ToSatoshis(l.Metadata.PricingCurrency, uint64(abs(sku.Surcharge)))
+		if err != nil {
+			return item, err
+		}
+		if sku.Surcharge > 0 {
+			if item.Price, err = add(item.Price, surcharge); err != nil {
+				return item, err
+			}
+			item.Surcharge = int64(surcharge)
+		} else {
+			item.Price = sub(item.Price, surcharge)
+			item.Surcharge = -int64(surcharge)
+		}
+	}
+
+	remaining := item.Price
+	for _, hash := range line.CouponHashes {
+		for _, coupon := range l.Coupons {
+			if coupon.GetHash() != hash {
+				continue
+			}
+			var discount uint64
+			if d := coupon.GetPriceDiscount(); d > 0 {
+				discount, err = conv.ToSatoshis(l.Metadata.PricingCurrency, d)
+			} else if d := coupon.GetPercentDiscount(); d > 0 {
+				discount, err = percentOf(remaining, percentage(d))
+			}
+			if err != nil {
+				return item, err
+			}
+			remaining = sub(remaining, discount)
+		}
+	}
+	item.Discount = item.Price - remaining
+
+	if item.Tax, err = percentOf(remaining, taxRate(l, country, false)); err != nil {
+		return item, err
+	}
+	unitTotal, err := add(remaining, item.Tax)
+	if err != nil {
+		return item, err
+	}
+	if item.Total, err = mul(unitTotal, uint64(item.Quantity)); err != nil {
+		return item, err
+	}
+	return item, nil
+}
+
+/* Add the shipping for a physical item to it. Items using combined shipping are
+   returned to be charged together once every item has been priced. */
+func priceShipping(line Line, country pb.CountryCode, conv *Converter, item *Item) ([]combinedShipping, error) {
+	l := line.Listing
+	option, service, err := shippingService(line, country)
+	if err != nil {
+		return nil, err
+	}
+	unitPrice, err := conv.ToSatoshis(l.Metadata.PricingCurrency, service.Price)
+	if err != nil {
+		return nil, err
+	}
+	price, err := mul(unitPrice, uint64(line.Quantity))
+	if err != nil {
+		return nil, err
+	}
+	rate := taxRate(l, country, true)
+
+	var combined []combinedShipping
+	if option.ShippingRules != nil {
+		for _, rule := range option.ShippingRules.Rules {
+			inRange := line.Quantity >= rule.MinRange && line.Quantity <= rule.MaxRange
+			switch option.ShippingRules.RuleType {
+			case pb.Listing_ShippingOption_ShippingRules_QUANTITY_DISCOUNT:
+				if inRange {
+					discount, err := conv.ToSatoshis(l.Metadata.PricingCurrency, rule.Price)
+					if err != nil {
+						return nil, err
+					}
+					price = sub(price, discount)
+				}
+			case pb.Listing_ShippingOption_ShippingRules_FLAT_FEE_WEIGHT_RANGE:
+				weight := uint32(l.Item.Grams * float32(line.Quantity))
+				inRange = weight >= rule.MinRange && weight <= rule.MaxRange
+				fallthrough
+			case pb.Listing_ShippingOption_ShippingRules_FLAT_FEE_QUANTITY_RANGE:
+				if inRange {
+					if price, err = conv.ToSatoshis(l.Metadata.PricingCurrency, rule.Price); err != nil {
+						return nil, err
+					}
+				}
+			case pb.Listing_ShippingOption_ShippingRules_COMBINED_SHIPPING_ADD,
+				pb.Listing_ShippingOption_ShippingRules_COMBINED_SHIPPING_SUBTRACT:
+				modifier, err := conv.ToSatoshis(l.Metadata.PricingCurrency, rule.Price)
+				if err != nil {
+					return nil, err
+				}
+				cs := combinedShipping{
+					quantity: line.Quantity,
+					price:    unitPrice,
+					modifier: modifier,
+					add:      option.ShippingRules.RuleType == pb.Listing_ShippingOption_ShippingRules_COMBINED_SHIPPING_ADD,
+				}
+				if cs.priceTax, err = percentOf(cs.price, rate); err != nil {
+					return nil, err
+				}
+				if cs.modTax, err = percentOf(cs.modifier, rate); err != nil {
+					return nil, err
+				}
+				combined = append(combined, cs)
+				price = 0
+			}
+		}
+	}
+
+	if item.ShippingTax, err = percentOf(price, rate); err != nil {
+		return nil, err
+	}
+	if item.Shipping, err = add(price, item.ShippingTax); err != nil {
+		return nil, err
+	}
+	if item.Total, err = add(item.Total, item.Shipping); err != nil {
+		return nil, err
+	}
+	return combined, nil
+}
+
+/* Combined shipping charges the lowest shipping price of the items using it once, then
+   adds or subtracts each item's modifier for every unit after its first. */
+func combineShipping(combined []combinedShipping) (shipping, tax uint64, err error) {
+	if len(combined) == 0 {
+		return 0, 0, nil
+	}
+	lowest := combined[0]
+	for _, cs := range combined[1:] {
+		if cs.price+cs.priceTax < lowest.price+lowest.priceTax {
+			lowest = cs
+		}
+	}
+	shipping, tax = lowest.price, lowest.priceTax
+	var subShipping, subTax uint64
+	for _, cs := range combined {
+		if cs.quantity < 2 {
+			continue
+		}
+		modifier, err := mul(cs.modifier, uint64(cs.quantity-1))
+		if err != nil {
+			return 0, 0, err
+		}
+		modTax, err := mul(cs.modTax, uint64(cs.quantity-1))
+		if err != nil {
+			return 0, 0, err
+		}
+		if !cs.add {
+			subShipping += modifier
+			subTax += modTax
+			continue
+		}
+		if shipping, err = add(shipping, modifier); err != nil {
+			return 0, 0, err
+		}
+		tax += modTax
+	}
+	shipping, tax = sub(shipping, subShipping), sub(tax, subTax)
+	if shipping, err = add(shipping, tax); err != nil {
+		return 0, 0, err
+	}
+	return shipping, tax, nil
+}
+
+func selectedSku(line Line) *pb.Listing_Item_Sku {
+	if line.Sku < 0 || line.Sku >= len(line.Listing.Item.Skus) {
+		return nil
+	}
+	return line.Listing.Item.Skus[line.Sku]
+}
+
+func shippingService(line Line, country pb.CountryCode) (*pb.Listing_ShippingOption, *pb.Listing_ShippingOption_Service, error) {
+	if line.ShippingOption == nil {
+		return nil, nil, errors.New("Item is missing a shipping option")
+	}
+	var option *pb.Listing_ShippingOption
+	for _, o := range line.Listing.ShippingOptions {
+		if o.Name == line.ShippingOption.Name {
+			option = o
+			break
+		}
+	}
+	if option == nil {
+		return nil, nil, errors.New("Shipping option not found in listing")
+	}
+	shipsTo := false
+	for _, region := range option.Regions {
+		if region == country || region == pb.CountryCode_ALL {
+			shipsTo = true
+			break
+		}
+	}
+	if !shipsTo {
+		return nil, nil, errors.New("Listing does not ship to the selected country")
+	}
+	for _, service := range option.Services {
+		if strings.ToLower(service.Name) == strings.ToLower(line.ShippingOption.Service) {
+			return option, service, nil
+		}
+	}
+	return nil, nil, errors.New("Shipping service not found in listing")
+}
+
+// The combined percentage of the listing's taxes which apply in the country
+func taxRate(l *pb.Listing, country pb.CountryCode, shipping bool) *big.Rat {
+	rate := new(big.Rat)
+	for _, tax := range l.Taxes {
+		if shipping && !tax.TaxShipping {
+			continue
+		}
+		for _, region := range tax.TaxRegions {
+			if region == country {
+				rate.Add(rate, percentage(tax.Percentage))
+				break
+			}
+		}
+	}
+	return rate
+}
+
+func abs(i int64) int64 {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
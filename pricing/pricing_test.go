@@ -0,0 +1,208 @@
+package pricing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+)
+
+type testRates map[string]float64
+
+func (r testRates) GetExchangeRate(currencyCode string) (float64, error) {
+	rate, ok := r[currencyCode]
+	if !ok {
+		return 0, errors.New("No rate")
+	}
+	return rate, nil
+}
+
+func (r testRates) UnitsPerCoin() int {
+	return 100000000
+}
+
+func testListing(price uint64) *pb.Listing {
+	return &pb.Listing{
+		Metadata: &pb.Listing_Metadata{
+			ContractType:    pb.Listing_Metadata_PHYSICAL_GOOD,
+			Format:          pb.Listing_Metadata_FIXED_PRICE,
+			PricingCurrency: "BTC",
+		},
+		Item: &pb.Listing_Item{
+			Price: price,
+			Skus:  []*pb.Listing_Item_Sku{{Surcharge: 5000}},
+		},
+		Coupons: []*pb.Listing_Coupon{
+			{
+				Code:     &pb.Listing_Coupon_Hash{Hash: "percent"},
+				Discount: &pb.Listing_Coupon_PercentDiscount{PercentDiscount: 10},
+			},
+			{
+				Code:     &pb.Listing_Coupon_Hash{Hash: "price"},
+				Discount: &pb.Listing_Coupon_PriceDiscount{PriceDiscount: 200000},
+			},
+		},
+		Taxes: []*pb.Listing_Tax{
+			{
+				TaxRegions:  []pb.CountryCode{pb.CountryCode_UNITED_STATES},
+				TaxShipping: true,
+				Percentage:  7.5,
+			},
+		},
+		ShippingOptions: []*pb.Listing_ShippingOption{
+			{
+				Name:     "standard",
+				Regions:  []pb.CountryCode{pb.CountryCode_UNITED_STATES},
+				Services: []*pb.Listing_ShippingOption_Service{{Name: "post", Price: 1000}},
+			},
+		},
+	}
+}
+
+func testLine(l *pb.Listing, quantity uint32, coupons ...string) Line {
+	return Line{
+		ListingHash:    "QmListing",
+		Listing:        l,
+		Quantity:       quantity,
+		Sku:            0,
+		CouponHashes:   coupons,
+		ShippingOption: &pb.Order_Item_ShippingOption{Name: "standard", Service: "Post"},
+	}
+}
+
+func TestConverter(t *testing.T) {
+	conv := NewConverter("BTC", testRates{"USD": 3000.7})
+	satoshis, err := conv.ToSatoshis("btc", 1234)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if satoshis != 1234 {
+		t.Errorf("Expected the wallet currency to be unchanged, got %d", satoshis)
+	}
+	// $1.00 is 33325.5... satoshis
+	satoshis, err = conv.ToSatoshis("USD", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if satoshis != 33325 {
+		t.Errorf("Expected 33325 satoshis, got %d", satoshis)
+	}
+	if conv.Used["USD"] != 3000.7 {
+		t.Error("Exchange rate used wasn't recorded")
+	}
+	if _, err := conv.ToSatoshis("EUR", 100); err == nil {
+		t.Error("Converted a currency without a rate")
+	}
+	if _, err := NewConverter("BTC", nil).ToSatoshis("USD", 100); err != ErrNoExchangeRates {
+		t.Error("Converted without exchange rates")
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	order := Order{
+		Lines:   []Line{testLine(testListing(100000), 3, "percent")},
+		Country: pb.CountryCode_UNITED_STATES,
+	}
+	b, err := Calculate(order, NewConverter("BTC", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := b.Items[0]
+	if item.Price != 105000 || item.Surcharge != 5000 || item.Discount != 10500 || item.Tax != 7087 {
+		t.Errorf("Unexpected item price %+v", item)
+	}
+	if item.Shipping != 3225 || item.ShippingTax != 225 || item.Total != 307986 {
+		t.Errorf("Unexpected item shipping %+v", item)
+	}
+	if b.Subtotal != 315000 || b.Discount != 31500 || b.Tax != 21261 || b.Shipping != 3225 || b.ShippingTax != 225 {
+		t.Errorf("Unexpected breakdown %+v", b)
+	}
+	if b.Total != 307986 {
+		t.Errorf("Expected a total of 307986, got %d", b.Total)
+	}
+
+	// Tax only applies in its regions
+	order.Country = pb.CountryCode_CANADA
+	order.Lines[0].Listing.ShippingOptions[0].Regions = []pb.CountryCode{pb.CountryCode_ALL}
+	b, err = Calculate(order, NewConverter("BTC", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Tax != 0 || b.ShippingTax != 0 || b.Total != 286500 {
+		t.Errorf("Unexpected untaxed breakdown %+v", b)
+	}
+}
+
+func TestCalculateDiscountLargerThanPrice(t *testing.T) {
+	order := Order{
+		Lines:   []Line{testLine(testListing(100000), 1, "percent", "price")},
+		Country: pb.CountryCode_UNITED_STATES,
+	}
+	b, err := Calculate(order, NewConverter("BTC", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Items[0].Discount != 105000 || b.Tax != 0 {
+		t.Errorf("Unexpected item %+v", b.Items[0])
+	}
+	if b.Total != 1075 {
+		t.Errorf("Expected only shipping to be charged, got %d", b.Total)
+	}
+}
+
+func TestCalculateCombinedShipping(t *testing.T) {
+	a, c := testListing(1000), testListing(1000)
+	for i, l := range []*pb.Listing{a, c} {
+		l.Taxes = nil
+		l.ShippingOptions[0].Services[0].Price = uint64(1000 - i*200)
+		l.ShippingOptions[0].ShippingRules = &pb.Listing_ShippingOption_ShippingRules{
+			RuleType: pb.Listing_ShippingOption_ShippingRules_COMBINED_SHIPPING_ADD,
+			Rules:    []*pb.Listing_ShippingOption_ShippingRules_Rule{{Price: 100}},
+		}
+	}
+	order := Order{
+		Lines:   []Line{testLine(a, 2), testLine(c, 3)},
+		Country: pb.CountryCode_UNITED_STATES,
+	}
+	b, err := Calculate(order, NewConverter("BTC", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The cheapest shipping is charged once plus 100 for each additional unit
+	if b.Shipping != 1100 || b.Items[0].Shipping != 0 || b.Items[1].Shipping != 0 {
+		t.Errorf("Unexpected combined shipping %+v", b)
+	}
+	if b.Total != 5*6000+1100 {
+		t.Errorf("Expected a total of %d, got %d", 5*6000+1100, b.Total)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	l := testListing(1000)
+	l.Item.Skus[0].Surcharge = -2000
+	order := Order{
+		Lines:   []Line{testLine(l, 1)},
+		Country: pb.CountryCode_UNITED_STATES,
+	}
+	if err := Validate(order); err == nil {
+		t.Error("Validated a variant with a negative price")
+	}
+	l.Item.Skus[0].Surcharge = -1000
+	if err := Validate(order); err != nil {
+		t.Error(err)
+	}
+	order.Country = pb.CountryCode_CANADA
+	if err := Validate(order); err == nil {
+		t.Error("Validated an item which doesn't ship to the buyer")
+	}
+	order.Country = pb.CountryCode_UNITED_STATES
+	order.Lines[0].ShippingOption.Service = "courier"
+	if err := Validate(order); err == nil {
+		t.Error("Validated an unknown shipping service")
+	}
+	order.Lines[0].ShippingOption.Service = "post"
+	order.Lines[0].Quantity = 0
+	if err := Validate(order); err == nil {
+		t.Error("Validated an item with no quantity")
+	}
+}
@@ -0,0 +1,67 @@
+package repo
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+/* The scopes an API token may be granted. Every scope allows GET requests other
+   than those for secrets such as the mnemonic. Orders allows acting on purchases and
+   sales, wallet allows spending and admin allows everything. */
+const (
+	APIScopeReadOnly = "read-only"
+	APIScopeOrders   = "orders"
+	APIScopeWallet   = "wallet"
+	APIScopeAdmin    = "admin"
+)
+
+var APIScopes = []string{APIScopeReadOnly, APIScopeOrders, APIScopeWallet, APIScopeAdmin}
+
+var ErrAPITokenNotFound = errors.New("API token not found")
+
+// Return the hex encoded SHA-256 hash the token is stored under
+func HashAPIToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+/* Generate a token with the given name and scopes and save its hash. The token is
+   returned so it can be shown once as it can't be read back from the datastore. */
+func NewAPIToken(tokens APITokens, name string, scopes []string) (string, APIToken, error) {
+	apiToken := APIToken{Name: name, Created: time.Now()}
+	if name == "" || strings.ContainsAny(name, "/ ") {
+		return "", apiToken, errors.New("A token name must be given and can't contain spaces or slashes")
+	}
+	if len(scopes) == 0 {
+		return "", apiToken, errors.New("A token must be granted at least one scope")
+	}
+	for _, scope := range scopes {
+		if !validAPIScope(scope) {
+			return "", apiToken, fmt.Errorf("Unknown scope %s, use one of %s", scope, strings.Join(APIScopes, ", "))
+		}
+		apiToken.Scopes = append(apiToken.Scopes, scope)
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", apiToken, err
+	}
+	token := hex.EncodeToString(b)
+	if err := tokens.Put(apiToken, HashAPIToken(token)); err != nil {
+		return "", apiToken, err
+	}
+	return token, apiToken, nil
+}
+
+func validAPIScope(scope string) bool {
+	for _, s := range APIScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
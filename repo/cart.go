@@ -0,0 +1,8 @@
+package repo
+
+import "errors"
+
+var (
+	ErrCartItemNotFound      = errors.New("Cart item not found")
+	ErrCheckoutGroupNotFound = errors.New("Checkout group not found")
+)
@@ -0,0 +1,5 @@
+package repo
+
+import "errors"
+
+var ErrChatAttachmentNotFound = errors.New("Chat attachment not found")
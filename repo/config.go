@@ -2,12 +2,21 @@ package repo
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/ipfs/go-ipfs/repo"
 	"github.com/ipfs/go-ipfs/repo/config"
 	"io/ioutil"
 	"path"
+	"time"
 )
 
+// How often the DHT is checked for offline messages when the config doesn't say
+const DefaultMessageRetrieverInterval = time.Hour
+
+// Polling more often than this would mostly just load the DHT
+const MinMessageRetrieverInterval = time.Minute
+
 var DefaultBootstrapAddresses = []string{
 	"/ip4/107.170.133.32/tcp/4001/ipfs/QmbY4yo9Eifg7DPjL7qK5JvNdiJaRAD7N76gVg4YoQsvgA", // Le Marché Serpette
 	"/ip4/139.59.174.197/tcp/4001/ipfs/QmcCoBtYyduyurcLHRF14QhhA88YojJJpGFuMHoMZuU8sc", // Brixton-Village
@@ -33,6 +42,19 @@ type TorConfig struct {
 	TorControl string
 }
 
+/* Settings for storing offline messages in an S3 compatible bucket. Messages are
+   deleted once the pointers to them expire. The bucket must be publicly readable at
+   PublicURL, an https URL, as that's where recipients download messages from. The
+   Endpoint must use https unless it's on this machine, such as MinIO run locally. */
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PublicURL string
+}
+
 type WalletConfig struct {
 	Type             string
 	Binary           string
@@ -46,6 +68,13 @@ type WalletConfig struct {
 	RPCPassword      string
 }
 
+/* A wallet for another coin to run alongside the default wallet. Wallets for coins
+   other than bitcoin must be of the electrum type. */
+type CoinWalletConfig struct {
+	Currency string
+	WalletConfig
+}
+
 func GetAPIConfig(cfgPath string) (*APIConfig, error) {
 	file, err := ioutil.ReadFile(cfgPath)
 	if err != nil {
@@ -133,6 +162,27 @@ func GetWalletConfig(cfgPath string) (*WalletConfig, error) {
 	return wCfg, nil
 }
 
+/* Return the wallets to run for other coins. Configs created before the setting was
+   added have none. */
+func GetCoinWalletConfigs(cfgPath string) ([]CoinWalletConfig, error) {
+	file, err := ioutil.ReadFile(cfgPath)
+	if err != nil {
+		return nil, err
+	}
+	var cfg struct {
+		Wallets []CoinWalletConfig `json:"Wallets"`
+	}
+	if err := json.Unmarshal(file, &cfg); err != nil {
+		return nil, err
+	}
+	for _, w := range cfg.Wallets {
+		if w.Currency == "" {
+			return nil, errors.New("Wallets in the config file must set a Currency")
+		}
+	}
+	return cfg.Wallets, nil
+}
+
 func GetTorConfig(cfgPath string) (TorConfig, error) {
 	file, err := ioutil.ReadFile(cfgPath)
 	if err != nil {
@@ -161,6 +211,49 @@ func GetDropboxApiToken(cfgPath string) (string, error) {
 	return token, nil
 }
 
+func GetS3Config(cfgPath string) (*S3Config, error) {
+	file, err := ioutil.ReadFile(cfgPath)
+	if err != nil {
+		return nil, err
+	}
+	var cfg struct {
+		S3 *S3Config `json:"S3-storage"`
+	}
+	if err := json.Unmarshal(file, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.S3 == nil {
+		return nil, errors.New("S3 storage not set in config file")
+	}
+	return cfg.S3, nil
+}
+
+/* Return how often to check for offline messages. Configs created before the setting
+   was added get DefaultMessageRetrieverInterval. */
+func GetMessageRetrieverInterval(cfgPath string) (time.Duration, error) {
+	file, err := ioutil.ReadFile(cfgPath)
+	if err != nil {
+		return 0, err
+	}
+	var cfg struct {
+		Interval *string `json:"Message-retriever-interval"`
+	}
+	if err := json.Unmarshal(file, &cfg); err != nil {
+		return 0, err
+	}
+	if cfg.Interval == nil {
+		return DefaultMessageRetrieverInterval, nil
+	}
+	interval, err := time.ParseDuration(*cfg.Interval)
+	if err != nil {
+		return 0, err
+	}
+	if interval < MinMessageRetrieverInterval {
+		return 0, fmt.Errorf("Message retriever interval must be at least %s", MinMessageRetrieverInterval)
+	}
+	return interval, nil
+}
+
 func GetCrosspostGateway(cfgPath string) ([]string, error) {
 	file, err := ioutil.ReadFile(cfgPath)
 	if err != nil {
@@ -3,6 +3,7 @@ package repo
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/ipfs/go-ipfs/repo/fsrepo"
 	"os"
@@ -97,6 +98,26 @@ func TestGetWalletConfig(t *testing.T) {
 	}
 }
 
+func TestGetCoinWalletConfigs(t *testing.T) {
+	wallets, err := GetCoinWalletConfigs(testConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wallets) != 1 {
+		t.Fatal("Expected one wallet, got ", len(wallets))
+	}
+	if wallets[0].Currency != "LTC" || wallets[0].Type != "electrum" {
+		t.Error("Wallet does not equal expected values")
+	}
+	if wallets[0].TrustedPeer != "ssl://electrum-ltc.example.com:50002" || wallets[0].MediumFeeDefault != 100 {
+		t.Error("Wallet settings do not equal expected values")
+	}
+	_, err = GetCoinWalletConfigs(nonexistentTestConfigPath)
+	if err == nil {
+		t.Error("GetCoinWalletConfigs didn't throw an error")
+	}
+}
+
 func TestGetDropboxApiToken(t *testing.T) {
 	dropboxApiToken, err := GetDropboxApiToken(testConfigPath)
 	if dropboxApiToken != "dropbox123" {
@@ -115,6 +136,36 @@ func TestGetDropboxApiToken(t *testing.T) {
 	}
 }
 
+func TestGetS3Config(t *testing.T) {
+	s3Config, err := GetS3Config(testConfigPath)
+	if err != nil {
+		t.Fatal("GetS3Config threw an unexpected error", err)
+	}
+	if s3Config.Endpoint != "https://s3.example.com" || s3Config.Bucket != "messages" || s3Config.AccessKey != "access123" || s3Config.SecretKey != "secret123" || s3Config.PublicURL != "https://messages.example.com" {
+		t.Error("S3 config does not equal expected values")
+	}
+
+	_, err = GetS3Config(nonexistentTestConfigPath)
+	if err == nil {
+		t.Error("GetS3Config didn't throw an error")
+	}
+}
+
+func TestGetMessageRetrieverInterval(t *testing.T) {
+	interval, err := GetMessageRetrieverInterval(testConfigPath)
+	if err != nil {
+		t.Fatal("GetMessageRetrieverInterval threw an unexpected error", err)
+	}
+	if interval != time.Minute*30 {
+		t.Error("Message retriever interval does not equal expected value, got", interval)
+	}
+
+	_, err = GetMessageRetrieverInterval(nonexistentTestConfigPath)
+	if err == nil {
+		t.Error("GetMessageRetrieverInterval didn't throw an error")
+	}
+}
+
 func TestGetResolverUrl(t *testing.T) {
 	resolverUrl, err := GetResolverUrl(testConfigPath)
 	if resolverUrl != "https://resolver.onename.com/" {
@@ -26,7 +26,22 @@ type Datastore interface {
 	Notifications() Notifications
 	Coupons() Coupons
 	TxMetadata() TxMetadata
+	TxAccounting() TxAccounting
+	ExchangeRateHistory() ExchangeRateHistory
 	ModeratedStores() ModeratedStores
+	Bids() Bids
+	Pledges() Pledges
+	Search() Search
+	APITokens() APITokens
+	Webhooks() Webhooks
+	WebhookDeliveries() WebhookDeliveries
+	OutgoingMessages() OutgoingMessages
+	GroupChats() GroupChats
+	Cart() Cart
+	CheckoutGroups() CheckoutGroups
+	DigitalAssets() DigitalAssets
+	LicenseKeys() LicenseKeys
+	OrderHistory() OrderHistory
 	Close()
 }
 
@@ -154,6 +169,12 @@ type Purchases interface {
 	// Update the funding level for the contract
 	UpdateFunding(orderId string, funded bool, records []*spvwallet.TransactionRecord) error
 
+	// Save the exchange rates, keyed by currency code, used to price the order
+	PutExchangeRates(orderID string, rates map[string]float64) error
+
+	// Return the exchange rates used to price the order
+	GetExchangeRates(orderID string) (map[string]float64, error)
+
 	// Delete an order
 	Delete(orderID string) error
 
@@ -165,6 +186,9 @@ type Purchases interface {
 
 	// Return the metadata for all purchases
 	GetAll(offsetId string, limit int) ([]Purchase, error)
+
+	// Return a page of the purchases matching the query along with the number of matches
+	Query(query OrderQuery) ([]Purchase, int, error)
 }
 
 type Sales interface {
@@ -177,6 +201,12 @@ type Sales interface {
 	// Update the funding level for the contract
 	UpdateFunding(orderId string, funded bool, records []*spvwallet.TransactionRecord) error
 
+	// Save the exchange rates, keyed by currency code, used to price the order
+	PutExchangeRates(orderID string, rates map[string]float64) error
+
+	// Return the exchange rates used to price the order
+	GetExchangeRates(orderID string) (map[string]float64, error)
+
 	// Delete an order
 	Delete(orderID string) error
 
@@ -188,6 +218,9 @@ type Sales interface {
 
 	// Return the metadata for all sales
 	GetAll(offsetId string, limit int) ([]Sale, error)
+
+	// Return a page of the sales matching the query along with the number of matches
+	Query(query OrderQuery) ([]Sale, int, error)
 }
 
 type Cases interface {
@@ -243,6 +276,12 @@ type Chat interface {
 
 	// Delete all messages from from a peer
 	DeleteConversation(peerID string) error
+
+	// Save a file sent with a message
+	PutAttachment(attachment ChatAttachment) error
+
+	// Return the attachment with the given id. Returns ErrChatAttachmentNotFound if there is none.
+	GetAttachment(id string) (ChatAttachment, error)
 }
 
 type Notifications interface {
@@ -287,6 +326,29 @@ type TxMetadata interface {
 	Delete(txid string) error
 }
 
+type TxAccounting interface {
+	// Save the record for a transaction. The first record saved for a txid is kept.
+	Put(record TxAccountingRecord) error
+
+	// Set the exchange rate of a saved record which doesn't have one yet
+	SetExchangeRate(txid, currency string, rate float64) error
+
+	// Get the record for the given txid
+	Get(txid string) (TxAccountingRecord, error)
+
+	// Get a map of the txid to each record
+	GetAll() (map[string]TxAccountingRecord, error)
+}
+
+type ExchangeRateHistory interface {
+	// Save the rates for every currency fetched at the given time
+	Put(rates map[string]float64, timestamp time.Time) error
+
+	/* Return the rates recorded for a currency between from and to, oldest first.
+	   A zero from or to leaves that end of the range open. */
+	Get(currencyCode string, from, to time.Time) ([]ExchangeRateSample, error)
+}
+
 type ModeratedStores interface {
 	// Put a B58 encoded peer ID to the database
 	Put(peerId string) error
@@ -298,3 +360,220 @@ type ModeratedStores interface {
 	// Delete a moderated store from the database
 	Delete(peerId string) error
 }
+
+type Bids interface {
+	// Save or update a bid
+	Put(orderID string, contract pb.RicardianContract, state BidState, outgoing bool) error
+
+	// Update the state of a bid
+	UpdateState(orderID string, state BidState) error
+
+	// Return a bid given the order ID
+	GetByOrderId(orderID string) (contract *pb.RicardianContract, state BidState, outgoing bool, err error)
+
+	// Return the metadata for all bids received on a listing, highest bid first
+	GetBySlug(slug string) ([]Bid, error)
+
+	/* Return the metadata for the bids we have placed.
+	   The offset and limit arguments can be used to for lazy loading. */
+	GetOutgoing(offsetId string, limit int) ([]Bid, error)
+
+	// Delete a bid
+	Delete(orderID string) error
+}
+
+type Pledges interface {
+	// Save or update a pledge to one of our crowdfund campaigns
+	Put(orderID string, contract pb.RicardianContract, state PledgeState) error
+
+	// Mark a pledge as funded
+	UpdateFunding(orderID string, funded bool) error
+
+	// Update the state of a pledge
+	UpdateState(orderID string, state PledgeState) error
+
+	// Return the metadata for all pledges to a campaign
+	GetBySlug(slug string) ([]Pledge, error)
+
+	// Delete a pledge
+	Delete(orderID string) error
+}
+
+type Search interface {
+	/* Replace the listings in the search index. Orders and chat messages are
+	   indexed as they are saved. */
+	IndexListings(listings []SearchListing) error
+
+	/* Return the results matching the query, best match first, along with the
+	   total number of matches. The offset and limit in the query select a page. */
+	Query(query SearchQuery) ([]SearchResult, int, error)
+}
+
+type APITokens interface {
+	// Save a new token. Only the hash of the token is stored so it can't be recovered.
+	Put(token APIToken, hash string) error
+
+	// Return the token with the given hash
+	GetByHash(hash string) (APIToken, error)
+
+	// Return every token, oldest first
+	GetAll() ([]APIToken, error)
+
+	// Delete the token with the given name. Returns ErrAPITokenNotFound if there is none.
+	Delete(name string) error
+}
+
+type Webhooks interface {
+	// Save a webhook, replacing any with the same id
+	Put(hook Webhook) error
+
+	// Return the webhook with the given id
+	Get(id string) (Webhook, error)
+
+	// Return every webhook, oldest first
+	GetAll() ([]Webhook, error)
+
+	// Delete the webhook with the given id. Returns ErrWebhookNotFound if there is none.
+	Delete(id string) error
+}
+
+type WebhookDeliveries interface {
+	// Save a new delivery and return its id
+	Put(delivery WebhookDelivery) (int64, error)
+
+	// Save the status, attempts and response of a delivery
+	Update(delivery WebhookDelivery) error
+
+	// Return the pending deliveries due at or before the given time, oldest first
+	GetDue(now time.Time) ([]WebhookDelivery, error)
+
+	/* Return deliveries, newest first, along with the total number of them. An empty
+	   status returns deliveries in any state. */
+	GetAll(status string, offset, limit int) ([]WebhookDelivery, int, error)
+}
+
+type OutgoingMessages interface {
+	// Save a message, replacing any with the same id
+	Put(msg OutgoingMessage) error
+
+	/* Save a message unless it has been acked since it was loaded so a retry can't
+	   undo an ack. Returns whether it was saved. */
+	UpdateUnlessAcked(msg OutgoingMessage) (bool, error)
+
+	// Return the message with the given id. Returns ErrOutgoingMessageNotFound if there is none.
+	Get(id string) (OutgoingMessage, error)
+
+	// Return the message which was stored offline under the given pointer
+	GetByPointerId(pointerId string) (OutgoingMessage, error)
+
+	// Return the messages about the given chat message or order, oldest first
+	GetByReference(reference string) ([]OutgoingMessage, error)
+
+	/* Return the messages sent since the given time which are stored offline and
+	   haven't been acked, oldest first. An empty peer ID returns them for every peer. */
+	GetUndelivered(peerId string, since time.Time) ([]OutgoingMessage, error)
+
+	/* Return messages, newest first, along with the total number of them. An empty
+	   peer ID or state matches any. */
+	GetAll(peerId, state string, offset, limit int) ([]OutgoingMessage, int, error)
+
+	/* Delete messages acked before ackedBefore and any sent before sentBefore. Returns
+	   how many were deleted. */
+	Prune(ackedBefore, sentBefore time.Time) (int, error)
+}
+
+type GroupChats interface {
+	// Save a group, replacing any with the same id
+	Put(group GroupChat) error
+
+	// Return the group with the given id. Returns ErrGroupChatNotFound if there is none.
+	Get(groupId string) (GroupChat, error)
+
+	// Return every group, most recently active first
+	GetAll() ([]GroupChat, error)
+
+	// Delete a group and its messages
+	Delete(groupId string) error
+
+	// Save a message to a group. Saving a message already in the group does nothing.
+	PutMessage(msg GroupChatMessage) error
+
+	/* Return a group's messages, newest first. The offset ID and limit can be used
+	   for lazy loading. */
+	GetMessages(groupId, offsetId string, limit int) ([]GroupChatMessage, error)
+
+	// Mark every message in a group as read
+	MarkAsRead(groupId string) error
+}
+
+type Cart interface {
+	// Save an item to the cart, replacing any with the same id
+	Put(item CartItem) error
+
+	// Return the item with the given id. Returns ErrCartItemNotFound if there is none.
+	Get(id string) (CartItem, error)
+
+	// Return every item in the cart, oldest first
+	GetAll() ([]CartItem, error)
+
+	// Remove an item from the cart. Returns ErrCartItemNotFound if there is none.
+	Delete(id string) error
+
+	// Empty the cart
+	DeleteAll() error
+}
+
+type CheckoutGroups interface {
+	// Save a checkout group, replacing any with the same id
+	Put(group CheckoutGroup) error
+
+	// Return the group with the given id. Returns ErrCheckoutGroupNotFound if there is none.
+	Get(id string) (CheckoutGroup, error)
+
+	// Return the group an order belongs to. Returns ErrCheckoutGroupNotFound if it isn't in one.
+	GetByOrderId(orderId string) (CheckoutGroup, error)
+
+	// Record the transaction which paid for an order in a group
+	SetFundingTxid(orderId, txid string) error
+}
+
+type DigitalAssets interface {
+	// Save the file for a listing, replacing any it already has
+	Put(asset DigitalAsset) error
+
+	// Return a listing's file along with its data. Returns ErrDigitalAssetNotFound if there is none.
+	Get(slug string) (DigitalAsset, error)
+
+	// Return every listing's file without its data
+	GetAll() ([]DigitalAsset, error)
+
+	// Delete a listing's file. Returns ErrDigitalAssetNotFound if there is none.
+	Delete(slug string) error
+}
+
+type LicenseKeys interface {
+	// Add keys to a listing's pool. Keys already in the pool are ignored.
+	Put(slug string, keys []string) error
+
+	/* Hand out the oldest unused key in a listing's pool to an order. If the order
+	   already has a key for the listing that key is returned again. Returns
+	   ErrNoLicenseKeys if the pool is empty. */
+	Assign(slug string, orderId string) (string, error)
+
+	// Return every key in a listing's pool, including those handed out, oldest first
+	Get(slug string) ([]LicenseKey, error)
+
+	// Return the number of unused keys for each listing with a pool
+	GetAll() (map[string]int, error)
+
+	// Delete the unused keys in a listing's pool
+	DeleteUnused(slug string) error
+}
+
+type OrderHistory interface {
+	// Record an action taken on an order
+	Put(event OrderEvent) error
+
+	// Return an order's history, oldest first
+	Get(orderId string) ([]OrderEvent, error)
+}
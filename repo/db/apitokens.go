@@ -0,0 +1,84 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+type APITokensDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (a *APITokensDB) Put(token repo.APIToken, hash string) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	scopes, err := json.Marshal(token.Scopes)
+	if err != nil {
+		return err
+	}
+	_, err = a.db.Exec("insert into apitokens(name, hash, scopes, timestamp) values(?,?,?,?)", token.Name, hash, string(scopes), int(token.Created.Unix()))
+	return err
+}
+
+func (a *APITokensDB) GetByHash(hash string) (repo.APIToken, error) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return scanAPIToken(a.db.QueryRow("select name, scopes, timestamp from apitokens where hash=?", hash))
+}
+
+func (a *APITokensDB) GetAll() ([]repo.APIToken, error) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	ret := []repo.APIToken{}
+	rows, err := a.db.Query("select name, scopes, timestamp from apitokens order by rowid")
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, token)
+	}
+	return ret, rows.Err()
+}
+
+func (a *APITokensDB) Delete(name string) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	res, err := a.db.Exec("delete from apitokens where name=?", name)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repo.ErrAPITokenNotFound
+	}
+	return nil
+}
+
+func scanAPIToken(row interface {
+	Scan(dest ...interface{}) error
+}) (repo.APIToken, error) {
+	var token repo.APIToken
+	var scopes string
+	var timestamp int
+	if err := row.Scan(&token.Name, &scopes, &timestamp); err != nil {
+		return token, err
+	}
+	if err := json.Unmarshal([]byte(scopes), &token.Scopes); err != nil {
+		return token, err
+	}
+	token.Created = time.Unix(int64(timestamp), 0)
+	return token, nil
+}
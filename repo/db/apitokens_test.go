@@ -0,0 +1,68 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+func TestAPITokensDB(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	atdb := APITokensDB{db: conn}
+	token := repo.APIToken{Name: "fulfillment", Scopes: []string{repo.APIScopeOrders}, Created: time.Unix(1500000000, 0)}
+	if err := atdb.Put(token, "abc"); err != nil {
+		t.Fatal(err)
+	}
+	if err := atdb.Put(token, "def"); err == nil {
+		t.Error("Saved two tokens with the same name")
+	}
+	ret, err := atdb.GetByHash("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.Name != "fulfillment" || len(ret.Scopes) != 1 || ret.Scopes[0] != repo.APIScopeOrders || !ret.Created.Equal(token.Created) {
+		t.Error("Returned wrong token")
+	}
+	if _, err := atdb.GetByHash("def"); err == nil {
+		t.Error("Returned a token for an unknown hash")
+	}
+	tokens, err := atdb.GetAll()
+	if err != nil || len(tokens) != 1 {
+		t.Error("Returned wrong tokens", err)
+	}
+	if err := atdb.Delete("fulfillment"); err != nil {
+		t.Error(err)
+	}
+	if err := atdb.Delete("fulfillment"); err != repo.ErrAPITokenNotFound {
+		t.Error("Deleting an unknown token returned", err)
+	}
+	if _, err := atdb.GetByHash("abc"); err == nil {
+		t.Error("Returned a deleted token")
+	}
+}
+
+func TestNewAPIToken(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	atdb := &APITokensDB{db: conn}
+	if _, _, err := repo.NewAPIToken(atdb, "tool", []string{"everything"}); err == nil {
+		t.Error("Created a token with an unknown scope")
+	}
+	if _, _, err := repo.NewAPIToken(atdb, "a/b", []string{repo.APIScopeAdmin}); err == nil {
+		t.Error("Created a token with a slash in its name")
+	}
+	secret, token, err := repo.NewAPIToken(atdb, "tool", []string{repo.APIScopeReadOnly, repo.APIScopeWallet})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret, err := atdb.GetByHash(repo.HashAPIToken(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.Name != token.Name || len(ret.Scopes) != 2 {
+		t.Error("Returned wrong token")
+	}
+}
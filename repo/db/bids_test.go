@@ -0,0 +1,166 @@
+package db
+
+import (
+	"database/sql"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"testing"
+	"time"
+)
+
+var bdb BidsDB
+
+func init() {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	bdb = BidsDB{
+		db: conn,
+	}
+}
+
+func newBidContract(amount uint64, buyerGuid string) *pb.RicardianContract {
+	c := new(pb.RicardianContract)
+	listing := new(pb.Listing)
+	listing.Slug = "test-auction"
+	item := new(pb.Listing_Item)
+	item.Title = "Test auction"
+	image := new(pb.Listing_Item_Image)
+	image.Tiny = "test image hash"
+	item.Images = []*pb.Listing_Item_Image{image}
+	listing.Item = item
+	listing.VendorID = &pb.ID{Guid: "vendor guid"}
+	c.VendorListings = []*pb.Listing{listing}
+	order := new(pb.Order)
+	order.BuyerID = &pb.ID{Guid: buyerGuid, BlockchainID: "@testbuyer"}
+	ts := new(timestamp.Timestamp)
+	ts.Seconds = time.Now().Unix()
+	order.Timestamp = ts
+	order.Items = []*pb.Order_Item{{ListingHash: "listing hash", Quantity: 1, BidAmount: amount}}
+	order.Payment = &pb.Order_Payment{Amount: amount, Method: pb.Order_Payment_DIRECT}
+	c.BuyerOrder = order
+	return c
+}
+
+func TestPutBid(t *testing.T) {
+	err := bdb.Put("bid1", *newBidContract(1000, "buyer guid"), repo.BidPending, false)
+	if err != nil {
+		t.Error(err)
+	}
+	stmt, _ := bdb.db.Prepare("select orderID, state, slug, amount, title, buyerID, buyerBlockchainID, vendorID, outgoing from bids where orderID=?")
+	defer stmt.Close()
+	var orderID, slug, title, buyerID, buyerHandle, vendorID string
+	var state, amount, outgoing int
+	err = stmt.QueryRow("bid1").Scan(&orderID, &state, &slug, &amount, &title, &buyerID, &buyerHandle, &vendorID, &outgoing)
+	if err != nil {
+		t.Error(err)
+	}
+	if orderID != "bid1" {
+		t.Errorf(`Expected %s got %s`, "bid1", orderID)
+	}
+	if state != int(repo.BidPending) {
+		t.Errorf("Expected state %d got %d", repo.BidPending, state)
+	}
+	if slug != "test-auction" {
+		t.Errorf(`Expected %s got %s`, "test-auction", slug)
+	}
+	if amount != 1000 {
+		t.Errorf("Expected amount 1000 got %d", amount)
+	}
+	if title != "Test auction" {
+		t.Errorf(`Expected %s got %s`, "Test auction", title)
+	}
+	if buyerID != "buyer guid" || buyerHandle != "@testbuyer" {
+		t.Error("Returned incorrect buyer ID")
+	}
+	if vendorID != "vendor guid" {
+		t.Errorf(`Expected %s got %s`, "vendor guid", vendorID)
+	}
+	if outgoing != 0 {
+		t.Error("Expected incoming bid")
+	}
+	bdb.Delete("bid1")
+}
+
+func TestUpdateBidState(t *testing.T) {
+	bdb.Put("bid2", *newBidContract(1000, "buyer guid"), repo.BidPending, false)
+	err := bdb.UpdateState("bid2", repo.BidWon)
+	if err != nil {
+		t.Error(err)
+	}
+	_, state, _, err := bdb.GetByOrderId("bid2")
+	if err != nil {
+		t.Error(err)
+	}
+	if state != repo.BidWon {
+		t.Errorf("Expected state %s got %s", repo.BidWon, state)
+	}
+	bdb.Delete("bid2")
+}
+
+func TestBidsDB_GetByOrderId(t *testing.T) {
+	bdb.Put("bid3", *newBidContract(1500, "buyer guid"), repo.BidPending, true)
+	c, state, outgoing, err := bdb.GetByOrderId("bid3")
+	if err != nil {
+		t.Error(err)
+	}
+	if c.BuyerOrder.Items[0].BidAmount != 1500 {
+		t.Error("Returned incorrect contract")
+	}
+	if state != repo.BidPending {
+		t.Error("Returned incorrect state")
+	}
+	if !outgoing {
+		t.Error("Expected outgoing bid")
+	}
+	_, _, _, err = bdb.GetByOrderId("fasdfas")
+	if err == nil {
+		t.Error("Get by unknown order ID failed to return error")
+	}
+	bdb.Delete("bid3")
+}
+
+func TestBidsDB_GetBySlug(t *testing.T) {
+	bdb.Put("low", *newBidContract(1000, "buyer1"), repo.BidPending, false)
+	bdb.Put("high", *newBidContract(3000, "buyer2"), repo.BidPending, false)
+	bdb.Put("mid", *newBidContract(2000, "buyer3"), repo.BidPending, false)
+	bdb.Put("mine", *newBidContract(5000, "buyer4"), repo.BidPending, true)
+	bids, err := bdb.GetBySlug("test-auction")
+	if err != nil {
+		t.Error(err)
+	}
+	if len(bids) != 3 {
+		t.Fatalf("Expected 3 bids got %d", len(bids))
+	}
+	if bids[0].OrderId != "high" || bids[1].OrderId != "mid" || bids[2].OrderId != "low" {
+		t.Error("Bids returned in incorrect order")
+	}
+	if bids[0].Amount != 3000 || bids[0].BuyerId != "buyer2" || bids[0].State != "PENDING" {
+		t.Error("Returned incorrect bid")
+	}
+	outgoing, err := bdb.GetOutgoing("", -1)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(outgoing) != 1 || outgoing[0].OrderId != "mine" {
+		t.Error("Returned incorrect outgoing bids")
+	}
+	for _, id := range []string{"low", "high", "mid", "mine"} {
+		bdb.Delete(id)
+	}
+}
+
+func TestBidsDB_Delete(t *testing.T) {
+	bdb.Put("bid4", *newBidContract(1000, "buyer guid"), repo.BidPending, false)
+	err := bdb.Delete("bid4")
+	if err != nil {
+		t.Error(err)
+	}
+	stmt, _ := bdb.db.Prepare("select orderID from bids where orderID=?")
+	defer stmt.Close()
+	var orderID string
+	stmt.QueryRow("bid4").Scan(&orderID)
+	if orderID != "" {
+		t.Error("Bid not deleted")
+	}
+}
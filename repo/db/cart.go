@@ -0,0 +1,100 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+const cartColumns = "id, vendorID, listingHash, quantity, options, shippingName, shippingService, memo, coupons, added"
+
+type CartDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (c *CartDB) Put(item repo.CartItem) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	options, err := json.Marshal(item.Options)
+	if err != nil {
+		return err
+	}
+	coupons, err := json.Marshal(item.Coupons)
+	if err != nil {
+		return err
+	}
+	_, err = c.db.Exec("insert or replace into cart("+cartColumns+") values(?,?,?,?,?,?,?,?,?,?)",
+		item.Id, item.VendorId, item.ListingHash, item.Quantity, string(options), item.Shipping.Name, item.Shipping.Service, item.Memo, string(coupons), int(item.Added.Unix()))
+	return err
+}
+
+func (c *CartDB) Get(id string) (repo.CartItem, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return scanCartItem(c.db.QueryRow("select "+cartColumns+" from cart where id=?", id))
+}
+
+func (c *CartDB) GetAll() ([]repo.CartItem, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	ret := []repo.CartItem{}
+	rows, err := c.db.Query("select " + cartColumns + " from cart order by added, rowid")
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		item, err := scanCartItem(rows)
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, item)
+	}
+	return ret, rows.Err()
+}
+
+func (c *CartDB) Delete(id string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	res, err := c.db.Exec("delete from cart where id=?", id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return repo.ErrCartItemNotFound
+	}
+	return nil
+}
+
+func (c *CartDB) DeleteAll() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("delete from cart")
+	return err
+}
+
+func scanCartItem(row interface {
+	Scan(dest ...interface{}) error
+}) (repo.CartItem, error) {
+	var item repo.CartItem
+	var options, coupons string
+	var added int
+	err := row.Scan(&item.Id, &item.VendorId, &item.ListingHash, &item.Quantity, &options, &item.Shipping.Name, &item.Shipping.Service, &item.Memo, &coupons, &added)
+	if err == sql.ErrNoRows {
+		return item, repo.ErrCartItemNotFound
+	} else if err != nil {
+		return item, err
+	}
+	if err := json.Unmarshal([]byte(options), &item.Options); err != nil {
+		return item, err
+	}
+	if err := json.Unmarshal([]byte(coupons), &item.Coupons); err != nil {
+		return item, err
+	}
+	item.Added = time.Unix(int64(added), 0)
+	return item, nil
+}
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+func TestCartDB(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	cdb := CartDB{db: conn}
+	now := time.Unix(1500000000, 0)
+	shirt := repo.CartItem{
+		Id:          "1",
+		VendorId:    "QmVendor1",
+		ListingHash: "QmShirt",
+		Quantity:    2,
+		Options:     []repo.CartItemOption{{Name: "size", Value: "large"}},
+		Shipping:    repo.CartItemShipping{Name: "standard", Service: "post"},
+		Coupons:     []string{"SAVE10"},
+		Added:       now,
+	}
+	hat := repo.CartItem{Id: "2", VendorId: "QmVendor2", ListingHash: "QmHat", Quantity: 1, Added: now.Add(time.Minute)}
+	for _, item := range []repo.CartItem{hat, shirt} {
+		if err := cdb.Put(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ret, err := cdb.Get("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.VendorId != "QmVendor1" || ret.Quantity != 2 || len(ret.Options) != 1 || ret.Options[0].Value != "large" ||
+		ret.Shipping.Service != "post" || len(ret.Coupons) != 1 || !ret.Added.Equal(now) {
+		t.Error("Returned wrong cart item")
+	}
+	shirt.Quantity = 3
+	if err := cdb.Put(shirt); err != nil {
+		t.Fatal(err)
+	}
+	all, err := cdb.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].Id != "1" || all[0].Quantity != 3 || all[1].Id != "2" {
+		t.Error("Cart items returned in the wrong order or not updated")
+	}
+	if err := cdb.Delete("1"); err != nil {
+		t.Error(err)
+	}
+	if err := cdb.Delete("1"); err != repo.ErrCartItemNotFound {
+		t.Error("Deleting an unknown cart item returned", err)
+	}
+	if _, err := cdb.Get("1"); err != repo.ErrCartItemNotFound {
+		t.Error("Getting a deleted cart item returned", err)
+	}
+	if err := cdb.DeleteAll(); err != nil {
+		t.Error(err)
+	}
+	all, err = cdb.GetAll()
+	if err != nil || len(all) != 0 {
+		t.Error("Cart was not emptied")
+	}
+}
+
+func TestCheckoutGroupsDB(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	cdb := CheckoutGroupsDB{db: conn}
+	group := repo.CheckoutGroup{
+		Id: "QmGroup",
+		Orders: []repo.CheckoutGroupOrder{
+			{OrderId: "QmOrder1", VendorId: "QmVendor1", PaymentAddress: "addr1", Amount: 1000},
+			{OrderId: "QmOrder2", VendorId: "QmVendor2", PaymentAddress: "addr2", Amount: 2000},
+		},
+		Timestamp: time.Unix(1500000000, 0),
+	}
+	if err := cdb.Put(group); err != nil {
+		t.Fatal(err)
+	}
+	ret, err := cdb.GetByOrderId("QmOrder2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.Id != "QmGroup" || len(ret.Orders) != 2 || ret.Orders[0].OrderId != "QmOrder1" || ret.Orders[1].Amount != 2000 || !ret.Timestamp.Equal(group.Timestamp) {
+		t.Error("Returned wrong checkout group")
+	}
+	if err := cdb.SetFundingTxid("QmOrder1", "txid"); err != nil {
+		t.Fatal(err)
+	}
+	ret, err = cdb.Get("QmGroup")
+	if err != nil || ret.Orders[0].FundingTxid != "txid" || ret.Orders[1].FundingTxid != "" {
+		t.Error("Funding txid was not saved")
+	}
+	if _, err := cdb.Get("QmNoSuchGroup"); err != repo.ErrCheckoutGroupNotFound {
+		t.Error("Getting an unknown group returned", err)
+	}
+	if _, err := cdb.GetByOrderId("QmOrder3"); err != repo.ErrCheckoutGroupNotFound {
+		t.Error("Getting the group of an unknown order returned", err)
+	}
+	if err := cdb.SetFundingTxid("QmOrder3", "txid"); err != repo.ErrCheckoutGroupNotFound {
+		t.Error("Funding an order in no group returned", err)
+	}
+}
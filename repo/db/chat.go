@@ -147,6 +147,9 @@ func (c *ChatDB) GetMessages(peerID string, subject string, offsetId string, lim
 		}
 		ret = append(ret, chatMessage)
 	}
+	for i := range ret {
+		ret[i].Attachments = c.attachments(ret[i].MessageId)
+	}
 	return ret
 }
 
@@ -229,6 +232,7 @@ func (c *ChatDB) GetUnreadCount(subject string) (int, error) {
 func (c *ChatDB) DeleteMessage(msgID string) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
+	c.db.Exec("delete from chatattachments where messageID=?", msgID)
 	c.db.Exec("delete from chat where messageID=?", msgID)
 	return nil
 }
@@ -236,6 +240,45 @@ func (c *ChatDB) DeleteMessage(msgID string) error {
 func (c *ChatDB) DeleteConversation(peerId string) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
+	c.db.Exec("delete from chatattachments where messageID in (select messageID from chat where peerId=? and subject='')", peerId)
 	c.db.Exec("delete from chat where peerId=? and subject=''", peerId)
 	return nil
 }
+
+func (c *ChatDB) PutAttachment(attachment repo.ChatAttachment) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("insert or replace into chatattachments(id, messageID, filename, mediaType, size, thumbnail, key) values(?,?,?,?,?,?,?)",
+		attachment.Id, attachment.MessageId, attachment.Filename, attachment.MediaType, attachment.Size, attachment.Thumbnail, attachment.Key)
+	return err
+}
+
+func (c *ChatDB) GetAttachment(id string) (repo.ChatAttachment, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	var a repo.ChatAttachment
+	err := c.db.QueryRow("select id, messageID, filename, mediaType, size, thumbnail, key from chatattachments where id=?", id).Scan(&a.Id, &a.MessageId, &a.Filename, &a.MediaType, &a.Size, &a.Thumbnail, &a.Key)
+	if err == sql.ErrNoRows {
+		return a, repo.ErrChatAttachmentNotFound
+	}
+	return a, err
+}
+
+// Return the attachments of a message. The caller must hold the lock.
+func (c *ChatDB) attachments(messageId string) []repo.ChatAttachment {
+	var ret []repo.ChatAttachment
+	rows, err := c.db.Query("select id, messageID, filename, mediaType, size, thumbnail, key from chatattachments where messageID=? order by rowid", messageId)
+	if err != nil {
+		log.Error(err)
+		return ret
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a repo.ChatAttachment
+		if err := rows.Scan(&a.Id, &a.MessageId, &a.Filename, &a.MediaType, &a.Size, &a.Thumbnail, &a.Key); err != nil {
+			continue
+		}
+		ret = append(ret, a)
+	}
+	return ret
+}
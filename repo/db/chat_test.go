@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"testing"
 	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
 )
 
 var chdb ChatDB
@@ -347,3 +349,37 @@ func TestChatDB_DeleteConversation(t *testing.T) {
 	}
 	stmt.Close()
 }
+
+func TestChatDB_Attachments(t *testing.T) {
+	setupDB()
+	err := chdb.Put("11111", "abc", "", "", time.Now(), false, true)
+	if err != nil {
+		t.Error(err)
+	}
+	attachment := repo.ChatAttachment{Id: "QmFile", MessageId: "11111", Filename: "photo.jpg", MediaType: "image/jpeg", Size: 2048, Thumbnail: "QmThumb", Key: []byte{1, 2, 3}}
+	err = chdb.PutAttachment(attachment)
+	if err != nil {
+		t.Error(err)
+	}
+	ret, err := chdb.GetAttachment("QmFile")
+	if err != nil {
+		t.Error(err)
+	}
+	if ret.MessageId != "11111" || ret.Filename != "photo.jpg" || ret.Size != 2048 || ret.Thumbnail != "QmThumb" || len(ret.Key) != 3 {
+		t.Error("Returned wrong attachment")
+	}
+	if _, err := chdb.GetAttachment("QmOther"); err != repo.ErrChatAttachmentNotFound {
+		t.Error("Getting an unknown attachment returned", err)
+	}
+	messages := chdb.GetMessages("abc", "", "", -1)
+	if len(messages) != 1 || len(messages[0].Attachments) != 1 || messages[0].Attachments[0].Id != "QmFile" {
+		t.Error("Attachments were not returned with their message")
+	}
+	err = chdb.DeleteMessage("11111")
+	if err != nil {
+		t.Error(err)
+	}
+	if _, err := chdb.GetAttachment("QmFile"); err != repo.ErrChatAttachmentNotFound {
+		t.Error("Attachment was not deleted with its message")
+	}
+}
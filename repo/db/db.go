@@ -2,7 +2,7 @@ package db
 
 import (
 	"database/sql"
-	"path"
+	"strings"
 	"sync"
 
 	"github.com/OpenBazaar/openbazaar-go/repo"
@@ -33,18 +33,28 @@ type SQLiteDatastore struct {
 	notifications   repo.Notifications
 	coupons         repo.Coupons
 	txMetadata      repo.TxMetadata
+	txAccounting    repo.TxAccounting
+	exchangeRates   repo.ExchangeRateHistory
 	moderatedStores repo.ModeratedStores
+	bids            repo.Bids
+	pledges         repo.Pledges
+	search          repo.Search
+	apiTokens       repo.APITokens
+	webhooks        repo.Webhooks
+	hookDeliveries  repo.WebhookDeliveries
+	outgoing        repo.OutgoingMessages
+	groupChats      repo.GroupChats
+	cart            repo.Cart
+	checkoutGroups  repo.CheckoutGroups
+	digitalAssets   repo.DigitalAssets
+	licenseKeys     repo.LicenseKeys
+	orderHistory    repo.OrderHistory
 	db              *sql.DB
 	lock            sync.RWMutex
 }
 
 func Create(repoPath, password string, testnet bool) (*SQLiteDatastore, error) {
-	var dbPath string
-	if testnet {
-		dbPath = path.Join(repoPath, "datastore", "testnet.db")
-	} else {
-		dbPath = path.Join(repoPath, "datastore", "mainnet.db")
-	}
+	dbPath := databasePath(repoPath, testnet)
 	conn, err := sql.Open("sqlite3", dbPath)
 	if err != nil {
 		return nil, err
@@ -77,20 +87,24 @@ func Create(repoPath, password string, testnet bool) (*SQLiteDatastore, error) {
 			lock: l,
 		},
 		keys: &KeysDB{
-			db:   conn,
-			lock: l,
+			db:    conn,
+			table: "keys",
+			lock:  l,
 		},
 		stxos: &StxoDB{
-			db:   conn,
-			lock: l,
+			db:    conn,
+			table: "stxos",
+			lock:  l,
 		},
 		txns: &TxnsDB{
-			db:   conn,
-			lock: l,
+			db:    conn,
+			table: "txns",
+			lock:  l,
 		},
 		utxos: &UtxoDB{
-			db:   conn,
-			lock: l,
+			db:    conn,
+			table: "utxos",
+			lock:  l,
 		},
 		settings: &SettingsDB{
 			db:   conn,
@@ -109,8 +123,9 @@ func Create(repoPath, password string, testnet bool) (*SQLiteDatastore, error) {
 			lock: l,
 		},
 		watchedScripts: &WatchedScriptsDB{
-			db:   conn,
-			lock: l,
+			db:    conn,
+			table: "watchedscripts",
+			lock:  l,
 		},
 		cases: &CasesDB{
 			db:   conn,
@@ -132,14 +147,83 @@ func Create(repoPath, password string, testnet bool) (*SQLiteDatastore, error) {
 			db:   conn,
 			lock: l,
 		},
+		txAccounting: &TxAccountingDB{
+			db:   conn,
+			lock: l,
+		},
+		exchangeRates: &ExchangeRateHistoryDB{
+			db:   conn,
+			lock: l,
+		},
 		moderatedStores: &ModeratedDB{
 			db:   conn,
 			lock: l,
 		},
+		bids: &BidsDB{
+			db:   conn,
+			lock: l,
+		},
+		pledges: &PledgesDB{
+			db:   conn,
+			lock: l,
+		},
+		search: &SearchDB{
+			db:   conn,
+			lock: l,
+		},
+		apiTokens: &APITokensDB{
+			db:   conn,
+			lock: l,
+		},
+		webhooks: &WebhooksDB{
+			db:   conn,
+			lock: l,
+		},
+		hookDeliveries: &WebhookDeliveriesDB{
+			db:   conn,
+			lock: l,
+		},
+		outgoing: &OutgoingMessagesDB{
+			db:   conn,
+			lock: l,
+		},
+		groupChats: &GroupChatsDB{
+			db:   conn,
+			lock: l,
+		},
+		cart: &CartDB{
+			db:   conn,
+			lock: l,
+		},
+		checkoutGroups: &CheckoutGroupsDB{
+			db:   conn,
+			lock: l,
+		},
+		digitalAssets: &DigitalAssetsDB{
+			db:   conn,
+			lock: l,
+		},
+		licenseKeys: &LicenseKeysDB{
+			db:   conn,
+			lock: l,
+		},
+		orderHistory: &OrderHistoryDB{
+			db:   conn,
+			lock: l,
+		},
 		db:   conn,
 		lock: l,
 	}
 
+	// An encrypted database can't be read without the password so it's migrated once it's opened with it
+	if !sqliteDB.Config().IsEncrypted() {
+		if err := sqliteDB.migrate(dbPath, password); err != nil {
+			return nil, err
+		}
+		if err := sqliteDB.initSearch(); err != nil {
+			return nil, err
+		}
+	}
 	return sqliteDB, nil
 }
 
@@ -223,38 +307,109 @@ func (d *SQLiteDatastore) TxMetadata() repo.TxMetadata {
 	return d.txMetadata
 }
 
+func (d *SQLiteDatastore) TxAccounting() repo.TxAccounting {
+	return d.txAccounting
+}
+
+func (d *SQLiteDatastore) ExchangeRateHistory() repo.ExchangeRateHistory {
+	return d.exchangeRates
+}
+
 func (d *SQLiteDatastore) ModeratedStores() repo.ModeratedStores {
 	return d.moderatedStores
 }
 
+func (d *SQLiteDatastore) Bids() repo.Bids {
+	return d.bids
+}
+
+func (d *SQLiteDatastore) Pledges() repo.Pledges {
+	return d.pledges
+}
+
+func (d *SQLiteDatastore) Search() repo.Search {
+	return d.search
+}
+
+func (d *SQLiteDatastore) APITokens() repo.APITokens {
+	return d.apiTokens
+}
+
+func (d *SQLiteDatastore) Webhooks() repo.Webhooks {
+	return d.webhooks
+}
+
+func (d *SQLiteDatastore) WebhookDeliveries() repo.WebhookDeliveries {
+	return d.hookDeliveries
+}
+
+func (d *SQLiteDatastore) OutgoingMessages() repo.OutgoingMessages {
+	return d.outgoing
+}
+
+func (d *SQLiteDatastore) GroupChats() repo.GroupChats {
+	return d.groupChats
+}
+
+func (d *SQLiteDatastore) Cart() repo.Cart {
+	return d.cart
+}
+
+func (d *SQLiteDatastore) CheckoutGroups() repo.CheckoutGroups {
+	return d.checkoutGroups
+}
+
+func (d *SQLiteDatastore) DigitalAssets() repo.DigitalAssets {
+	return d.digitalAssets
+}
+
+func (d *SQLiteDatastore) LicenseKeys() repo.LicenseKeys {
+	return d.licenseKeys
+}
+
+func (d *SQLiteDatastore) OrderHistory() repo.OrderHistory {
+	return d.orderHistory
+}
+
 func (d *SQLiteDatastore) Copy(dbPath string, password string) error {
 	d.lock.Lock()
 	defer d.lock.Unlock()
 	var cp string
-	stmt := "select name from sqlite_master where type='table'"
+	stmt := "select name, sql from sqlite_master where type='table'"
 	rows, err := d.db.Query(stmt)
 	if err != nil {
 		log.Error(err)
 		return err
 	}
 	var tables []string
+	virtualTables := make(map[string]bool)
 	for rows.Next() {
-		var name string
-		if err := rows.Scan(&name); err != nil {
+		var name, def string
+		if err := rows.Scan(&name, &def); err != nil {
 			return err
 		}
+		if isVirtualTable(def) {
+			virtualTables[name] = true
+		}
 		tables = append(tables, name)
 	}
+	schema := "plaintext"
 	if password == "" {
 		cp = `attach database '` + dbPath + `' as plaintext key '';`
-		for _, name := range tables {
-			cp = cp + "insert into plaintext." + name + " select * from main." + name + ";"
-		}
 	} else {
+		schema = "encrypted"
 		cp = `attach database '` + dbPath + `' as encrypted key '` + password + `';`
-		for _, name := range tables {
-			cp = cp + "insert into encrypted." + name + " select * from main." + name + ";"
+	}
+	for _, name := range tables {
+		if isShadowTable(name, virtualTables) {
+			continue
 		}
+		if virtualTables[name] {
+			// Search tables kept up to date by triggers were filled as their source tables were copied
+			cp = cp + "insert into " + schema + "." + name + " select * from main." + name + " where not exists (select 1 from " + schema + "." + name + ");"
+			continue
+		}
+		cp = cp + "insert into " + schema + "." + name + " select * from main." + name + ";"
 	}
 
 	_, err = d.db.Exec(cp)
@@ -265,6 +420,22 @@ func (d *SQLiteDatastore) Copy(dbPath string, password string) error {
 	return nil
 }
 
+func isVirtualTable(def string) bool {
+	return strings.HasPrefix(strings.ToLower(def), "create virtual table")
+}
+
+// The tables SQLite creates to store the contents of a virtual table
+func isShadowTable(name string, virtualTables map[string]bool) bool {
+	for vt := range virtualTables {
+		for _, suffix := range []string{"_data", "_idx", "_content", "_docsize", "_config"} {
+			if name == vt+suffix {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func initDatabaseTables(db *sql.DB, password string) error {
 	var sqlStmt string
 	if password != "" {
@@ -284,24 +455,53 @@ func initDatabaseTables(db *sql.DB, password string) error {
 	create table txmetadata (txid text primary key not null, address text, memo text, orderID text, thumbnail text, canBumpFee integer);
 	create table inventory (slug text, variantIndex integer, count integer);
 	create index index_inventory on inventory (slug);
-	create table purchases (orderID text primary key not null, contract blob, state integer, read integer, timestamp integer, total integer, thumbnail text, vendorID text, vendorBlockchainID text, title text, shippingName text, shippingAddress text, paymentAddr text, funded integer, transactions blob);
+	create table purchases (orderID text primary key not null, contract blob, state integer, read integer, timestamp integer, total integer, thumbnail text, vendorID text, vendorBlockchainID text, title text, shippingName text, shippingAddress text, paymentAddr text, funded integer, transactions blob, exchangeRates blob);
 	create index index_purchases on purchases (paymentAddr);
-	create table sales (orderID text primary key not null, contract blob, state integer, read integer, timestamp integer, total integer, thumbnail text, buyerID text, buyerBlockchainID text, title text, shippingName text, shippingAddress text, paymentAddr text, funded integer, transactions blob);
+	create table sales (orderID text primary key not null, contract blob, state integer, read integer, timestamp integer, total integer, thumbnail text, buyerID text, buyerBlockchainID text, title text, shippingName text, shippingAddress text, paymentAddr text, funded integer, transactions blob, exchangeRates blob);
 	create index index_sales on sales (paymentAddr);
 	create table watchedscripts (scriptPubKey text primary key not null);
 	create table cases (caseID text primary key not null, buyerContract blob, vendorContract blob, buyerValidationErrors blob, vendorValidationErrors blob, buyerPayoutAddress text, vendorPayoutAddress text, buyerOutpoints blob, vendorOutpoints blob, state integer, read integer, timestamp integer, buyerOpened integer, claim text, disputeResolution blob);
 	create table chat (messageID text primary key not null, peerID text, subject text, message text, read integer, timestamp integer, outgoing integer);
 	create index index_chat on chat (peerID, subject, read, timestamp);
+	create table chatattachments (id text primary key not null, messageID text, filename text, mediaType text, size integer, thumbnail text, key blob);
+	create index index_chatattachments on chatattachments (messageID);
+	create table cart (id text primary key not null, vendorID text, listingHash text, quantity integer, options text, shippingName text, shippingService text, memo text, coupons text, added integer);
+	create table checkoutgroups (orderID text primary key not null, groupID text, vendorID text, paymentAddr text, amount integer, fundingTxid text, timestamp integer);
+	create index index_checkoutgroups on checkoutgroups (groupID);
+	create table digitalassets (slug text primary key not null, filename text, mediaType text, size integer, data blob, added integer);
+	create table licensekeys (slug text not null, licenseKey text not null, orderID text, added integer, primary key (slug, licenseKey));
+	create index index_licensekeys on licensekeys (orderID);
+	create table orderhistory (orderID text, action text, detail text, timestamp integer);
+	create index index_orderhistory on orderhistory (orderID);
 	create table notifications (serializedNotification blob, timestamp integer, read integer);
 	create table coupons (slug text, code text, hash text);
 	create index index_coupons on coupons (slug);
 	create table moderatedstores (peerID text primary key not null);
+	create table bids (orderID text primary key not null, contract blob, state integer, slug text, amount integer, timestamp integer, title text, thumbnail text, buyerID text, buyerBlockchainID text, vendorID text, outgoing integer);
+	create index index_bids on bids (slug, outgoing);
+	create table pledges (orderID text primary key not null, slug text, amount integer, timestamp integer, buyerID text, buyerBlockchainID text, funded integer, state integer);
+	create index index_pledges on pledges (slug);
+	create table txaccounting (txid text primary key not null, currency text, exchangeRate real, fee integer, timestamp integer);
+	create table exchangerates (currency text, rate real, timestamp integer);
+	create index index_exchangerates on exchangerates (currency, timestamp);
+	create table apitokens (name text primary key not null, hash text unique not null, scopes text, timestamp integer);
+	create table webhooks (id text primary key not null, url text, secret text, events text, timestamp integer);
+	create table webhookdeliveries (webhookID text, url text, event text, payload blob, status text, attempts integer, responseCode integer, error text, nextAttempt integer, timestamp integer);
+	create index index_webhookdeliveries on webhookdeliveries (status, nextAttempt);
+	create table outgoingmessages (id text primary key not null, peerID text, messageType text, reference text, message blob, state text, pointerID text, attempts integer, error text, timestamp integer, updated integer);
+	create index index_outgoingmessages on outgoingmessages (peerID, state);
+	create index index_outgoingmessages_pointer on outgoingmessages (pointerID);
+	create index index_outgoingmessages_reference on outgoingmessages (reference);
+	create table groupchats (groupID text primary key not null, creator text, subject text, members text, signedMembers blob, updated integer);
+	create table groupchatmessages (messageID text primary key not null, groupID text, peerID text, message text, read integer, outgoing integer, timestamp integer);
+	create index index_groupchatmessages on groupchatmessages (groupID, timestamp);
 	`
 	_, err := db.Exec(sqlStmt)
 	if err != nil {
 		return err
 	}
-	return nil
+	_, err = createSearchTables(db)
+	return err
 }
 
 type ConfigDB struct {
@@ -335,6 +535,11 @@ func (c *ConfigDB) Init(mnemonic string, identityKey []byte, password string) er
 		tx.Rollback()
 		return err
 	}
+	_, err = stmt.Exec("schema_version", LatestSchemaVersion())
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
 	tx.Commit()
 	return nil
 }
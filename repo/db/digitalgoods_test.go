@@ -0,0 +1,96 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+func TestDigitalAssetsDB(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	adb := DigitalAssetsDB{db: conn}
+	asset := repo.DigitalAsset{
+		Slug:      "ebook",
+		Filename:  "book.pdf",
+		MediaType: "application/pdf",
+		Size:      5,
+		Data:      []byte("hello"),
+		Added:     time.Unix(1500000000, 0),
+	}
+	if err := adb.Put(asset); err != nil {
+		t.Fatal(err)
+	}
+	ret, err := adb.Get("ebook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.Filename != "book.pdf" || ret.MediaType != "application/pdf" || string(ret.Data) != "hello" || !ret.Added.Equal(asset.Added) {
+		t.Error("Returned wrong digital asset")
+	}
+	all, err := adb.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || all[0].Slug != "ebook" || all[0].Data != nil {
+		t.Error("GetAll returned the wrong assets")
+	}
+	if err := adb.Delete("ebook"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := adb.Get("ebook"); err != repo.ErrDigitalAssetNotFound {
+		t.Error("Digital asset was not deleted")
+	}
+	if err := adb.Delete("ebook"); err != repo.ErrDigitalAssetNotFound {
+		t.Error("Deleting a missing asset didn't return ErrDigitalAssetNotFound")
+	}
+}
+
+func TestLicenseKeysDB(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	ldb := LicenseKeysDB{db: conn}
+	if err := ldb.Put("game", []string{"AAAA", "BBBB", "AAAA"}); err != nil {
+		t.Fatal(err)
+	}
+	key, err := ldb.Assign("game", "order1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "AAAA" {
+		t.Errorf("Expected the oldest key, got %s", key)
+	}
+	key, err = ldb.Assign("game", "order1")
+	if err != nil || key != "AAAA" {
+		t.Error("Order wasn't given the same key again")
+	}
+	key, err = ldb.Assign("game", "order2")
+	if err != nil || key != "BBBB" {
+		t.Error("Second order wasn't given the next key")
+	}
+	if _, err := ldb.Assign("game", "order3"); err != repo.ErrNoLicenseKeys {
+		t.Error("Assigned a key from an empty pool")
+	}
+	if err := ldb.Put("game", []string{"CCCC"}); err != nil {
+		t.Fatal(err)
+	}
+	counts, err := ldb.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(counts) != 1 || counts["game"] != 1 {
+		t.Errorf("Unexpected unused key counts %v", counts)
+	}
+	if err := ldb.DeleteUnused("game"); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := ldb.Get("game")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || keys[0].OrderId != "order1" || keys[1].OrderId != "order2" {
+		t.Error("Deleted keys which had been handed out")
+	}
+}
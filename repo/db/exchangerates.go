@@ -0,0 +1,69 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+type ExchangeRateHistoryDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (e *ExchangeRateHistoryDB) Put(rates map[string]float64, timestamp time.Time) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	tx, err := e.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert into exchangerates(currency, rate, timestamp) values(?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for currency, rate := range rates {
+		_, err = stmt.Exec(strings.ToUpper(currency), rate, int(timestamp.Unix()))
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (e *ExchangeRateHistoryDB) Get(currencyCode string, from, to time.Time) ([]repo.ExchangeRateSample, error) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	ret := []repo.ExchangeRateSample{}
+	stm := "select currency, rate, timestamp from exchangerates where currency=?"
+	args := []interface{}{strings.ToUpper(currencyCode)}
+	if !from.IsZero() {
+		stm += " and timestamp>=?"
+		args = append(args, int(from.Unix()))
+	}
+	if !to.IsZero() {
+		stm += " and timestamp<=?"
+		args = append(args, int(to.Unix()))
+	}
+	rows, err := e.db.Query(stm+" order by timestamp, rowid", args...)
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var sample repo.ExchangeRateSample
+		var timestamp int
+		if err := rows.Scan(&sample.Currency, &sample.Rate, &timestamp); err != nil {
+			return ret, err
+		}
+		sample.Timestamp = time.Unix(int64(timestamp), 0)
+		ret = append(ret, sample)
+	}
+	return ret, rows.Err()
+}
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestExchangeRateHistoryDB_Get(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	erdb := ExchangeRateHistoryDB{db: conn}
+	start := time.Unix(1500000000, 0)
+	for i := 0; i < 3; i++ {
+		err := erdb.Put(map[string]float64{"USD": 2500 + float64(i), "eur": 2100 + float64(i)}, start.Add(time.Duration(i)*time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	samples, err := erdb.Get("usd", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 3 || samples[0].Rate != 2500 || samples[2].Rate != 2502 || samples[0].Currency != "USD" {
+		t.Error("Returned wrong samples")
+	}
+	if !samples[1].Timestamp.Equal(start.Add(time.Hour)) {
+		t.Error("Returned wrong timestamp")
+	}
+	samples, err = erdb.Get("EUR", start.Add(time.Hour), start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 1 || samples[0].Rate != 2101 {
+		t.Error("Returned wrong samples for the time range")
+	}
+	samples, err = erdb.Get("BTC", time.Time{}, time.Time{})
+	if err != nil || len(samples) != 0 {
+		t.Error("Returned samples for an unknown currency")
+	}
+}
@@ -0,0 +1,147 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+type GroupChatsDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (g *GroupChatsDB) Put(group repo.GroupChat) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	members, err := json.Marshal(group.Members)
+	if err != nil {
+		return err
+	}
+	_, err = g.db.Exec("insert or replace into groupchats(groupID, creator, subject, members, signedMembers, updated) values(?,?,?,?,?,?)",
+		group.GroupId, group.Creator, group.Subject, string(members), group.SignedMembers, int(group.Updated.Unix()))
+	return err
+}
+
+func (g *GroupChatsDB) Get(groupId string) (repo.GroupChat, error) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return scanGroupChat(g.db.QueryRow("select groupID, creator, subject, members, signedMembers, updated from groupchats where groupID=?", groupId))
+}
+
+func (g *GroupChatsDB) GetAll() ([]repo.GroupChat, error) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	ret := []repo.GroupChat{}
+	rows, err := g.db.Query("select groupID, creator, subject, members, signedMembers, updated from groupchats order by max(updated, coalesce((select max(timestamp) from groupchatmessages where groupchatmessages.groupID=groupchats.groupID), 0)) desc, rowid desc")
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		group, err := scanGroupChat(rows)
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, group)
+	}
+	return ret, rows.Err()
+}
+
+func (g *GroupChatsDB) Delete(groupId string) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	tx, err := g.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("delete from groupchatmessages where groupID=?", groupId); err != nil {
+		tx.Rollback()
+		return err
+	}
+	res, err := tx.Exec("delete from groupchats where groupID=?", groupId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		tx.Rollback()
+		return repo.ErrGroupChatNotFound
+	}
+	return tx.Commit()
+}
+
+func (g *GroupChatsDB) PutMessage(msg repo.GroupChatMessage) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	readInt := 0
+	if msg.Read {
+		readInt = 1
+	}
+	outgoingInt := 0
+	if msg.Outgoing {
+		outgoingInt = 1
+	}
+	_, err := g.db.Exec("insert or ignore into groupchatmessages(messageID, groupID, peerID, message, read, outgoing, timestamp) values(?,?,?,?,?,?,?)",
+		msg.MessageId, msg.GroupId, msg.PeerId, msg.Message, readInt, outgoingInt, int(msg.Timestamp.Unix()))
+	return err
+}
+
+func (g *GroupChatsDB) GetMessages(groupId, offsetId string, limit int) ([]repo.GroupChatMessage, error) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	stm := "select messageID, groupID, peerID, message, read, outgoing, timestamp from groupchatmessages where groupID=?"
+	args := []interface{}{groupId}
+	if offsetId != "" {
+		stm += " and (timestamp<(select timestamp from groupchatmessages where messageID=?) or (timestamp=(select timestamp from groupchatmessages where messageID=?) and rowid<(select rowid from groupchatmessages where messageID=?)))"
+		args = append(args, offsetId, offsetId, offsetId)
+	}
+	rows, err := g.db.Query(stm+" order by timestamp desc, rowid desc limit "+strconv.Itoa(limit), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ret := []repo.GroupChatMessage{}
+	for rows.Next() {
+		var msg repo.GroupChatMessage
+		var readInt, outgoingInt, timestamp int
+		if err := rows.Scan(&msg.MessageId, &msg.GroupId, &msg.PeerId, &msg.Message, &readInt, &outgoingInt, &timestamp); err != nil {
+			return ret, err
+		}
+		msg.Read = readInt == 1
+		msg.Outgoing = outgoingInt == 1
+		msg.Timestamp = time.Unix(int64(timestamp), 0)
+		ret = append(ret, msg)
+	}
+	return ret, rows.Err()
+}
+
+func (g *GroupChatsDB) MarkAsRead(groupId string) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	_, err := g.db.Exec("update groupchatmessages set read=1 where groupID=? and read=0", groupId)
+	return err
+}
+
+func scanGroupChat(row interface {
+	Scan(dest ...interface{}) error
+}) (repo.GroupChat, error) {
+	var group repo.GroupChat
+	var members string
+	var updated int
+	err := row.Scan(&group.GroupId, &group.Creator, &group.Subject, &members, &group.SignedMembers, &updated)
+	if err == sql.ErrNoRows {
+		return group, repo.ErrGroupChatNotFound
+	} else if err != nil {
+		return group, err
+	}
+	if err := json.Unmarshal([]byte(members), &group.Members); err != nil {
+		return group, err
+	}
+	group.Updated = time.Unix(int64(updated), 0)
+	return group, nil
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+func TestGroupChatsDB(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	gdb := GroupChatsDB{db: conn}
+	now := time.Unix(1500000000, 0)
+	first := repo.GroupChat{GroupId: "QmGroup1", Creator: "QmCreator", Subject: "dispute", Members: []string{"QmCreator", "QmBuyer"}, SignedMembers: []byte{1, 2}, Updated: now}
+	second := repo.GroupChat{GroupId: "QmGroup2", Creator: "QmCreator", Members: []string{"QmCreator"}, Updated: now.Add(time.Minute)}
+	for _, g := range []repo.GroupChat{first, second} {
+		if err := gdb.Put(g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ret, err := gdb.Get("QmGroup1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.Creator != "QmCreator" || ret.Subject != "dispute" || len(ret.Members) != 2 || ret.Members[1] != "QmBuyer" || len(ret.SignedMembers) != 2 || !ret.Updated.Equal(now) {
+		t.Error("Returned wrong group")
+	}
+	if _, err := gdb.Get("QmGroup3"); err != repo.ErrGroupChatNotFound {
+		t.Error("Getting an unknown group returned", err)
+	}
+
+	// A message makes the first group the most recently active
+	for i, id := range []string{"a", "b", "c"} {
+		msg := repo.GroupChatMessage{MessageId: id, GroupId: "QmGroup1", PeerId: "QmBuyer", Message: "hello " + id, Timestamp: now.Add(time.Hour + time.Duration(i)*time.Second)}
+		if err := gdb.PutMessage(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := gdb.PutMessage(repo.GroupChatMessage{MessageId: "a", GroupId: "QmGroup1", Message: "changed"}); err != nil {
+		t.Error(err)
+	}
+	groups, err := gdb.GetAll()
+	if err != nil || len(groups) != 2 || groups[0].GroupId != "QmGroup1" {
+		t.Error("Returned groups in the wrong order", err)
+	}
+
+	msgs, err := gdb.GetMessages("QmGroup1", "", -1)
+	if err != nil || len(msgs) != 3 || msgs[0].MessageId != "c" || msgs[2].Message != "hello a" || msgs[0].Read {
+		t.Error("Returned wrong messages", err)
+	}
+	msgs, err = gdb.GetMessages("QmGroup1", "c", 1)
+	if err != nil || len(msgs) != 1 || msgs[0].MessageId != "b" {
+		t.Error("Returned wrong messages after the offset", err)
+	}
+	if err := gdb.MarkAsRead("QmGroup1"); err != nil {
+		t.Error(err)
+	}
+	msgs, _ = gdb.GetMessages("QmGroup1", "", -1)
+	for _, msg := range msgs {
+		if !msg.Read {
+			t.Error("Message was not marked as read")
+		}
+	}
+
+	if err := gdb.Delete("QmGroup1"); err != nil {
+		t.Error(err)
+	}
+	if msgs, _ := gdb.GetMessages("QmGroup1", "", -1); len(msgs) != 0 {
+		t.Error("Messages were not deleted with their group")
+	}
+	if err := gdb.Delete("QmGroup1"); err != repo.ErrGroupChatNotFound {
+		t.Error("Deleting an unknown group returned", err)
+	}
+}
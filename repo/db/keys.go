@@ -11,8 +11,9 @@ import (
 )
 
 type KeysDB struct {
-	db   *sql.DB
-	lock sync.RWMutex
+	db    *sql.DB
+	table string
+	lock  sync.RWMutex
 }
 
 func (k *KeysDB) Put(scriptPubKey []byte, keyPath spvwallet.KeyPath) error {
@@ -22,7 +23,7 @@ func (k *KeysDB) Put(scriptPubKey []byte, keyPath spvwallet.KeyPath) error {
 	if err != nil {
 		return err
 	}
-	stmt, _ := tx.Prepare("insert into keys(scriptPubKey, purpose, keyIndex, used) values(?,?,?,?)")
+	stmt, _ := tx.Prepare("insert into " + k.table + "(scriptPubKey, purpose, keyIndex, used) values(?,?,?,?)")
 	defer stmt.Close()
 	_, err = stmt.Exec(hex.EncodeToString(scriptPubKey), int(keyPath.Purpose), keyPath.Index, 0)
 	if err != nil {
@@ -40,7 +41,7 @@ func (k *KeysDB) ImportKey(scriptPubKey []byte, key *btcec.PrivateKey) error {
 	if err != nil {
 		return err
 	}
-	stmt, _ := tx.Prepare("insert into keys(scriptPubKey, purpose, used, key) values(?,?,?,?)")
+	stmt, _ := tx.Prepare("insert into " + k.table + "(scriptPubKey, purpose, used, key) values(?,?,?,?)")
 	defer stmt.Close()
 	_, err = stmt.Exec(hex.EncodeToString(scriptPubKey), -1, 0, hex.EncodeToString(key.Serialize()))
 	if err != nil {
@@ -58,7 +59,7 @@ func (k *KeysDB) MarkKeyAsUsed(scriptPubKey []byte) error {
 	if err != nil {
 		return err
 	}
-	stmt, _ := tx.Prepare("update keys set used=1 where scriptPubKey=?")
+	stmt, _ := tx.Prepare("update " + k.table + " set used=1 where scriptPubKey=?")
 
 	defer stmt.Close()
 	_, err = stmt.Exec(hex.EncodeToString(scriptPubKey))
@@ -74,7 +75,7 @@ func (k *KeysDB) GetLastKeyIndex(purpose spvwallet.KeyPurpose) (int, bool, error
 	k.lock.RLock()
 	defer k.lock.RUnlock()
 
-	stm := "select keyIndex, used from keys where purpose=" + strconv.Itoa(int(purpose)) + " order by rowid desc limit 1"
+	stm := "select keyIndex, used from " + k.table + " where purpose=" + strconv.Itoa(int(purpose)) + " order by rowid desc limit 1"
 	stmt, err := k.db.Prepare(stm)
 	defer stmt.Close()
 	var index int
@@ -96,7 +97,7 @@ func (k *KeysDB) GetPathForScript(scriptPubKey []byte) (spvwallet.KeyPath, error
 	k.lock.RLock()
 	defer k.lock.RUnlock()
 
-	stmt, err := k.db.Prepare("select purpose, keyIndex from keys where scriptPubKey=?")
+	stmt, err := k.db.Prepare("select purpose, keyIndex from " + k.table + " where scriptPubKey=?")
 	if err != nil {
 		return spvwallet.KeyPath{}, err
 	}
@@ -118,7 +119,7 @@ func (k *KeysDB) GetKeyForScript(scriptPubKey []byte) (*btcec.PrivateKey, error)
 	k.lock.Lock()
 	defer k.lock.Unlock()
 
-	stmt, err := k.db.Prepare("select key from keys where scriptPubKey=? and purpose=-1")
+	stmt, err := k.db.Prepare("select key from " + k.table + " where scriptPubKey=? and purpose=-1")
 	if err != nil {
 		return nil, err
 	}
@@ -140,7 +141,7 @@ func (k *KeysDB) GetUnused(purpose spvwallet.KeyPurpose) ([]int, error) {
 	k.lock.RLock()
 	defer k.lock.RUnlock()
 	var ret []int
-	stm := "select keyIndex from keys where purpose=" + strconv.Itoa(int(purpose)) + " and used=0 order by rowid asc"
+	stm := "select keyIndex from " + k.table + " where purpose=" + strconv.Itoa(int(purpose)) + " and used=0 order by rowid asc"
 	rows, err := k.db.Query(stm)
 	if err != nil {
 		return ret, err
@@ -161,7 +162,7 @@ func (k *KeysDB) GetAll() ([]spvwallet.KeyPath, error) {
 	k.lock.RLock()
 	defer k.lock.RUnlock()
 	var ret []spvwallet.KeyPath
-	stm := "select purpose, keyIndex from keys"
+	stm := "select purpose, keyIndex from " + k.table
 	rows, err := k.db.Query(stm)
 	if err != nil {
 		return ret, err
@@ -187,7 +188,7 @@ func (k *KeysDB) GetLookaheadWindows() map[spvwallet.KeyPurpose]int {
 	defer k.lock.RUnlock()
 	windows := make(map[spvwallet.KeyPurpose]int)
 	for i := 0; i < 2; i++ {
-		stm := "select used from keys where purpose=" + strconv.Itoa(i) + " order by rowid desc"
+		stm := "select used from " + k.table + " where purpose=" + strconv.Itoa(i) + " order by rowid desc"
 		rows, err := k.db.Query(stm)
 		if err != nil {
 			continue
@@ -16,7 +16,8 @@ func init() {
 	conn, _ := sql.Open("sqlite3", ":memory:")
 	initDatabaseTables(conn, "")
 	kdb = KeysDB{
-		db: conn,
+		db:    conn,
+		table: "keys",
 	}
 }
 
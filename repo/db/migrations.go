@@ -0,0 +1,278 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+/* A Migration upgrades the schema of an existing database by one version. The
+   statements in Up are run in a single transaction. New databases are created by
+   initDatabaseTables with the latest schema so any change made here must be made
+   there as well. */
+type Migration struct {
+	Description string
+	Up          string
+}
+
+// Migrations in the order they are applied. Migration n upgrades the schema from version n to n+1.
+var migrations = []Migration{
+	{
+		Description: "Add the bids and pledges tables",
+		Up: `
+		create table if not exists bids (orderID text primary key not null, contract blob, state integer, slug text, amount integer, timestamp integer, title text, thumbnail text, buyerID text, buyerBlockchainID text, vendorID text, outgoing integer);
+		create index if not exists index_bids on bids (slug, outgoing);
+		create table if not exists pledges (orderID text primary key not null, slug text, amount integer, timestamp integer, buyerID text, buyerBlockchainID text, funded integer, state integer);
+		create index if not exists index_pledges on pledges (slug);
+		`,
+	},
+	{
+		// The search tables are created by initSearch as they need FTS5, which SQLite may have been built without
+		Description: "Add the full-text search tables",
+		Up:          "",
+	},
+	{
+		Description: "Add the txaccounting table",
+		Up: `
+		create table if not exists txaccounting (txid text primary key not null, currency text, exchangeRate real, fee integer, timestamp integer);
+		`,
+	},
+	{
+		Description: "Add the exchange rate history and the rates used to price orders",
+		Up: `
+		alter table purchases add column exchangeRates blob;
+		alter table sales add column exchangeRates blob;
+		create table if not exists exchangerates (currency text, rate real, timestamp integer);
+		create index if not exists index_exchangerates on exchangerates (currency, timestamp);
+		`,
+	},
+	{
+		Description: "Add the apitokens table",
+		Up: `
+		create table if not exists apitokens (name text primary key not null, hash text unique not null, scopes text, timestamp integer);
+		`,
+	},
+	{
+		Description: "Add the webhooks and webhookdeliveries tables",
+		Up: `
+		create table if not exists webhooks (id text primary key not null, url text, secret text, events text, timestamp integer);
+		create table if not exists webhookdeliveries (webhookID text, url text, event text, payload blob, status text, attempts integer, responseCode integer, error text, nextAttempt integer, timestamp integer);
+		create index if not exists index_webhookdeliveries on webhookdeliveries (status, nextAttempt);
+		`,
+	},
+	{
+		Description: "Add the outgoingmessages table",
+		Up: `
+		create table if not exists outgoingmessages (id text primary key not null, peerID text, messageType text, reference text, message blob, state text, pointerID text, attempts integer, error text, timestamp integer, updated integer);
+		create index if not exists index_outgoingmessages on outgoingmessages (peerID, state);
+		create index if not exists index_outgoingmessages_pointer on outgoingmessages (pointerID);
+		create index if not exists index_outgoingmessages_reference on outgoingmessages (reference);
+		`,
+	},
+	{
+		Description: "Add the group chat tables",
+		Up: `
+		create table if not exists groupchats (groupID text primary key not null, creator text, subject text, members text, signedMembers blob, updated integer);
+		create table if not exists groupchatmessages (messageID text primary key not null, groupID text, peerID text, message text, read integer, outgoing integer, timestamp integer);
+		create index if not exists index_groupchatmessages on groupchatmessages (groupID, timestamp);
+		`,
+	},
+	{
+		Description: "Add the chatattachments table",
+		Up: `
+		create table if not exists chatattachments (id text primary key not null, messageID text, filename text, mediaType text, size integer, thumbnail text, key blob);
+		create index if not exists index_chatattachments on chatattachments (messageID);
+		`,
+	},
+	{
+		Description: "Add the cart and checkoutgroups tables",
+		Up: `
+		create table if not exists cart (id text primary key not null, vendorID text, listingHash text, quantity integer, options text, shippingName text, shippingService text, memo text, coupons text, added integer);
+		create table if not exists checkoutgroups (orderID text primary key not null, groupID text, vendorID text, paymentAddr text, amount integer, fundingTxid text, timestamp integer);
+		create index if not exists index_checkoutgroups on checkoutgroups (groupID);
+		`,
+	},
+	{
+		Description: "Add the digitalassets and licensekeys tables",
+		Up: `
+		create table if not exists digitalassets (slug text primary key not null, filename text, mediaType text, size integer, data blob, added integer);
+		create table if not exists licensekeys (slug text not null, licenseKey text not null, orderID text, added integer, primary key (slug, licenseKey));
+		create index if not exists index_licensekeys on licensekeys (orderID);
+		`,
+	},
+	{
+		Description: "Add the orderhistory table",
+		Up: `
+		create table if not exists orderhistory (orderID text, action text, detail text, timestamp integer);
+		create index if not exists index_orderhistory on orderhistory (orderID);
+		`,
+	},
+}
+
+// The schema version of a newly created database
+func LatestSchemaVersion() int {
+	return len(migrations)
+}
+
+/* Return the migrations which have not yet been applied to the database in the
+   repo along with its current schema version. The database is not modified. */
+func PendingMigrations(repoPath, password string, testnet bool) ([]Migration, int, error) {
+	dbPath := databasePath(repoPath, testnet)
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, 0, err
+	}
+	conn, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer conn.Close()
+	conn.SetMaxOpenConns(1)
+	if password != "" {
+		conn.Exec("pragma key='" + password + "';")
+	}
+	if _, err := conn.Exec("select count(*) from sqlite_master;"); err != nil {
+		return nil, 0, errors.New("could not decrypt the database")
+	}
+	initialized, err := isInitialized(conn)
+	if err != nil || !initialized {
+		return nil, 0, err
+	}
+	version, err := schemaVersion(conn)
+	if err != nil {
+		return nil, 0, err
+	}
+	if version > len(migrations) {
+		return nil, version, nil
+	}
+	return migrations[version:], version, nil
+}
+
+func databasePath(repoPath string, testnet bool) string {
+	if testnet {
+		return path.Join(repoPath, "datastore", "testnet.db")
+	}
+	return path.Join(repoPath, "datastore", "mainnet.db")
+}
+
+// A database is initialized once the config table has been created
+func isInitialized(db *sql.DB) (bool, error) {
+	var count int
+	err := db.QueryRow("select count(*) from sqlite_master where type='table' and name='config'").Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Databases created before versioning was added have no schema_version row and are at version 0
+func schemaVersion(db *sql.DB) (int, error) {
+	var version int
+	err := db.QueryRow("select value from config where key=?", "schema_version").Scan(&version)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+/* Apply any pending migrations. Before the first one is applied the database is
+   copied to a backup file next to it so it can be restored if something goes wrong. */
+func (d *SQLiteDatastore) migrate(dbPath, password string) error {
+	initialized, err := isInitialized(d.db)
+	if err != nil || !initialized {
+		return err
+	}
+	version, err := schemaVersion(d.db)
+	if err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("Database schema version %d is newer than this version of openbazaar-go supports", version)
+	}
+	if version == len(migrations) {
+		return nil
+	}
+
+	backupPath := strings.TrimSuffix(dbPath, ".db") + fmt.Sprintf(".v%d.backup.db", version)
+	if err := d.backup(backupPath, password); err != nil {
+		return fmt.Errorf("Failed to back up the database before migrating: %s", err)
+	}
+	log.Noticef("Backed up database to %s", backupPath)
+
+	for i := version; i < len(migrations); i++ {
+		log.Noticef("Migrating database to version %d: %s", i+1, migrations[i].Description)
+		if err := d.applyMigration(migrations[i], i+1); err != nil {
+			return fmt.Errorf("Migration to version %d failed: %s", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (d *SQLiteDatastore) applyMigration(m Migration, version int) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(m.Up); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec("insert or replace into config(key, value) values(?,?)", "schema_version", version); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Create a database at backupPath with the current schema and copy everything into it
+func (d *SQLiteDatastore) backup(backupPath, password string) error {
+	os.Remove(backupPath)
+	rows, err := d.db.Query("select name, sql from sqlite_master where sql is not null")
+	if err != nil {
+		return err
+	}
+	var names, schema []string
+	virtualTables := make(map[string]bool)
+	for rows.Next() {
+		var name, stmt string
+		if err := rows.Scan(&name, &stmt); err != nil {
+			rows.Close()
+			return err
+		}
+		if isVirtualTable(stmt) {
+			virtualTables[name] = true
+		}
+		names = append(names, name)
+		schema = append(schema, stmt)
+	}
+	rows.Close()
+	// Shadow tables are created along with their virtual table
+	for i := len(schema) - 1; i >= 0; i-- {
+		if isShadowTable(names[i], virtualTables) {
+			schema = append(schema[:i], schema[i+1:]...)
+		}
+	}
+
+	conn, err := sql.Open("sqlite3", backupPath)
+	if err != nil {
+		return err
+	}
+	conn.SetMaxOpenConns(1)
+	sqlStmt := ""
+	if password != "" {
+		sqlStmt = "PRAGMA key = '" + password + "';"
+	}
+	sqlStmt += strings.Join(schema, ";\n") + ";"
+	_, err = conn.Exec(sqlStmt)
+	conn.Close()
+	if err != nil {
+		return err
+	}
+	return d.Copy(backupPath, password)
+}
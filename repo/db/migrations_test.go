@@ -0,0 +1,140 @@
+package db
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+func TestInitSetsSchemaVersion(t *testing.T) {
+	version, err := schemaVersion(testDB.db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != LatestSchemaVersion() {
+		t.Errorf("Expected schema version %d got %d", LatestSchemaVersion(), version)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	repoPath, err := ioutil.TempDir("", "migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(repoPath)
+	os.MkdirAll(path.Join(repoPath, "datastore"), os.ModePerm)
+
+	// Set up a database as it was before schema versioning
+	conn, err := sql.Open("sqlite3", databasePath(repoPath, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := initDatabaseTables(conn, ""); err != nil {
+		t.Fatal(err)
+	}
+	_, err = conn.Exec("drop table bids; drop table pledges; insert into config(key, value) values('mnemonic', 'Mnemonic Passphrase'); insert into followers(peerID) values('abc');")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{"drop trigger search_purchases_replace", "drop trigger search_purchases_insert", "drop trigger search_purchases_update", "drop trigger search_purchases_delete",
+		"drop trigger search_sales_replace", "drop trigger search_sales_insert", "drop trigger search_sales_update", "drop trigger search_sales_delete",
+		"drop trigger search_chat_insert", "drop trigger search_chat_delete",
+		"drop table search_listings", "drop table search_purchases", "drop table search_sales", "drop table search_chat",
+		"drop table purchases", "create table purchases (orderID text primary key not null, contract blob, state integer, read integer, timestamp integer, total integer, thumbnail text, vendorID text, vendorBlockchainID text, title text, shippingName text, shippingAddress text, paymentAddr text, funded integer, transactions blob)",
+		"drop table sales", "create table sales (orderID text primary key not null, contract blob, state integer, read integer, timestamp integer, total integer, thumbnail text, buyerID text, buyerBlockchainID text, title text, shippingName text, shippingAddress text, paymentAddr text, funded integer, transactions blob)",
+		"drop table exchangerates", "drop table apitokens", "drop table webhooks", "drop table webhookdeliveries", "drop table outgoingmessages", "drop table groupchats", "drop table groupchatmessages", "drop table chatattachments", "drop table cart", "drop table checkoutgroups", "drop table digitalassets", "drop table licensekeys", "drop table orderhistory"} {
+		if _, err := conn.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = conn.Exec("insert into chat(messageID, peerID, subject, message, read, timestamp, outgoing) values('1', 'abc', '', 'hello world', 0, 0, 0);")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	pending, version, err := PendingMigrations(repoPath, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 0 || len(pending) != LatestSchemaVersion() {
+		t.Errorf("Expected %d pending migrations from version 0 got %d from version %d", LatestSchemaVersion(), len(pending), version)
+	}
+
+	sqliteDB, err := Create(repoPath, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqliteDB.Close()
+	version, err = schemaVersion(sqliteDB.db)
+	if err != nil || version != LatestSchemaVersion() {
+		t.Errorf("Expected schema version %d got %d", LatestSchemaVersion(), version)
+	}
+	if _, err := sqliteDB.Bids().GetOutgoing("", -1); err != nil {
+		t.Error("Bids table was not created", err)
+	}
+	results, _, err := sqliteDB.Search().Query(repo.SearchQuery{Terms: []string{"hello"}, Limit: -1})
+	if err != nil || len(results) != 1 || results[0].Id != "1" {
+		t.Error("Existing chat messages were not indexed", err)
+	}
+	if err := sqliteDB.ExchangeRateHistory().Put(map[string]float64{"USD": 2500}, time.Now()); err != nil {
+		t.Error("Exchange rates table was not created", err)
+	}
+	if err := sqliteDB.Sales().PutExchangeRates("1", map[string]float64{"USD": 2500}); err != nil {
+		t.Error("Exchange rates column was not added to sales", err)
+	}
+	if _, err := sqliteDB.APITokens().GetAll(); err != nil {
+		t.Error("API tokens table was not created", err)
+	}
+	if _, _, err := sqliteDB.WebhookDeliveries().GetAll("", 0, -1); err != nil {
+		t.Error("Webhook deliveries table was not created", err)
+	}
+	if _, _, err := sqliteDB.OutgoingMessages().GetAll("", "", 0, -1); err != nil {
+		t.Error("Outgoing messages table was not created", err)
+	}
+	if _, err := sqliteDB.GroupChats().GetAll(); err != nil {
+		t.Error("Group chat tables were not created", err)
+	}
+	if _, err := sqliteDB.Chat().GetAttachment("QmNoSuchFile"); err != repo.ErrChatAttachmentNotFound {
+		t.Error("Chat attachments table was not created", err)
+	}
+	if _, err := sqliteDB.Cart().GetAll(); err != nil {
+		t.Error("Cart table was not created", err)
+	}
+	if _, err := sqliteDB.CheckoutGroups().Get("NoSuchGroup"); err != repo.ErrCheckoutGroupNotFound {
+		t.Error("Checkout groups table was not created", err)
+	}
+	if _, err := sqliteDB.DigitalAssets().Get("no-such-listing"); err != repo.ErrDigitalAssetNotFound {
+		t.Error("Digital assets table was not created", err)
+	}
+	if _, err := sqliteDB.LicenseKeys().GetAll(); err != nil {
+		t.Error("License keys table was not created", err)
+	}
+	if _, err := sqliteDB.OrderHistory().Get("QmNoSuchOrder"); err != nil {
+		t.Error("Order history table was not created", err)
+	}
+	pending, _, err = PendingMigrations(repoPath, "", false)
+	if err != nil || len(pending) != 0 {
+		t.Error("Migrations still pending after Create")
+	}
+
+	backup, err := sql.Open("sqlite3", path.Join(repoPath, "datastore", "mainnet.v0.backup.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backup.Close()
+	var peerID string
+	if err := backup.QueryRow("select peerID from followers").Scan(&peerID); err != nil || peerID != "abc" {
+		t.Error("Backup is missing data", err)
+	}
+	var count int
+	backup.QueryRow("select count(*) from sqlite_master where type='table' and name='bids'").Scan(&count)
+	if count != 0 {
+		t.Error("Backup should have the schema from before the migration")
+	}
+}
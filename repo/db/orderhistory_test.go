@@ -0,0 +1,40 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+func TestOrderHistoryDB(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	hdb := OrderHistoryDB{db: conn}
+	now := time.Unix(1500000000, 0)
+	events := []repo.OrderEvent{
+		{OrderId: "order1", Action: repo.OrderActionConfirmed, Detail: "Payment is within the limit", Timestamp: now},
+		{OrderId: "order2", Action: repo.OrderActionRejected, Timestamp: now},
+		{OrderId: "order1", Action: repo.OrderActionFulfilled, Timestamp: now},
+	}
+	for _, e := range events {
+		if err := hdb.Put(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	history, err := hdb.Get("order1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].Action != repo.OrderActionConfirmed || history[1].Action != repo.OrderActionFulfilled {
+		t.Error("Returned the wrong history")
+	}
+	if history[0].Detail != "Payment is within the limit" || !history[0].Timestamp.Equal(now) || history[0].OrderId != "order1" {
+		t.Error("Event was not saved correctly")
+	}
+	history, err = hdb.Get("order3")
+	if err != nil || len(history) != 0 {
+		t.Error("Returned history for an unknown order")
+	}
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"strings"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+// Build the where and order by clauses selecting the purchases or sales matching the query
+func orderQueryClauses(query repo.OrderQuery) (string, string, []interface{}) {
+	var filters []string
+	var args []interface{}
+	if len(query.States) > 0 {
+		var placeholders []string
+		for _, state := range query.States {
+			placeholders = append(placeholders, "?")
+			args = append(args, int(state))
+		}
+		filters = append(filters, "state in ("+strings.Join(placeholders, ",")+")")
+	}
+	if query.Funded != nil {
+		if *query.Funded {
+			filters = append(filters, "funded=1")
+		} else {
+			filters = append(filters, "(funded is null or funded=0)")
+		}
+	}
+	if query.Read != nil {
+		if *query.Read {
+			filters = append(filters, "read=1")
+		} else {
+			filters = append(filters, "read=0")
+		}
+	}
+	if !query.From.IsZero() {
+		filters = append(filters, "timestamp>=?")
+		args = append(args, int(query.From.Unix()))
+	}
+	if !query.To.IsZero() {
+		filters = append(filters, "timestamp<=?")
+		args = append(args, int(query.To.Unix()))
+	}
+	var where string
+	if len(filters) > 0 {
+		where = " where " + strings.Join(filters, " and ")
+	}
+
+	direction := " desc"
+	if query.Ascending {
+		direction = " asc"
+	}
+	orderBy := " order by rowid"
+	switch query.SortBy {
+	case repo.OrderSortTimestamp:
+		orderBy = " order by timestamp" + direction + ", rowid"
+	case repo.OrderSortTotal:
+		orderBy = " order by total" + direction + ", rowid"
+	}
+	return where, orderBy, args
+}
@@ -0,0 +1,140 @@
+package db
+
+import (
+	"database/sql"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+const outgoingMessageColumns = "id, peerID, messageType, reference, message, state, pointerID, attempts, error, timestamp, updated"
+
+type OutgoingMessagesDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (o *OutgoingMessagesDB) Put(m repo.OutgoingMessage) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	_, err := o.db.Exec("insert or replace into outgoingmessages("+outgoingMessageColumns+") values(?,?,?,?,?,?,?,?,?,?,?)",
+		m.Id, m.PeerId, m.MessageType, m.Reference, m.Message, m.State, m.PointerId, m.Attempts, m.Error, int(m.Timestamp.Unix()), int(m.Updated.Unix()))
+	return err
+}
+
+func (o *OutgoingMessagesDB) UpdateUnlessAcked(m repo.OutgoingMessage) (bool, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	res, err := o.db.Exec("update outgoingmessages set message=?, state=?, attempts=?, error=?, updated=? where id=? and state!=?",
+		m.Message, m.State, m.Attempts, m.Error, int(m.Updated.Unix()), m.Id, repo.OutgoingMessageAcked)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (o *OutgoingMessagesDB) Get(id string) (repo.OutgoingMessage, error) {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+	return scanOutgoingMessage(o.db.QueryRow("select "+outgoingMessageColumns+" from outgoingmessages where id=?", id))
+}
+
+func (o *OutgoingMessagesDB) GetByPointerId(pointerId string) (repo.OutgoingMessage, error) {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+	return scanOutgoingMessage(o.db.QueryRow("select "+outgoingMessageColumns+" from outgoingmessages where pointerID=?", pointerId))
+}
+
+func (o *OutgoingMessagesDB) GetByReference(reference string) ([]repo.OutgoingMessage, error) {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+	rows, err := o.db.Query("select "+outgoingMessageColumns+" from outgoingmessages where reference=? order by timestamp, rowid", reference)
+	if err != nil {
+		return nil, err
+	}
+	return outgoingMessagesFromRows(rows)
+}
+
+func (o *OutgoingMessagesDB) GetUndelivered(peerId string, since time.Time) ([]repo.OutgoingMessage, error) {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+	stm := "select " + outgoingMessageColumns + " from outgoingmessages where state=? and timestamp>=?"
+	args := []interface{}{repo.OutgoingMessageStoredOffline, int(since.Unix())}
+	if peerId != "" {
+		stm += " and peerID=?"
+		args = append(args, peerId)
+	}
+	rows, err := o.db.Query(stm+" order by timestamp, rowid", args...)
+	if err != nil {
+		return nil, err
+	}
+	return outgoingMessagesFromRows(rows)
+}
+
+func (o *OutgoingMessagesDB) GetAll(peerId, state string, offset, limit int) ([]repo.OutgoingMessage, int, error) {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+	where := " where 1=1"
+	var args []interface{}
+	if peerId != "" {
+		where += " and peerID=?"
+		args = append(args, peerId)
+	}
+	if state != "" {
+		where += " and state=?"
+		args = append(args, state)
+	}
+	var count int
+	if err := o.db.QueryRow("select count(*) from outgoingmessages"+where, args...).Scan(&count); err != nil {
+		return nil, 0, err
+	}
+	rows, err := o.db.Query("select "+outgoingMessageColumns+" from outgoingmessages"+where+" order by timestamp desc, rowid desc limit "+strconv.Itoa(limit)+" offset "+strconv.Itoa(offset), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	ret, err := outgoingMessagesFromRows(rows)
+	return ret, count, err
+}
+
+func (o *OutgoingMessagesDB) Prune(ackedBefore, sentBefore time.Time) (int, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	res, err := o.db.Exec("delete from outgoingmessages where (state=? and updated<?) or timestamp<?", repo.OutgoingMessageAcked, int(ackedBefore.Unix()), int(sentBefore.Unix()))
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func scanOutgoingMessage(row interface {
+	Scan(dest ...interface{}) error
+}) (repo.OutgoingMessage, error) {
+	var m repo.OutgoingMessage
+	var timestamp, updated int
+	err := row.Scan(&m.Id, &m.PeerId, &m.MessageType, &m.Reference, &m.Message, &m.State, &m.PointerId, &m.Attempts, &m.Error, &timestamp, &updated)
+	if err == sql.ErrNoRows {
+		return m, repo.ErrOutgoingMessageNotFound
+	} else if err != nil {
+		return m, err
+	}
+	m.Timestamp = time.Unix(int64(timestamp), 0)
+	m.Updated = time.Unix(int64(updated), 0)
+	return m, nil
+}
+
+func outgoingMessagesFromRows(rows *sql.Rows) ([]repo.OutgoingMessage, error) {
+	defer rows.Close()
+	ret := []repo.OutgoingMessage{}
+	for rows.Next() {
+		m, err := scanOutgoingMessage(rows)
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, m)
+	}
+	return ret, rows.Err()
+}
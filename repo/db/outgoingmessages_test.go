@@ -0,0 +1,93 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+func TestOutgoingMessagesDB(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	odb := OutgoingMessagesDB{db: conn}
+	now := time.Unix(1500000000, 0)
+	stored := repo.OutgoingMessage{Id: "a", PeerId: "QmPeer1", MessageType: "CHAT", Reference: "QmChat", Message: []byte{1, 2, 3}, State: repo.OutgoingMessageStoredOffline, PointerId: "QmPointer", Attempts: 1, Timestamp: now, Updated: now}
+	direct := repo.OutgoingMessage{Id: "b", PeerId: "QmPeer2", MessageType: "FOLLOW", State: repo.OutgoingMessageSentDirect, Attempts: 1, Timestamp: now.Add(time.Second), Updated: now}
+	for _, m := range []repo.OutgoingMessage{stored, direct} {
+		if err := odb.Put(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ret, err := odb.Get("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.PeerId != "QmPeer1" || ret.Reference != "QmChat" || len(ret.Message) != 3 || ret.PointerId != "QmPointer" || !ret.Timestamp.Equal(now) {
+		t.Error("Returned wrong outgoing message")
+	}
+	if _, err := odb.Get("c"); err != repo.ErrOutgoingMessageNotFound {
+		t.Error("Getting an unknown message returned", err)
+	}
+	ret, err = odb.GetByPointerId("QmPointer")
+	if err != nil || ret.Id != "a" {
+		t.Error("Returned wrong message for pointer", err)
+	}
+	refs, err := odb.GetByReference("QmChat")
+	if err != nil || len(refs) != 1 || refs[0].Id != "a" {
+		t.Error("Returned wrong messages for reference", err)
+	}
+	undelivered, err := odb.GetUndelivered("", now)
+	if err != nil || len(undelivered) != 1 || undelivered[0].Id != "a" {
+		t.Error("Returned wrong undelivered messages", err)
+	}
+	if undelivered, _ := odb.GetUndelivered("QmPeer2", now); len(undelivered) != 0 {
+		t.Error("Returned undelivered messages for the wrong peer")
+	}
+	if undelivered, _ := odb.GetUndelivered("", now.Add(time.Hour)); len(undelivered) != 0 {
+		t.Error("Returned undelivered messages sent before the cutoff")
+	}
+
+	stored.State = repo.OutgoingMessageAcked
+	stored.Message = nil
+	if err := odb.Put(stored); err != nil {
+		t.Fatal(err)
+	}
+	if undelivered, _ := odb.GetUndelivered("", now); len(undelivered) != 0 {
+		t.Error("Returned an acked message as undelivered")
+	}
+	acked, total, err := odb.GetAll("QmPeer1", repo.OutgoingMessageAcked, 0, -1)
+	if err != nil || total != 1 || len(acked) != 1 || len(acked[0].Message) != 0 {
+		t.Error("Returned wrong acked messages", err)
+	}
+	all, total, err := odb.GetAll("", "", 0, 1)
+	if err != nil || total != 2 || len(all) != 1 || all[0].Id != "b" {
+		t.Error("Returned wrong page of messages", err)
+	}
+
+	// A retry finishing after the ack doesn't overwrite it
+	retried := stored
+	retried.State = repo.OutgoingMessageSentDirect
+	if saved, err := odb.UpdateUnlessAcked(retried); err != nil || saved {
+		t.Error("Overwrote an acked message", err)
+	}
+	if ret, _ := odb.Get("a"); ret.State != repo.OutgoingMessageAcked {
+		t.Error("Acked message changed state to", ret.State)
+	}
+	direct.Attempts = 2
+	if saved, err := odb.UpdateUnlessAcked(direct); err != nil || !saved {
+		t.Error("Didn't update a message which wasn't acked", err)
+	}
+
+	// Acked messages are pruned after ackedBefore and the rest once they're older than sentBefore
+	if n, err := odb.Prune(now, now); err != nil || n != 0 {
+		t.Error("Pruned messages before the cutoffs", err)
+	}
+	if n, err := odb.Prune(now.Add(time.Second), now); err != nil || n != 1 {
+		t.Error("Didn't prune the acked message", err)
+	}
+	if n, err := odb.Prune(now, now.Add(time.Hour)); err != nil || n != 1 {
+		t.Error("Didn't prune the expired message", err)
+	}
+}
@@ -0,0 +1,116 @@
+package db
+
+import (
+	"database/sql"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"testing"
+	"time"
+)
+
+var pldb PledgesDB
+
+func init() {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	pldb = PledgesDB{
+		db: conn,
+	}
+}
+
+func newPledgeContract(amount uint64, seconds int64) *pb.RicardianContract {
+	c := new(pb.RicardianContract)
+	listing := new(pb.Listing)
+	listing.Slug = "test-campaign"
+	c.VendorListings = []*pb.Listing{listing}
+	order := new(pb.Order)
+	order.BuyerID = &pb.ID{Guid: "buyer guid", BlockchainID: "@testbuyer"}
+	order.Timestamp = &timestamp.Timestamp{Seconds: seconds}
+	order.Payment = &pb.Order_Payment{Amount: amount, Method: pb.Order_Payment_DIRECT}
+	c.BuyerOrder = order
+	return c
+}
+
+func TestPutPledge(t *testing.T) {
+	err := pldb.Put("pledge1", *newPledgeContract(500, time.Now().Unix()), repo.PledgePending)
+	if err != nil {
+		t.Error(err)
+	}
+	stmt, _ := pldb.db.Prepare("select orderID, slug, amount, buyerID, buyerBlockchainID, state from pledges where orderID=?")
+	defer stmt.Close()
+	var orderID, slug, buyerID, buyerHandle string
+	var amount, state int
+	err = stmt.QueryRow("pledge1").Scan(&orderID, &slug, &amount, &buyerID, &buyerHandle, &state)
+	if err != nil {
+		t.Error(err)
+	}
+	if orderID != "pledge1" {
+		t.Errorf(`Expected %s got %s`, "pledge1", orderID)
+	}
+	if slug != "test-campaign" {
+		t.Errorf(`Expected %s got %s`, "test-campaign", slug)
+	}
+	if amount != 500 {
+		t.Errorf("Expected amount 500 got %d", amount)
+	}
+	if buyerID != "buyer guid" || buyerHandle != "@testbuyer" {
+		t.Error("Returned incorrect buyer ID")
+	}
+	if state != int(repo.PledgePending) {
+		t.Errorf("Expected state %d got %d", repo.PledgePending, state)
+	}
+	pldb.Delete("pledge1")
+}
+
+func TestPledgesDB_UpdateFunding(t *testing.T) {
+	pldb.Put("pledge2", *newPledgeContract(500, time.Now().Unix()), repo.PledgePending)
+	err := pldb.UpdateFunding("pledge2", true)
+	if err != nil {
+		t.Error(err)
+	}
+	// Funding must survive an update to the pledge
+	pldb.Put("pledge2", *newPledgeContract(500, time.Now().Unix()), repo.PledgePending)
+	pledges, err := pldb.GetBySlug("test-campaign")
+	if err != nil {
+		t.Error(err)
+	}
+	if len(pledges) != 1 || !pledges[0].Funded {
+		t.Error("Failed to update funding")
+	}
+	pldb.Delete("pledge2")
+}
+
+func TestPledgesDB_GetBySlug(t *testing.T) {
+	pldb.Put("second", *newPledgeContract(700, 200), repo.PledgePending)
+	pldb.Put("first", *newPledgeContract(300, 100), repo.PledgePending)
+	err := pldb.UpdateState("second", repo.PledgeRefunded)
+	if err != nil {
+		t.Error(err)
+	}
+	pledges, err := pldb.GetBySlug("test-campaign")
+	if err != nil {
+		t.Error(err)
+	}
+	if len(pledges) != 2 {
+		t.Fatalf("Expected 2 pledges got %d", len(pledges))
+	}
+	if pledges[0].OrderId != "first" || pledges[1].OrderId != "second" {
+		t.Error("Pledges returned in incorrect order")
+	}
+	if pledges[0].Amount != 300 || pledges[0].Funded || pledges[0].State != "PENDING" {
+		t.Error("Returned incorrect pledge")
+	}
+	if pledges[1].State != "REFUNDED" {
+		t.Error("Failed to update state")
+	}
+	pledges, err = pldb.GetBySlug("other-campaign")
+	if err != nil {
+		t.Error(err)
+	}
+	if len(pledges) != 0 {
+		t.Error("Returned pledges for the wrong campaign")
+	}
+	pldb.Delete("first")
+	pldb.Delete("second")
+}
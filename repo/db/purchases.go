@@ -38,7 +38,7 @@ func (p *PurchasesDB) Put(orderID string, contract pb.RicardianContract, state p
 	if err != nil {
 		return err
 	}
-	stm := `insert or replace into purchases(orderID, contract, state, read, timestamp, total, thumbnail, vendorID, vendorBlockchainID, title, shippingName, shippingAddress, paymentAddr, funded, transactions) values(?,?,?,?,?,?,?,?,?,?,?,?,?,(select funded from purchases where orderID="` + orderID + `"),(select transactions from purchases where orderID="` + orderID + `"))`
+	stm := `insert or replace into purchases(orderID, contract, state, read, timestamp, total, thumbnail, vendorID, vendorBlockchainID, title, shippingName, shippingAddress, paymentAddr, funded, transactions, exchangeRates) values(?,?,?,?,?,?,?,?,?,?,?,?,?,(select funded from purchases where orderID="` + orderID + `"),(select transactions from purchases where orderID="` + orderID + `"),(select exchangeRates from purchases where orderID="` + orderID + `"))`
 	stmt, err := tx.Prepare(stm)
 	if err != nil {
 		return err
@@ -109,6 +109,34 @@ func (p *PurchasesDB) UpdateFunding(orderId string, funded bool, records []*spvw
 	return nil
 }
 
+func (p *PurchasesDB) PutExchangeRates(orderID string, rates map[string]float64) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	serializedRates, err := json.Marshal(rates)
+	if err != nil {
+		return err
+	}
+	_, err = p.db.Exec("update purchases set exchangeRates=? where orderID=?", string(serializedRates), orderID)
+	return err
+}
+
+func (p *PurchasesDB) GetExchangeRates(orderID string) (map[string]float64, error) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	var serializedRates sql.NullString
+	err := p.db.QueryRow("select exchangeRates from purchases where orderID=?", orderID).Scan(&serializedRates)
+	if err != nil {
+		return nil, err
+	}
+	rates := make(map[string]float64)
+	if serializedRates.Valid && serializedRates.String != "" {
+		if err := json.Unmarshal([]byte(serializedRates.String), &rates); err != nil {
+			return nil, err
+		}
+	}
+	return rates, nil
+}
+
 func (p *PurchasesDB) Delete(orderID string) error {
 	p.lock.Lock()
 	defer p.lock.Unlock()
@@ -133,6 +161,33 @@ func (p *PurchasesDB) GetAll(offsetId string, limit int) ([]repo.Purchase, error
 	if err != nil {
 		return nil, err
 	}
+	return purchasesFromRows(rows)
+}
+
+func (p *PurchasesDB) Query(query repo.OrderQuery) ([]repo.Purchase, int, error) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	where, orderBy, args := orderQueryClauses(query)
+	var count int
+	err := p.db.QueryRow("select count(*) from purchases"+where, args...).Scan(&count)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset := query.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	stm := "select orderID, timestamp, total, title, thumbnail, vendorID, vendorBlockchainID, shippingName, shippingAddress, state, read from purchases" + where + orderBy + " limit " + strconv.Itoa(query.Limit) + " offset " + strconv.Itoa(offset)
+	rows, err := p.db.Query(stm, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	ret, err := purchasesFromRows(rows)
+	return ret, count, err
+}
+
+func purchasesFromRows(rows *sql.Rows) ([]repo.Purchase, error) {
 	defer rows.Close()
 	var ret []repo.Purchase
 	for rows.Next() {
@@ -3,6 +3,7 @@ package db
 import (
 	"database/sql"
 	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
 	"github.com/OpenBazaar/spvwallet"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcutil"
@@ -226,6 +227,33 @@ func TestPurchasePutAfterFundingUpdate(t *testing.T) {
 	}
 }
 
+func TestPurchaseExchangeRates(t *testing.T) {
+	err := purdb.Put("orderID", *contract, 1, false)
+	if err != nil {
+		t.Error(err)
+	}
+	err = purdb.PutExchangeRates("orderID", map[string]float64{"USD": 2500.5})
+	if err != nil {
+		t.Error(err)
+	}
+	// Rates are kept when the order is updated
+	err = purdb.Put("orderID", *contract, 3, false)
+	if err != nil {
+		t.Error(err)
+	}
+	rates, err := purdb.GetExchangeRates("orderID")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(rates) != 1 || rates["USD"] != 2500.5 {
+		t.Error("Returned wrong exchange rates")
+	}
+	if _, err := purdb.GetExchangeRates("unknown"); err == nil {
+		t.Error("Returned exchange rates for an unknown order")
+	}
+}
+
 func TestPurchasesGetByPaymentAddress(t *testing.T) {
 	purdb.Put("orderID", *contract, 0, false)
 	addr, err := btcutil.DecodeAddress(contract.BuyerOrder.Payment.Address, &chaincfg.MainNetParams)
@@ -284,3 +312,44 @@ func TestPurchasesDB_GetAll(t *testing.T) {
 		t.Error("Returned incorrect number of purchases")
 	}
 }
+
+func TestPurchasesDB_Query(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	db := PurchasesDB{db: conn}
+	stmt, _ := conn.Prepare("insert into purchases(orderID, timestamp, total, title, thumbnail, vendorID, vendorBlockchainID, shippingName, shippingAddress, state, read, funded) values(?,?,?,'','','','','','',?,?,?)")
+	defer stmt.Close()
+	stmt.Exec("a", 100, 30, int(pb.OrderState_PENDING), 0, nil)
+	stmt.Exec("b", 300, 10, int(pb.OrderState_CONFIRMED), 1, 0)
+	stmt.Exec("c", 200, 20, int(pb.OrderState_PENDING), 1, 1)
+
+	purchases, count, err := db.Query(repo.OrderQuery{Limit: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 || len(purchases) != 3 || purchases[0].OrderId != "a" || purchases[2].OrderId != "c" {
+		t.Error("Returned purchases out of insertion order")
+	}
+	purchases, count, _ = db.Query(repo.OrderQuery{SortBy: repo.OrderSortTotal, Limit: 2})
+	if count != 3 || len(purchases) != 2 || purchases[0].OrderId != "a" || purchases[1].OrderId != "c" {
+		t.Error("Returned wrong purchases sorted by total")
+	}
+	purchases, _, _ = db.Query(repo.OrderQuery{SortBy: repo.OrderSortTimestamp, Ascending: true, Offset: 1, Limit: -1})
+	if len(purchases) != 2 || purchases[0].OrderId != "c" || purchases[1].OrderId != "b" {
+		t.Error("Returned wrong purchases sorted by timestamp")
+	}
+	unfunded := false
+	purchases, count, _ = db.Query(repo.OrderQuery{Funded: &unfunded, Limit: -1})
+	if count != 2 || purchases[0].OrderId != "a" || purchases[1].OrderId != "b" {
+		t.Error("Returned wrong unfunded purchases")
+	}
+	read := true
+	purchases, count, _ = db.Query(repo.OrderQuery{States: []pb.OrderState{pb.OrderState_PENDING}, Read: &read, Limit: -1})
+	if count != 1 || purchases[0].OrderId != "c" {
+		t.Error("Returned wrong purchases for state and read filters")
+	}
+	_, count, _ = db.Query(repo.OrderQuery{From: time.Unix(150, 0), To: time.Unix(250, 0), Limit: -1})
+	if count != 1 {
+		t.Error("Returned wrong purchases for date range")
+	}
+}
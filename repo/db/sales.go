@@ -38,7 +38,7 @@ func (s *SalesDB) Put(orderID string, contract pb.RicardianContract, state pb.Or
 	if err != nil {
 		return err
 	}
-	stm := `insert or replace into sales(orderID, contract, state, read, timestamp, total, thumbnail, buyerID, buyerBlockchainID, title, shippingName, shippingAddress, paymentAddr, funded, transactions) values(?,?,?,?,?,?,?,?,?,?,?,?,?,(select funded from sales where orderID="` + orderID + `"),(select transactions from sales where orderID="` + orderID + `"))`
+	stm := `insert or replace into sales(orderID, contract, state, read, timestamp, total, thumbnail, buyerID, buyerBlockchainID, title, shippingName, shippingAddress, paymentAddr, funded, transactions, exchangeRates) values(?,?,?,?,?,?,?,?,?,?,?,?,?,(select funded from sales where orderID="` + orderID + `"),(select transactions from sales where orderID="` + orderID + `"),(select exchangeRates from sales where orderID="` + orderID + `"))`
 	stmt, err := tx.Prepare(stm)
 	if err != nil {
 		return err
@@ -110,6 +110,34 @@ func (s *SalesDB) UpdateFunding(orderId string, funded bool, records []*spvwalle
 	return nil
 }
 
+func (s *SalesDB) PutExchangeRates(orderID string, rates map[string]float64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	serializedRates, err := json.Marshal(rates)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec("update sales set exchangeRates=? where orderID=?", string(serializedRates), orderID)
+	return err
+}
+
+func (s *SalesDB) GetExchangeRates(orderID string) (map[string]float64, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	var serializedRates sql.NullString
+	err := s.db.QueryRow("select exchangeRates from sales where orderID=?", orderID).Scan(&serializedRates)
+	if err != nil {
+		return nil, err
+	}
+	rates := make(map[string]float64)
+	if serializedRates.Valid && serializedRates.String != "" {
+		if err := json.Unmarshal([]byte(serializedRates.String), &rates); err != nil {
+			return nil, err
+		}
+	}
+	return rates, nil
+}
+
 func (s *SalesDB) Delete(orderID string) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
@@ -134,6 +162,33 @@ func (s *SalesDB) GetAll(offsetId string, limit int) ([]repo.Sale, error) {
 	if err != nil {
 		return nil, err
 	}
+	return salesFromRows(rows)
+}
+
+func (s *SalesDB) Query(query repo.OrderQuery) ([]repo.Sale, int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	where, orderBy, args := orderQueryClauses(query)
+	var count int
+	err := s.db.QueryRow("select count(*) from sales"+where, args...).Scan(&count)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset := query.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	stm := "select orderID, timestamp, total, title, thumbnail, buyerID, buyerBlockchainID, shippingName, shippingAddress, state, read from sales" + where + orderBy + " limit " + strconv.Itoa(query.Limit) + " offset " + strconv.Itoa(offset)
+	rows, err := s.db.Query(stm, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	ret, err := salesFromRows(rows)
+	return ret, count, err
+}
+
+func salesFromRows(rows *sql.Rows) ([]repo.Sale, error) {
 	defer rows.Close()
 	var ret []repo.Sale
 	for rows.Next() {
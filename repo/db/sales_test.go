@@ -3,6 +3,7 @@ package db
 import (
 	"database/sql"
 	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
 	"github.com/OpenBazaar/spvwallet"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcutil"
@@ -225,6 +226,33 @@ func TestSalePutAfterFundingUpdate(t *testing.T) {
 	}
 }
 
+func TestSaleExchangeRates(t *testing.T) {
+	err := saldb.Put("orderID", *contract, 1, false)
+	if err != nil {
+		t.Error(err)
+	}
+	err = saldb.PutExchangeRates("orderID", map[string]float64{"USD": 2500.5})
+	if err != nil {
+		t.Error(err)
+	}
+	// Rates are kept when the order is updated
+	err = saldb.Put("orderID", *contract, 3, false)
+	if err != nil {
+		t.Error(err)
+	}
+	rates, err := saldb.GetExchangeRates("orderID")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(rates) != 1 || rates["USD"] != 2500.5 {
+		t.Error("Returned wrong exchange rates")
+	}
+	if _, err := saldb.GetExchangeRates("unknown"); err == nil {
+		t.Error("Returned exchange rates for an unknown order")
+	}
+}
+
 func TestSalesGetByPaymentAddress(t *testing.T) {
 	saldb.Put("orderID", *contract, 0, false)
 	addr, err := btcutil.DecodeAddress(contract.BuyerOrder.Payment.Address, &chaincfg.MainNetParams)
@@ -282,3 +310,27 @@ func TestSalesDB_GetAll(t *testing.T) {
 		t.Error("Returned incorrect number of sales")
 	}
 }
+
+func TestSalesDB_Query(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	db := SalesDB{db: conn}
+	stmt, _ := conn.Prepare("insert into sales(orderID, timestamp, total, title, thumbnail, buyerID, buyerBlockchainID, shippingName, shippingAddress, state, read, funded) values(?,?,?,'','','','','','',?,?,?)")
+	defer stmt.Close()
+	stmt.Exec("a", 100, 30, int(pb.OrderState_COMPLETE), 0, 1)
+	stmt.Exec("b", 300, 10, int(pb.OrderState_DISPUTED), 0, 1)
+	stmt.Exec("c", 200, 20, int(pb.OrderState_PENDING), 1, 1)
+
+	unread := false
+	sales, count, err := db.Query(repo.OrderQuery{States: []pb.OrderState{pb.OrderState_COMPLETE, pb.OrderState_DISPUTED}, Read: &unread, SortBy: repo.OrderSortTimestamp, Limit: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 || sales[0].OrderId != "b" || sales[1].OrderId != "a" {
+		t.Error("Returned wrong sales")
+	}
+	sales, count, _ = db.Query(repo.OrderQuery{SortBy: repo.OrderSortTotal, Ascending: true, Limit: 1})
+	if count != 3 || len(sales) != 1 || sales[0].OrderId != "b" {
+		t.Error("Returned wrong page of sales")
+	}
+}
@@ -0,0 +1,279 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+/* The FTS5 tables behind search. Orders and chat messages share the rowid of the
+   row they index and are kept up to date by triggers. The before insert triggers
+   remove the entry for a row that is about to be replaced by an insert or replace
+   as SQLite doesn't fire delete triggers for those. */
+const searchTables = `
+	create virtual table search_listings using fts5(slug unindexed, title, tags);
+	create virtual table search_purchases using fts5(title, handle);
+	create virtual table search_sales using fts5(title, handle);
+	create virtual table search_chat using fts5(message);
+	create trigger search_purchases_replace before insert on purchases begin
+		delete from search_purchases where rowid in (select rowid from purchases where orderID=new.orderID);
+	end;
+	create trigger search_purchases_insert after insert on purchases begin
+		insert into search_purchases(rowid, title, handle) values(new.rowid, new.title, coalesce(new.vendorBlockchainID, '') || ' ' || coalesce(new.vendorID, ''));
+	end;
+	create trigger search_purchases_update after update of title, vendorID, vendorBlockchainID on purchases begin
+		delete from search_purchases where rowid=old.rowid;
+		insert into search_purchases(rowid, title, handle) values(new.rowid, new.title, coalesce(new.vendorBlockchainID, '') || ' ' || coalesce(new.vendorID, ''));
+	end;
+	create trigger search_purchases_delete after delete on purchases begin
+		delete from search_purchases where rowid=old.rowid;
+	end;
+	create trigger search_sales_replace before insert on sales begin
+		delete from search_sales where rowid in (select rowid from sales where orderID=new.orderID);
+	end;
+	create trigger search_sales_insert after insert on sales begin
+		insert into search_sales(rowid, title, handle) values(new.rowid, new.title, coalesce(new.buyerBlockchainID, '') || ' ' || coalesce(new.buyerID, ''));
+	end;
+	create trigger search_sales_update after update of title, buyerID, buyerBlockchainID on sales begin
+		delete from search_sales where rowid=old.rowid;
+		insert into search_sales(rowid, title, handle) values(new.rowid, new.title, coalesce(new.buyerBlockchainID, '') || ' ' || coalesce(new.buyerID, ''));
+	end;
+	create trigger search_sales_delete after delete on sales begin
+		delete from search_sales where rowid=old.rowid;
+	end;
+	create trigger search_chat_insert after insert on chat begin
+		insert into search_chat(rowid, message) values(new.rowid, new.message);
+	end;
+	create trigger search_chat_delete after delete on chat begin
+		delete from search_chat where rowid=old.rowid;
+	end;
+	`
+
+// Index the orders and chat messages saved before the search tables were created
+const searchBackfill = `
+	insert into search_purchases(rowid, title, handle) select rowid, title, coalesce(vendorBlockchainID, '') || ' ' || coalesce(vendorID, '') from purchases;
+	insert into search_sales(rowid, title, handle) select rowid, title, coalesce(buyerBlockchainID, '') || ' ' || coalesce(buyerID, '') from sales;
+	insert into search_chat(rowid, message) select rowid, message from chat;
+	`
+
+type SearchDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+/* Create the search tables if they're missing, indexing the existing orders and chat
+   messages. This is skipped when SQLite was built without FTS5 so the node still
+   runs, only with search disabled. Returns whether the search tables exist. */
+func createSearchTables(db *sql.DB) (bool, error) {
+	if exists, err := searchTablesExist(db); err != nil || exists {
+		return exists, err
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(searchTables + searchBackfill); err != nil {
+		tx.Rollback()
+		if strings.Contains(err.Error(), "no such module: fts5") {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func searchTablesExist(db *sql.DB) (bool, error) {
+	var count int
+	err := db.QueryRow("select count(*) from sqlite_master where type='table' and name='search_listings'").Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Add the search tables to a database created by a build without FTS5 once it's opened by one with it
+func (d *SQLiteDatastore) initSearch() error {
+	initialized, err := isInitialized(d.db)
+	if err != nil || !initialized {
+		return err
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	enabled, err := createSearchTables(d.db)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		log.Warning(repo.ErrSearchUnavailable)
+	}
+	return nil
+}
+
+func (s *SearchDB) IndexListings(listings []repo.SearchListing) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if exists, err := searchTablesExist(s.db); err != nil {
+		return err
+	} else if !exists {
+		return repo.ErrSearchUnavailable
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("delete from search_listings"); err != nil {
+		tx.Rollback()
+		return err
+	}
+	stmt, err := tx.Prepare("insert into search_listings(slug, title, tags) values(?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for _, l := range listings {
+		_, err = stmt.Exec(l.Slug, l.Title, strings.Join(l.Tags, " "))
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SearchDB) Query(query repo.SearchQuery) ([]repo.SearchResult, int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	var ret []repo.SearchResult
+	if exists, err := searchTablesExist(s.db); err != nil {
+		return ret, 0, err
+	} else if !exists {
+		return ret, 0, repo.ErrSearchUnavailable
+	}
+	if len(query.Terms) == 0 {
+		return ret, 0, nil
+	}
+	// Every term must match, the last as a prefix so results show up while typing
+	var phrases []string
+	for _, term := range query.Terms {
+		phrases = append(phrases, `"`+strings.Replace(term, `"`, `""`, -1)+`"`)
+	}
+	match := strings.Join(phrases, " ") + "*"
+
+	if query.Includes(repo.SearchTypeListing) {
+		rows, err := s.db.Query("select slug, title, bm25(search_listings) from search_listings where search_listings match ?", match)
+		if err != nil {
+			return ret, 0, err
+		}
+		for rows.Next() {
+			var slug, title string
+			var rank float64
+			if err := rows.Scan(&slug, &title, &rank); err != nil {
+				rows.Close()
+				return ret, 0, err
+			}
+			ret = append(ret, repo.SearchResult{
+				Type:  repo.SearchTypeListing,
+				Id:    slug,
+				Title: title,
+				Rank:  -rank,
+			})
+		}
+		rows.Close()
+	}
+
+	orderTables := []struct {
+		resultType, table, peerColumn, handleColumn string
+	}{
+		{repo.SearchTypePurchase, "purchases", "vendorID", "vendorBlockchainID"},
+		{repo.SearchTypeSale, "sales", "buyerID", "buyerBlockchainID"},
+	}
+	for _, t := range orderTables {
+		if !query.Includes(t.resultType) {
+			continue
+		}
+		stm := "select o.orderID, o.title, o." + t.peerColumn + ", o." + t.handleColumn + ", o.state, o.timestamp, bm25(search_" + t.table + ") from search_" + t.table + " join " + t.table + " o on o.rowid=search_" + t.table + ".rowid where search_" + t.table + " match ?"
+		stm, args := filterSearch(stm, []interface{}{match}, query, "o.")
+		rows, err := s.db.Query(stm, args...)
+		if err != nil {
+			return ret, 0, err
+		}
+		for rows.Next() {
+			var orderID, title, peerID, handle string
+			var stateInt, timestamp int
+			var rank float64
+			if err := rows.Scan(&orderID, &title, &peerID, &handle, &stateInt, &timestamp, &rank); err != nil {
+				rows.Close()
+				return ret, 0, err
+			}
+			ts := time.Unix(int64(timestamp), 0)
+			ret = append(ret, repo.SearchResult{
+				Type:      t.resultType,
+				Id:        orderID,
+				Title:     title,
+				PeerId:    peerID,
+				Handle:    handle,
+				State:     pb.OrderState(stateInt).String(),
+				Timestamp: &ts,
+				Rank:      -rank,
+			})
+		}
+		rows.Close()
+	}
+
+	if query.Includes(repo.SearchTypeChat) {
+		stm := "select c.messageID, c.peerID, c.subject, snippet(search_chat, 0, '', '', '...', 16), c.timestamp, bm25(search_chat) from search_chat join chat c on c.rowid=search_chat.rowid where search_chat match ?"
+		stm, args := filterSearch(stm, []interface{}{match}, query, "c.")
+		rows, err := s.db.Query(stm, args...)
+		if err != nil {
+			return ret, 0, err
+		}
+		for rows.Next() {
+			var messageID, peerID, subject, snippet string
+			var timestamp int
+			var rank float64
+			if err := rows.Scan(&messageID, &peerID, &subject, &snippet, &timestamp, &rank); err != nil {
+				rows.Close()
+				return ret, 0, err
+			}
+			ts := time.Unix(int64(timestamp), 0)
+			ret = append(ret, repo.SearchResult{
+				Type:      repo.SearchTypeChat,
+				Id:        messageID,
+				Snippet:   snippet,
+				PeerId:    peerID,
+				Subject:   subject,
+				Timestamp: &ts,
+				Rank:      -rank,
+			})
+		}
+		rows.Close()
+	}
+	ret, total := repo.PageSearchResults(ret, query.Offset, query.Limit)
+	return ret, total, nil
+}
+
+// Add the state and time filters in the query to a search statement
+func filterSearch(stm string, args []interface{}, query repo.SearchQuery, prefix string) (string, []interface{}) {
+	if len(query.States) > 0 {
+		var placeholders []string
+		for _, state := range query.States {
+			placeholders = append(placeholders, "?")
+			args = append(args, int(state))
+		}
+		stm += " and " + prefix + "state in (" + strings.Join(placeholders, ",") + ")"
+	}
+	if !query.From.IsZero() {
+		stm += " and " + prefix + "timestamp>=?"
+		args = append(args, int(query.From.Unix()))
+	}
+	if !query.To.IsZero() {
+		stm += " and " + prefix + "timestamp<=?"
+		args = append(args, int(query.To.Unix()))
+	}
+	return stm, args
+}
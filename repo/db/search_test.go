@@ -0,0 +1,130 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+func newSearchDB() (*SearchDB, *sql.DB) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	return &SearchDB{db: conn}, conn
+}
+
+func TestSearchDB_IndexListings(t *testing.T) {
+	s, _ := newSearchDB()
+	err := s.IndexListings([]repo.SearchListing{
+		{Slug: "red-shoes", Title: "Red running shoes", Tags: []string{"footwear"}},
+		{Slug: "blue-shirt", Title: "Blue shirt", Tags: []string{"clothing"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, total, err := s.Query(repo.SearchQuery{Terms: []string{"foot"}, Limit: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || results[0].Type != repo.SearchTypeListing || results[0].Id != "red-shoes" {
+		t.Error("Returned wrong listing for tag prefix")
+	}
+
+	// Indexing again replaces the previous listings
+	err = s.IndexListings([]repo.SearchListing{{Slug: "blue-shirt", Title: "Blue shirt"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, total, _ = s.Query(repo.SearchQuery{Terms: []string{"shoes"}, Limit: -1})
+	if total != 0 {
+		t.Error("Old listings were not removed from the index")
+	}
+}
+
+func TestSearchDB_QueryOrders(t *testing.T) {
+	s, conn := newSearchDB()
+	purchases := PurchasesDB{db: conn}
+	sales := SalesDB{db: conn}
+	if err := purchases.Put("orderID", *contract, pb.OrderState_PENDING, false); err != nil {
+		t.Fatal(err)
+	}
+	// Saving the order again must not leave a second entry in the index
+	if err := purchases.Put("orderID", *contract, pb.OrderState_FULFILLED, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := sales.Put("saleID", *contract, pb.OrderState_PENDING, false); err != nil {
+		t.Fatal(err)
+	}
+
+	results, total, err := s.Query(repo.SearchQuery{Terms: []string{"test", "listing"}, Limit: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 {
+		t.Fatalf("Expected 2 results got %d", total)
+	}
+	results, total, _ = s.Query(repo.SearchQuery{Terms: []string{"testvendor"}, Limit: -1})
+	if total != 1 || results[0].Type != repo.SearchTypePurchase || results[0].Id != "orderID" || results[0].State != "FULFILLED" {
+		t.Error("Returned wrong purchase for vendor handle")
+	}
+	_, total, _ = s.Query(repo.SearchQuery{Terms: []string{"test"}, States: []pb.OrderState{pb.OrderState_PENDING}, Limit: -1})
+	if total != 1 {
+		t.Error("State filter returned wrong results")
+	}
+	_, total, _ = s.Query(repo.SearchQuery{Terms: []string{"test"}, Types: []string{repo.SearchTypeSale}, Limit: -1})
+	if total != 1 {
+		t.Error("Type filter returned wrong results")
+	}
+	_, total, _ = s.Query(repo.SearchQuery{Terms: []string{"test"}, To: time.Now().Add(-time.Hour), Limit: -1})
+	if total != 0 {
+		t.Error("Time filter returned wrong results")
+	}
+
+	if err := purchases.Delete("orderID"); err != nil {
+		t.Fatal(err)
+	}
+	_, total, _ = s.Query(repo.SearchQuery{Terms: []string{"testvendor"}, Limit: -1})
+	if total != 0 {
+		t.Error("Deleted purchase is still in the index")
+	}
+}
+
+func TestSearchDB_QueryChat(t *testing.T) {
+	s, conn := newSearchDB()
+	chat := ChatDB{db: conn}
+	now := time.Now()
+	chat.Put("1", "abc", "", "Is this still available?", now.Add(-time.Minute), false, false)
+	chat.Put("2", "abc", "", "Yes it is available", now, false, true)
+	chat.Put("3", "xyz", "", "Thanks for the order", now, false, false)
+
+	results, total, err := s.Query(repo.SearchQuery{Terms: []string{"available"}, Offset: 1, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 || len(results) != 1 {
+		t.Fatalf("Expected 1 of 2 results got %d of %d", len(results), total)
+	}
+	if results[0].Type != repo.SearchTypeChat || results[0].PeerId != "abc" || results[0].Snippet == "" {
+		t.Error("Returned wrong chat result")
+	}
+	if err := chat.DeleteMessage("3"); err != nil {
+		t.Fatal(err)
+	}
+	_, total, _ = s.Query(repo.SearchQuery{Terms: []string{"thanks"}, Limit: -1})
+	if total != 0 {
+		t.Error("Deleted message is still in the index")
+	}
+}
+
+func TestSearchDB_Unavailable(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	s := &SearchDB{db: conn}
+	if _, _, err := s.Query(repo.SearchQuery{Terms: []string{"shoes"}, Limit: -1}); err != repo.ErrSearchUnavailable {
+		t.Error("Searching without the search tables didn't return ErrSearchUnavailable", err)
+	}
+	if err := s.IndexListings([]repo.SearchListing{{Slug: "red-shoes", Title: "Red running shoes"}}); err != repo.ErrSearchUnavailable {
+		t.Error("Indexing without the search tables didn't return ErrSearchUnavailable", err)
+	}
+}
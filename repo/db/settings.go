@@ -107,6 +107,9 @@ func (s *SettingsDB) Update(settings repo.SettingsData) error {
 	if settings.SMTPSettings == nil {
 		settings.SMTPSettings = current.SMTPSettings
 	}
+	if settings.OrderRules == nil {
+		settings.OrderRules = current.OrderRules
+	}
 	err = s.Put(settings)
 	if err != nil {
 		return err
@@ -12,15 +12,16 @@ import (
 )
 
 type StxoDB struct {
-	db   *sql.DB
-	lock sync.RWMutex
+	db    *sql.DB
+	table string
+	lock  sync.RWMutex
 }
 
 func (s *StxoDB) Put(stxo spvwallet.Stxo) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 	tx, _ := s.db.Begin()
-	stmt, err := tx.Prepare("insert or replace into stxos(outpoint, value, height, scriptPubKey, spendHeight, spendTxid) values(?,?,?,?,?,?)")
+	stmt, err := tx.Prepare("insert or replace into " + s.table + "(outpoint, value, height, scriptPubKey, spendHeight, spendTxid) values(?,?,?,?,?,?)")
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -40,7 +41,7 @@ func (s *StxoDB) GetAll() ([]spvwallet.Stxo, error) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 	var ret []spvwallet.Stxo
-	stm := "select outpoint, value, height, scriptPubKey, spendHeight, spendTxid from stxos"
+	stm := "select outpoint, value, height, scriptPubKey, spendHeight, spendTxid from " + s.table
 	rows, err := s.db.Query(stm)
 	if err != nil {
 		return ret, err
@@ -92,7 +93,7 @@ func (s *StxoDB) Delete(stxo spvwallet.Stxo) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 	outpoint := stxo.Utxo.Op.Hash.String() + ":" + strconv.Itoa(int(stxo.Utxo.Op.Index))
-	_, err := s.db.Exec("delete from stxos where outpoint=?", outpoint)
+	_, err := s.db.Exec("delete from "+s.table+" where outpoint=?", outpoint)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+type TxAccountingDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (t *TxAccountingDB) Put(record repo.TxAccountingRecord) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	_, err := t.db.Exec("insert or ignore into txaccounting(txid, currency, exchangeRate, fee, timestamp) values(?,?,?,?,?)",
+		record.Txid, record.Currency, record.ExchangeRate, record.Fee, int(record.Timestamp.Unix()))
+	return err
+}
+
+func (t *TxAccountingDB) SetExchangeRate(txid, currency string, rate float64) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	_, err := t.db.Exec("update txaccounting set currency=?, exchangeRate=? where txid=? and (currency is null or currency='')", currency, rate, txid)
+	return err
+}
+
+func (t *TxAccountingDB) Get(txid string) (repo.TxAccountingRecord, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	row := t.db.QueryRow("select txid, currency, exchangeRate, fee, timestamp from txaccounting where txid=?", txid)
+	return scanTxAccountingRecord(row)
+}
+
+func (t *TxAccountingDB) GetAll() (map[string]repo.TxAccountingRecord, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	ret := make(map[string]repo.TxAccountingRecord)
+	rows, err := t.db.Query("select txid, currency, exchangeRate, fee, timestamp from txaccounting")
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		record, err := scanTxAccountingRecord(rows)
+		if err != nil {
+			return ret, err
+		}
+		ret[record.Txid] = record
+	}
+	return ret, nil
+}
+
+func scanTxAccountingRecord(row interface {
+	Scan(dest ...interface{}) error
+}) (repo.TxAccountingRecord, error) {
+	var record repo.TxAccountingRecord
+	var fee sql.NullInt64
+	var timestamp int
+	if err := row.Scan(&record.Txid, &record.Currency, &record.ExchangeRate, &fee, &timestamp); err != nil {
+		return record, err
+	}
+	if fee.Valid {
+		record.Fee = &fee.Int64
+	}
+	record.Timestamp = time.Unix(int64(timestamp), 0)
+	return record, nil
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+func TestTxAccountingDB_Put(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	tadb := TxAccountingDB{db: conn}
+	fee := int64(2250)
+	err := tadb.Put(repo.TxAccountingRecord{Txid: "abc", Currency: "USD", ExchangeRate: 2500.5, Fee: &fee, Timestamp: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A later record for the same transaction must not replace the rate it was first seen at
+	err = tadb.Put(repo.TxAccountingRecord{Txid: "abc", Currency: "EUR", ExchangeRate: 3000, Timestamp: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, err := tadb.Get("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Currency != "USD" || record.ExchangeRate != 2500.5 || record.Fee == nil || *record.Fee != fee {
+		t.Error("Returned wrong record")
+	}
+
+	if err := tadb.Put(repo.TxAccountingRecord{Txid: "def", Currency: "USD", ExchangeRate: 2600, Timestamp: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	records, err := tadb.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records["def"].Fee != nil {
+		t.Error("Returned wrong records")
+	}
+	if _, err := tadb.Get("xyz"); err == nil {
+		t.Error("Get returned a record for an unknown transaction")
+	}
+}
+
+func TestTxAccountingDB_SetExchangeRate(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	tadb := TxAccountingDB{db: conn}
+	fee := int64(2250)
+	if err := tadb.Put(repo.TxAccountingRecord{Txid: "abc", Fee: &fee, Timestamp: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tadb.SetExchangeRate("abc", "USD", 2500.5); err != nil {
+		t.Fatal(err)
+	}
+	record, err := tadb.Get("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Currency != "USD" || record.ExchangeRate != 2500.5 || record.Fee == nil || *record.Fee != fee {
+		t.Error("Exchange rate was not saved")
+	}
+	// The rate the transaction was first seen at is kept
+	if err := tadb.SetExchangeRate("abc", "EUR", 3000); err != nil {
+		t.Fatal(err)
+	}
+	record, _ = tadb.Get("abc")
+	if record.Currency != "USD" || record.ExchangeRate != 2500.5 {
+		t.Error("Exchange rate was replaced")
+	}
+}
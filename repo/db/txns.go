@@ -11,8 +11,9 @@ import (
 )
 
 type TxnsDB struct {
-	db   *sql.DB
-	lock sync.RWMutex
+	db    *sql.DB
+	table string
+	lock  sync.RWMutex
 }
 
 func (t *TxnsDB) Put(txn *wire.MsgTx, value, height int, timestamp time.Time) error {
@@ -22,7 +23,7 @@ func (t *TxnsDB) Put(txn *wire.MsgTx, value, height int, timestamp time.Time) er
 	if err != nil {
 		return err
 	}
-	stmt, err := tx.Prepare("insert or replace into txns(txid, value, height, timestamp, tx) values(?,?,?,?,?)")
+	stmt, err := tx.Prepare("insert or replace into " + t.table + "(txid, value, height, timestamp, tx) values(?,?,?,?,?)")
 	defer stmt.Close()
 	if err != nil {
 		tx.Rollback()
@@ -43,7 +44,7 @@ func (t *TxnsDB) Get(txid chainhash.Hash) (*wire.MsgTx, spvwallet.Txn, error) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 	var txn spvwallet.Txn
-	stmt, err := t.db.Prepare("select tx, value, height, timestamp from txns where txid=?")
+	stmt, err := t.db.Prepare("select tx, value, height, timestamp from " + t.table + " where txid=?")
 	if err != nil {
 		return nil, txn, err
 	}
@@ -72,7 +73,7 @@ func (t *TxnsDB) GetAll() ([]spvwallet.Txn, error) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 	var ret []spvwallet.Txn
-	stm := "select tx, value, height, timestamp from txns"
+	stm := "select tx, value, height, timestamp from " + t.table
 	rows, err := t.db.Query(stm)
 	defer rows.Close()
 	if err != nil {
@@ -99,7 +100,7 @@ func (t *TxnsDB) GetAll() ([]spvwallet.Txn, error) {
 func (t *TxnsDB) Delete(txid *chainhash.Hash) error {
 	t.lock.Lock()
 	defer t.lock.Unlock()
-	_, err := t.db.Exec("delete from txns where txid=?", txid.String())
+	_, err := t.db.Exec("delete from "+t.table+" where txid=?", txid.String())
 	if err != nil {
 		return err
 	}
@@ -113,7 +114,7 @@ func (t *TxnsDB) MarkAsDead(txid chainhash.Hash) error {
 	if err != nil {
 		return err
 	}
-	stmt, err := tx.Prepare("update txns set height=-1 where txid=?")
+	stmt, err := tx.Prepare("update " + t.table + " set height=-1 where txid=?")
 	if err != nil {
 		return err
 	}
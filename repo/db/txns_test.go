@@ -15,7 +15,8 @@ func init() {
 	conn, _ := sql.Open("sqlite3", ":memory:")
 	initDatabaseTables(conn, "")
 	txdb = TxnsDB{
-		db: conn,
+		db:    conn,
+		table: "txns",
 	}
 }
 
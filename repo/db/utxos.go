@@ -12,15 +12,16 @@ import (
 )
 
 type UtxoDB struct {
-	db   *sql.DB
-	lock sync.RWMutex
+	db    *sql.DB
+	table string
+	lock  sync.RWMutex
 }
 
 func (u *UtxoDB) Put(utxo spvwallet.Utxo) error {
 	u.lock.Lock()
 	defer u.lock.Unlock()
 	tx, _ := u.db.Begin()
-	stmt, err := tx.Prepare("insert or replace into utxos(outpoint, value, height, scriptPubKey, freeze) values(?,?,?,?,?)")
+	stmt, err := tx.Prepare("insert or replace into " + u.table + "(outpoint, value, height, scriptPubKey, freeze) values(?,?,?,?,?)")
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -44,7 +45,7 @@ func (u *UtxoDB) GetAll() ([]spvwallet.Utxo, error) {
 	u.lock.RLock()
 	defer u.lock.RUnlock()
 	var ret []spvwallet.Utxo
-	stm := "select outpoint, value, height, scriptPubKey, freeze from utxos"
+	stm := "select outpoint, value, height, scriptPubKey, freeze from " + u.table
 	rows, err := u.db.Query(stm)
 	if err != nil {
 		return ret, err
@@ -91,7 +92,7 @@ func (u *UtxoDB) Freeze(utxo spvwallet.Utxo) error {
 	u.lock.Lock()
 	defer u.lock.Unlock()
 	outpoint := utxo.Op.Hash.String() + ":" + strconv.Itoa(int(utxo.Op.Index))
-	_, err := u.db.Exec("update utxos set freeze=? where outpoint=?", 1, outpoint)
+	_, err := u.db.Exec("update "+u.table+" set freeze=? where outpoint=?", 1, outpoint)
 	if err != nil {
 		return err
 	}
@@ -102,7 +103,7 @@ func (u *UtxoDB) Delete(utxo spvwallet.Utxo) error {
 	u.lock.Lock()
 	defer u.lock.Unlock()
 	outpoint := utxo.Op.Hash.String() + ":" + strconv.Itoa(int(utxo.Op.Index))
-	_, err := u.db.Exec("delete from utxos where outpoint=?", outpoint)
+	_, err := u.db.Exec("delete from "+u.table+" where outpoint=?", outpoint)
 	if err != nil {
 		return err
 	}
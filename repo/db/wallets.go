@@ -0,0 +1,70 @@
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/OpenBazaar/spvwallet"
+)
+
+/* Wallets for other coins are created from the same seed as the default wallet so
+   they have the same scripts. Each keeps its keys, outputs and transactions in tables
+   of its own named after the coin. */
+const walletTables = `
+create table if not exists %[1]s_keys (scriptPubKey text primary key not null, purpose integer, keyIndex integer, used integer, key text);
+create table if not exists %[1]s_utxos (outpoint text primary key not null, value integer, height integer, scriptPubKey text, freeze int);
+create table if not exists %[1]s_stxos (outpoint text primary key not null, value integer, height integer, scriptPubKey text, spendHeight integer, spendTxid text);
+create table if not exists %[1]s_txns (txid text primary key not null, value integer, height integer, timestamp integer, tx blob);
+create table if not exists %[1]s_watchedscripts (scriptPubKey text primary key not null);
+`
+
+var coinPattern = regexp.MustCompile(`^[a-z0-9]+$`)
+
+type walletDatastore struct {
+	keys           spvwallet.Keys
+	stxos          spvwallet.Stxos
+	txns           spvwallet.Txns
+	utxos          spvwallet.Utxos
+	watchedScripts spvwallet.WatchedScripts
+}
+
+// Return the datastore for the wallet of the given coin, creating its tables if needed
+func (d *SQLiteDatastore) WalletDatastore(coin string) (spvwallet.Datastore, error) {
+	coin = strings.ToLower(coin)
+	if !coinPattern.MatchString(coin) {
+		return nil, fmt.Errorf("Invalid coin %s", coin)
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if _, err := d.db.Exec(fmt.Sprintf(walletTables, coin)); err != nil {
+		return nil, err
+	}
+	return &walletDatastore{
+		keys:           &KeysDB{db: d.db, table: coin + "_keys"},
+		stxos:          &StxoDB{db: d.db, table: coin + "_stxos"},
+		txns:           &TxnsDB{db: d.db, table: coin + "_txns"},
+		utxos:          &UtxoDB{db: d.db, table: coin + "_utxos"},
+		watchedScripts: &WatchedScriptsDB{db: d.db, table: coin + "_watchedscripts"},
+	}, nil
+}
+
+func (w *walletDatastore) Keys() spvwallet.Keys {
+	return w.keys
+}
+
+func (w *walletDatastore) Stxos() spvwallet.Stxos {
+	return w.stxos
+}
+
+func (w *walletDatastore) Txns() spvwallet.Txns {
+	return w.txns
+}
+
+func (w *walletDatastore) Utxos() spvwallet.Utxos {
+	return w.utxos
+}
+
+func (w *walletDatastore) WatchedScripts() spvwallet.WatchedScripts {
+	return w.watchedScripts
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/OpenBazaar/spvwallet"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+func TestWalletDatastore(t *testing.T) {
+	ltc, err := testDB.WalletDatastore("LTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, _ := chainhash.NewHashFromStr("a0d4cbcd8d0694e1132400b5e114b31bc3e0d8a2ac26e054f78727c95485b528")
+	utxo := spvwallet.Utxo{
+		Op:           *wire.NewOutPoint(h, 0),
+		AtHeight:     300000,
+		Value:        100000000,
+		ScriptPubkey: []byte("scriptpubkey"),
+	}
+	if err := testDB.Utxos().Put(utxo); err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Utxos().Delete(utxo)
+	if err := ltc.Utxos().Put(utxo); err != nil {
+		t.Fatal(err)
+	}
+	if err := ltc.Utxos().Delete(utxo); err != nil {
+		t.Fatal(err)
+	}
+	utxos, err := testDB.Utxos().GetAll()
+	if err != nil || len(utxos) != 1 {
+		t.Error("Litecoin wallet changed the default wallet's outputs")
+	}
+	if utxos, err := ltc.Utxos().GetAll(); err != nil || len(utxos) != 0 {
+		t.Error("Output was not deleted from the litecoin wallet")
+	}
+
+	// The tables already exist the second time
+	if _, err := testDB.WalletDatastore("ltc"); err != nil {
+		t.Error(err)
+	}
+	if _, err := testDB.WalletDatastore("ltc; drop table keys"); err == nil {
+		t.Error("Created wallet tables with an invalid name")
+	}
+}
@@ -7,15 +7,16 @@ import (
 )
 
 type WatchedScriptsDB struct {
-	db   *sql.DB
-	lock sync.RWMutex
+	db    *sql.DB
+	table string
+	lock  sync.RWMutex
 }
 
 func (w *WatchedScriptsDB) Put(scriptPubKey []byte) error {
 	w.lock.Lock()
 	defer w.lock.Unlock()
 	tx, _ := w.db.Begin()
-	stmt, err := tx.Prepare("insert or replace into watchedscripts(scriptPubKey) values(?)")
+	stmt, err := tx.Prepare("insert or replace into " + w.table + "(scriptPubKey) values(?)")
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -34,7 +35,7 @@ func (w *WatchedScriptsDB) GetAll() ([][]byte, error) {
 	w.lock.RLock()
 	defer w.lock.RUnlock()
 	var ret [][]byte
-	stm := "select scriptPubKey from watchedscripts"
+	stm := "select scriptPubKey from " + w.table
 	rows, err := w.db.Query(stm)
 	if err != nil {
 		return ret, err
@@ -57,7 +58,7 @@ func (w *WatchedScriptsDB) GetAll() ([][]byte, error) {
 func (w *WatchedScriptsDB) Delete(scriptPubKey []byte) error {
 	w.lock.Lock()
 	defer w.lock.Unlock()
-	_, err := w.db.Exec("delete from watchedscripts where scriptPubKey=?", hex.EncodeToString(scriptPubKey))
+	_, err := w.db.Exec("delete from "+w.table+" where scriptPubKey=?", hex.EncodeToString(scriptPubKey))
 	if err != nil {
 		return err
 	}
@@ -13,7 +13,8 @@ func init() {
 	conn, _ := sql.Open("sqlite3", ":memory:")
 	initDatabaseTables(conn, "")
 	wsdb = WatchedScriptsDB{
-		db: conn,
+		db:    conn,
+		table: "watchedscripts",
 	}
 }
 
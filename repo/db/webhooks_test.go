@@ -0,0 +1,77 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+func TestWebhooksDB(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	wdb := WebhooksDB{db: conn}
+	hook := repo.Webhook{Id: "abc", URL: "https://example.com/hook", Secret: "secret", Events: []string{"order"}, Created: time.Unix(1500000000, 0)}
+	if err := wdb.Put(hook); err != nil {
+		t.Fatal(err)
+	}
+	ret, err := wdb.Get("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.URL != hook.URL || ret.Secret != "secret" || len(ret.Events) != 1 || ret.Events[0] != "order" || !ret.Created.Equal(hook.Created) {
+		t.Error("Returned wrong webhook")
+	}
+	hooks, err := wdb.GetAll()
+	if err != nil || len(hooks) != 1 {
+		t.Error("Returned wrong webhooks", err)
+	}
+	if err := wdb.Delete("abc"); err != nil {
+		t.Error(err)
+	}
+	if err := wdb.Delete("abc"); err != repo.ErrWebhookNotFound {
+		t.Error("Deleting an unknown webhook returned", err)
+	}
+	if _, err := wdb.Get("abc"); err == nil {
+		t.Error("Returned a deleted webhook")
+	}
+}
+
+func TestWebhookDeliveriesDB(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	ddb := WebhookDeliveriesDB{db: conn}
+	now := time.Unix(1500000000, 0)
+	delivery := repo.WebhookDelivery{WebhookId: "abc", URL: "https://example.com/hook", Event: "order", Payload: "{}", Status: repo.WebhookDeliveryPending, NextAttempt: now, Timestamp: now}
+	id, err := ddb.Put(delivery)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delivery.NextAttempt = now.Add(time.Hour)
+	if _, err := ddb.Put(delivery); err != nil {
+		t.Fatal(err)
+	}
+	due, err := ddb.GetDue(now)
+	if err != nil || len(due) != 1 || due[0].Id != id {
+		t.Fatal("Returned wrong due deliveries", err)
+	}
+	due[0].Status = repo.WebhookDeliveryDelivered
+	due[0].Attempts = 1
+	due[0].ResponseCode = 200
+	if err := ddb.Update(due[0]); err != nil {
+		t.Fatal(err)
+	}
+	due, err = ddb.GetDue(now.Add(2 * time.Hour))
+	if err != nil || len(due) != 1 || due[0].Id == id {
+		t.Error("Returned a delivered delivery as due", err)
+	}
+	delivered, total, err := ddb.GetAll(repo.WebhookDeliveryDelivered, 0, -1)
+	if err != nil || total != 1 || len(delivered) != 1 || delivered[0].ResponseCode != 200 || delivered[0].Attempts != 1 {
+		t.Error("Returned wrong delivered deliveries", err)
+	}
+	all, total, err := ddb.GetAll("", 0, 1)
+	if err != nil || total != 2 || len(all) != 1 || all[0].Id == id {
+		t.Error("Returned wrong page of deliveries", err)
+	}
+}
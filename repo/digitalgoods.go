@@ -0,0 +1,8 @@
+package repo
+
+import "errors"
+
+var (
+	ErrDigitalAssetNotFound = errors.New("Digital asset not found")
+	ErrNoLicenseKeys        = errors.New("No license keys left")
+)
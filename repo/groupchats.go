@@ -0,0 +1,15 @@
+package repo
+
+import "errors"
+
+var ErrGroupChatNotFound = errors.New("Group chat not found")
+
+// Return whether a peer is one of the group's members
+func (g GroupChat) IsMember(peerId string) bool {
+	for _, m := range g.Members {
+		if m == peerId {
+			return true
+		}
+	}
+	return false
+}
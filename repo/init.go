@@ -201,6 +201,9 @@ func addConfigExtensions(repoRoot string, testnet bool) error {
 	if err := extendConfigFile(r, "Wallet", w); err != nil {
 		return err
 	}
+	if err := extendConfigFile(r, "Wallets", []CoinWalletConfig{}); err != nil {
+		return err
+	}
 	if err := extendConfigFile(r, "Resolver", "https://resolver.onename.com/"); err != nil {
 		return err
 	}
@@ -210,6 +213,12 @@ func addConfigExtensions(repoRoot string, testnet bool) error {
 	if err := extendConfigFile(r, "Dropbox-api-token", ""); err != nil {
 		return err
 	}
+	if err := extendConfigFile(r, "S3-storage", S3Config{Region: "us-east-1"}); err != nil {
+		return err
+	}
+	if err := extendConfigFile(r, "Message-retriever-interval", DefaultMessageRetrieverInterval.String()); err != nil {
+		return err
+	}
 	if err := extendConfigFile(r, "JSON-API", a); err != nil {
 		return err
 	}
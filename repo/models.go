@@ -2,23 +2,26 @@ package repo
 
 import (
 	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
 )
 
 type SettingsData struct {
-	PaymentDataInQR    *bool              `json:"paymentDataInQR"`
-	ShowNotifications  *bool              `json:"showNotifications"`
-	ShowNsfw           *bool              `json:"showNsfw"`
-	ShippingAddresses  *[]ShippingAddress `json:"shippingAddresses"`
-	LocalCurrency      *string            `json:"localCurrency"`
-	Country            *string            `json:"country"`
-	Language           *string            `json:"language"`
-	TermsAndConditions *string            `json:"termsAndConditions"`
-	RefundPolicy       *string            `json:"refundPolicy"`
-	BlockedNodes       *[]string          `json:"blockedNodes"`
-	StoreModerators    *[]string          `json:"storeModerators"`
-	MisPaymentBuffer   *float32           `json:"mispaymentBuffer"`
-	SMTPSettings       *SMTPSettings      `json:"smtpSettings"`
-	Version            *string            `json:"version"`
+	PaymentDataInQR    *bool                 `json:"paymentDataInQR"`
+	ShowNotifications  *bool                 `json:"showNotifications"`
+	ShowNsfw           *bool                 `json:"showNsfw"`
+	ShippingAddresses  *[]ShippingAddress    `json:"shippingAddresses"`
+	LocalCurrency      *string               `json:"localCurrency"`
+	Country            *string               `json:"country"`
+	Language           *string               `json:"language"`
+	TermsAndConditions *string               `json:"termsAndConditions"`
+	RefundPolicy       *string               `json:"refundPolicy"`
+	BlockedNodes       *[]string             `json:"blockedNodes"`
+	StoreModerators    *[]string             `json:"storeModerators"`
+	MisPaymentBuffer   *float32              `json:"mispaymentBuffer"`
+	SMTPSettings       *SMTPSettings         `json:"smtpSettings"`
+	OrderRules         *map[string]OrderRule `json:"orderRules"`
+	Version            *string               `json:"version"`
 }
 
 type ShippingAddress struct {
@@ -49,13 +52,27 @@ type Coupon struct {
 }
 
 type ChatMessage struct {
-	MessageId string    `json:"messageId"`
-	PeerId    string    `json:"peerId"`
-	Subject   string    `json:"subject"`
-	Message   string    `json:"message"`
-	Read      bool      `json:"read"`
-	Outgoing  bool      `json:"outgoing"`
-	Timestamp time.Time `json:"timestamp"`
+	MessageId   string           `json:"messageId"`
+	PeerId      string           `json:"peerId"`
+	Subject     string           `json:"subject"`
+	Message     string           `json:"message"`
+	Read        bool             `json:"read"`
+	Outgoing    bool             `json:"outgoing"`
+	Timestamp   time.Time        `json:"timestamp"`
+	Attachments []ChatAttachment `json:"attachments,omitempty"`
+}
+
+/* A file sent with a chat message. Id is the IPFS hash of the file encrypted with Key
+   and Thumbnail, for images, the hash of an encrypted thumbnail. The key is never
+   returned by the API as the node decrypts attachments itself. */
+type ChatAttachment struct {
+	Id        string `json:"id"`
+	MessageId string `json:"messageId"`
+	Filename  string `json:"filename"`
+	MediaType string `json:"mediaType"`
+	Size      int64  `json:"size"`
+	Thumbnail string `json:"thumbnail,omitempty"`
+	Key       []byte `json:"-"`
 }
 
 type ChatConversation struct {
@@ -75,6 +92,200 @@ type Metadata struct {
 	CanBumpFee bool
 }
 
+/* The exchange rate to the user's local currency at the time a transaction was
+   first seen, along with the fee we paid if we funded it. Transactions which were
+   already confirmed when first seen have no rate and an empty Currency. Fee is nil
+   when it couldn't be determined, for example when none or only some of the inputs
+   are ours. */
+type TxAccountingRecord struct {
+	Txid         string
+	Currency     string
+	ExchangeRate float64
+	Fee          *int64
+	Timestamp    time.Time
+}
+
+// A named bearer token for the API and the scopes it was granted
+type APIToken struct {
+	Name    string    `json:"name"`
+	Scopes  []string  `json:"scopes"`
+	Created time.Time `json:"created"`
+}
+
+// An endpoint notifications are posted to. Only the listed events are sent, or all of them if none are.
+type Webhook struct {
+	Id      string    `json:"id"`
+	URL     string    `json:"url"`
+	Secret  string    `json:"-"`
+	Events  []string  `json:"events"`
+	Created time.Time `json:"created"`
+}
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+/* A notification to post to a webhook. Pending deliveries are retried until they
+   succeed or run out of attempts and are kept afterwards as a log. */
+type WebhookDelivery struct {
+	Id           int64     `json:"id"`
+	WebhookId    string    `json:"webhookId"`
+	URL          string    `json:"url"`
+	Event        string    `json:"event"`
+	Payload      string    `json:"payload"`
+	Status       string    `json:"status"`
+	Attempts     int       `json:"attempts"`
+	ResponseCode int       `json:"responseCode"`
+	Error        string    `json:"error"`
+	NextAttempt  time.Time `json:"nextAttempt"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+const (
+	OutgoingMessageSentDirect    = "sent-direct"
+	OutgoingMessageStoredOffline = "stored-offline"
+	OutgoingMessageAcked         = "acked"
+	OutgoingMessageFailed        = "failed"
+)
+
+/* A message we've sent another peer and how far it got. Messages which couldn't be
+   sent directly are stored offline and kept with their serialized pb.Message so
+   direct delivery can be retried until it succeeds or the recipient acks the
+   offline copy. Reference is the chat message or order id the message is about. */
+type OutgoingMessage struct {
+	Id          string    `json:"id"`
+	PeerId      string    `json:"peerId"`
+	MessageType string    `json:"messageType"`
+	Reference   string    `json:"reference"`
+	Message     []byte    `json:"-"`
+	State       string    `json:"state"`
+	PointerId   string    `json:"pointerId"`
+	Attempts    int       `json:"attempts"`
+	Error       string    `json:"error"`
+	Timestamp   time.Time `json:"timestamp"`
+	Updated     time.Time `json:"updated"`
+}
+
+/* A group conversation. The member list is set by the creator and Members is our
+   copy of the latest signed list, which is kept serialized so it can be attached
+   to the messages we send. Updated is when that list was signed. */
+type GroupChat struct {
+	GroupId       string    `json:"groupId"`
+	Creator       string    `json:"creator"`
+	Subject       string    `json:"subject"`
+	Members       []string  `json:"members"`
+	SignedMembers []byte    `json:"-"`
+	Updated       time.Time `json:"updated"`
+}
+
+type GroupChatMessage struct {
+	MessageId string    `json:"messageId"`
+	GroupId   string    `json:"groupId"`
+	PeerId    string    `json:"peerId"`
+	Message   string    `json:"message"`
+	Read      bool      `json:"read"`
+	Outgoing  bool      `json:"outgoing"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type CartItemOption struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type CartItemShipping struct {
+	Name    string `json:"name"`
+	Service string `json:"service"`
+}
+
+// An item in the shopping cart. Apart from the id and vendor it's the same as an item in a purchase.
+type CartItem struct {
+	Id          string           `json:"id"`
+	VendorId    string           `json:"vendorId"`
+	ListingHash string           `json:"listingHash"`
+	Quantity    int              `json:"quantity"`
+	Options     []CartItemOption `json:"options"`
+	Shipping    CartItemShipping `json:"shipping"`
+	Memo        string           `json:"memo"`
+	Coupons     []string         `json:"coupons"`
+	Added       time.Time        `json:"added"`
+}
+
+// The orders placed, one per vendor, by checking out the cart
+type CheckoutGroup struct {
+	Id        string               `json:"id"`
+	Orders    []CheckoutGroupOrder `json:"orders"`
+	Timestamp time.Time            `json:"timestamp"`
+}
+
+// Orders in a group are paid together if the wallet can pay several addresses at once and one by one if not
+type CheckoutGroupOrder struct {
+	OrderId        string `json:"orderId"`
+	VendorId       string `json:"vendorId"`
+	PaymentAddress string `json:"paymentAddress"`
+	Amount         uint64 `json:"amount"`
+	FundingTxid    string `json:"fundingTxid,omitempty"`
+}
+
+// A file delivered automatically to buyers of a digital listing. Data is only loaded by Get.
+type DigitalAsset struct {
+	Slug      string    `json:"slug"`
+	Filename  string    `json:"filename"`
+	MediaType string    `json:"mediaType"`
+	Size      int64     `json:"size"`
+	Data      []byte    `json:"-"`
+	Added     time.Time `json:"added"`
+}
+
+// A license key in a listing's pool. OrderId is set once it has been handed out.
+type LicenseKey struct {
+	Slug    string    `json:"slug"`
+	Key     string    `json:"key"`
+	OrderId string    `json:"orderId,omitempty"`
+	Added   time.Time `json:"added"`
+}
+
+// The rule for listings without one of their own
+const DefaultOrderRule = "*"
+
+/* How a vendor's node handles orders for a listing without waiting for the vendor.
+   Rules are keyed by listing slug in the settings. The zero value does nothing. */
+type OrderRule struct {
+	// Confirm funded offline orders whose payment is at most this amount in the wallet's smallest unit
+	AutoConfirmMax uint64 `json:"autoConfirmMax"`
+
+	// Fulfill funded orders for digital goods and services. Digital goods without a file
+	// or license keys to deliver are sent the url and password.
+	AutoFulfill      bool   `json:"autoFulfill"`
+	DeliveryUrl      string `json:"deliveryUrl,omitempty"`
+	DeliveryPassword string `json:"deliveryPassword,omitempty"`
+
+	// Reject orders for physical goods to countries none of the listing's shipping options go to
+	RejectOutsideShippingRegions bool `json:"rejectOutsideShippingRegions"`
+}
+
+const (
+	OrderActionConfirmed = "CONFIRMED"
+	OrderActionRejected  = "REJECTED"
+	OrderActionFulfilled = "FULFILLED"
+)
+
+// Something the node did to an order on the vendor's behalf
+type OrderEvent struct {
+	OrderId   string    `json:"orderId"`
+	Action    string    `json:"action"`
+	Detail    string    `json:"detail,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type ExchangeRateSample struct {
+	Currency  string    `json:"currency"`
+	Rate      float64   `json:"rate"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
 type Purchase struct {
 	OrderId            string    `json:"orderId"`
 	Timestamp          time.Time `json:"timestamp"`
@@ -88,6 +299,8 @@ type Purchase struct {
 	State              string    `json:"state"`
 	Read               bool      `json:"read"`
 	UnreadChatMessages int       `json:"unreadChatMessages"`
+	CheckoutGroup      string    `json:"checkoutGroup,omitempty"`
+	FundingTxid        string    `json:"fundingTxid,omitempty"`
 }
 
 type Sale struct {
@@ -105,6 +318,26 @@ type Sale struct {
 	UnreadChatMessages int       `json:"unreadChatMessages"`
 }
 
+const (
+	OrderSortTimestamp = "timestamp"
+	OrderSortTotal     = "total"
+)
+
+/* Filters and sorting for the purchases or sales returned by Query. Unset fields
+   don't filter. Without a sort field orders are returned in the order they were
+   first saved. A negative limit returns every match after the offset. */
+type OrderQuery struct {
+	States    []pb.OrderState
+	Funded    *bool
+	Read      *bool
+	From      time.Time
+	To        time.Time
+	SortBy    string
+	Ascending bool
+	Offset    int
+	Limit     int
+}
+
 type Case struct {
 	CaseId             string    `json:"caseId"`
 	Timestamp          time.Time `json:"timestamp"`
@@ -120,3 +353,107 @@ type Case struct {
 	Read               bool      `json:"read"`
 	UnreadChatMessages int       `json:"unreadChatMessages"`
 }
+
+type BidState int
+
+const (
+	BidPending BidState = iota
+	BidWon
+	BidLost
+)
+
+func (s BidState) String() string {
+	switch s {
+	case BidPending:
+		return "PENDING"
+	case BidWon:
+		return "WON"
+	case BidLost:
+		return "LOST"
+	}
+	return "UNKNOWN"
+}
+
+type Bid struct {
+	OrderId     string    `json:"orderId"`
+	Slug        string    `json:"slug"`
+	Timestamp   time.Time `json:"timestamp"`
+	Title       string    `json:"title"`
+	Thumbnail   string    `json:"thumbnail"`
+	Amount      uint64    `json:"amount"`
+	BuyerId     string    `json:"buyerId"`
+	BuyerHandle string    `json:"buyerHandle"`
+	VendorId    string    `json:"vendorId"`
+	State       string    `json:"state"`
+	Outgoing    bool      `json:"outgoing"`
+}
+
+type PledgeState int
+
+const (
+	PledgePending PledgeState = iota
+	PledgeClaimed
+	PledgeRefunded
+	PledgeCanceled
+)
+
+func (s PledgeState) String() string {
+	switch s {
+	case PledgePending:
+		return "PENDING"
+	case PledgeClaimed:
+		return "CLAIMED"
+	case PledgeRefunded:
+		return "REFUNDED"
+	case PledgeCanceled:
+		return "CANCELED"
+	}
+	return "UNKNOWN"
+}
+
+type Pledge struct {
+	OrderId     string    `json:"orderId"`
+	Slug        string    `json:"slug"`
+	Timestamp   time.Time `json:"timestamp"`
+	Amount      uint64    `json:"amount"`
+	BuyerId     string    `json:"buyerId"`
+	BuyerHandle string    `json:"buyerHandle"`
+	Funded      bool      `json:"funded"`
+	State       string    `json:"state"`
+}
+
+const (
+	SearchTypeListing  = "listing"
+	SearchTypePurchase = "purchase"
+	SearchTypeSale     = "sale"
+	SearchTypeChat     = "chat"
+)
+
+type SearchListing struct {
+	Slug  string
+	Title string
+	Tags  []string
+}
+
+type SearchQuery struct {
+	Terms  []string
+	Types  []string
+	States []pb.OrderState
+	From   time.Time
+	To     time.Time
+	Offset int
+	Limit  int
+}
+
+type SearchResult struct {
+	Type      string     `json:"type"`
+	Id        string     `json:"id"`
+	Title     string     `json:"title,omitempty"`
+	Snippet   string     `json:"snippet,omitempty"`
+	PeerId    string     `json:"peerId,omitempty"`
+	Handle    string     `json:"handle,omitempty"`
+	Subject   string     `json:"subject,omitempty"`
+	State     string     `json:"state,omitempty"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	Rank      float64    `json:"rank"`
+}
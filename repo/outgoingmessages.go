@@ -0,0 +1,5 @@
+package repo
+
+import "errors"
+
+var ErrOutgoingMessageNotFound = errors.New("Outgoing message not found")
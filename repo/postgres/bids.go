@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/OpenBazaar/jsonpb"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"sync"
+	"time"
+)
+
+type BidsDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (b *BidsDB) Put(orderID string, contract pb.RicardianContract, state repo.BidState, outgoing bool) error {
+	if len(contract.BuyerOrder.Items) == 0 {
+		return errors.New("Bid does not contain an item")
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	outgoingInt := 0
+	if outgoing {
+		outgoingInt = 1
+	}
+	m := jsonpb.Marshaler{
+		EnumsAsInts:  false,
+		EmitDefaults: true,
+		Indent:       "    ",
+		OrigName:     false,
+	}
+	out, err := m.MarshalToString(&contract)
+	if err != nil {
+		return err
+	}
+
+	tx, err := b.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert into bids(orderID, contract, state, slug, amount, timestamp, title, thumbnail, buyerID, buyerBlockchainID, vendorID, outgoing) values($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12) on conflict (orderID) do update set contract=excluded.contract, state=excluded.state, slug=excluded.slug, amount=excluded.amount, timestamp=excluded.timestamp, title=excluded.title, thumbnail=excluded.thumbnail, buyerID=excluded.buyerID, buyerBlockchainID=excluded.buyerBlockchainID, vendorID=excluded.vendorID, outgoing=excluded.outgoing")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(
+		orderID,
+		out,
+		int(state),
+		contract.VendorListings[0].Slug,
+		int(contract.BuyerOrder.Items[0].BidAmount),
+		int(contract.BuyerOrder.Timestamp.Seconds),
+		contract.VendorListings[0].Item.Title,
+		contract.VendorListings[0].Item.Images[0].Tiny,
+		contract.BuyerOrder.BuyerID.Guid,
+		contract.BuyerOrder.BuyerID.BlockchainID,
+		contract.VendorListings[0].VendorID.Guid,
+		outgoingInt,
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (b *BidsDB) UpdateState(orderID string, state repo.BidState) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	_, err := b.db.Exec("update bids set state=$1 where orderID=$2", int(state), orderID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (b *BidsDB) GetByOrderId(orderID string) (*pb.RicardianContract, repo.BidState, bool, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	stmt, err := b.db.Prepare("select contract, state, outgoing from bids where orderID=$1")
+	if err != nil {
+		return nil, repo.BidState(0), false, err
+	}
+	defer stmt.Close()
+	var contract []byte
+	var stateInt int
+	var outgoingInt int
+	err = stmt.QueryRow(orderID).Scan(&contract, &stateInt, &outgoingInt)
+	if err != nil {
+		return nil, repo.BidState(0), false, err
+	}
+	rc := new(pb.RicardianContract)
+	err = jsonpb.UnmarshalString(string(contract), rc)
+	if err != nil {
+		return nil, repo.BidState(0), false, err
+	}
+	outgoing := false
+	if outgoingInt == 1 {
+		outgoing = true
+	}
+	return rc, repo.BidState(stateInt), outgoing, nil
+}
+
+func (b *BidsDB) GetBySlug(slug string) ([]repo.Bid, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	rows, err := b.db.Query("select orderID, slug, timestamp, title, thumbnail, amount, buyerID, buyerBlockchainID, vendorID, state, outgoing from bids where slug=$1 and outgoing=0 order by amount desc, timestamp asc", slug)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanBids(rows)
+}
+
+func (b *BidsDB) GetOutgoing(offsetId string, limit int) ([]repo.Bid, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	var stm string
+	var args []interface{}
+	if offsetId != "" {
+		stm = "select orderID, slug, timestamp, title, thumbnail, amount, buyerID, buyerBlockchainID, vendorID, state, outgoing from bids where outgoing=1 and rowid<(select rowid from bids where orderID=$1) order by rowid desc limit " + limitClause(limit)
+		args = append(args, offsetId)
+	} else {
+		stm = "select orderID, slug, timestamp, title, thumbnail, amount, buyerID, buyerBlockchainID, vendorID, state, outgoing from bids where outgoing=1 order by rowid desc limit " + limitClause(limit)
+	}
+	rows, err := b.db.Query(stm, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanBids(rows)
+}
+
+func (b *BidsDB) Delete(orderID string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	_, err := b.db.Exec("delete from bids where orderID=$1", orderID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func scanBids(rows *sql.Rows) ([]repo.Bid, error) {
+	var ret []repo.Bid
+	for rows.Next() {
+		var orderID, slug, title, thumbnail, buyerID, buyerHandle, vendorID string
+		var timestamp, amount, stateInt, outgoingInt int
+		if err := rows.Scan(&orderID, &slug, &timestamp, &title, &thumbnail, &amount, &buyerID, &buyerHandle, &vendorID, &stateInt, &outgoingInt); err != nil {
+			return ret, err
+		}
+		outgoing := false
+		if outgoingInt > 0 {
+			outgoing = true
+		}
+		ret = append(ret, repo.Bid{
+			OrderId:     orderID,
+			Slug:        slug,
+			Timestamp:   time.Unix(int64(timestamp), 0),
+			Title:       title,
+			Thumbnail:   thumbnail,
+			Amount:      uint64(amount),
+			BuyerId:     buyerID,
+			BuyerHandle: buyerHandle,
+			VendorId:    vendorID,
+			State:       repo.BidState(stateInt).String(),
+			Outgoing:    outgoing,
+		})
+	}
+	return ret, nil
+}
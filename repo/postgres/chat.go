@@ -0,0 +1,283 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"sync"
+	"time"
+)
+
+type ChatDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (c *ChatDB) Put(messageId string, peerId string, subject string, message string, timestamp time.Time, read bool, outgoing bool) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	stm := `insert into chat(messageID, peerID, subject, message, read, timestamp, outgoing) values($1,$2,$3,$4,$5,$6,$7)`
+	stmt, err := tx.Prepare(stm)
+	if err != nil {
+		return err
+	}
+	readInt := 0
+	if read {
+		readInt = 1
+	}
+
+	outgoingInt := 0
+	if outgoing {
+		outgoingInt = 1
+	}
+
+	defer stmt.Close()
+	_, err = stmt.Exec(
+		messageId,
+		peerId,
+		subject,
+		message,
+		readInt,
+		int(timestamp.Unix()),
+		outgoingInt,
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (c *ChatDB) GetConversations() []repo.ChatConversation {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	var ret []repo.ChatConversation
+
+	stm := "select peerID from chat group by peerID order by max(timestamp) desc"
+	rows, err := c.db.Query(stm)
+	if err != nil {
+		return ret
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var peerId string
+		if err := rows.Scan(&peerId); err != nil {
+			continue
+		}
+		ids = append(ids, peerId)
+	}
+	for _, peerId := range ids {
+		stm := "select count(*) from chat where peerID=$1 and read=0 and subject='' and outgoing=0"
+		row := c.db.QueryRow(stm, peerId)
+		var count int
+		row.Scan(&count)
+		stm = "select timestamp, message, outgoing from chat where peerID=$1 and subject='' order by timestamp desc limit 1"
+		row = c.db.QueryRow(stm, peerId)
+		var m string
+		var ts int
+		var outInt int
+		row.Scan(&ts, &m, &outInt)
+		outgoing := false
+		if outInt > 0 {
+			outgoing = true
+		}
+		timestamp := time.Unix(int64(ts), 0)
+		convo := repo.ChatConversation{
+			PeerId:    peerId,
+			Unread:    count,
+			Last:      m,
+			Timestamp: timestamp,
+			Outgoing:  outgoing,
+		}
+		ret = append(ret, convo)
+	}
+	return ret
+}
+
+func (c *ChatDB) GetMessages(peerID string, subject string, offsetId string, limit int) []repo.ChatMessage {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	var ret []repo.ChatMessage
+
+	var rows *sql.Rows
+	var err error
+	if offsetId != "" {
+		rows, err = c.db.Query("select messageID, message, read, timestamp, outgoing from chat where subject=$1 and peerID=$2 and timestamp<(select timestamp from chat where messageID=$3) order by timestamp desc limit "+limitClause(limit), subject, peerID, offsetId)
+	} else {
+		rows, err = c.db.Query("select messageID, message, read, timestamp, outgoing from chat where subject=$1 and peerID=$2 order by timestamp desc limit "+limitClause(limit), subject, peerID)
+	}
+	if err != nil {
+		log.Error(err)
+		return ret
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var msgID string
+		var message string
+		var readInt int
+		var timestampInt int
+		var outgoingInt int
+		if err := rows.Scan(&msgID, &message, &readInt, &timestampInt, &outgoingInt); err != nil {
+			continue
+		}
+		var read bool
+		if readInt == 1 {
+			read = true
+		}
+		var outgoing bool
+		if outgoingInt == 1 {
+			outgoing = true
+		}
+		timestamp := time.Unix(int64(timestampInt), 0)
+		chatMessage := repo.ChatMessage{
+			PeerId:    peerID,
+			MessageId: msgID,
+			Subject:   subject,
+			Message:   message,
+			Read:      read,
+			Timestamp: timestamp,
+			Outgoing:  outgoing,
+		}
+		ret = append(ret, chatMessage)
+	}
+	for i := range ret {
+		ret[i].Attachments = c.attachments(ret[i].MessageId)
+	}
+	return ret
+}
+
+func (c *ChatDB) MarkAsRead(peerID string, subject string, outgoing bool, messageId string) (string, bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	updated := false
+	outgoingInt := 0
+	if outgoing {
+		outgoingInt = 1
+	}
+	var stmt *sql.Stmt
+	var tx *sql.Tx
+	var err error
+	if messageId != "" {
+		stm := "select messageID from chat where peerID=$1 and subject=$2 and outgoing=$3 and read=0 and timestamp<=(select timestamp from chat where messageID=$4) limit 1"
+		rows, err := c.db.Query(stm, peerID, subject, outgoingInt, messageId)
+		if err != nil {
+			return "", updated, err
+		}
+		if rows.Next() {
+			updated = true
+		}
+		rows.Close()
+		tx, err = c.db.Begin()
+		if err != nil {
+			return "", updated, err
+		}
+		stmt, _ = tx.Prepare("update chat set read=1 where peerID=$1 and subject=$2 and outgoing=$3 and timestamp<=(select timestamp from chat where messageID=$4)")
+		_, err = stmt.Exec(peerID, subject, outgoingInt, messageId)
+	} else {
+		stm := "select messageID from chat where peerID=$1 and subject=$2 and outgoing=$3 and read=0 limit 1"
+		rows, err := c.db.Query(stm, peerID, subject, outgoingInt)
+		if err != nil {
+			return "", updated, err
+		}
+		if rows.Next() {
+			updated = true
+		}
+		rows.Close()
+		tx, err = c.db.Begin()
+		if err != nil {
+			return "", updated, err
+		}
+		stmt, _ = tx.Prepare("update chat set read=1 where peerID=$1 and subject=$2 and outgoing=$3")
+		_, err = stmt.Exec(peerID, subject, outgoingInt)
+	}
+	defer stmt.Close()
+	if err != nil {
+		tx.Rollback()
+		return "", updated, err
+	}
+	tx.Commit()
+
+	stmt2, err := c.db.Prepare("select timestamp, messageID from chat where peerID=$1 and subject=$2 and outgoing=$3 order by timestamp desc limit 1")
+	if err != nil {
+		return "", updated, err
+	}
+	defer stmt2.Close()
+	var ts int
+	var msgId string
+	err = stmt2.QueryRow(peerID, subject, outgoingInt).Scan(&ts, &msgId)
+	if err != nil {
+		return "", updated, err
+	}
+	return msgId, updated, nil
+}
+
+func (c *ChatDB) GetUnreadCount(subject string) (int, error) {
+	stm := "select count(*) from chat where read=0 and subject=$1 and outgoing=0"
+	row := c.db.QueryRow(stm, subject)
+	var count int
+	err := row.Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (c *ChatDB) DeleteMessage(msgID string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.db.Exec("delete from chatattachments where messageID=$1", msgID)
+	c.db.Exec("delete from chat where messageID=$1", msgID)
+	return nil
+}
+
+func (c *ChatDB) DeleteConversation(peerId string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.db.Exec("delete from chatattachments where messageID in (select messageID from chat where peerId=$1 and subject='')", peerId)
+	c.db.Exec("delete from chat where peerId=$1 and subject=''", peerId)
+	return nil
+}
+
+func (c *ChatDB) PutAttachment(attachment repo.ChatAttachment) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("insert into chatattachments(id, messageID, filename, mediaType, size, thumbnail, key) values($1,$2,$3,$4,$5,$6,$7) on conflict (id) do update set messageID=excluded.messageID, filename=excluded.filename, mediaType=excluded.mediaType, size=excluded.size, thumbnail=excluded.thumbnail, key=excluded.key",
+		attachment.Id, attachment.MessageId, attachment.Filename, attachment.MediaType, attachment.Size, attachment.Thumbnail, attachment.Key)
+	return err
+}
+
+func (c *ChatDB) GetAttachment(id string) (repo.ChatAttachment, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	var a repo.ChatAttachment
+	err := c.db.QueryRow("select id, messageID, filename, mediaType, size, thumbnail, key from chatattachments where id=$1", id).Scan(&a.Id, &a.MessageId, &a.Filename, &a.MediaType, &a.Size, &a.Thumbnail, &a.Key)
+	if err == sql.ErrNoRows {
+		return a, repo.ErrChatAttachmentNotFound
+	}
+	return a, err
+}
+
+// Return the attachments of a message. The caller must hold the lock.
+func (c *ChatDB) attachments(messageId string) []repo.ChatAttachment {
+	var ret []repo.ChatAttachment
+	rows, err := c.db.Query("select id, messageID, filename, mediaType, size, thumbnail, key from chatattachments where messageID=$1 order by rowid", messageId)
+	if err != nil {
+		log.Error(err)
+		return ret
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a repo.ChatAttachment
+		if err := rows.Scan(&a.Id, &a.MessageId, &a.Filename, &a.MediaType, &a.Size, &a.Thumbnail, &a.Key); err != nil {
+			continue
+		}
+		ret = append(ret, a)
+	}
+	return ret
+}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+type CheckoutGroupsDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (c *CheckoutGroupsDB) Put(group repo.CheckoutGroup) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("delete from checkoutgroups where groupID=$1", group.Id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, o := range group.Orders {
+		_, err := tx.Exec("insert into checkoutgroups(orderID, groupID, vendorID, paymentAddr, amount, fundingTxid, timestamp) values($1,$2,$3,$4,$5,$6,$7) on conflict (orderID) do update set groupID=excluded.groupID, vendorID=excluded.vendorID, paymentAddr=excluded.paymentAddr, amount=excluded.amount, fundingTxid=excluded.fundingTxid, timestamp=excluded.timestamp",
+			o.OrderId, group.Id, o.VendorId, o.PaymentAddress, int64(o.Amount), o.FundingTxid, int(group.Timestamp.Unix()))
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (c *CheckoutGroupsDB) Get(id string) (repo.CheckoutGroup, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.get(id)
+}
+
+func (c *CheckoutGroupsDB) GetByOrderId(orderId string) (repo.CheckoutGroup, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	var id string
+	err := c.db.QueryRow("select groupID from checkoutgroups where orderID=$1", orderId).Scan(&id)
+	if err == sql.ErrNoRows {
+		return repo.CheckoutGroup{}, repo.ErrCheckoutGroupNotFound
+	} else if err != nil {
+		return repo.CheckoutGroup{}, err
+	}
+	return c.get(id)
+}
+
+func (c *CheckoutGroupsDB) SetFundingTxid(orderId, txid string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	res, err := c.db.Exec("update checkoutgroups set fundingTxid=$1 where orderID=$2", txid, orderId)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return repo.ErrCheckoutGroupNotFound
+	}
+	return nil
+}
+
+func (c *CheckoutGroupsDB) get(id string) (repo.CheckoutGroup, error) {
+	group := repo.CheckoutGroup{Id: id}
+	rows, err := c.db.Query("select orderID, vendorID, paymentAddr, amount, fundingTxid, timestamp from checkoutgroups where groupID=$1 order by rowid", id)
+	if err != nil {
+		return group, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var o repo.CheckoutGroupOrder
+		var amount int64
+		var timestamp int
+		if err := rows.Scan(&o.OrderId, &o.VendorId, &o.PaymentAddress, &amount, &o.FundingTxid, &timestamp); err != nil {
+			return group, err
+		}
+		o.Amount = uint64(amount)
+		group.Timestamp = time.Unix(int64(timestamp), 0)
+		group.Orders = append(group.Orders, o)
+	}
+	if err := rows.Err(); err != nil {
+		return group, err
+	}
+	if len(group.Orders) == 0 {
+		return group, repo.ErrCheckoutGroupNotFound
+	}
+	return group, nil
+}
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+type CouponDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (c *CouponDB) Put(coupons []repo.Coupon) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert into coupons(slug, code, hash) values($1,$2,$3)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for _, coupon := range coupons {
+		_, err := stmt.Exec(coupon.Slug, coupon.Code, coupon.Hash)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (c *CouponDB) Get(slug string) ([]repo.Coupon, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	rows, err := c.db.Query("select slug, code, hash from coupons where slug=$1", slug)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+	var ret []repo.Coupon
+	for rows.Next() {
+		var slug string
+		var code string
+		var hash string
+		rows.Scan(&slug, &code, &hash)
+		ret = append(ret, repo.Coupon{Slug: slug, Code: code, Hash: hash})
+	}
+	return ret, nil
+}
+
+func (c *CouponDB) Delete(slug string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("delete from coupons where slug=$1", slug)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+type DigitalAssetsDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (d *DigitalAssetsDB) Put(asset repo.DigitalAsset) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	_, err := d.db.Exec("insert into digitalassets(slug, filename, mediaType, size, data, added) values($1,$2,$3,$4,$5,$6) on conflict (slug) do update set filename=excluded.filename, mediaType=excluded.mediaType, size=excluded.size, data=excluded.data, added=excluded.added",
+		asset.Slug, asset.Filename, asset.MediaType, asset.Size, asset.Data, int(asset.Added.Unix()))
+	return err
+}
+
+func (d *DigitalAssetsDB) Get(slug string) (repo.DigitalAsset, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	asset := repo.DigitalAsset{Slug: slug}
+	var added int
+	err := d.db.QueryRow("select filename, mediaType, size, data, added from digitalassets where slug=$1", slug).Scan(&asset.Filename, &asset.MediaType, &asset.Size, &asset.Data, &added)
+	if err == sql.ErrNoRows {
+		return asset, repo.ErrDigitalAssetNotFound
+	} else if err != nil {
+		return asset, err
+	}
+	asset.Added = time.Unix(int64(added), 0)
+	return asset, nil
+}
+
+func (d *DigitalAssetsDB) GetAll() ([]repo.DigitalAsset, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	ret := []repo.DigitalAsset{}
+	rows, err := d.db.Query("select slug, filename, mediaType, size, added from digitalassets order by slug")
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var asset repo.DigitalAsset
+		var added int
+		if err := rows.Scan(&asset.Slug, &asset.Filename, &asset.MediaType, &asset.Size, &added); err != nil {
+			return ret, err
+		}
+		asset.Added = time.Unix(int64(added), 0)
+		ret = append(ret, asset)
+	}
+	return ret, rows.Err()
+}
+
+func (d *DigitalAssetsDB) Delete(slug string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	res, err := d.db.Exec("delete from digitalassets where slug=$1", slug)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return repo.ErrDigitalAssetNotFound
+	}
+	return nil
+}
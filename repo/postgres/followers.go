@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"database/sql"
+	"sync"
+)
+
+type FollowerDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (f *FollowerDB) Put(follower string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	tx, err := f.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert into followers(peerID) values($1)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(follower)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (f *FollowerDB) Get(offsetId string, limit int) ([]string, error) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	var rows *sql.Rows
+	var err error
+	if offsetId != "" {
+		rows, err = f.db.Query("select peerID from followers where rowid<(select rowid from followers where peerID=$1) order by rowid desc limit "+limitClause(limit), offsetId)
+	} else {
+		rows, err = f.db.Query("select peerID from followers order by rowid desc limit " + limitClause(limit))
+	}
+	var ret []string
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var peerID string
+		rows.Scan(&peerID)
+		ret = append(ret, peerID)
+	}
+	return ret, nil
+}
+
+func (f *FollowerDB) Delete(follower string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	_, err := f.db.Exec("delete from followers where peerID=$1", follower)
+	return err
+}
+
+func (f *FollowerDB) Count() int {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	row := f.db.QueryRow("select count(*) from followers")
+	var count int
+	row.Scan(&count)
+	return count
+}
+
+func (f *FollowerDB) FollowsMe(peerId string) bool {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	var peerID string
+	err := f.db.QueryRow("select peerID from followers where peerID=$1", peerId).Scan(&peerID)
+	if err != nil {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"database/sql"
+	"sync"
+)
+
+type FollowingDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (f *FollowingDB) Put(follower string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	tx, err := f.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert into following(peerID) values($1)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(follower)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (f *FollowingDB) Get(offsetId string, limit int) ([]string, error) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	var rows *sql.Rows
+	var err error
+	if offsetId != "" {
+		rows, err = f.db.Query("select peerID from following where rowid<(select rowid from following where peerID=$1) order by rowid desc limit "+limitClause(limit), offsetId)
+	} else {
+		rows, err = f.db.Query("select peerID from following order by rowid desc limit " + limitClause(limit))
+	}
+	var ret []string
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var peerID string
+		rows.Scan(&peerID)
+		ret = append(ret, peerID)
+	}
+	return ret, nil
+}
+
+func (f *FollowingDB) Delete(follower string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	_, err := f.db.Exec("delete from following where peerID=$1", follower)
+	return err
+}
+
+func (f *FollowingDB) Count() int {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	row := f.db.QueryRow("select count(*) from following")
+	var count int
+	row.Scan(&count)
+	return count
+}
+
+func (f *FollowingDB) IsFollowing(peerId string) bool {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	var peerID string
+	err := f.db.QueryRow("select peerID from following where peerID=$1", peerId).Scan(&peerID)
+	if err != nil {
+		return false
+	}
+	return true
+}
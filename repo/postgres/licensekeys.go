@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+type LicenseKeysDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (l *LicenseKeysDB) Put(slug string, keys []string) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	tx, err := l.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert into licensekeys(slug, licenseKey, orderID, added) values($1,$2,'',$3) on conflict do nothing")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	added := int(time.Now().Unix())
+	for _, key := range keys {
+		if _, err := stmt.Exec(slug, key, added); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (l *LicenseKeysDB) Assign(slug string, orderId string) (string, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	tx, err := l.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	var key string
+	err = tx.QueryRow("select licenseKey from licensekeys where slug=$1 and orderID=$2", slug, orderId).Scan(&key)
+	if err == nil {
+		tx.Rollback()
+		return key, nil
+	} else if err != sql.ErrNoRows {
+		tx.Rollback()
+		return "", err
+	}
+	err = tx.QueryRow("select licenseKey from licensekeys where slug=$1 and orderID='' order by added, rowid limit 1 for update", slug).Scan(&key)
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		return "", repo.ErrNoLicenseKeys
+	} else if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+	if _, err := tx.Exec("update licensekeys set orderID=$1 where slug=$2 and licenseKey=$3", orderId, slug, key); err != nil {
+		tx.Rollback()
+		return "", err
+	}
+	return key, tx.Commit()
+}
+
+func (l *LicenseKeysDB) Get(slug string) ([]repo.LicenseKey, error) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	ret := []repo.LicenseKey{}
+	rows, err := l.db.Query("select licenseKey, orderID, added from licensekeys where slug=$1 order by added, rowid", slug)
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		key := repo.LicenseKey{Slug: slug}
+		var added int
+		if err := rows.Scan(&key.Key, &key.OrderId, &added); err != nil {
+			return ret, err
+		}
+		key.Added = time.Unix(int64(added), 0)
+		ret = append(ret, key)
+	}
+	return ret, rows.Err()
+}
+
+func (l *LicenseKeysDB) GetAll() (map[string]int, error) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	ret := make(map[string]int)
+	rows, err := l.db.Query("select slug, sum(case when orderID='' then 1 else 0 end) from licensekeys group by slug")
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var slug string
+		var count int
+		if err := rows.Scan(&slug, &count); err != nil {
+			return ret, err
+		}
+		ret[slug] = count
+	}
+	return ret, rows.Err()
+}
+
+func (l *LicenseKeysDB) DeleteUnused(slug string) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	_, err := l.db.Exec("delete from licensekeys where slug=$1 and orderID=''", slug)
+	return err
+}
@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lib/pq"
+	_ "github.com/mutecomm/go-sqlcipher"
+)
+
+// The tables copied by MigrateFromSQLite
+var migratedTables = []string{
+	"config",
+	"followers",
+	"following",
+	"offlinemessages",
+	"pointers",
+	"keys",
+	"utxos",
+	"stxos",
+	"txns",
+	"txmetadata",
+	"txaccounting",
+	"exchangerates",
+	"apitokens",
+	"webhooks",
+	"webhookdeliveries",
+	"outgoingmessages",
+	"groupchats",
+	"groupchatmessages",
+	"inventory",
+	"purchases",
+	"sales",
+	"watchedscripts",
+	"cases",
+	"chat",
+	"chatattachments",
+	"cart",
+	"checkoutgroups",
+	"digitalassets",
+	"licensekeys",
+	"orderhistory",
+	"notifications",
+	"coupons",
+	"moderatedstores",
+	"bids",
+	"pledges",
+	"search_listings",
+}
+
+// The tables of wallets for other coins are named after the coin
+var walletTablePattern = regexp.MustCompile(`^([a-z0-9]+)_(keys|utxos|stxos|txns|watchedscripts)$`)
+
+/* MigrateFromSQLite copies the contents of the SQLite datastore at dbPath into an
+   empty Postgres database. Rows keep their rowid so results are returned in the
+   same order as before. Tables missing from older SQLite repos are skipped. */
+func MigrateFromSQLite(dbPath, password string, d *PostgresDatastore) error {
+	src, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	// The key only applies to the connection it was set on
+	src.SetMaxOpenConns(1)
+	if password != "" {
+		if _, err := src.Exec("pragma key='" + password + "';"); err != nil {
+			return err
+		}
+	}
+	if _, err := src.Exec("select count(*) from sqlite_master"); err != nil {
+		return fmt.Errorf("Unable to read %s, is the password correct? %s", dbPath, err)
+	}
+
+	if err := initDatabaseTables(d.db); err != nil {
+		return err
+	}
+	var count int
+	if err := d.db.QueryRow("select count(*) from config").Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrAlreadyInitialized
+	}
+	tables, err := withWalletTables(src, d)
+	if err != nil {
+		return err
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		var name string
+		err := src.QueryRow("select name from sqlite_master where type='table' and name=?", table).Scan(&name)
+		if err == sql.ErrNoRows {
+			log.Warningf("Table %s not found in %s, skipping", table, dbPath)
+			continue
+		} else if err != nil {
+			tx.Rollback()
+			return err
+		}
+		n, err := migrateTable(src, tx, table)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Migrating %s: %s", table, err)
+		}
+		log.Infof("Migrated %d rows from %s", n, table)
+	}
+	return tx.Commit()
+}
+
+// Add the tables of any wallets for other coins to those copied, creating them in Postgres
+func withWalletTables(src *sql.DB, d *PostgresDatastore) ([]string, error) {
+	tables := append([]string{}, migratedTables...)
+	rows, err := src.Query("select name from sqlite_master where type='table'")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		match := walletTablePattern.FindStringSubmatch(name)
+		if match == nil {
+			continue
+		}
+		if _, err := d.WalletDatastore(match[1]); err != nil {
+			return nil, err
+		}
+		tables = append(tables, name)
+	}
+	return tables, rows.Err()
+}
+
+func migrateTable(src *sql.DB, tx *sql.Tx, table string) (int, error) {
+	// SQLite is loose with types so convert each value to what the Postgres column holds
+	types := make(map[string]string)
+	typeRows, err := tx.Query("select column_name, data_type from information_schema.columns where table_schema=current_schema() and table_name=$1", table)
+	if err != nil {
+		return 0, err
+	}
+	for typeRows.Next() {
+		var column, dataType string
+		if err := typeRows.Scan(&column, &dataType); err != nil {
+			typeRows.Close()
+			return 0, err
+		}
+		types[column] = dataType
+	}
+	typeRows.Close()
+
+	// The config table is keyed by name and has no use for its rowid
+	stm := "select rowid, * from " + table
+	if table == "config" {
+		stm = "select * from config"
+	}
+	rows, err := src.Query(stm)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	var quoted, placeholders []string
+	for i, column := range columns {
+		column = strings.ToLower(column)
+		if _, ok := types[column]; !ok {
+			return 0, fmt.Errorf("column %s does not exist in Postgres", column)
+		}
+		columns[i] = column
+		quoted = append(quoted, pq.QuoteIdentifier(column))
+		placeholders = append(placeholders, fmt.Sprintf("$%d", i+1))
+	}
+	stmt, err := tx.Prepare("insert into " + table + "(" + strings.Join(quoted, ", ") + ") values(" + strings.Join(placeholders, ",") + ")")
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	n := 0
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return n, err
+		}
+		for i, column := range columns {
+			switch v := values[i].(type) {
+			case string:
+				if types[column] == "bytea" {
+					values[i] = []byte(v)
+				}
+			case []byte:
+				if types[column] != "bytea" {
+					values[i] = string(v)
+				}
+			}
+		}
+		if _, err := stmt.Exec(values...); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return n, err
+	}
+	if table != "config" {
+		_, err = tx.Exec("select setval(pg_get_serial_sequence($1, 'rowid'), coalesce(max(rowid), 0)+1, false) from "+table, table)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
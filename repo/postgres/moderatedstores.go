@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"database/sql"
+	"sync"
+)
+
+type ModeratedDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (m *ModeratedDB) Put(peerId string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert into moderatedstores(peerID) values($1)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(peerId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (m *ModeratedDB) Get(offsetId string, limit int) ([]string, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	var rows *sql.Rows
+	var err error
+	if offsetId != "" {
+		rows, err = m.db.Query("select peerID from moderatedstores where rowid<(select rowid from moderatedstores where peerID=$1) order by rowid desc limit "+limitClause(limit), offsetId)
+	} else {
+		rows, err = m.db.Query("select peerID from moderatedstores order by rowid desc limit " + limitClause(limit))
+	}
+	var ret []string
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var peerID string
+		rows.Scan(&peerID)
+		ret = append(ret, peerID)
+	}
+	return ret, nil
+}
+
+func (m *ModeratedDB) Delete(peerId string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	_, err := m.db.Exec("delete from moderatedstores where peerID=$1", peerId)
+	return err
+}
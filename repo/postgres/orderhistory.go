@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+type OrderHistoryDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (o *OrderHistoryDB) Put(event repo.OrderEvent) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	_, err := o.db.Exec("insert into orderhistory(orderID, action, detail, timestamp) values($1,$2,$3,$4)",
+		event.OrderId, event.Action, event.Detail, int(event.Timestamp.Unix()))
+	return err
+}
+
+func (o *OrderHistoryDB) Get(orderId string) ([]repo.OrderEvent, error) {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+	ret := []repo.OrderEvent{}
+	rows, err := o.db.Query("select action, detail, timestamp from orderhistory where orderID=$1 order by timestamp, rowid", orderId)
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		event := repo.OrderEvent{OrderId: orderId}
+		var timestamp int
+		if err := rows.Scan(&event.Action, &event.Detail, &timestamp); err != nil {
+			return ret, err
+		}
+		event.Timestamp = time.Unix(int64(timestamp), 0)
+		ret = append(ret, event)
+	}
+	return ret, rows.Err()
+}
@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"sync"
+	"time"
+)
+
+type PledgesDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (p *PledgesDB) Put(orderID string, contract pb.RicardianContract, state repo.PledgeState) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	stm := `insert into pledges(orderID, slug, amount, timestamp, buyerID, buyerBlockchainID, state) values($1,$2,$3,$4,$5,$6,$7) on conflict (orderID) do update set slug=excluded.slug, amount=excluded.amount, timestamp=excluded.timestamp, buyerID=excluded.buyerID, buyerBlockchainID=excluded.buyerBlockchainID, state=excluded.state`
+	stmt, err := tx.Prepare(stm)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(
+		orderID,
+		contract.VendorListings[0].Slug,
+		int(contract.BuyerOrder.Payment.Amount),
+		int(contract.BuyerOrder.Timestamp.Seconds),
+		contract.BuyerOrder.BuyerID.Guid,
+		contract.BuyerOrder.BuyerID.BlockchainID,
+		int(state),
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (p *PledgesDB) UpdateFunding(orderID string, funded bool) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	fundedInt := 0
+	if funded {
+		fundedInt = 1
+	}
+	_, err := p.db.Exec("update pledges set funded=$1 where orderID=$2", fundedInt, orderID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *PledgesDB) UpdateState(orderID string, state repo.PledgeState) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	_, err := p.db.Exec("update pledges set state=$1 where orderID=$2", int(state), orderID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *PledgesDB) GetBySlug(slug string) ([]repo.Pledge, error) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	rows, err := p.db.Query("select orderID, slug, amount, timestamp, buyerID, buyerBlockchainID, funded, state from pledges where slug=$1 order by timestamp asc", slug)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ret []repo.Pledge
+	for rows.Next() {
+		var orderID, s, buyerID, buyerHandle string
+		var amount, timestamp, stateInt int
+		var fundedInt *int
+		if err := rows.Scan(&orderID, &s, &amount, &timestamp, &buyerID, &buyerHandle, &fundedInt, &stateInt); err != nil {
+			return ret, err
+		}
+		funded := false
+		if fundedInt != nil && *fundedInt == 1 {
+			funded = true
+		}
+		ret = append(ret, repo.Pledge{
+			OrderId:     orderID,
+			Slug:        s,
+			Timestamp:   time.Unix(int64(timestamp), 0),
+			Amount:      uint64(amount),
+			BuyerId:     buyerID,
+			BuyerHandle: buyerHandle,
+			Funded:      funded,
+			State:       repo.PledgeState(stateInt).String(),
+		})
+	}
+	return ret, nil
+}
+
+func (p *PledgesDB) Delete(orderID string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	_, err := p.db.Exec("delete from pledges where orderID=$1", orderID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,400 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/OpenBazaar/spvwallet"
+	_ "github.com/lib/pq"
+	"github.com/op/go-logging"
+)
+
+var log = logging.MustGetLogger("postgres")
+
+var ErrAlreadyInitialized = errors.New("Database has already been initialized")
+
+/* PostgresDatastore implements repo.Datastore and the wallet's spvwallet.Datastore
+   on a PostgreSQL server so a node's data can live outside of its data directory.
+   Each node needs a database of its own. */
+type PostgresDatastore struct {
+	config          repo.Config
+	followers       repo.Followers
+	following       repo.Following
+	offlineMessages repo.OfflineMessages
+	pointers        repo.Pointers
+	keys            spvwallet.Keys
+	stxos           spvwallet.Stxos
+	txns            spvwallet.Txns
+	utxos           spvwallet.Utxos
+	watchedScripts  spvwallet.WatchedScripts
+	settings        repo.Settings
+	inventory       repo.Inventory
+	purchases       repo.Purchases
+	sales           repo.Sales
+	cases           repo.Cases
+	chat            repo.Chat
+	notifications   repo.Notifications
+	coupons         repo.Coupons
+	txMetadata      repo.TxMetadata
+	txAccounting    repo.TxAccounting
+	exchangeRates   repo.ExchangeRateHistory
+	moderatedStores repo.ModeratedStores
+	bids            repo.Bids
+	pledges         repo.Pledges
+	search          repo.Search
+	apiTokens       repo.APITokens
+	webhooks        repo.Webhooks
+	hookDeliveries  repo.WebhookDeliveries
+	outgoing        repo.OutgoingMessages
+	groupChats      repo.GroupChats
+	cart            repo.Cart
+	checkoutGroups  repo.CheckoutGroups
+	digitalAssets   repo.DigitalAssets
+	licenseKeys     repo.LicenseKeys
+	orderHistory    repo.OrderHistory
+	db              *sql.DB
+}
+
+// Open a connection to the database at the given postgres:// URL or key=value connection string
+func Open(dataSource string) (*PostgresDatastore, error) {
+	conn, err := sql.Open("postgres", dataSource)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	// Bring the schema of an existing database up to date with any tables or columns added since
+	if err := initDatabaseTables(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	pgDB := &PostgresDatastore{
+		config:          &ConfigDB{db: conn},
+		followers:       &FollowerDB{db: conn},
+		following:       &FollowingDB{db: conn},
+		offlineMessages: &OfflineMessagesDB{db: conn},
+		pointers:        &PointersDB{db: conn},
+		keys:            &KeysDB{db: conn, table: "keys"},
+		stxos:           &StxoDB{db: conn, table: "stxos"},
+		txns:            &TxnsDB{db: conn, table: "txns"},
+		utxos:           &UtxoDB{db: conn, table: "utxos"},
+		watchedScripts:  &WatchedScriptsDB{db: conn, table: "watchedscripts"},
+		settings:        &SettingsDB{db: conn},
+		inventory:       &InventoryDB{db: conn},
+		purchases:       &PurchasesDB{db: conn},
+		sales:           &SalesDB{db: conn},
+		cases:           &CasesDB{db: conn},
+		chat:            &ChatDB{db: conn},
+		notifications:   &NotficationsDB{db: conn},
+		coupons:         &CouponDB{db: conn},
+		txMetadata:      &TxMetadataDB{db: conn},
+		txAccounting:    &TxAccountingDB{db: conn},
+		exchangeRates:   &ExchangeRateHistoryDB{db: conn},
+		moderatedStores: &ModeratedDB{db: conn},
+		bids:            &BidsDB{db: conn},
+		pledges:         &PledgesDB{db: conn},
+		search:          &SearchDB{db: conn},
+		apiTokens:       &APITokensDB{db: conn},
+		webhooks:        &WebhooksDB{db: conn},
+		hookDeliveries:  &WebhookDeliveriesDB{db: conn},
+		outgoing:        &OutgoingMessagesDB{db: conn},
+		groupChats:      &GroupChatsDB{db: conn},
+		cart:            &CartDB{db: conn},
+		checkoutGroups:  &CheckoutGroupsDB{db: conn},
+		digitalAssets:   &DigitalAssetsDB{db: conn},
+		licenseKeys:     &LicenseKeysDB{db: conn},
+		orderHistory:    &OrderHistoryDB{db: conn},
+		db:              conn,
+	}
+	return pgDB, nil
+}
+
+func (d *PostgresDatastore) Close() {
+	d.db.Close()
+}
+
+func (d *PostgresDatastore) Config() repo.Config {
+	return d.config
+}
+
+func (d *PostgresDatastore) Followers() repo.Followers {
+	return d.followers
+}
+
+func (d *PostgresDatastore) Following() repo.Following {
+	return d.following
+}
+
+func (d *PostgresDatastore) OfflineMessages() repo.OfflineMessages {
+	return d.offlineMessages
+}
+
+func (d *PostgresDatastore) Pointers() repo.Pointers {
+	return d.pointers
+}
+
+func (d *PostgresDatastore) Keys() spvwallet.Keys {
+	return d.keys
+}
+
+func (d *PostgresDatastore) Stxos() spvwallet.Stxos {
+	return d.stxos
+}
+
+func (d *PostgresDatastore) Txns() spvwallet.Txns {
+	return d.txns
+}
+
+func (d *PostgresDatastore) Utxos() spvwallet.Utxos {
+	return d.utxos
+}
+
+func (d *PostgresDatastore) Settings() repo.Settings {
+	return d.settings
+}
+
+func (d *PostgresDatastore) Inventory() repo.Inventory {
+	return d.inventory
+}
+
+func (d *PostgresDatastore) Purchases() repo.Purchases {
+	return d.purchases
+}
+
+func (d *PostgresDatastore) Sales() repo.Sales {
+	return d.sales
+}
+
+func (d *PostgresDatastore) WatchedScripts() spvwallet.WatchedScripts {
+	return d.watchedScripts
+}
+
+func (d *PostgresDatastore) Cases() repo.Cases {
+	return d.cases
+}
+
+func (d *PostgresDatastore) Chat() repo.Chat {
+	return d.chat
+}
+
+func (d *PostgresDatastore) Notifications() repo.Notifications {
+	return d.notifications
+}
+
+func (d *PostgresDatastore) Coupons() repo.Coupons {
+	return d.coupons
+}
+
+func (d *PostgresDatastore) TxMetadata() repo.TxMetadata {
+	return d.txMetadata
+}
+
+func (d *PostgresDatastore) TxAccounting() repo.TxAccounting {
+	return d.txAccounting
+}
+
+func (d *PostgresDatastore) ExchangeRateHistory() repo.ExchangeRateHistory {
+	return d.exchangeRates
+}
+
+func (d *PostgresDatastore) ModeratedStores() repo.ModeratedStores {
+	return d.moderatedStores
+}
+
+func (d *PostgresDatastore) Bids() repo.Bids {
+	return d.bids
+}
+
+func (d *PostgresDatastore) Pledges() repo.Pledges {
+	return d.pledges
+}
+
+func (d *PostgresDatastore) Search() repo.Search {
+	return d.search
+}
+
+func (d *PostgresDatastore) APITokens() repo.APITokens {
+	return d.apiTokens
+}
+
+func (d *PostgresDatastore) Webhooks() repo.Webhooks {
+	return d.webhooks
+}
+
+func (d *PostgresDatastore) WebhookDeliveries() repo.WebhookDeliveries {
+	return d.hookDeliveries
+}
+
+func (d *PostgresDatastore) OutgoingMessages() repo.OutgoingMessages {
+	return d.outgoing
+}
+
+func (d *PostgresDatastore) GroupChats() repo.GroupChats {
+	return d.groupChats
+}
+
+func (d *PostgresDatastore) Cart() repo.Cart {
+	return d.cart
+}
+
+func (d *PostgresDatastore) CheckoutGroups() repo.CheckoutGroups {
+	return d.checkoutGroups
+}
+
+func (d *PostgresDatastore) DigitalAssets() repo.DigitalAssets {
+	return d.digitalAssets
+}
+
+func (d *PostgresDatastore) LicenseKeys() repo.LicenseKeys {
+	return d.licenseKeys
+}
+
+func (d *PostgresDatastore) OrderHistory() repo.OrderHistory {
+	return d.orderHistory
+}
+
+/* The tables match the SQLite schema so a repo can be migrated column for column.
+   SQLite's implicit rowid is made explicit as it's used to order results, and JSON
+   which SQLite stores in blobs is stored as text. */
+func initDatabaseTables(db *sql.DB) error {
+	sqlStmt := `
+	create table if not exists config (key text primary key not null, value bytea);
+	create table if not exists followers (rowid bigserial, peerID text primary key not null);
+	create table if not exists following (rowid bigserial, peerID text primary key not null);
+	create table if not exists offlinemessages (rowid bigserial, url text primary key not null, timestamp bigint);
+	create table if not exists pointers (rowid bigserial, pointerID text primary key not null, key text, address text, cancelID text, purpose integer, timestamp bigint);
+	create table if not exists keys (rowid bigserial, scriptPubKey text primary key not null, purpose integer, keyIndex integer, used integer, key text);
+	create table if not exists utxos (rowid bigserial, outpoint text primary key not null, value bigint, height integer, scriptPubKey text, "freeze" integer);
+	create table if not exists stxos (rowid bigserial, outpoint text primary key not null, value bigint, height integer, scriptPubKey text, spendHeight integer, spendTxid text);
+	create table if not exists txns (rowid bigserial, txid text primary key not null, value bigint, height integer, timestamp bigint, tx bytea);
+	create table if not exists txmetadata (rowid bigserial, txid text primary key not null, address text, memo text, orderID text, thumbnail text, canBumpFee integer);
+	create table if not exists inventory (rowid bigserial, slug text, variantIndex integer, count bigint);
+	create index if not exists index_inventory on inventory (slug);
+	create table if not exists purchases (rowid bigserial, orderID text primary key not null, contract text, state integer, read integer, timestamp bigint, total bigint, thumbnail text, vendorID text, vendorBlockchainID text, title text, shippingName text, shippingAddress text, paymentAddr text, funded integer, transactions text, exchangeRates text);
+	alter table purchases add column if not exists exchangeRates text;
+	create index if not exists index_purchases on purchases (paymentAddr);
+	create table if not exists sales (rowid bigserial, orderID text primary key not null, contract text, state integer, read integer, timestamp bigint, total bigint, thumbnail text, buyerID text, buyerBlockchainID text, title text, shippingName text, shippingAddress text, paymentAddr text, funded integer, transactions text, exchangeRates text);
+	alter table sales add column if not exists exchangeRates text;
+	create index if not exists index_sales on sales (paymentAddr);
+	create table if not exists watchedscripts (rowid bigserial, scriptPubKey text primary key not null);
+	create table if not exists cases (rowid bigserial, caseID text primary key not null, buyerContract text, vendorContract text, buyerValidationErrors text, vendorValidationErrors text, buyerPayoutAddress text, vendorPayoutAddress text, buyerOutpoints text, vendorOutpoints text, state integer, read integer, timestamp bigint, buyerOpened integer, claim text, disputeResolution text);
+	create table if not exists chat (rowid bigserial, messageID text primary key not null, peerID text, subject text, message text, read integer, timestamp bigint, outgoing integer);
+	create index if not exists index_chat on chat (peerID, subject, read, timestamp);
+	create table if not exists chatattachments (rowid bigserial, id text primary key not null, messageID text, filename text, mediaType text, size bigint, thumbnail text, key bytea);
+	create index if not exists index_chatattachments on chatattachments (messageID);
+	create table if not exists cart (rowid bigserial, id text primary key not null, vendorID text, listingHash text, quantity integer, options text, shippingName text, shippingService text, memo text, coupons text, added bigint);
+	create table if not exists checkoutgroups (rowid bigserial, orderID text primary key not null, groupID text, vendorID text, paymentAddr text, amount bigint, fundingTxid text, timestamp bigint);
+	create index if not exists index_checkoutgroups on checkoutgroups (groupID);
+	create table if not exists digitalassets (rowid bigserial, slug text primary key not null, filename text, mediaType text, size bigint, data bytea, added bigint);
+	create table if not exists licensekeys (rowid bigserial, slug text not null, licenseKey text not null, orderID text, added bigint, primary key (slug, licenseKey));
+	create index if not exists index_licensekeys on licensekeys (orderID);
+	create table if not exists orderhistory (rowid bigserial, orderID text, action text, detail text, timestamp bigint);
+	create index if not exists index_orderhistory on orderhistory (orderID);
+	create table if not exists notifications (rowid bigserial primary key, serializedNotification text, timestamp bigint, read integer);
+	create table if not exists coupons (rowid bigserial, slug text, code text, hash text);
+	create index if not exists index_coupons on coupons (slug);
+	create table if not exists moderatedstores (rowid bigserial, peerID text primary key not null);
+	create table if not exists bids (rowid bigserial, orderID text primary key not null, contract text, state integer, slug text, amount bigint, timestamp bigint, title text, thumbnail text, buyerID text, buyerBlockchainID text, vendorID text, outgoing integer);
+	create index if not exists index_bids on bids (slug, outgoing);
+	create table if not exists pledges (rowid bigserial, orderID text primary key not null, slug text, amount bigint, timestamp bigint, buyerID text, buyerBlockchainID text, funded integer, state integer);
+	create index if not exists index_pledges on pledges (slug);
+	create table if not exists txaccounting (rowid bigserial, txid text primary key not null, currency text, exchangeRate double precision, fee bigint, timestamp bigint);
+	create table if not exists exchangerates (rowid bigserial, currency text, rate double precision, timestamp bigint);
+	create index if not exists index_exchangerates on exchangerates (currency, timestamp);
+	create table if not exists apitokens (rowid bigserial, name text primary key not null, hash text unique not null, scopes text, timestamp bigint);
+	create table if not exists webhooks (rowid bigserial, id text primary key not null, url text, secret text, events text, timestamp bigint);
+	create table if not exists webhookdeliveries (rowid bigserial primary key, webhookID text, url text, event text, payload text, status text, attempts integer, responseCode integer, error text, nextAttempt bigint, timestamp bigint);
+	create index if not exists index_webhookdeliveries on webhookdeliveries (status, nextAttempt);
+	create table if not exists outgoingmessages (rowid bigserial, id text primary key not null, peerID text, messageType text, reference text, message bytea, state text, pointerID text, attempts integer, error text, timestamp bigint, updated bigint);
+	create index if not exists index_outgoingmessages on outgoingmessages (peerID, state);
+	create index if not exists index_outgoingmessages_pointer on outgoingmessages (pointerID);
+	create index if not exists index_outgoingmessages_reference on outgoingmessages (reference);
+	create table if not exists groupchats (rowid bigserial, groupID text primary key not null, creator text, subject text, members text, signedMembers bytea, updated bigint);
+	create table if not exists groupchatmessages (rowid bigserial, messageID text primary key not null, groupID text, peerID text, message text, read integer, outgoing integer, timestamp bigint);
+	create index if not exists index_groupchatmessages on groupchatmessages (groupID, timestamp);
+	create table if not exists search_listings (rowid bigserial, slug text primary key not null, title text, tags text);
+	`
+	_, err := db.Exec(sqlStmt)
+	return err
+}
+
+// The API asks for every row with a negative limit which SQLite allows but Postgres does not
+func limitClause(limit int) string {
+	if limit < 0 {
+		return "all"
+	}
+	return strconv.Itoa(limit)
+}
+
+type ConfigDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+// The password is ignored as encryption at rest is left to the Postgres server
+func (c *ConfigDB) Init(mnemonic string, identityKey []byte, password string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := initDatabaseTables(c.db); err != nil {
+		return err
+	}
+	var count int
+	if err := c.db.QueryRow("select count(*) from config where key=$1", "mnemonic").Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrAlreadyInitialized
+	}
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert into config(key, value) values($1,$2)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec("mnemonic", []byte(mnemonic))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	_, err = stmt.Exec("identityKey", identityKey)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (c *ConfigDB) GetMnemonic() (string, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	var mnemonic []byte
+	err := c.db.QueryRow("select value from config where key=$1", "mnemonic").Scan(&mnemonic)
+	if err != nil {
+		return "", err
+	}
+	return string(mnemonic), nil
+}
+
+func (c *ConfigDB) GetIdentityKey() ([]byte, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	var identityKey []byte
+	err := c.db.QueryRow("select value from config where key=$1", "identityKey").Scan(&identityKey)
+	if err != nil {
+		return nil, err
+	}
+	return identityKey, nil
+}
+
+func (c *ConfigDB) IsEncrypted() bool {
+	return false
+}
@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/pb"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+/* Orders and chat messages are searched with to_tsvector directly rather than through
+   an index of their own. Listings are indexed in search_listings as they don't live in
+   the database. */
+type SearchDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (s *SearchDB) IndexListings(listings []repo.SearchListing) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("delete from search_listings"); err != nil {
+		tx.Rollback()
+		return err
+	}
+	stmt, err := tx.Prepare("insert into search_listings(slug, title, tags) values($1,$2,$3) on conflict (slug) do update set title=excluded.title, tags=excluded.tags")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for _, l := range listings {
+		_, err = stmt.Exec(l.Slug, l.Title, strings.Join(l.Tags, " "))
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SearchDB) Query(query repo.SearchQuery) ([]repo.SearchResult, int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	var ret []repo.SearchResult
+	if len(query.Terms) == 0 {
+		return ret, 0, nil
+	}
+	// Every term must match, the last as a prefix so results show up while typing
+	var lexemes []string
+	for _, term := range query.Terms {
+		lexemes = append(lexemes, "'"+strings.Replace(term, "'", "''", -1)+"'")
+	}
+	tsquery := strings.Join(lexemes, " & ") + ":*"
+
+	if query.Includes(repo.SearchTypeListing) {
+		rows, err := s.db.Query("select slug, title, ts_rank(to_tsvector('simple', title || ' ' || tags), q) from search_listings, to_tsquery('simple', $1) q where to_tsvector('simple', title || ' ' || tags) @@ q", tsquery)
+		if err != nil {
+			return ret, 0, err
+		}
+		for rows.Next() {
+			var slug, title string
+			var rank float64
+			if err := rows.Scan(&slug, &title, &rank); err != nil {
+				rows.Close()
+				return ret, 0, err
+			}
+			ret = append(ret, repo.SearchResult{
+				Type:  repo.SearchTypeListing,
+				Id:    slug,
+				Title: title,
+				Rank:  rank,
+			})
+		}
+		rows.Close()
+	}
+
+	orderTables := []struct {
+		resultType, table, peerColumn, handleColumn string
+	}{
+		{repo.SearchTypePurchase, "purchases", "vendorID", "vendorBlockchainID"},
+		{repo.SearchTypeSale, "sales", "buyerID", "buyerBlockchainID"},
+	}
+	for _, t := range orderTables {
+		if !query.Includes(t.resultType) {
+			continue
+		}
+		document := "to_tsvector('simple', coalesce(title, '') || ' ' || coalesce(" + t.handleColumn + ", '') || ' ' || coalesce(" + t.peerColumn + ", ''))"
+		stm := "select orderID, title, " + t.peerColumn + ", coalesce(" + t.handleColumn + ", ''), state, timestamp, ts_rank(" + document + ", q) from " + t.table + ", to_tsquery('simple', $1) q where " + document + " @@ q"
+		stm, args := filterSearch(stm, []interface{}{tsquery}, query)
+		rows, err := s.db.Query(stm, args...)
+		if err != nil {
+			return ret, 0, err
+		}
+		for rows.Next() {
+			var orderID, title, peerID, handle string
+			var stateInt, timestamp int
+			var rank float64
+			if err := rows.Scan(&orderID, &title, &peerID, &handle, &stateInt, &timestamp, &rank); err != nil {
+				rows.Close()
+				return ret, 0, err
+			}
+			ts := time.Unix(int64(timestamp), 0)
+			ret = append(ret, repo.SearchResult{
+				Type:      t.resultType,
+				Id:        orderID,
+				Title:     title,
+				PeerId:    peerID,
+				Handle:    handle,
+				State:     pb.OrderState(stateInt).String(),
+				Timestamp: &ts,
+				Rank:      rank,
+			})
+		}
+		rows.Close()
+	}
+
+	if query.Includes(repo.SearchTypeChat) {
+		stm := "select messageID, peerID, subject, ts_headline('simple', message, q, 'StartSel=\"\",StopSel=\"\",MaxWords=16,MinWords=4'), timestamp, ts_rank(to_tsvector('simple', message), q) from chat, to_tsquery('simple', $1) q where to_tsvector('simple', message) @@ q"
+		stm, args := filterSearch(stm, []interface{}{tsquery}, query)
+		rows, err := s.db.Query(stm, args...)
+		if err != nil {
+			return ret, 0, err
+		}
+		for rows.Next() {
+			var messageID, peerID, subject, snippet string
+			var timestamp int
+			var rank float64
+			if err := rows.Scan(&messageID, &peerID, &subject, &snippet, &timestamp, &rank); err != nil {
+				rows.Close()
+				return ret, 0, err
+			}
+			ts := time.Unix(int64(timestamp), 0)
+			ret = append(ret, repo.SearchResult{
+				Type:      repo.SearchTypeChat,
+				Id:        messageID,
+				Snippet:   snippet,
+				PeerId:    peerID,
+				Subject:   subject,
+				Timestamp: &ts,
+				Rank:      rank,
+			})
+		}
+		rows.Close()
+	}
+	ret, total := repo.PageSearchResults(ret, query.Offset, query.Limit)
+	return ret, total, nil
+}
+
+// Add the state and time filters in the query to a search statement
+func filterSearch(stm string, args []interface{}, query repo.SearchQuery) (string, []interface{}) {
+	if len(query.States) > 0 {
+		var placeholders []string
+		for _, state := range query.States {
+			args = append(args, int(state))
+			placeholders = append(placeholders, "$"+strconv.Itoa(len(args)))
+		}
+		stm += " and state in (" + strings.Join(placeholders, ",") + ")"
+	}
+	if !query.From.IsZero() {
+		args = append(args, int(query.From.Unix()))
+		stm += " and timestamp>=$" + strconv.Itoa(len(args))
+	}
+	if !query.To.IsZero() {
+		args = append(args, int(query.To.Unix()))
+		stm += " and timestamp<=$" + strconv.Itoa(len(args))
+	}
+	return stm, args
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"github.com/OpenBazaar/spvwallet"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type StxoDB struct {
+	db    *sql.DB
+	table string
+	lock  sync.RWMutex
+}
+
+func (s *StxoDB) Put(stxo spvwallet.Stxo) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	tx, _ := s.db.Begin()
+	stmt, err := tx.Prepare("insert into " + s.table + "(outpoint, value, height, scriptPubKey, spendHeight, spendTxid) values($1,$2,$3,$4,$5,$6) on conflict (outpoint) do update set value=excluded.value, height=excluded.height, scriptPubKey=excluded.scriptPubKey, spendHeight=excluded.spendHeight, spendTxid=excluded.spendTxid")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	outpoint := stxo.Utxo.Op.Hash.String() + ":" + strconv.Itoa(int(stxo.Utxo.Op.Index))
+	_, err = stmt.Exec(outpoint, int(stxo.Utxo.Value), int(stxo.Utxo.AtHeight), hex.EncodeToString(stxo.Utxo.ScriptPubkey), int(stxo.SpendHeight), stxo.SpendTxid.String())
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (s *StxoDB) GetAll() ([]spvwallet.Stxo, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	var ret []spvwallet.Stxo
+	stm := "select outpoint, value, height, scriptPubKey, spendHeight, spendTxid from " + s.table
+	rows, err := s.db.Query(stm)
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var outpoint string
+		var value int
+		var height int
+		var scriptPubKey string
+		var spendHeight int
+		var spendTxid string
+		if err := rows.Scan(&outpoint, &value, &height, &scriptPubKey, &spendHeight, &spendTxid); err != nil {
+			continue
+		}
+		s := strings.Split(outpoint, ":")
+		shaHash, err := chainhash.NewHashFromStr(s[0])
+		if err != nil {
+			continue
+		}
+		index, err := strconv.Atoi(s[1])
+		if err != nil {
+			continue
+		}
+		scriptBytes, err := hex.DecodeString(scriptPubKey)
+		if err != nil {
+			continue
+		}
+		spentHash, err := chainhash.NewHashFromStr(spendTxid)
+		if err != nil {
+			continue
+		}
+		utxo := spvwallet.Utxo{
+			Op:           *wire.NewOutPoint(shaHash, uint32(index)),
+			AtHeight:     int32(height),
+			Value:        int64(value),
+			ScriptPubkey: scriptBytes,
+		}
+		ret = append(ret, spvwallet.Stxo{
+			Utxo:        utxo,
+			SpendHeight: int32(spendHeight),
+			SpendTxid:   *spentHash,
+		})
+	}
+	return ret, nil
+}
+
+func (s *StxoDB) Delete(stxo spvwallet.Stxo) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	outpoint := stxo.Utxo.Op.Hash.String() + ":" + strconv.Itoa(int(stxo.Utxo.Op.Index))
+	_, err := s.db.Exec("delete from "+s.table+" where outpoint=$1", outpoint)
+	if err != nil {
+		return err
+	}
+	return nil
+}
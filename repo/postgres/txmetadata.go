@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"sync"
+)
+
+type TxMetadataDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (t *TxMetadataDB) Put(m repo.Metadata) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	tx, _ := t.db.Begin()
+	stmt, err := tx.Prepare("insert into txmetadata(txid, address, memo, orderID, thumbnail, canBumpFee) values($1,$2,$3,$4,$5,$6) on conflict (txid) do update set address=excluded.address, memo=excluded.memo, orderID=excluded.orderID, thumbnail=excluded.thumbnail, canBumpFee=excluded.canBumpFee")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	bumpable := 0
+	if m.CanBumpFee {
+		bumpable = 1
+	}
+	_, err = stmt.Exec(m.Txid, m.Address, m.Memo, m.OrderId, m.Thumbnail, bumpable)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (t *TxMetadataDB) Get(txid string) (repo.Metadata, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	var m repo.Metadata
+	stmt, err := t.db.Prepare("select txid, address, memo, orderID, thumbnail, canBumpFee from txmetadata where txid=$1")
+	defer stmt.Close()
+	var id, address, memo, orderId, thumbnail string
+	var canBumpFee int
+	err = stmt.QueryRow(txid).Scan(&id, &address, &memo, &orderId, &thumbnail, &canBumpFee)
+	if err != nil {
+		return m, err
+	}
+	bumpable := false
+	if canBumpFee > 0 {
+		bumpable = true
+	}
+	m = repo.Metadata{
+		Txid:       id,
+		Address:    address,
+		Memo:       memo,
+		OrderId:    orderId,
+		Thumbnail:  thumbnail,
+		CanBumpFee: bumpable,
+	}
+	return m, nil
+}
+
+func (t *TxMetadataDB) GetAll() (map[string]repo.Metadata, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	ret := make(map[string]repo.Metadata)
+	stm := "select txid, address, memo, orderID, thumbnail, canBumpFee from txmetadata"
+	rows, err := t.db.Query(stm)
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var txid, address, memo, orderId, thumbnail string
+		var canBumpFee int
+		if err := rows.Scan(&txid, &address, &memo, &orderId, &thumbnail, &canBumpFee); err != nil {
+			return ret, err
+		}
+		bumpable := false
+		if canBumpFee > 0 {
+			bumpable = true
+		}
+		m := repo.Metadata{
+			Txid:       txid,
+			Address:    address,
+			Memo:       memo,
+			OrderId:    orderId,
+			Thumbnail:  thumbnail,
+			CanBumpFee: bumpable,
+		}
+		ret[txid] = m
+	}
+	return ret, nil
+}
+
+func (t *TxMetadataDB) Delete(txid string) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	_, err := t.db.Exec("delete from txmetadata where txid=$1", txid)
+	if err != nil {
+		return err
+	}
+	return nil
+}
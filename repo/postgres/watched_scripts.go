@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"sync"
+)
+
+type WatchedScriptsDB struct {
+	db    *sql.DB
+	table string
+	lock  sync.RWMutex
+}
+
+func (w *WatchedScriptsDB) Put(scriptPubKey []byte) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	tx, _ := w.db.Begin()
+	stmt, err := tx.Prepare("insert into " + w.table + "(scriptPubKey) values($1) on conflict do nothing")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(hex.EncodeToString(scriptPubKey))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (w *WatchedScriptsDB) GetAll() ([][]byte, error) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	var ret [][]byte
+	stm := "select scriptPubKey from " + w.table
+	rows, err := w.db.Query(stm)
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var scriptHex string
+		if err := rows.Scan(&scriptHex); err != nil {
+			continue
+		}
+		scriptPubKey, err := hex.DecodeString(scriptHex)
+		if err != nil {
+			continue
+		}
+		ret = append(ret, scriptPubKey)
+	}
+	return ret, nil
+}
+
+func (w *WatchedScriptsDB) Delete(scriptPubKey []byte) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	_, err := w.db.Exec("delete from "+w.table+" where scriptPubKey=$1", hex.EncodeToString(scriptPubKey))
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/openbazaar-go/repo"
+)
+
+type WebhooksDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (w *WebhooksDB) Put(hook repo.Webhook) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	events, err := json.Marshal(hook.Events)
+	if err != nil {
+		return err
+	}
+	_, err = w.db.Exec("insert into webhooks(id, url, secret, events, timestamp) values($1,$2,$3,$4,$5) on conflict (id) do update set url=excluded.url, secret=excluded.secret, events=excluded.events, timestamp=excluded.timestamp", hook.Id, hook.URL, hook.Secret, string(events), int(hook.Created.Unix()))
+	return err
+}
+
+func (w *WebhooksDB) Get(id string) (repo.Webhook, error) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return scanWebhook(w.db.QueryRow("select id, url, secret, events, timestamp from webhooks where id=$1", id))
+}
+
+func (w *WebhooksDB) GetAll() ([]repo.Webhook, error) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	ret := []repo.Webhook{}
+	rows, err := w.db.Query("select id, url, secret, events, timestamp from webhooks order by rowid")
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		hook, err := scanWebhook(rows)
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, hook)
+	}
+	return ret, rows.Err()
+}
+
+func (w *WebhooksDB) Delete(id string) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	res, err := w.db.Exec("delete from webhooks where id=$1", id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repo.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func scanWebhook(row interface {
+	Scan(dest ...interface{}) error
+}) (repo.Webhook, error) {
+	var hook repo.Webhook
+	var events string
+	var timestamp int
+	if err := row.Scan(&hook.Id, &hook.URL, &hook.Secret, &events, &timestamp); err != nil {
+		return hook, err
+	}
+	if err := json.Unmarshal([]byte(events), &hook.Events); err != nil {
+		return hook, err
+	}
+	hook.Created = time.Unix(int64(timestamp), 0)
+	return hook, nil
+}
+
+type WebhookDeliveriesDB struct {
+	db   *sql.DB
+	lock sync.RWMutex
+}
+
+func (w *WebhookDeliveriesDB) Put(d repo.WebhookDelivery) (int64, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	var id int64
+	err := w.db.QueryRow("insert into webhookdeliveries(webhookID, url, event, payload, status, attempts, responseCode, error, nextAttempt, timestamp) values($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) returning rowid",
+		d.WebhookId, d.URL, d.Event, d.Payload, d.Status, d.Attempts, d.ResponseCode, d.Error, int(d.NextAttempt.Unix()), int(d.Timestamp.Unix())).Scan(&id)
+	return id, err
+}
+func (w *WebhookDeliveriesDB) Update(d repo.WebhookDelivery) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	_, err := w.db.Exec("update webhookdeliveries set status=$1, attempts=$2, responseCode=$3, error=$4, nextAttempt=$5 where rowid=$6", d.Status, d.Attempts, d.ResponseCode, d.Error, int(d.NextAttempt.Unix()), d.Id)
+	return err
+}
+
+func (w *WebhookDeliveriesDB) GetDue(now time.Time) ([]repo.WebhookDelivery, error) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	rows, err := w.db.Query("select rowid, webhookID, url, event, payload, status, attempts, responseCode, error, nextAttempt, timestamp from webhookdeliveries where status=$1 and nextAttempt<=$2 order by rowid", repo.WebhookDeliveryPending, int(now.Unix()))
+	if err != nil {
+		return nil, err
+	}
+	return webhookDeliveriesFromRows(rows)
+}
+
+func (w *WebhookDeliveriesDB) GetAll(status string, offset, limit int) ([]repo.WebhookDelivery, int, error) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	where := ""
+	var args []interface{}
+	if status != "" {
+		where = " where status=$1"
+		args = append(args, status)
+	}
+	var count int
+	if err := w.db.QueryRow("select count(*) from webhookdeliveries"+where, args...).Scan(&count); err != nil {
+		return nil, 0, err
+	}
+	rows, err := w.db.Query("select rowid, webhookID, url, event, payload, status, attempts, responseCode, error, nextAttempt, timestamp from webhookdeliveries"+where+" order by rowid desc limit "+limitClause(limit)+" offset "+strconv.Itoa(offset), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	ret, err := webhookDeliveriesFromRows(rows)
+	return ret, count, err
+}
+
+func webhookDeliveriesFromRows(rows *sql.Rows) ([]repo.WebhookDelivery, error) {
+	defer rows.Close()
+	ret := []repo.WebhookDelivery{}
+	for rows.Next() {
+		var d repo.WebhookDelivery
+		var nextAttempt, timestamp int
+		if err := rows.Scan(&d.Id, &d.WebhookId, &d.URL, &d.Event, &d.Payload, &d.Status, &d.Attempts, &d.ResponseCode, &d.Error, &nextAttempt, &timestamp); err != nil {
+			return ret, err
+		}
+		d.NextAttempt = time.Unix(int64(nextAttempt), 0)
+		d.Timestamp = time.Unix(int64(timestamp), 0)
+		ret = append(ret, d)
+	}
+	return ret, rows.Err()
+}
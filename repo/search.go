@@ -0,0 +1,65 @@
+package repo
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var ErrSearchUnavailable = errors.New("Search is unavailable as SQLite was built without FTS5. Build with CGO_CFLAGS=-DSQLITE_ENABLE_FTS5 to enable it")
+
+// Split a search string into the lowercase words to look for. Punctuation is dropped.
+func SearchTerms(q string) []string {
+	return strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+/* Returns whether results of the given type should be searched. Listings have no
+   state or timestamp so they're left out when filtering by either, and only orders
+   have a state. */
+func (q SearchQuery) Includes(resultType string) bool {
+	if len(q.Types) > 0 {
+		found := false
+		for _, t := range q.Types {
+			if t == resultType {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	switch resultType {
+	case SearchTypeListing:
+		return len(q.States) == 0 && q.From.IsZero() && q.To.IsZero()
+	case SearchTypeChat:
+		return len(q.States) == 0
+	}
+	return true
+}
+
+// Sort the results best match first, newest first among equal matches, and return the page selected by the offset and limit
+func PageSearchResults(results []SearchResult, offset, limit int) ([]SearchResult, int) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		if results[i].Timestamp == nil || results[j].Timestamp == nil {
+			return results[j].Timestamp == nil && results[i].Timestamp != nil
+		}
+		return results[i].Timestamp.After(*results[j].Timestamp)
+	})
+	total := len(results)
+	if offset < 0 {
+		offset = 0
+	} else if offset > total {
+		offset = total
+	}
+	results = results[offset:]
+	if limit >= 0 && limit < len(results) {
+		results = results[:limit]
+	}
+	return results, total
+}
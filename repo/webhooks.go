@@ -0,0 +1,18 @@
+package repo
+
+import "errors"
+
+var ErrWebhookNotFound = errors.New("Webhook not found")
+
+// Returns whether the webhook should be sent the event
+func (w Webhook) Wants(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	ma "gx/ipfs/QmSWLfmj5frN9xVLMMN846dMDriy5wN5jeghUm7aTW3DAG/go-multiaddr"
 	peer "gx/ipfs/QmWUswjn261LSyVxWAEpMVtPdy8zmKBJJfBpG3Qdpa8ZsE/go-libp2p-peer"
 	mh "gx/ipfs/QmbZ6Cee2uHjG7hf19qLHppgKDRtaG4CVtMzdmK9VCVqLu/go-multihash"
+	"net/url"
+	"path"
+	"strings"
 
 	"github.com/dropbox/dropbox-sdk-go-unofficial"
 	"github.com/dropbox/dropbox-sdk-go-unofficial/files"
@@ -64,3 +68,33 @@ func (s *DropBoxStorage) Store(peerID peer.ID, ciphertext []byte) (ma.Multiaddr,
 	}
 	return addr, nil
 }
+
+// The shared link ends with the name the message was uploaded under
+func (s *DropBoxStorage) Delete(addr ma.Multiaddr) error {
+	enc, err := addr.ValueForProtocol(ma.P_IPFS)
+	if err != nil {
+		return err
+	}
+	m, err := mh.FromB58String(enc)
+	if err != nil {
+		return err
+	}
+	d, err := mh.Decode(m)
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(string(d.Digest))
+	if err != nil {
+		return err
+	}
+	_, name := path.Split(u.Path)
+	if name == "" {
+		return errors.New("Address is not of a stored message")
+	}
+	api := dropbox.Client(s.apiToken, dropbox.Options{Verbose: true})
+	_, err = api.Delete(files.NewDeleteArg("/" + name))
+	if err != nil && strings.Contains(err.Error(), "not_found") {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,343 @@
+package s3
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	ma "gx/ipfs/QmSWLfmj5frN9xVLMMN846dMDriy5wN5jeghUm7aTW3DAG/go-multiaddr"
+	peer "gx/ipfs/QmWUswjn261LSyVxWAEpMVtPdy8zmKBJJfBpG3Qdpa8ZsE/go-libp2p-peer"
+	mh "gx/ipfs/QmbZ6Cee2uHjG7hf19qLHppgKDRtaG4CVtMzdmK9VCVqLu/go-multihash"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	rep "github.com/OpenBazaar/openbazaar-go/net/repointer"
+	"github.com/OpenBazaar/openbazaar-go/repo"
+	"github.com/op/go-logging"
+	"golang.org/x/net/proxy"
+)
+
+var log = logging.MustGetLogger("s3")
+
+const (
+	// Messages are stored under this prefix so cleanup leaves the rest of the bucket alone
+	keyPrefix = "openbazaar-outbox/"
+
+	// How often expired messages are removed from the bucket
+	cleanupInterval = time.Hour
+
+	emptyPayloadHash = "e3b0c44298fc1c149afbe4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+/* S3Storage stores messages in a bucket on Amazon S3 or a compatible service such
+   as MinIO. Recipients download them over HTTPS from the bucket's public URL.
+   Messages are kept for as long as the pointers to them are republished. */
+type S3Storage struct {
+	endpoint   *url.URL
+	publicURL  string
+	region     string
+	bucket     string
+	accessKey  string
+	secretKey  string
+	expiry     time.Duration
+	httpClient *http.Client
+}
+
+func NewS3Storage(cfg repo.S3Config, dialer proxy.Dialer) (*S3Storage, error) {
+	dial := net.Dial
+	if dialer != nil {
+		dial = dialer.Dial
+	}
+	return newS3Storage(cfg, &http.Client{Transport: &http.Transport{Dial: dial}, Timeout: time.Minute})
+}
+
+func newS3Storage(cfg repo.S3Config, httpClient *http.Client) (*S3Storage, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, errors.New("S3 endpoint, bucket and credentials must be set in the config file")
+	}
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "https" && !(endpoint.Scheme == "http" && isLoopback(endpoint.Host))) {
+		return nil, errors.New("S3 endpoint must be an https URL, or http on this machine")
+	}
+	if !strings.HasPrefix(cfg.PublicURL, "https://") {
+		return nil, errors.New("S3 public URL must be set to the https URL the bucket can be read from")
+	}
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	s := &S3Storage{
+		endpoint:   endpoint,
+		publicURL:  strings.TrimRight(cfg.PublicURL, "/"),
+		region:     region,
+		bucket:     cfg.Bucket,
+		accessKey:  cfg.AccessKey,
+		secretKey:  cfg.SecretKey,
+		expiry:     rep.MessagePointerTTL,
+		httpClient: httpClient,
+	}
+
+	// Check the bucket exists and the credentials work
+	resp, err := s.do("HEAD", "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return s, nil
+}
+
+func (s *S3Storage) Store(peerID peer.ID, ciphertext []byte) (ma.Multiaddr, error) {
+	b := sha256.Sum256(ciphertext)
+	key := keyPrefix + hex.EncodeToString(b[:])
+	resp, err := s.do("PUT", key, nil, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	// Create encoded multiaddr
+	enc, err := mh.Encode([]byte(s.publicURL+"/"+key), mh.SHA1)
+	if err != nil {
+		return nil, err
+	}
+	m, err := mh.Cast(enc)
+	if err != nil {
+		return nil, err
+	}
+	return ma.NewMultiaddr("/ipfs/" + m.B58String() + "/https/")
+}
+
+// Delete the message stored at an address returned by Store
+func (s *S3Storage) Delete(addr ma.Multiaddr) error {
+	enc, err := addr.ValueForProtocol(ma.P_IPFS)
+	if err != nil {
+		return err
+	}
+	m, err := mh.FromB58String(enc)
+	if err != nil {
+		return err
+	}
+	d, err := mh.Decode(m)
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(string(d.Digest))
+	if err != nil {
+		return err
+	}
+	i := strings.Index(u.Path, keyPrefix)
+	if i < 0 {
+		return errors.New("Address is not of a stored message")
+	}
+	resp, err := s.do("DELETE", u.Path[i:], nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Periodically remove expired messages from the bucket
+func (s *S3Storage) Run() {
+	tick := time.NewTicker(cleanupInterval)
+	defer tick.Stop()
+	s.removeExpired()
+	for range tick.C {
+		s.removeExpired()
+	}
+}
+
+func (s *S3Storage) removeExpired() {
+	n, err := s.Cleanup(time.Now().Add(-s.expiry))
+	if err != nil {
+		log.Error("Failed to remove expired messages from S3:", err)
+	} else if n > 0 {
+		log.Infof("Removed %d expired messages from S3", n)
+	}
+}
+
+// Delete every message stored before the cutoff and return how many were removed
+func (s *S3Storage) Cleanup(cutoff time.Time) (int, error) {
+	type listResult struct {
+		Contents []struct {
+			Key          string
+			LastModified time.Time
+		}
+		IsTruncated           bool
+		NextContinuationToken string
+	}
+	deleted := 0
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {keyPrefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		resp, err := s.do("GET", "", query, nil)
+		if err != nil {
+			return deleted, err
+		}
+		var list listResult
+		err = xml.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if err != nil {
+			return deleted, err
+		}
+		for _, obj := range list.Contents {
+			if !obj.LastModified.Before(cutoff) {
+				continue
+			}
+			resp, err := s.do("DELETE", obj.Key, nil, nil)
+			if err != nil {
+				return deleted, err
+			}
+			resp.Body.Close()
+			deleted++
+		}
+		if !list.IsTruncated || list.NextContinuationToken == "" {
+			return deleted, nil
+		}
+		token = list.NextContinuationToken
+	}
+}
+
+// Make a signed request for the bucket or an object in it. Responses other than 2xx are returned as errors.
+func (s *S3Storage) do(method, key string, query url.Values, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, s.objectURL(key, query), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	payloadHash := emptyPayloadHash
+	if len(body) > 0 {
+		h := sha256.Sum256(body)
+		payloadHash = hex.EncodeToString(h[:])
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+	s.sign(req, payloadHash, time.Now())
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("S3 %s %s failed: %s %s", method, key, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+// Objects are addressed path style as virtual hosted buckets aren't supported by every S3 compatible service
+func (s *S3Storage) objectURL(key string, query url.Values) string {
+	u := *s.endpoint
+	u.Path = strings.TrimRight(u.Path, "/") + "/" + s.bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawQuery = canonicalQuery(query)
+	return u.String()
+}
+
+// Add an AWS Signature Version 4 Authorization header covering the host and all headers set on the request
+func (s *S3Storage) sign(req *http.Request, payloadHash string, t time.Time) {
+	amzDate := t.UTC().Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	var names []string
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders string
+	for _, k := range names {
+		canonicalHeaders += k + ":" + headers[k] + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := s.scope(t)
+	signature := s.signature(t, canonicalRequest)
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.accessKey+"/"+scope+", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func (s *S3Storage) scope(t time.Time) string {
+	return t.UTC().Format("20060102") + "/" + s.region + "/s3/aws4_request"
+}
+
+func (s *S3Storage) signature(t time.Time, canonicalRequest string) string {
+	h := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + t.UTC().Format("20060102T150405Z") + "\n" + s.scope(t) + "\n" + hex.EncodeToString(h[:])
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), t.UTC().Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// Credentials are only sent over plain http to a service on this machine, such as MinIO run locally
+func isLoopback(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func canonicalURI(u *url.URL) string {
+	p := u.EscapedPath()
+	if p == "" {
+		return "/"
+	}
+	return p
+}
+
+// Sort and encode the query as AWS expects. Only unreserved characters are left unescaped.
+func canonicalQuery(query url.Values) string {
+	var keys []string
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		vals := query[k]
+		sort.Strings(vals)
+		for _, v := range vals {
+			parts = append(parts, awsEscape(k)+"="+awsEscape(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func awsEscape(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
@@ -12,12 +12,17 @@ import (
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
 	"github.com/ipfs/go-ipfs/commands"
 	"golang.org/x/net/proxy"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
+// Files in the outbox younger than this are never swept
+const sweepGracePeriod = time.Hour
+
 type SelfHostedStorage struct {
 	repoPath          string
 	context           commands.Context
@@ -66,3 +71,68 @@ func (s *SelfHostedStorage) Store(peerID peer.ID, ciphertext []byte) (ma.Multiad
 	}
 	return maAddr, nil
 }
+
+// Remove the message from the outbox and unpin it so IPFS can garbage collect it
+func (s *SelfHostedStorage) Delete(addr ma.Multiaddr) error {
+	hash, err := addr.ValueForProtocol(ma.P_IPFS)
+	if err != nil {
+		return err
+	}
+	// Files in the outbox are named by the hash of their contents which we get from our pinned copy
+	ciphertext, err := ipfs.Cat(s.context, hash)
+	if err != nil {
+		return err
+	}
+	b := sha256.Sum256(ciphertext)
+	err = os.Remove(path.Join(s.repoPath, "outbox", hex.EncodeToString(b[:])))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return unpin(s.context, hash)
+}
+
+// Remove the files in the outbox which aren't at one of the addresses in use
+func (s *SelfHostedStorage) Sweep(inUse []ma.Multiaddr) (int, error) {
+	keep := make(map[string]bool)
+	for _, addr := range inUse {
+		if hash, err := addr.ValueForProtocol(ma.P_IPFS); err == nil {
+			keep[hash] = true
+		}
+	}
+	files, err := ioutil.ReadDir(path.Join(s.repoPath, "outbox"))
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, f := range files {
+		// Skip new files as their pointer may not have been saved yet
+		if f.IsDir() || time.Since(f.ModTime()) < sweepGracePeriod {
+			continue
+		}
+		filePath := path.Join(s.repoPath, "outbox", f.Name())
+		hash, err := ipfs.GetHash(s.context, filePath)
+		if err != nil {
+			return removed, err
+		}
+		if keep[hash] {
+			continue
+		}
+		if err := os.Remove(filePath); err != nil {
+			return removed, err
+		}
+		if err := unpin(s.context, hash); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// Unpin a message ignoring the error IPFS gives when it's not pinned
+func unpin(ctx commands.Context, hash string) error {
+	err := ipfs.UnPinDir(ctx, hash)
+	if err != nil && strings.Contains(err.Error(), "not pinned") {
+		return nil
+	}
+	return err
+}
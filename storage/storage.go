@@ -21,4 +21,16 @@ type OfflineMessagingStorage interface {
 
 	   Note all messages are encrypted before passed in here. */
 	Store(peerID peer.ID, ciphertext []byte) (ma.Multiaddr, error)
+
+	/* Delete removes a message saved by Store given the address it returned. It's
+	   called once the recipient acknowledges the message or it expires so storage
+	   doesn't grow forever. Deleting a message which is already gone is not an error. */
+	Delete(addr ma.Multiaddr) error
+}
+
+/* Storage which can find the messages it holds implements Sweeper so any left behind,
+   say because a Delete failed, can be removed. Sweep removes every stored message
+   whose address isn't in inUse and returns how many it removed. */
+type Sweeper interface {
+	Sweep(inUse []ma.Multiaddr) (int, error)
 }
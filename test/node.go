@@ -2,6 +2,7 @@ package test
 
 import (
 	// "github.com/ipfs/go-ipfs/thirdparty/testutil"
+	"github.com/OpenBazaar/openbazaar-go/bitcoin"
 	"github.com/OpenBazaar/openbazaar-go/core"
 	"github.com/OpenBazaar/openbazaar-go/ipfs"
 	"github.com/OpenBazaar/openbazaar-go/net"
@@ -62,6 +63,11 @@ func NewNode() (*core.OpenBazaarNode, error) {
 		return nil, err
 	}
 
+	wallets, err := bitcoin.NewWalletRegistry(wallet)
+	if err != nil {
+		return nil, err
+	}
+
 	// Put it all together in an OpenBazaarNode
 	node := &core.OpenBazaarNode{
 		Context:    ctx,
@@ -69,6 +75,7 @@ func NewNode() (*core.OpenBazaarNode, error) {
 		IpfsNode:   ipfsNode,
 		Datastore:  repository.DB,
 		Wallet:     wallet,
+		Wallets:    wallets,
 		BanManager: net.NewBanManager([]peer.ID{}),
 	}
 
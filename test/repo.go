@@ -3,6 +3,7 @@ package test
 import (
 	"os"
 	"path"
+	"time"
 
 	"github.com/OpenBazaar/openbazaar-go/repo"
 	"github.com/OpenBazaar/openbazaar-go/repo/db"
@@ -86,6 +87,37 @@ func (r *Repository) Reset() error {
 		}
 	}
 
+	// Remove any API tokens
+	tokens, err := r.DB.APITokens().GetAll()
+	if err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		err := r.DB.APITokens().Delete(token.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Remove any webhooks
+	hooks, err := r.DB.Webhooks().GetAll()
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		err := r.DB.Webhooks().Delete(hook.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Remove any outgoing messages
+	now := time.Now()
+	_, err = r.DB.OutgoingMessages().Prune(now, now)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
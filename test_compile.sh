@@ -15,6 +15,7 @@ go test -coverprofile=netservice.cover.out ./net/repointer
 go test -coverprofile=netservice.cover.out ./net/retriever
 go test -coverprofile=repo.cover.out ./repo
 go test -coverprofile=repodb.cover.out ./repo/db
+go test -coverprofile=repopostgres.cover.out ./repo/postgres
 go test -coverprofile=storage.cover.out ./storage
 go test -coverprofile=dropbox.cover.out ./storage/dropbox
 go test -coverprofile=selfhosted.cover.out ./storage/selfhosted